@@ -39,6 +39,13 @@ The component label for the run controller.
 app.kubernetes.io/component: run-controller
 {{- end -}}
 
+{{/*
+The label selecting the configuration ConfigMaps validated by the config webhook.
+*/}}
+{{- define "steward.runController.configValidationLabel" -}}
+steward.sap.com/validate-config: "true"
+{{- end -}}
+
 
 {{/*
 The additional labels for the service monitors.
@@ -41,6 +41,16 @@ func Test_ConfigPipelineRuns(t *testing.T) {
 			},
 			expectedError: "",
 		},
+		{
+			name: "defaultImagePullSecrets",
+			values: map[string]string{
+				"pipelineRuns.defaultImagePullSecrets": "{secret1,secret2}",
+			},
+			expectedMapEntries: map[string]string{
+				"defaultImagePullSecrets": "secret1,secret2",
+			},
+			expectedError: "",
+		},
 		{
 			name: "old",
 			values: map[string]string{
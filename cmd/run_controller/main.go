@@ -4,13 +4,26 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"math"
+	"net"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/SAP/stewardci-core/pkg/featureflag"
 	"github.com/SAP/stewardci-core/pkg/k8s"
 	"github.com/SAP/stewardci-core/pkg/metrics"
 	"github.com/SAP/stewardci-core/pkg/runctl"
+	"github.com/SAP/stewardci-core/pkg/runctl/cfg"
+	"github.com/SAP/stewardci-core/pkg/runctl/cfg/webhook"
+	"github.com/SAP/stewardci-core/pkg/runctl/cloudevents"
+	"github.com/SAP/stewardci-core/pkg/runctl/leaderelection"
+	runctlmetrics "github.com/SAP/stewardci-core/pkg/runctl/metrics"
+	"github.com/SAP/stewardci-core/pkg/runctl/sharding"
 	"github.com/SAP/stewardci-core/pkg/signals"
+	"github.com/SAP/stewardci-core/pkg/tracing"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	klog "k8s.io/klog/v2"
@@ -25,6 +38,22 @@ const (
 	// metricsPort is the TCP port number to be used by the metrics
 	// HTTP server.
 	metricsPort = 9090
+
+	// leaseName is the name of the Lease object in the system namespace
+	// used for leader election.
+	leaseName = "steward-run-controller"
+
+	// shardLeaseNamePrefix is the name prefix of the Lease objects in the
+	// system namespace used for sharding.
+	shardLeaseNamePrefix = "steward-run-controller"
+
+	// tracingServiceName is the service name of trace spans recorded by
+	// the run controller.
+	tracingServiceName = "steward-run-controller"
+
+	// tracingShutdownTimeout is the maximum time to wait for pending trace
+	// spans to be exported when exiting.
+	tracingShutdownTimeout = 5 * time.Second
 )
 
 var (
@@ -35,7 +64,41 @@ var (
 	heartbeatLogging  bool
 	heartbeatLogLevel int
 
+	heartbeatLivenessFactor int
+
 	k8sAPIRequestTimeout time.Duration
+
+	drainTimeout time.Duration
+
+	leaderElection              bool
+	leaderElectionLeaseDuration time.Duration
+	leaderElectionRenewDeadline time.Duration
+	leaderElectionRetryPeriod   time.Duration
+
+	shards             int
+	shardLeaseDuration time.Duration
+	shardRenewDeadline time.Duration
+	shardRetryPeriod   time.Duration
+
+	metricsNamespaceAllowlist string
+	metricsNamespaceTopN      int
+
+	startLatencySLOThresholds string
+
+	cloudEventsQueueSize int
+
+	egressAllowedNetworks string
+
+	configWebhookPort     uint
+	configWebhookCertFile string
+	configWebhookKeyFile  string
+
+	tracingOTLPEndpoint string
+	tracingOTLPInsecure bool
+
+	// shutdownTracing flushes pending spans and shuts down tracing.
+	// It is nil if tracing is disabled.
+	shutdownTracing func(context.Context) error
 )
 
 func init() {
@@ -84,12 +147,144 @@ func init() {
 		3,
 		"The log level to be used for controller heartbeats.",
 	)
+	flag.IntVar(
+		&heartbeatLivenessFactor,
+		"heartbeat-liveness-factor",
+		5,
+		"The liveness check fails if no controller heartbeat has been processed within this multiple of the heartbeat interval."+
+			" Zero disables the liveness check.",
+	)
 	flag.DurationVar(
 		&k8sAPIRequestTimeout,
 		"k8s-api-request-timeout",
 		15*time.Minute,
 		"The maximum length of time to wait before giving up on a server request. A value of zero means no timeout.",
 	)
+	flag.DurationVar(
+		&drainTimeout,
+		"drain-timeout",
+		25*time.Second,
+		"The maximum time to wait for in-flight reconciliations to finish on shutdown."+
+			" Should be less than the termination grace period of the pod. A value of zero means no waiting."+
+			" If leader election or sharding is enabled, it must be less than the lease duration minus the renew deadline"+
+			" and defaults to half of this difference.",
+	)
+	flag.BoolVar(
+		&leaderElection,
+		"leader-election",
+		false,
+		"Whether to run leader election, so that only one of multiple controller instances is active at a time.",
+	)
+	flag.DurationVar(
+		&leaderElectionLeaseDuration,
+		"leader-election-lease-duration",
+		15*time.Second,
+		"The time standby instances wait after the last renewal of the leader lease before trying to take over leadership.",
+	)
+	flag.DurationVar(
+		&leaderElectionRenewDeadline,
+		"leader-election-renew-deadline",
+		10*time.Second,
+		"The time the leader retries renewing the leader lease before giving up leadership.",
+	)
+	flag.DurationVar(
+		&leaderElectionRetryPeriod,
+		"leader-election-retry-period",
+		2*time.Second,
+		"The time instances wait between attempts to acquire or renew the leader lease.",
+	)
+	flag.IntVar(
+		&shards,
+		"shards",
+		0,
+		"The number of shards pipeline runs are distributed to. Each controller instance processes the pipeline runs"+
+			" of the shards it owns. Zero disables sharding. Cannot be combined with leader election.",
+	)
+	flag.DurationVar(
+		&shardLeaseDuration,
+		"shard-lease-duration",
+		15*time.Second,
+		"The time instances wait after the last renewal of a shard lease before taking over the shard.",
+	)
+	flag.DurationVar(
+		&shardRenewDeadline,
+		"shard-renew-deadline",
+		10*time.Second,
+		"The time after the last renewal of a shard lease after which an instance stops processing the shard and cancels its in-flight reconciliations.",
+	)
+	flag.DurationVar(
+		&shardRetryPeriod,
+		"shard-retry-period",
+		2*time.Second,
+		"The interval of renewing shard leases and rebalancing shards between instances.",
+	)
+	flag.StringVar(
+		&metricsNamespaceAllowlist,
+		"metrics-namespace-allowlist",
+		"",
+		"A comma-separated list of namespaces for which pipeline run metrics are broken down by namespace.",
+	)
+	flag.IntVar(
+		&metricsNamespaceTopN,
+		"metrics-namespace-top-n",
+		0,
+		"The number of namespaces with most pipeline runs for which pipeline run metrics are broken down by namespace"+
+			" in addition to the namespaces in the allowlist. Zero disables the top N breakdown.",
+	)
+	flag.StringVar(
+		&startLatencySLOThresholds,
+		"start-latency-slo-thresholds",
+		"",
+		"A comma-separated list of durations for which pipeline runs are counted as started within or not,"+
+			" e.g. '1m,5m'. Empty disables the start SLO metric.",
+	)
+	flag.IntVar(
+		&cloudEventsQueueSize,
+		"cloudevents-queue-size",
+		cloudevents.DefaultQueueSize,
+		"The maximum number of CloudEvents about pipeline runs waiting for delivery."+
+			" Events emitted while the queue is full are dropped.",
+	)
+	flag.StringVar(
+		&egressAllowedNetworks,
+		"egress-allowed-networks",
+		"",
+		"A comma-separated list of networks in CIDR notation, e.g. '10.0.0.0/8', notification targets"+
+			" and CloudEvents sinks of pipeline runs and GitHub API servers may be located in."+
+			" Loopback, link-local and private addresses are rejected otherwise.",
+	)
+	flag.UintVar(
+		&configWebhookPort,
+		"config-webhook-port",
+		0,
+		"The TCP port number of the HTTPS server serving the validating admission webhook for the configuration ConfigMaps."+
+			" Zero disables the webhook server.",
+	)
+	flag.StringVar(
+		&configWebhookCertFile,
+		"config-webhook-cert-file",
+		"",
+		"The path to the PEM-encoded TLS certificate of the config webhook server.",
+	)
+	flag.StringVar(
+		&configWebhookKeyFile,
+		"config-webhook-key-file",
+		"",
+		"The path to the PEM-encoded TLS private key of the config webhook server.",
+	)
+	flag.StringVar(
+		&tracingOTLPEndpoint,
+		"tracing-otlp-endpoint",
+		"",
+		"The host and optional port of the OTLP/HTTP endpoint trace spans of pipeline run reconciliations are exported to."+
+			" Empty disables tracing.",
+	)
+	flag.BoolVar(
+		&tracingOTLPInsecure,
+		"tracing-otlp-insecure",
+		false,
+		"Whether to connect to the OTLP endpoint without TLS.",
+	)
 
 	flag.Parse()
 }
@@ -144,35 +339,279 @@ func main() {
 		flushLogsAndExit()
 	}
 
-	logger.V(2).Info("Starting metrics server",
-		"metricsEndpoint", fmt.Sprintf("http://0.0.0.0:%d/metrics", metricsPort),
-	)
-	metrics.StartServer(logger, metricsPort)
+	if leaderElection && shards > 0 {
+		logger.Error(nil, "Leader election and sharding cannot be enabled both",
+			"flags", []string{"-leader-election", "-shards"},
+		)
+		flushLogsAndExit()
+	}
+
+	if leaderElection || shards > 0 {
+		if err := adjustDrainTimeout(); err != nil {
+			logger.Error(err, "Invalid drain timeout", "flag", "-drain-timeout")
+			flushLogsAndExit()
+		}
+	}
+
+	var shardManager *sharding.Manager
+	if shards > 0 {
+		shardManager, err = newShardManager(logger, factory)
+		if err != nil {
+			logger.Error(err, "Failed to set up sharding")
+			flushLogsAndExit()
+		}
+	}
+
+	if tracingOTLPEndpoint != "" {
+		logger.V(2).Info("Enabling tracing",
+			"otlpEndpoint", tracingOTLPEndpoint,
+			"insecure", tracingOTLPInsecure,
+		)
+		shutdownTracing, err = tracing.Init(ctx, tracing.Options{
+			ServiceName:  tracingServiceName,
+			OTLPEndpoint: tracingOTLPEndpoint,
+			Insecure:     tracingOTLPInsecure,
+		})
+		if err != nil {
+			logger.Error(err, "Failed to set up tracing")
+			flushLogsAndExit()
+		}
+		defer flushTracing()
+	}
+
+	runctlmetrics.PipelineRunNamespaces.Configure(splitList(metricsNamespaceAllowlist), metricsNamespaceTopN)
+
+	var sloThresholds []time.Duration
+	for _, item := range splitList(startLatencySLOThresholds) {
+		threshold, err := time.ParseDuration(item)
+		if err != nil || threshold <= 0 {
+			logger.Error(err, "Invalid start latency SLO threshold",
+				"flag", "-start-latency-slo-thresholds",
+				"value", item,
+			)
+			flushLogsAndExit()
+		}
+		sloThresholds = append(sloThresholds, threshold)
+	}
+	runctlmetrics.PipelineRunsStart.SetSLOThresholds(sloThresholds)
+
+	configStore := cfg.NewStore(logger, factory.CoreV1(), resyncPeriod)
+
+	var egressNetworks []*net.IPNet
+	for _, item := range splitList(egressAllowedNetworks) {
+		_, network, err := net.ParseCIDR(item)
+		if err != nil {
+			logger.Error(err, "Invalid allowed egress network",
+				"flag", "-egress-allowed-networks",
+				"value", item,
+			)
+			flushLogsAndExit()
+		}
+		egressNetworks = append(egressNetworks, network)
+	}
+
+	cloudEventsEmitter, err := cloudevents.NewEmitter(logger, cloudevents.Options{
+		QueueSize:       cloudEventsQueueSize,
+		AllowedNetworks: egressNetworks,
+	})
+	if err != nil {
+		logger.Error(err, "Failed to set up CloudEvents emitter")
+		flushLogsAndExit()
+	}
 
 	logger.V(3).Info("Creating controller")
 	controllerOpts := runctl.ControllerOpts{
 		HeartbeatInterval:       heartbeatInterval,
 		HeartbeatLoggingEnabled: heartbeatLogging,
 		HeartbeatLogLevel:       heartbeatLogLevel,
+		HeartbeatLivenessFactor: heartbeatLivenessFactor,
+		DrainTimeout:            drainTimeout,
+		ConfigStore:             configStore,
+		CloudEvents:             cloudEventsEmitter,
+		EgressAllowedNetworks:   egressNetworks,
+	}
+	if shardManager != nil {
+		// assign only if non-nil to avoid a non-nil interface holding a nil pointer
+		controllerOpts.Shards = shardManager
 	}
 
 	controller := runctl.NewController(logger, factory, controllerOpts)
 
+	logger.V(2).Info("Starting metrics server",
+		"metricsEndpoint", fmt.Sprintf("http://0.0.0.0:%d/metrics", metricsPort),
+		"livenessEndpoint", fmt.Sprintf("http://0.0.0.0:%d/healthz", metricsPort),
+		"readinessEndpoint", fmt.Sprintf("http://0.0.0.0:%d/readyz", metricsPort),
+	)
+	metrics.StartServer(logger, metricsPort, metrics.HealthChecks{
+		Liveness:  controller.CheckLiveness,
+		Readiness: controller.CheckReadiness,
+	})
+
+	if configWebhookPort > 0 {
+		if configWebhookPort > math.MaxUint16 || configWebhookCertFile == "" || configWebhookKeyFile == "" {
+			logger.Error(nil, "Invalid config webhook settings",
+				"flags", []string{"-config-webhook-port", "-config-webhook-cert-file", "-config-webhook-key-file"},
+			)
+			flushLogsAndExit()
+		}
+		logger.V(2).Info("Starting config webhook server",
+			"webhookEndpoint", fmt.Sprintf("https://0.0.0.0:%d%s", configWebhookPort, webhook.Path),
+		)
+		webhook.StartServer(logger, uint16(configWebhookPort), configWebhookCertFile, configWebhookKeyFile)
+	}
+
 	logger.V(3).Info("Creating signal handlers")
 	stopCh := signals.SetupShutdownSignalHandler(logger, flushLogsAndExit)
 	signals.SetupThreadDumpSignalHandler(logger)
 
+	// Informers are started independent of leadership, so that standby
+	// instances have warm caches when taking over.
 	logger.V(2).Info("Starting Informers")
 	factory.StewardInformerFactory().Start(stopCh)
 	factory.TektonInformerFactory().Start(stopCh)
+	factory.KubernetesInformerFactory().Start(stopCh)
+	configStore.Start(stopCh)
+	cloudEventsEmitter.Start(stopCh)
+
+	runController := func(ctx context.Context, stopCh <-chan struct{}) error {
+		logger.V(2).Info("Running controller", "threadiness", threadiness)
+		return controller.Run(ctx, threadiness, stopCh)
+	}
 
-	logger.V(2).Info("Running controller", "threadiness", threadiness)
-	if err = controller.Run(threadiness, stopCh); err != nil {
-		logger.Error(err, "Failed to run controller")
+	if leaderElection {
+		err = runWithLeaderElection(logger, factory, stopCh, runController)
+	} else if shardManager != nil {
+		err = runWithSharding(shardManager, stopCh, runController)
+	} else {
+		runctlmetrics.ControllerLeader.Set(1)
+		err = runController(ctx, stopCh)
+	}
+	if err != nil {
+		if errors.Is(err, leaderelection.ErrLeadershipLost) {
+			logger.Error(err, "Exiting to restart as standby instance")
+		} else {
+			logger.Error(err, "Failed to run controller")
+		}
 		flushLogsAndExit()
 	}
 }
 
+// adjustDrainTimeout validates the drain timeout if leader election or
+// sharding is enabled. Another instance may take over the pipeline runs
+// after the lease duration minus the renew deadline, so the drain timeout
+// must be shorter. If not set explicitly, the drain timeout defaults to
+// half of this difference.
+func adjustDrainTimeout() error {
+	leaseDuration, renewDeadline := leaderElectionLeaseDuration, leaderElectionRenewDeadline
+	if shards > 0 {
+		leaseDuration, renewDeadline = shardLeaseDuration, shardRenewDeadline
+	}
+	maxDrainTimeout := leaseDuration - renewDeadline
+
+	drainTimeoutSet := false
+	flag.Visit(func(f *flag.Flag) {
+		drainTimeoutSet = drainTimeoutSet || f.Name == "drain-timeout"
+	})
+	if !drainTimeoutSet {
+		drainTimeout = maxDrainTimeout / 2
+	}
+	if drainTimeout >= maxDrainTimeout {
+		return fmt.Errorf(
+			"drain timeout %s must be less than lease duration %s minus renew deadline %s",
+			drainTimeout, leaseDuration, renewDeadline,
+		)
+	}
+	return nil
+}
+
+func runWithLeaderElection(logger logr.Logger, factory k8s.ClientFactory, stopCh <-chan struct{}, runController func(ctx context.Context, stopCh <-chan struct{}) error) error {
+	identity, err := os.Hostname()
+	if err != nil {
+		return errors.Wrap(err, "failed to determine identity for leader election")
+	}
+	config := leaderelection.Config{
+		LeaseName:      leaseName,
+		LeaseNamespace: system.Namespace(),
+		Identity:       identity,
+		LeaseDuration:  leaderElectionLeaseDuration,
+		RenewDeadline:  leaderElectionRenewDeadline,
+		RetryPeriod:    leaderElectionRetryPeriod,
+	}
+	logger.V(2).Info("Starting leader election",
+		"leaseDuration", config.LeaseDuration,
+		"renewDeadline", config.RenewDeadline,
+		"retryPeriod", config.RetryPeriod,
+	)
+	return leaderelection.Run(logger, factory.CoordinationV1(), config, stopCh, runController)
+}
+
+func newShardManager(logger logr.Logger, factory k8s.ClientFactory) (*sharding.Manager, error) {
+	identity, err := os.Hostname()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to determine identity for sharding")
+	}
+	config := sharding.Config{
+		Shards:          shards,
+		LeaseNamePrefix: shardLeaseNamePrefix,
+		LeaseNamespace:  system.Namespace(),
+		Identity:        identity,
+		LeaseDuration:   shardLeaseDuration,
+		RenewDeadline:   shardRenewDeadline,
+		RetryPeriod:     shardRetryPeriod,
+	}
+	logger.V(2).Info("Enabling sharding",
+		"shards", config.Shards,
+		"leaseDuration", config.LeaseDuration,
+		"renewDeadline", config.RenewDeadline,
+		"retryPeriod", config.RetryPeriod,
+	)
+	return sharding.NewManager(logger, factory.CoordinationV1(), config)
+}
+
+// runWithSharding runs the controller while the shard manager acquires
+// shards. After the controller has stopped, it waits for the shard manager
+// to release the shards.
+func runWithSharding(shardManager *sharding.Manager, stopCh <-chan struct{}, runController func(ctx context.Context, stopCh <-chan struct{}) error) error {
+	// every instance is active, sharding restricts the pipeline runs processed
+	runctlmetrics.ControllerLeader.Set(1)
+
+	shardingDone := make(chan struct{})
+	go func() {
+		defer close(shardingDone)
+		shardManager.Run(stopCh)
+	}()
+	// reconciliations are cancelled by the shard manager if a shard is lost
+	if err := runController(context.Background(), stopCh); err != nil {
+		// shard leases expire after exit
+		return err
+	}
+	<-shardingDone
+	return nil
+}
+
+// splitList splits a comma-separated list into its trimmed non-empty
+// elements.
+func splitList(list string) []string {
+	var result []string
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// flushTracing flushes pending spans and shuts down tracing, waiting at
+// most for the tracing shutdown timeout.
+func flushTracing() {
+	if shutdownTracing != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), tracingShutdownTimeout)
+		defer cancel()
+		shutdownTracing(ctx) // ignore error
+	}
+}
+
 func flushLogsAndExit() {
+	flushTracing()
 	klog.FlushAndExit(klog.ExitFlushTimeout, 1)
 }
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/SAP/stewardci-core/pkg/runctl/cfg"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	k8syaml "k8s.io/apimachinery/pkg/util/yaml"
+)
+
+const (
+	exitCodeInvalid = 1
+	exitCodeUsage   = 2
+)
+
+const usage = `Usage: steward-config validate FILE...
+
+Validates the Steward configuration ConfigMaps contained in the given
+YAML or JSON files (use "-" for stdin). Files may contain multiple
+documents. Documents other than the ConfigMaps "steward-pipelineruns",
+"steward-pipelineruns-network-policies", "steward-maintenance-mode" and
+"steward-feature-flags" are ignored.
+
+Exits with code 1 if an invalid ConfigMap has been found.
+`
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+	}
+	flag.Parse()
+
+	args := flag.Args()
+	if len(args) < 2 || args[0] != "validate" {
+		flag.Usage()
+		os.Exit(exitCodeUsage)
+	}
+
+	valid := true
+	for _, fileName := range args[1:] {
+		fileValid, err := validateFile(fileName, os.Stdout)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", fileName, err.Error())
+			os.Exit(exitCodeUsage)
+		}
+		valid = valid && fileValid
+	}
+	if !valid {
+		os.Exit(exitCodeInvalid)
+	}
+}
+
+// validateFile validates all Steward configuration ConfigMaps in the given
+// file and reports the results to out.
+// It returns false if an invalid ConfigMap has been found, or an error if
+// the file cannot be read or parsed.
+func validateFile(fileName string, out io.Writer) (bool, error) {
+	var reader io.Reader
+	if fileName == "-" {
+		reader = os.Stdin
+	} else {
+		file, err := os.Open(fileName)
+		if err != nil {
+			return false, err
+		}
+		defer file.Close()
+		reader = file
+	}
+
+	valid := true
+	decoder := k8syaml.NewYAMLOrJSONDecoder(bufio.NewReader(reader), 4096)
+	for {
+		configMap := &corev1.ConfigMap{}
+		if err := decoder.Decode(configMap); err != nil {
+			if err == io.EOF {
+				return valid, nil
+			}
+			return false, errors.Wrap(err, "cannot parse file")
+		}
+		if configMap.APIVersion != "v1" || configMap.Kind != "ConfigMap" || !cfg.IsConfigMapName(configMap.GetName()) {
+			continue
+		}
+		if err := cfg.ValidateConfigMap(configMap); err != nil {
+			valid = false
+			fmt.Fprintf(out, "%s: ConfigMap %q: invalid: %s\n", fileName, configMap.GetName(), err.Error())
+		} else {
+			fmt.Fprintf(out, "%s: ConfigMap %q: valid\n", fileName, configMap.GetName())
+		}
+	}
+}
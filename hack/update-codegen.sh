@@ -298,7 +298,7 @@ if is_generate_mocks; then
         "pkg/runctl/run/mocks/mocks.go"
     generate_mocks \
         "github.com/SAP/stewardci-core/pkg/runctl/metrics" \
-        "CounterMetric,PipelineRunsMetric,StateItemsMetric,ResultsMetric" \
+        "CounterMetric,PipelineRunsMetric,StateItemsMetric,ResultsMetric,OperationDurationMetric,PipelineRunsCountMetric,PipelineRunsStartMetric,OutcomesMetric" \
         "pkg/runctl/metrics/testing/mocks.go"
     generate_mocks \
         "github.com/go-logr/logr" \
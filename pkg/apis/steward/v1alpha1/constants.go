@@ -10,6 +10,38 @@ const (
 	// If this annotation is set on a secret it will be created in the run namespace
 	// with this name if it is listed in the pipelineRuns spec.secrets list.
 	AnnotationSecretRename = steward.GroupName + "/secret-rename-to"
+
+	// AnnotationSecretMaskingExcludeKeys is the key of the annotation used to
+	// exclude values of a pipeline secret from secret masking.
+	// The annotation value is a comma-separated list of keys of the secret's
+	// data whose values should not be masked in pipeline run logs.
+	AnnotationSecretMaskingExcludeKeys = steward.GroupName + "/secret-masking-exclude-keys"
+
+	// AnnotationCredentialKind is the key of the annotation denoting the kind
+	// of credentials stored in a secret, if different from what the secret
+	// type suggests.
+	// See the `CredentialKind...` constants for possible values.
+	AnnotationCredentialKind = steward.GroupName + "/credential-kind"
+
+	// AnnotationMaintenanceBypass is the key of the annotation on a pipeline
+	// run requesting to start it although maintenance mode is active.
+	// The annotation value should state the reason for the bypass.
+	// The bypass is only granted in namespaces listed in key
+	// `bypassNamespaces` of the maintenance mode config map.
+	AnnotationMaintenanceBypass = steward.GroupName + "/maintenance-bypass"
+
+	// AnnotationTraceParent is the key of the annotation on a pipeline run
+	// storing the W3C trace context "traceparent" of the trace all
+	// reconciliations of the pipeline run are recorded in.
+	// It is set by the run controller if tracing is enabled.
+	AnnotationTraceParent = steward.GroupName + "/traceparent"
+)
+
+// credential kinds
+const (
+	// CredentialKindGitHubApp denotes a secret containing the ID and private
+	// key of a GitHub App. Such secrets can be used as pipeline clone secret.
+	CredentialKindGitHubApp = "github-app"
 )
 
 // labels
@@ -34,6 +66,11 @@ const (
 	// the namespace of the Steward _pipeline run_ that the labelled object is
 	// owned by.
 	LabelOwnerPipelineRunNamespace = steward.GroupName + "/owner-pipelinerun-namespace"
+
+	// LabelMaintenanceMode is the key of the label on a namespace that puts
+	// the namespace in maintenance mode if set to "true". Pipeline runs in
+	// such namespaces are not started.
+	LabelMaintenanceMode = steward.GroupName + "/maintenance-mode"
 )
 
 // K8s events
@@ -46,6 +83,11 @@ const (
 	// faces an intermittent error during wait phase.
 	EventReasonWaitingFailed = "WaitingFailed"
 
+	// EventReasonRunPending is the reason for an event occuring when the run
+	// controller detects a problem preventing the pipeline execution from
+	// starting, like an unschedulable pod or failing image pulls.
+	EventReasonRunPending = "RunPending"
+
 	// EventReasonRunningFailed is the reason for a event occuring when the run controller
 	// faces an intermittent error during running phase.
 	EventReasonRunningFailed = "RunningFailed"
@@ -58,6 +100,19 @@ const (
 	// loading of the pipeline runs configuration fails.
 	EventReasonLoadPipelineRunsConfigFailed = "LoadPipelineRunsConfigFailed"
 
+	// EventReasonLoadMaintenanceModeConfigFailed is the reason for an event occuring when the
+	// loading of the maintenance mode configuration fails.
+	EventReasonLoadMaintenanceModeConfigFailed = "LoadMaintenanceModeConfigFailed"
+
+	// EventReasonLoadFeatureFlagsConfigFailed is the reason for an event occuring when the
+	// loading of the feature flags configuration fails.
+	EventReasonLoadFeatureFlagsConfigFailed = "LoadFeatureFlagsConfigFailed"
+
+	// EventReasonSecretsCopied is the reason for an event occuring when the run
+	// controller has copied secrets into the run namespace of a pipeline run.
+	// The event lists the copied secrets but never any secret values.
+	EventReasonSecretsCopied = "SecretsCopied"
+
 	// EventReasonMaintenanceMode is the reason for an event occuring when a pipeline
 	// run is not started due to maintenance mode
 	EventReasonMaintenanceMode = "MaintenanceMode"
@@ -67,4 +122,35 @@ const (
 
 	// MaintenanceModeKeyName is the name of the key to enable the maintenance mode
 	MaintenanceModeKeyName = "maintenanceMode"
+
+	// MaintenanceWindowsKeyName is the name of the key in the maintenance mode
+	// config map defining scheduled maintenance windows
+	MaintenanceWindowsKeyName = "windows"
+
+	// EventReasonMaintenanceAnnounced is the reason for an event occuring when a
+	// pipeline run is started shortly before a scheduled maintenance window
+	EventReasonMaintenanceAnnounced = "MaintenanceAnnounced"
+
+	// EventReasonMaintenanceAbort is the reason for an event occuring when a
+	// pipeline run is aborted because it is still active after the drain grace
+	// period of a maintenance window
+	EventReasonMaintenanceAbort = "MaintenanceAbort"
+
+	// MaintenanceNamespacesKeyName is the name of the key in the maintenance
+	// mode config map listing namespaces in maintenance mode
+	MaintenanceNamespacesKeyName = "namespaces"
+
+	// MaintenanceBypassNamespacesKeyName is the name of the key in the
+	// maintenance mode config map listing namespaces in which pipeline runs
+	// may bypass maintenance mode
+	MaintenanceBypassNamespacesKeyName = "bypassNamespaces"
+
+	// EventReasonMaintenanceBypass is the reason for an event occuring when a
+	// pipeline run is started although maintenance mode is active
+	EventReasonMaintenanceBypass = "MaintenanceBypass"
+
+	// EventReasonMaintenanceBypassDenied is the reason for an event occuring
+	// when a pipeline run requests to bypass maintenance mode but is not
+	// allowed to
+	EventReasonMaintenanceBypassDenied = "MaintenanceBypassDenied"
 )
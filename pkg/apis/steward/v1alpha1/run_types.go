@@ -3,6 +3,7 @@ package v1alpha1
 import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 // PipelineRun is a Kubernetes custom resource type representing the execution
@@ -78,6 +79,25 @@ type PipelineSpec struct {
 	Profiles *Profiles `json:"profiles,omitempty"`
 
 	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// CloudEvents configures the emission of CloudEvents for this pipeline
+	// run.
+	// +optional
+	CloudEvents *CloudEventsSpec `json:"cloudEvents,omitempty"`
+
+	// Notifications is the list of webhooks to be notified about lifecycle
+	// events of this pipeline run.
+	// +optional
+	Notifications []NotificationTarget `json:"notifications,omitempty"`
+}
+
+// CloudEventsSpec configures the emission of CloudEvents for a pipeline run.
+type CloudEventsSpec struct {
+	// SinkURL is the HTTPS URL CloudEvents about lifecycle transitions
+	// of the pipeline run are sent to.
+	// If empty, the sink configured for the Steward installation is used.
+	// +optional
+	SinkURL string `json:"sinkURL,omitempty"`
 }
 
 // JenkinsfileRunnerSpec carries configuration options for the Jenkinsfile Runner container.
@@ -94,6 +114,8 @@ type JenkinsFile struct {
 
 	// URL is the URL of the Git repository containing the pipeline definition
 	// (aka `Jenkinsfile`).
+	// Besides HTTP(S) URLs, SSH URLs (`ssh://[user@]host[:port]/path`) and
+	// SCP-style URLs (`[user@]host:path`) are supported.
 	URL string `json:"repoUrl"`
 
 	// Revision is the revision of the pipeline Git repository to be used, e.g.
@@ -107,6 +129,10 @@ type JenkinsFile struct {
 	// RepoAuthSecret is the name of the Kubernetes `v1/Secret` resource object
 	// of type `kubernetes.io/basic-auth` that contains the username and
 	// password for authentication when cloning from `spec.jenkinsFile.repoUrl`.
+	// Alternatively, the secret can contain the credentials of a GitHub App
+	// (see `CredentialKindGitHubApp`).
+	// For SSH URLs, the secret must be of type `kubernetes.io/ssh-auth` and
+	// contain the known hosts of the Git server in key `known_hosts`.
 	// +optional
 	RepoAuthSecret string `json:"repoAuthSecret,omitempty"`
 }
@@ -156,6 +182,11 @@ type PipelineStatus struct {
 	// +optional
 	FinishedAt *metav1.Time `json:"finishedAt,omitempty"`
 
+	// ExitCode is the exit code of the Jenkinsfile Runner container.
+	// It is set as soon as the container has terminated.
+	// +optional
+	ExitCode *int32 `json:"exitCode,omitempty"`
+
 	State              State                 `json:"state"`
 	StateDetails       StateItem             `json:"stateDetails"`
 	StateHistory       []StateItem           `json:"stateHistory"`
@@ -166,6 +197,31 @@ type PipelineStatus struct {
 	History            []string              `json:"history"`
 	Namespace          string                `json:"namespace"`
 	AuxiliaryNamespace string                `json:"auxiliaryNamespace"`
+
+	// CopiedSecrets is the list of secrets that have been copied into the
+	// run namespace. It serves as an audit trail of the credentials the
+	// pipeline run had access to and never contains secret values.
+	// +optional
+	CopiedSecrets []CopiedSecret `json:"copiedSecrets,omitempty"`
+
+	// LogTail is the end of the Jenkinsfile Runner log of a failed
+	// pipeline run, with the values of copied secrets redacted.
+	// It is captured before the run namespace gets deleted, if enabled
+	// in the pipeline runs configuration.
+	// +optional
+	LogTail string `json:"logTail,omitempty"`
+
+	// EffectiveConfig is the configuration relevant for this pipeline run
+	// as determined when the pipeline run has been prepared. It is used in
+	// all later phases, so that configuration changes do not affect
+	// pipeline runs already in progress.
+	// +optional
+	EffectiveConfig *EffectiveConfig `json:"effectiveConfig,omitempty"`
+
+	// Notifications records the deliveries of notifications to the
+	// webhooks specified in `spec.notifications`.
+	// +optional
+	Notifications []NotificationStatus `json:"notifications,omitempty"`
 }
 
 // StateItem holds start and end time of a state in the history
@@ -175,6 +231,153 @@ type StateItem struct {
 	FinishedAt metav1.Time `json:"finishedAt,omitempty"`
 }
 
+// CopiedSecret records a secret that has been copied from the namespace of
+// the pipeline run into the run namespace.
+// Default image pull secrets are copied from the Steward system namespace
+// instead.
+type CopiedSecret struct {
+	// Purpose is the reason why the secret has been copied.
+	Purpose SecretPurpose `json:"purpose"`
+
+	// SourceName is the name of the secret in the namespace of the pipeline
+	// run.
+	SourceName string `json:"sourceName"`
+
+	// SourceUID is the UID of the secret in the namespace of the pipeline
+	// run.
+	// +optional
+	SourceUID types.UID `json:"sourceUID,omitempty"`
+
+	// SourceResourceVersion is the resource version of the secret in the
+	// namespace of the pipeline run at the time it was copied.
+	// +optional
+	SourceResourceVersion string `json:"sourceResourceVersion,omitempty"`
+
+	// TargetName is the name of the copy in the run namespace.
+	TargetName string `json:"targetName"`
+
+	// Type is the type of the secret.
+	// +optional
+	Type corev1.SecretType `json:"type,omitempty"`
+}
+
+// EffectiveConfig is the configuration applied to a pipeline run, resolved
+// from the Steward configuration and the pipeline run spec.
+type EffectiveConfig struct {
+	// JenkinsfileRunnerImage is the Jenkinsfile Runner container image.
+	// If empty, the default image of the Tekton task is used.
+	// +optional
+	JenkinsfileRunnerImage string `json:"jenkinsfileRunnerImage,omitempty"`
+
+	// JenkinsfileRunnerImagePullPolicy is the pull policy for
+	// `JenkinsfileRunnerImage`.
+	// +optional
+	JenkinsfileRunnerImagePullPolicy string `json:"jenkinsfileRunnerImagePullPolicy,omitempty"`
+
+	// JenkinsfileRunnerPodSecurityContextRunAsUser is the numerical user id
+	// the Jenkinsfile Runner process is started as.
+	// +optional
+	JenkinsfileRunnerPodSecurityContextRunAsUser *int64 `json:"jenkinsfileRunnerPodSecurityContextRunAsUser,omitempty"`
+
+	// JenkinsfileRunnerPodSecurityContextRunAsGroup is the numerical group
+	// id the Jenkinsfile Runner process is started as.
+	// +optional
+	JenkinsfileRunnerPodSecurityContextRunAsGroup *int64 `json:"jenkinsfileRunnerPodSecurityContextRunAsGroup,omitempty"`
+
+	// JenkinsfileRunnerPodSecurityContextFSGroup is the numerical filesystem
+	// group id the Jenkinsfile Runner pod uses.
+	// +optional
+	JenkinsfileRunnerPodSecurityContextFSGroup *int64 `json:"jenkinsfileRunnerPodSecurityContextFSGroup,omitempty"`
+
+	// Timeout is the maximum execution time of the pipeline run.
+	// If not set, the default timeout of the Tekton task applies.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// TimeoutWait is the maximum time the pipeline run can stay in state
+	// waiting.
+	// If not set, the default wait timeout of the run controller applies.
+	// +optional
+	TimeoutWait *metav1.Duration `json:"timeoutWait,omitempty"`
+
+	// NetworkProfile is the name of the network profile.
+	// If empty, no network policy is applied.
+	// +optional
+	NetworkProfile string `json:"networkProfile,omitempty"`
+
+	// NetworkPolicyHash is a hash of the network policy manifest applied to
+	// the run namespace.
+	// +optional
+	NetworkPolicyHash string `json:"networkPolicyHash,omitempty"`
+
+	// LimitRangeHash is a hash of the limit range manifest applied to the
+	// run namespace.
+	// +optional
+	LimitRangeHash string `json:"limitRangeHash,omitempty"`
+
+	// ResourceQuotaHash is a hash of the resource quota manifest applied to
+	// the run namespace.
+	// +optional
+	ResourceQuotaHash string `json:"resourceQuotaHash,omitempty"`
+
+	// TektonTaskName is the name of the Tekton task running the Jenkinsfile
+	// Runner pod.
+	// +optional
+	TektonTaskName string `json:"tektonTaskName,omitempty"`
+
+	// TektonTaskNamespace is the namespace of the Tekton task running the
+	// Jenkinsfile Runner pod.
+	// +optional
+	TektonTaskNamespace string `json:"tektonTaskNamespace,omitempty"`
+
+	// CloudEventsSinkURL is the URL CloudEvents about the pipeline run are
+	// sent to if the pipeline run does not specify a sink.
+	// If empty, no CloudEvents are sent in this case.
+	// +optional
+	CloudEventsSinkURL string `json:"cloudEventsSinkURL,omitempty"`
+
+	// JenkinsfileRunnerExitCodeResults maps exit codes of the Jenkinsfile
+	// Runner (decimal strings) to pipeline run results.
+	// +optional
+	JenkinsfileRunnerExitCodeResults map[string]Result `json:"jenkinsfileRunnerExitCodeResults,omitempty"`
+
+	// FailureResults maps reasons of resource-related run failures to
+	// pipeline run results.
+	// +optional
+	FailureResults map[string]Result `json:"failureResults,omitempty"`
+
+	// LogTailLines is the maximum number of lines at the end of the
+	// Jenkinsfile Runner log captured for a failed pipeline run.
+	// If zero, no log tail is captured.
+	// +optional
+	LogTailLines int64 `json:"logTailLines,omitempty"`
+
+	// LogTailMaxBytes is the maximum size in bytes of the captured log
+	// tail.
+	// If zero, the default of the run controller applies.
+	// +optional
+	LogTailMaxBytes int64 `json:"logTailMaxBytes,omitempty"`
+}
+
+// SecretPurpose denotes why a secret has been copied into the run namespace.
+type SecretPurpose string
+
+const (
+	// SecretPurposePipelineClone - the secret is used to clone the pipeline
+	// repository (`spec.jenkinsFile.repoAuthSecret`)
+	SecretPurposePipelineClone SecretPurpose = "pipelineClone"
+	// SecretPurposeImagePull - the secret is used to pull container images
+	// (`spec.imagePullSecrets`)
+	SecretPurposeImagePull SecretPurpose = "imagePull"
+	// SecretPurposeDefaultImagePull - the secret is a default image pull
+	// secret configured by the Steward administrator, which has been copied
+	// from the Steward system namespace
+	SecretPurposeDefaultImagePull SecretPurpose = "defaultImagePull"
+	// SecretPurposePipeline - the secret is made available to the pipeline
+	// (`spec.secrets`)
+	SecretPurposePipeline SecretPurpose = "pipeline"
+)
+
 // State represents the state
 type State string
 
@@ -209,6 +412,10 @@ const (
 	ResultErrorContent Result = "error_content"
 	// ResultErrorConfig - the pipeline run failed due to a client-side configuration error
 	ResultErrorConfig Result = "error_config"
+	// ResultUnstable - the pipeline run finished, but Jenkins reported the build as unstable
+	ResultUnstable Result = "unstable"
+	// ResultErrorResources - the pipeline run failed because it ran out of resources, e.g. memory
+	ResultErrorResources Result = "error_resources"
 	// ResultAborted - the pipeline run has been aborted
 	ResultAborted Result = "aborted"
 	// ResultTimeout - the pipeline run timed out
@@ -259,3 +466,82 @@ type Profiles struct {
 	// If empty, a default profile will be used.
 	Network string `json:"network,omitempty"`
 }
+
+// NotificationTarget is a webhook to be notified about lifecycle events of
+// a pipeline run.
+type NotificationTarget struct {
+
+	// Name identifies the notification target within the pipeline run.
+	// It must be unique within `spec.notifications`.
+	Name string `json:"name"`
+
+	// URL is the HTTPS URL notifications are posted to.
+	URL string `json:"url"`
+
+	// Events is the list of events the target should be notified about.
+	// If empty, the target is notified when the pipeline run has finished.
+	// +optional
+	Events []NotificationEvent `json:"events,omitempty"`
+
+	// SigningSecret is the name of a Kubernetes `v1/Secret` resource object
+	// in the same namespace as the PipelineRun object. The value of key
+	// `key` is used to sign notifications with HMAC-SHA256.
+	// If empty, notifications are not signed.
+	// +optional
+	SigningSecret string `json:"signingSecret,omitempty"`
+}
+
+// NotificationEvent is a lifecycle event of a pipeline run notification
+// targets can be notified about.
+type NotificationEvent string
+
+const (
+	// NotificationEventStarted - the pipeline execution has started
+	NotificationEventStarted NotificationEvent = "started"
+	// NotificationEventFinished - the pipeline run has finished with any
+	// result
+	NotificationEventFinished NotificationEvent = "finished"
+	// NotificationEventFailed - the pipeline run has finished with a
+	// result other than `success`
+	NotificationEventFailed NotificationEvent = "failed"
+)
+
+// NotificationStatus records the delivery of a notification to a
+// notification target.
+type NotificationStatus struct {
+
+	// Name is the name of the notification target.
+	Name string `json:"name"`
+
+	// Event is the event the target is notified about.
+	Event NotificationEvent `json:"event"`
+
+	// State is the delivery state of the notification.
+	State NotificationState `json:"state"`
+
+	// Attempts is the number of delivery attempts made so far.
+	// +optional
+	Attempts int32 `json:"attempts,omitempty"`
+
+	// LastAttemptAt is the time of the last delivery attempt.
+	// +optional
+	LastAttemptAt *metav1.Time `json:"lastAttemptAt,omitempty"`
+
+	// Message describes the reason why the last delivery attempt failed.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// NotificationState is the delivery state of a notification.
+type NotificationState string
+
+const (
+	// NotificationStatePending - the notification has not been delivered
+	// yet and will be (re-)tried
+	NotificationStatePending NotificationState = "pending"
+	// NotificationStateDelivered - the notification has been delivered
+	NotificationStateDelivered NotificationState = "delivered"
+	// NotificationStateFailed - the delivery of the notification failed
+	// finally
+	NotificationStateFailed NotificationState = "failed"
+)
@@ -32,6 +32,93 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudEventsSpec) DeepCopyInto(out *CloudEventsSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudEventsSpec.
+func (in *CloudEventsSpec) DeepCopy() *CloudEventsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudEventsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CopiedSecret) DeepCopyInto(out *CopiedSecret) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CopiedSecret.
+func (in *CopiedSecret) DeepCopy() *CopiedSecret {
+	if in == nil {
+		return nil
+	}
+	out := new(CopiedSecret)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EffectiveConfig) DeepCopyInto(out *EffectiveConfig) {
+	*out = *in
+	if in.JenkinsfileRunnerPodSecurityContextRunAsUser != nil {
+		in, out := &in.JenkinsfileRunnerPodSecurityContextRunAsUser, &out.JenkinsfileRunnerPodSecurityContextRunAsUser
+		*out = new(int64)
+		**out = **in
+	}
+	if in.JenkinsfileRunnerPodSecurityContextRunAsGroup != nil {
+		in, out := &in.JenkinsfileRunnerPodSecurityContextRunAsGroup, &out.JenkinsfileRunnerPodSecurityContextRunAsGroup
+		*out = new(int64)
+		**out = **in
+	}
+	if in.JenkinsfileRunnerPodSecurityContextFSGroup != nil {
+		in, out := &in.JenkinsfileRunnerPodSecurityContextFSGroup, &out.JenkinsfileRunnerPodSecurityContextFSGroup
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.TimeoutWait != nil {
+		in, out := &in.TimeoutWait, &out.TimeoutWait
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.JenkinsfileRunnerExitCodeResults != nil {
+		in, out := &in.JenkinsfileRunnerExitCodeResults, &out.JenkinsfileRunnerExitCodeResults
+		*out = make(map[string]Result, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.FailureResults != nil {
+		in, out := &in.FailureResults, &out.FailureResults
+		*out = make(map[string]Result, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EffectiveConfig.
+func (in *EffectiveConfig) DeepCopy() *EffectiveConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(EffectiveConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Elasticsearch) DeepCopyInto(out *Elasticsearch) {
 	*out = *in
@@ -105,6 +192,47 @@ func (in *Logging) DeepCopy() *Logging {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotificationStatus) DeepCopyInto(out *NotificationStatus) {
+	*out = *in
+	if in.LastAttemptAt != nil {
+		in, out := &in.LastAttemptAt, &out.LastAttemptAt
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationStatus.
+func (in *NotificationStatus) DeepCopy() *NotificationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NotificationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotificationTarget) DeepCopyInto(out *NotificationTarget) {
+	*out = *in
+	if in.Events != nil {
+		in, out := &in.Events, &out.Events
+		*out = make([]NotificationEvent, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationTarget.
+func (in *NotificationTarget) DeepCopy() *NotificationTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(NotificationTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PipelineRun) DeepCopyInto(out *PipelineRun) {
 	*out = *in
@@ -228,6 +356,18 @@ func (in *PipelineSpec) DeepCopyInto(out *PipelineSpec) {
 		*out = new(v1.Duration)
 		**out = **in
 	}
+	if in.CloudEvents != nil {
+		in, out := &in.CloudEvents, &out.CloudEvents
+		*out = new(CloudEventsSpec)
+		**out = **in
+	}
+	if in.Notifications != nil {
+		in, out := &in.Notifications, &out.Notifications
+		*out = make([]NotificationTarget, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -252,6 +392,11 @@ func (in *PipelineStatus) DeepCopyInto(out *PipelineStatus) {
 		in, out := &in.FinishedAt, &out.FinishedAt
 		*out = (*in).DeepCopy()
 	}
+	if in.ExitCode != nil {
+		in, out := &in.ExitCode, &out.ExitCode
+		*out = new(int32)
+		**out = **in
+	}
 	in.StateDetails.DeepCopyInto(&out.StateDetails)
 	if in.StateHistory != nil {
 		in, out := &in.StateHistory, &out.StateHistory
@@ -266,6 +411,23 @@ func (in *PipelineStatus) DeepCopyInto(out *PipelineStatus) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.CopiedSecrets != nil {
+		in, out := &in.CopiedSecrets, &out.CopiedSecrets
+		*out = make([]CopiedSecret, len(*in))
+		copy(*out, *in)
+	}
+	if in.EffectiveConfig != nil {
+		in, out := &in.EffectiveConfig, &out.EffectiveConfig
+		*out = new(EffectiveConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Notifications != nil {
+		in, out := &in.Notifications, &out.Notifications
+		*out = make([]NotificationStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
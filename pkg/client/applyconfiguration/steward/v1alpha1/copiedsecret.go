@@ -0,0 +1,96 @@
+/*
+#########################
+#  SAP Steward-CI       #
+#########################
+
+THIS CODE IS GENERATED! DO NOT TOUCH!
+
+Copyright SAP SE.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by applyconfiguration-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	v1alpha1 "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	types "k8s.io/apimachinery/pkg/types"
+)
+
+// CopiedSecretApplyConfiguration represents an declarative configuration of the CopiedSecret type for use
+// with apply.
+type CopiedSecretApplyConfiguration struct {
+	Purpose               *v1alpha1.SecretPurpose `json:"purpose,omitempty"`
+	SourceName            *string                 `json:"sourceName,omitempty"`
+	SourceUID             *types.UID              `json:"sourceUID,omitempty"`
+	SourceResourceVersion *string                 `json:"sourceResourceVersion,omitempty"`
+	TargetName            *string                 `json:"targetName,omitempty"`
+	Type                  *v1.SecretType          `json:"type,omitempty"`
+}
+
+// CopiedSecretApplyConfiguration constructs an declarative configuration of the CopiedSecret type for use with
+// apply.
+func CopiedSecret() *CopiedSecretApplyConfiguration {
+	return &CopiedSecretApplyConfiguration{}
+}
+
+// WithPurpose sets the Purpose field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Purpose field is set to the value of the last call.
+func (b *CopiedSecretApplyConfiguration) WithPurpose(value v1alpha1.SecretPurpose) *CopiedSecretApplyConfiguration {
+	b.Purpose = &value
+	return b
+}
+
+// WithSourceName sets the SourceName field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the SourceName field is set to the value of the last call.
+func (b *CopiedSecretApplyConfiguration) WithSourceName(value string) *CopiedSecretApplyConfiguration {
+	b.SourceName = &value
+	return b
+}
+
+// WithSourceUID sets the SourceUID field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the SourceUID field is set to the value of the last call.
+func (b *CopiedSecretApplyConfiguration) WithSourceUID(value types.UID) *CopiedSecretApplyConfiguration {
+	b.SourceUID = &value
+	return b
+}
+
+// WithSourceResourceVersion sets the SourceResourceVersion field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the SourceResourceVersion field is set to the value of the last call.
+func (b *CopiedSecretApplyConfiguration) WithSourceResourceVersion(value string) *CopiedSecretApplyConfiguration {
+	b.SourceResourceVersion = &value
+	return b
+}
+
+// WithTargetName sets the TargetName field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the TargetName field is set to the value of the last call.
+func (b *CopiedSecretApplyConfiguration) WithTargetName(value string) *CopiedSecretApplyConfiguration {
+	b.TargetName = &value
+	return b
+}
+
+// WithType sets the Type field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Type field is set to the value of the last call.
+func (b *CopiedSecretApplyConfiguration) WithType(value v1.SecretType) *CopiedSecretApplyConfiguration {
+	b.Type = &value
+	return b
+}
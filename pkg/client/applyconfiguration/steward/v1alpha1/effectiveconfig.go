@@ -0,0 +1,215 @@
+/*
+#########################
+#  SAP Steward-CI       #
+#########################
+
+THIS CODE IS GENERATED! DO NOT TOUCH!
+
+Copyright SAP SE.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by applyconfiguration-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	v1alpha1 "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// EffectiveConfigApplyConfiguration represents an declarative configuration of the EffectiveConfig type for use
+// with apply.
+type EffectiveConfigApplyConfiguration struct {
+	JenkinsfileRunnerImage                        *string                    `json:"jenkinsfileRunnerImage,omitempty"`
+	JenkinsfileRunnerImagePullPolicy              *string                    `json:"jenkinsfileRunnerImagePullPolicy,omitempty"`
+	JenkinsfileRunnerPodSecurityContextRunAsUser  *int64                     `json:"jenkinsfileRunnerPodSecurityContextRunAsUser,omitempty"`
+	JenkinsfileRunnerPodSecurityContextRunAsGroup *int64                     `json:"jenkinsfileRunnerPodSecurityContextRunAsGroup,omitempty"`
+	JenkinsfileRunnerPodSecurityContextFSGroup    *int64                     `json:"jenkinsfileRunnerPodSecurityContextFSGroup,omitempty"`
+	Timeout                                       *v1.Duration               `json:"timeout,omitempty"`
+	TimeoutWait                                   *v1.Duration               `json:"timeoutWait,omitempty"`
+	NetworkProfile                                *string                    `json:"networkProfile,omitempty"`
+	NetworkPolicyHash                             *string                    `json:"networkPolicyHash,omitempty"`
+	LimitRangeHash                                *string                    `json:"limitRangeHash,omitempty"`
+	ResourceQuotaHash                             *string                    `json:"resourceQuotaHash,omitempty"`
+	TektonTaskName                                *string                    `json:"tektonTaskName,omitempty"`
+	TektonTaskNamespace                           *string                    `json:"tektonTaskNamespace,omitempty"`
+	CloudEventsSinkURL                            *string                    `json:"cloudEventsSinkURL,omitempty"`
+	JenkinsfileRunnerExitCodeResults              map[string]v1alpha1.Result `json:"jenkinsfileRunnerExitCodeResults,omitempty"`
+	FailureResults                                map[string]v1alpha1.Result `json:"failureResults,omitempty"`
+	LogTailLines                                  *int64                     `json:"logTailLines,omitempty"`
+	LogTailMaxBytes                               *int64                     `json:"logTailMaxBytes,omitempty"`
+}
+
+// EffectiveConfigApplyConfiguration constructs an declarative configuration of the EffectiveConfig type for use with
+// apply.
+func EffectiveConfig() *EffectiveConfigApplyConfiguration {
+	return &EffectiveConfigApplyConfiguration{}
+}
+
+// WithJenkinsfileRunnerImage sets the JenkinsfileRunnerImage field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the JenkinsfileRunnerImage field is set to the value of the last call.
+func (b *EffectiveConfigApplyConfiguration) WithJenkinsfileRunnerImage(value string) *EffectiveConfigApplyConfiguration {
+	b.JenkinsfileRunnerImage = &value
+	return b
+}
+
+// WithJenkinsfileRunnerImagePullPolicy sets the JenkinsfileRunnerImagePullPolicy field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the JenkinsfileRunnerImagePullPolicy field is set to the value of the last call.
+func (b *EffectiveConfigApplyConfiguration) WithJenkinsfileRunnerImagePullPolicy(value string) *EffectiveConfigApplyConfiguration {
+	b.JenkinsfileRunnerImagePullPolicy = &value
+	return b
+}
+
+// WithJenkinsfileRunnerPodSecurityContextRunAsUser sets the JenkinsfileRunnerPodSecurityContextRunAsUser field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the JenkinsfileRunnerPodSecurityContextRunAsUser field is set to the value of the last call.
+func (b *EffectiveConfigApplyConfiguration) WithJenkinsfileRunnerPodSecurityContextRunAsUser(value int64) *EffectiveConfigApplyConfiguration {
+	b.JenkinsfileRunnerPodSecurityContextRunAsUser = &value
+	return b
+}
+
+// WithJenkinsfileRunnerPodSecurityContextRunAsGroup sets the JenkinsfileRunnerPodSecurityContextRunAsGroup field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the JenkinsfileRunnerPodSecurityContextRunAsGroup field is set to the value of the last call.
+func (b *EffectiveConfigApplyConfiguration) WithJenkinsfileRunnerPodSecurityContextRunAsGroup(value int64) *EffectiveConfigApplyConfiguration {
+	b.JenkinsfileRunnerPodSecurityContextRunAsGroup = &value
+	return b
+}
+
+// WithJenkinsfileRunnerPodSecurityContextFSGroup sets the JenkinsfileRunnerPodSecurityContextFSGroup field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the JenkinsfileRunnerPodSecurityContextFSGroup field is set to the value of the last call.
+func (b *EffectiveConfigApplyConfiguration) WithJenkinsfileRunnerPodSecurityContextFSGroup(value int64) *EffectiveConfigApplyConfiguration {
+	b.JenkinsfileRunnerPodSecurityContextFSGroup = &value
+	return b
+}
+
+// WithTimeout sets the Timeout field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Timeout field is set to the value of the last call.
+func (b *EffectiveConfigApplyConfiguration) WithTimeout(value v1.Duration) *EffectiveConfigApplyConfiguration {
+	b.Timeout = &value
+	return b
+}
+
+// WithTimeoutWait sets the TimeoutWait field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the TimeoutWait field is set to the value of the last call.
+func (b *EffectiveConfigApplyConfiguration) WithTimeoutWait(value v1.Duration) *EffectiveConfigApplyConfiguration {
+	b.TimeoutWait = &value
+	return b
+}
+
+// WithNetworkProfile sets the NetworkProfile field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the NetworkProfile field is set to the value of the last call.
+func (b *EffectiveConfigApplyConfiguration) WithNetworkProfile(value string) *EffectiveConfigApplyConfiguration {
+	b.NetworkProfile = &value
+	return b
+}
+
+// WithNetworkPolicyHash sets the NetworkPolicyHash field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the NetworkPolicyHash field is set to the value of the last call.
+func (b *EffectiveConfigApplyConfiguration) WithNetworkPolicyHash(value string) *EffectiveConfigApplyConfiguration {
+	b.NetworkPolicyHash = &value
+	return b
+}
+
+// WithLimitRangeHash sets the LimitRangeHash field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the LimitRangeHash field is set to the value of the last call.
+func (b *EffectiveConfigApplyConfiguration) WithLimitRangeHash(value string) *EffectiveConfigApplyConfiguration {
+	b.LimitRangeHash = &value
+	return b
+}
+
+// WithResourceQuotaHash sets the ResourceQuotaHash field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ResourceQuotaHash field is set to the value of the last call.
+func (b *EffectiveConfigApplyConfiguration) WithResourceQuotaHash(value string) *EffectiveConfigApplyConfiguration {
+	b.ResourceQuotaHash = &value
+	return b
+}
+
+// WithTektonTaskName sets the TektonTaskName field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the TektonTaskName field is set to the value of the last call.
+func (b *EffectiveConfigApplyConfiguration) WithTektonTaskName(value string) *EffectiveConfigApplyConfiguration {
+	b.TektonTaskName = &value
+	return b
+}
+
+// WithTektonTaskNamespace sets the TektonTaskNamespace field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the TektonTaskNamespace field is set to the value of the last call.
+func (b *EffectiveConfigApplyConfiguration) WithTektonTaskNamespace(value string) *EffectiveConfigApplyConfiguration {
+	b.TektonTaskNamespace = &value
+	return b
+}
+
+// WithCloudEventsSinkURL sets the CloudEventsSinkURL field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the CloudEventsSinkURL field is set to the value of the last call.
+func (b *EffectiveConfigApplyConfiguration) WithCloudEventsSinkURL(value string) *EffectiveConfigApplyConfiguration {
+	b.CloudEventsSinkURL = &value
+	return b
+}
+
+// WithJenkinsfileRunnerExitCodeResults puts the entries into the JenkinsfileRunnerExitCodeResults field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, the entries provided by each call will be put on the JenkinsfileRunnerExitCodeResults field,
+// overwriting an existing map entries in JenkinsfileRunnerExitCodeResults field with the same key.
+func (b *EffectiveConfigApplyConfiguration) WithJenkinsfileRunnerExitCodeResults(entries map[string]v1alpha1.Result) *EffectiveConfigApplyConfiguration {
+	if b.JenkinsfileRunnerExitCodeResults == nil && len(entries) > 0 {
+		b.JenkinsfileRunnerExitCodeResults = make(map[string]v1alpha1.Result, len(entries))
+	}
+	for k, v := range entries {
+		b.JenkinsfileRunnerExitCodeResults[k] = v
+	}
+	return b
+}
+
+// WithFailureResults puts the entries into the FailureResults field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, the entries provided by each call will be put on the FailureResults field,
+// overwriting an existing map entries in FailureResults field with the same key.
+func (b *EffectiveConfigApplyConfiguration) WithFailureResults(entries map[string]v1alpha1.Result) *EffectiveConfigApplyConfiguration {
+	if b.FailureResults == nil && len(entries) > 0 {
+		b.FailureResults = make(map[string]v1alpha1.Result, len(entries))
+	}
+	for k, v := range entries {
+		b.FailureResults[k] = v
+	}
+	return b
+}
+
+// WithLogTailLines sets the LogTailLines field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the LogTailLines field is set to the value of the last call.
+func (b *EffectiveConfigApplyConfiguration) WithLogTailLines(value int64) *EffectiveConfigApplyConfiguration {
+	b.LogTailLines = &value
+	return b
+}
+
+// WithLogTailMaxBytes sets the LogTailMaxBytes field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the LogTailMaxBytes field is set to the value of the last call.
+func (b *EffectiveConfigApplyConfiguration) WithLogTailMaxBytes(value int64) *EffectiveConfigApplyConfiguration {
+	b.LogTailMaxBytes = &value
+	return b
+}
@@ -33,18 +33,20 @@ import (
 // PipelineStatusApplyConfiguration represents an declarative configuration of the PipelineStatus type for use
 // with apply.
 type PipelineStatusApplyConfiguration struct {
-	StartedAt          *v1.Time                      `json:"startedAt,omitempty"`
-	FinishedAt         *v1.Time                      `json:"finishedAt,omitempty"`
-	State              *v1alpha1.State               `json:"state,omitempty"`
-	StateDetails       *StateItemApplyConfiguration  `json:"stateDetails,omitempty"`
-	StateHistory       []StateItemApplyConfiguration `json:"stateHistory,omitempty"`
-	Result             *v1alpha1.Result              `json:"result,omitempty"`
-	Container          *corev1.ContainerState        `json:"container,omitempty"`
-	MessageShort       *string                       `json:"messageShort,omitempty"`
-	Message            *string                       `json:"message,omitempty"`
-	History            []string                      `json:"history,omitempty"`
-	Namespace          *string                       `json:"namespace,omitempty"`
-	AuxiliaryNamespace *string                       `json:"auxiliaryNamespace,omitempty"`
+	StartedAt          *v1.Time                           `json:"startedAt,omitempty"`
+	FinishedAt         *v1.Time                           `json:"finishedAt,omitempty"`
+	State              *v1alpha1.State                    `json:"state,omitempty"`
+	StateDetails       *StateItemApplyConfiguration       `json:"stateDetails,omitempty"`
+	StateHistory       []StateItemApplyConfiguration      `json:"stateHistory,omitempty"`
+	Result             *v1alpha1.Result                   `json:"result,omitempty"`
+	Container          *corev1.ContainerState             `json:"container,omitempty"`
+	MessageShort       *string                            `json:"messageShort,omitempty"`
+	Message            *string                            `json:"message,omitempty"`
+	History            []string                           `json:"history,omitempty"`
+	Namespace          *string                            `json:"namespace,omitempty"`
+	AuxiliaryNamespace *string                            `json:"auxiliaryNamespace,omitempty"`
+	CopiedSecrets      []CopiedSecretApplyConfiguration   `json:"copiedSecrets,omitempty"`
+	EffectiveConfig    *EffectiveConfigApplyConfiguration `json:"effectiveConfig,omitempty"`
 }
 
 // PipelineStatusApplyConfiguration constructs an declarative configuration of the PipelineStatus type for use with
@@ -155,3 +157,24 @@ func (b *PipelineStatusApplyConfiguration) WithAuxiliaryNamespace(value string)
 	b.AuxiliaryNamespace = &value
 	return b
 }
+
+// WithCopiedSecrets adds the given value to the CopiedSecrets field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the CopiedSecrets field.
+func (b *PipelineStatusApplyConfiguration) WithCopiedSecrets(values ...*CopiedSecretApplyConfiguration) *PipelineStatusApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithCopiedSecrets")
+		}
+		b.CopiedSecrets = append(b.CopiedSecrets, *values[i])
+	}
+	return b
+}
+
+// WithEffectiveConfig sets the EffectiveConfig field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the EffectiveConfig field is set to the value of the last call.
+func (b *PipelineStatusApplyConfiguration) WithEffectiveConfig(value *EffectiveConfigApplyConfiguration) *PipelineStatusApplyConfiguration {
+	b.EffectiveConfig = value
+	return b
+}
@@ -35,6 +35,10 @@ import (
 func ForKind(kind schema.GroupVersionKind) interface{} {
 	switch kind {
 	// Group=steward.sap.com, Version=v1alpha1
+	case v1alpha1.SchemeGroupVersion.WithKind("CopiedSecret"):
+		return &stewardv1alpha1.CopiedSecretApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("EffectiveConfig"):
+		return &stewardv1alpha1.EffectiveConfigApplyConfiguration{}
 	case v1alpha1.SchemeGroupVersion.WithKind("Elasticsearch"):
 		return &stewardv1alpha1.ElasticsearchApplyConfiguration{}
 	case v1alpha1.SchemeGroupVersion.WithKind("JenkinsFile"):
@@ -0,0 +1,107 @@
+// Package egress provides HTTP clients for requests to endpoints specified
+// by tenants, e.g. webhooks or API servers.
+//
+// As tenants control the target URLs, the clients must not be usable to
+// make the controller send requests to internal services. Therefore the
+// clients use HTTPS only and never connect to loopback, link-local or
+// private addresses outside the allowed networks. The addresses are
+// checked when connecting, after name resolution. For the same reason,
+// proxies are not used.
+package egress
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// maxRedirects is the maximum number of redirects followed.
+const maxRedirects = 10
+
+// Options are the options for creating an HTTP client.
+type Options struct {
+	// Timeout is the timeout of a request including the connection
+	// establishment.
+	Timeout time.Duration
+
+	// AllowedNetworks are the networks targets may be located in although
+	// their addresses are loopback, link-local or private addresses.
+	// Connections to such addresses are rejected otherwise.
+	AllowedNetworks []*net.IPNet
+
+	// FollowRedirects controls whether redirects to HTTPS URLs are
+	// followed. If false, the redirect response is returned.
+	FollowRedirects bool
+}
+
+// NewHTTPClient returns an HTTP client which connects to permitted
+// addresses only and never follows redirects to other schemes than HTTPS.
+func NewHTTPClient(opts Options) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: opts.Timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			return CheckAddress(address, opts.AllowedNetworks)
+		},
+	}
+	return &http.Client{
+		Timeout: opts.Timeout,
+		Transport: &http.Transport{
+			// no proxy, as the addresses connected to must be checked
+			Proxy:               nil,
+			DialContext:         dialer.DialContext,
+			ForceAttemptHTTP2:   true,
+			MaxIdleConns:        100,
+			IdleConnTimeout:     90 * time.Second,
+			TLSHandshakeTimeout: 10 * time.Second,
+		},
+		CheckRedirect: func(request *http.Request, via []*http.Request) error {
+			if !opts.FollowRedirects {
+				return http.ErrUseLastResponse
+			}
+			if err := CheckScheme(request.URL); err != nil {
+				return err
+			}
+			if len(via) >= maxRedirects {
+				return fmt.Errorf("stopped after %d redirects", maxRedirects)
+			}
+			return nil
+		},
+	}
+}
+
+// CheckAddress returns an error if the given address of a connection
+// about to be established is a loopback, link-local or private address
+// not in the given allowed networks.
+func CheckAddress(address string, allowedNetworks []*net.IPNet) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return fmt.Errorf("invalid address %q", host)
+	}
+	for _, allowed := range allowedNetworks {
+		if allowed.Contains(ip) {
+			return nil
+		}
+	}
+	if ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsPrivate() || ip.IsUnspecified() || ip.IsMulticast() {
+		return fmt.Errorf("address %s is not allowed", ip)
+	}
+	return nil
+}
+
+// CheckScheme returns an error if the given URL does not use scheme HTTPS.
+func CheckScheme(targetURL *url.URL) error {
+	if targetURL.Scheme != "https" {
+		return errors.New("URL must use scheme https")
+	}
+	return nil
+}
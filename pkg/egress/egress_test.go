@@ -0,0 +1,173 @@
+package egress
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func mustParseCIDR(t *testing.T, cidr string) *net.IPNet {
+	t.Helper()
+	_, network, err := net.ParseCIDR(cidr)
+	assert.NilError(t, err)
+	return network
+}
+
+func Test_CheckAddress(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		address       string
+		allowed       bool
+		allowedInList bool
+	}{
+		{"93.184.216.34:443", true, true},
+		{"[2606:2800:220:1:248:1893:25c8:1946]:443", true, true},
+		{"127.0.0.1:443", false, false},
+		{"[::1]:443", false, false},
+		{"[::ffff:127.0.0.1]:443", false, false},
+		{"0.0.0.0:443", false, false},
+		{"169.254.169.254:80", false, false},
+		{"[fe80::1]:443", false, false},
+		{"10.1.2.3:443", false, true},
+		{"172.16.0.1:443", false, false},
+		{"192.168.1.1:443", false, false},
+		{"[fd00::1]:443", false, false},
+	} {
+		tc := tc // capture current value before going parallel
+		t.Run(tc.address, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			allowedNetworks := []*net.IPNet{mustParseCIDR(t, "10.0.0.0/8")}
+
+			// EXERCISE
+			resultErr := CheckAddress(tc.address, nil)
+			resultErrWithAllowlist := CheckAddress(tc.address, allowedNetworks)
+
+			// VERIFY
+			assert.Equal(t, tc.allowed, resultErr == nil, "error: %v", resultErr)
+			assert.Equal(t, tc.allowedInList, resultErrWithAllowlist == nil, "error: %v", resultErrWithAllowlist)
+		})
+	}
+}
+
+func Test_CheckScheme(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		url     string
+		allowed bool
+	}{
+		{"https://host1.example.com/path1", true},
+		{"http://host1.example.com/path1", false},
+		{"ftp://host1.example.com", false},
+		{"host1.example.com", false},
+	} {
+		tc := tc // capture current value before going parallel
+		t.Run(tc.url, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			targetURL, err := url.Parse(tc.url)
+			assert.NilError(t, err)
+
+			// EXERCISE
+			resultErr := CheckScheme(targetURL)
+
+			// VERIFY
+			assert.Equal(t, tc.allowed, resultErr == nil, "error: %v", resultErr)
+		})
+	}
+}
+
+func Test_NewHTTPClient(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name               string
+		path               string
+		allowedNetworks    []string
+		followRedirects    bool
+		expectedErr        string
+		expectedStatusCode int
+	}{
+		{
+			name:        "loopback",
+			path:        "/",
+			expectedErr: "address 127.0.0.1 is not allowed",
+		},
+		{
+			name:               "loopback_allowed",
+			path:               "/",
+			allowedNetworks:    []string{"127.0.0.0/8"},
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "redirect_not_followed",
+			path:               "/redirect_https",
+			allowedNetworks:    []string{"127.0.0.0/8"},
+			expectedStatusCode: http.StatusTemporaryRedirect,
+		},
+		{
+			name:               "redirect_followed",
+			path:               "/redirect_https",
+			allowedNetworks:    []string{"127.0.0.0/8"},
+			followRedirects:    true,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:            "redirect_to_http",
+			path:            "/redirect_http",
+			allowedNetworks: []string{"127.0.0.0/8"},
+			followRedirects: true,
+			expectedErr:     "URL must use scheme https",
+		},
+	} {
+		tc := tc // capture current value before going parallel
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/redirect_https":
+					http.Redirect(w, r, "https://"+r.Host+"/", http.StatusTemporaryRedirect)
+				case "/redirect_http":
+					http.Redirect(w, r, "http://"+r.Host+"/", http.StatusTemporaryRedirect)
+				default:
+					w.WriteHeader(http.StatusOK)
+				}
+			}))
+			t.Cleanup(server.Close)
+			var allowedNetworks []*net.IPNet
+			for _, cidr := range tc.allowedNetworks {
+				allowedNetworks = append(allowedNetworks, mustParseCIDR(t, cidr))
+			}
+			examinee := NewHTTPClient(Options{
+				AllowedNetworks: allowedNetworks,
+				FollowRedirects: tc.followRedirects,
+			})
+			// trust the self-signed certificate of the local server
+			examinee.Transport.(*http.Transport).TLSClientConfig = server.Client().Transport.(*http.Transport).TLSClientConfig
+
+			// EXERCISE
+			response, resultErr := examinee.Get(server.URL + tc.path)
+
+			// VERIFY
+			if tc.expectedErr != "" {
+				assert.Assert(t, resultErr != nil)
+				assert.Assert(t, strings.Contains(resultErr.Error(), tc.expectedErr), resultErr.Error())
+				return
+			}
+			assert.NilError(t, resultErr)
+			defer response.Body.Close()
+			assert.Equal(t, tc.expectedStatusCode, response.StatusCode)
+		})
+	}
+}
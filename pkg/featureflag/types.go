@@ -29,13 +29,20 @@ The value is a string of feature flag keys separated by sequences of
 whitespace and comma.
 Each key can be prefixed with `+` (enable flag) or `-` (disable flag).
 Without prefix the flag gets enabled.
+
+At runtime, feature flags can be overridden using the same format (see
+SetOverrides). Overrides take precedence over the environment variable,
+which takes precedence over the defaults.
 */
 package featureflag
 
 import (
+	"fmt"
 	"os"
+	"reflect"
 	"regexp"
 	"sort"
+	"strings"
 	"sync"
 
 	"k8s.io/klog/v2"
@@ -52,12 +59,15 @@ func init() {
 
 var (
 	flags      = make(map[string]*FeatureFlag)
-	flagsMutex sync.Mutex
+	flagsMutex sync.RWMutex
+
+	separatorRegexp = regexp.MustCompile(`[[:space:],]+`)
 )
 
 // FeatureFlag defines a feature flag
 type FeatureFlag struct {
 	Key          string
+	override     *bool
 	enabled      *bool
 	defaultValue *bool
 }
@@ -84,6 +94,15 @@ func New(key string, defaultValue *bool) *FeatureFlag {
 
 // Enabled checks if the flag is enabled.
 func (f *FeatureFlag) Enabled() bool {
+	flagsMutex.RLock()
+	defer flagsMutex.RUnlock()
+	return f.enabledLocked()
+}
+
+func (f *FeatureFlag) enabledLocked() bool {
+	if f.override != nil {
+		return *f.override
+	}
 	if f.enabled != nil {
 		return *f.enabled
 	}
@@ -100,41 +119,115 @@ func Bool(b bool) *bool {
 
 // ParseFlags is responsible for parse out the feature flag usage.
 func ParseFlags(f string) {
+	parse(f, func(key string, enabled bool) {
+		ff := New(key, nil)
+		flagsMutex.Lock()
+		defer flagsMutex.Unlock()
+		ff.enabled = &enabled
+	})
+}
+
+// SetOverrides overrides feature flags at runtime. f has the same format
+// as the environment variable. Overrides set by previous calls are
+// replaced, i.e. flags not contained in f fall back to the value set via
+// environment variable or the default.
+// Unknown feature flags are ignored and reported as error.
+// It returns whether the value of any feature flag has changed.
+func SetOverrides(f string) (bool, error) {
+	before := Values()
+	unknown := []string{}
+
+	flagsMutex.Lock()
+	for _, ff := range flags {
+		ff.override = nil
+	}
+	parse(f, func(key string, enabled bool) {
+		ff := flags[key]
+		if ff == nil || ff.defaultValue == nil {
+			unknown = append(unknown, key)
+			return
+		}
+		ff.override = &enabled
+	})
+	flagsMutex.Unlock()
+
+	changed := !reflect.DeepEqual(before, Values())
+	if len(unknown) > 0 {
+		return changed, unknownFlagsError(unknown)
+	}
+	return changed, nil
+}
+
+// Validate returns an error if f cannot be used to override feature flags,
+// i.e. if it contains unknown feature flags.
+func Validate(f string) error {
+	unknown := []string{}
+	flagsMutex.RLock()
+	defer flagsMutex.RUnlock()
+	parse(f, func(key string, _ bool) {
+		if ff := flags[key]; ff == nil || ff.defaultValue == nil {
+			unknown = append(unknown, key)
+		}
+	})
+	if len(unknown) > 0 {
+		return unknownFlagsError(unknown)
+	}
+	return nil
+}
+
+func unknownFlagsError(keys []string) error {
+	quoted := make([]string, len(keys))
+	for i, key := range keys {
+		quoted[i] = fmt.Sprintf("%q", key)
+	}
+	return fmt.Errorf("unknown feature flags: %s", strings.Join(quoted, ", "))
+}
+
+// parse calls fn for each feature flag contained in f.
+func parse(f string, fn func(key string, enabled bool)) {
 	if f == "" {
 		return
 	}
-	for _, s := range regexp.MustCompile(`[[:space:],]+`).Split(f, -1) {
+	for _, s := range separatorRegexp.Split(f, -1) {
 		if s == "" {
 			continue
 		}
 		enabled := true
-		var ff *FeatureFlag
+		key := s
 		if s[0] == '+' || s[0] == '-' {
-			ff = New(s[1:], nil)
-			if s[0] == '-' {
-				enabled = false
-			}
-		} else {
-			ff = New(s, nil)
+			key = s[1:]
+			enabled = s[0] == '+'
 		}
-		ff.enabled = &enabled
+		fn(key, enabled)
 	}
 }
 
+// Values returns the current values of all feature flags by key.
+func Values() map[string]bool {
+	flagsMutex.RLock()
+	defer flagsMutex.RUnlock()
+
+	values := make(map[string]bool, len(flags))
+	for key, ff := range flags {
+		values[key] = ff.enabledLocked()
+	}
+	return values
+}
+
 // Log logs all feature flags using the given logger.
 func Log(logger klog.Logger) {
-	keys := make([]string, 0, len(flags))
-	for key := range flags {
+	values := Values()
+	keys := make([]string, 0, len(values))
+	for key := range values {
 		keys = append(keys, key)
 	}
 	sort.Strings(keys)
 
 	for _, key := range keys {
-		ff := flags[key]
 		logger.Info(
 			"Feature flag",
-			"key", ff.Key,
-			"enabled", ff.Enabled(),
+			"key", key,
+			"enabled", values[key],
 		)
 	}
 }
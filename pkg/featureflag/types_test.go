@@ -126,6 +126,86 @@ func Test_Log_NoFlags(t *testing.T) {
 	g.Expect(logEntries).To(HaveLen(0))
 }
 
+func Test_SetOverrides(t *testing.T) {
+	// SETUP
+	g := NewGomegaWithT(t)
+
+	origFlags := flags
+	t.Cleanup(func() { flags = origFlags })
+	flags = make(map[string]*FeatureFlag)
+
+	flagA := New("a", Bool(false))
+	flagB := New("b", Bool(false))
+	flagC := New("c", Bool(true))
+	ParseFlags("+a -c")
+
+	// EXERCISE
+	changed, err := SetOverrides("-a, +b")
+
+	// VERIFY
+	g.Expect(err).To(BeNil())
+	g.Expect(changed).To(BeTrue())
+	// override over env
+	g.Expect(flagA.Enabled()).To(BeFalse())
+	// override over default
+	g.Expect(flagB.Enabled()).To(BeTrue())
+	// env over default
+	g.Expect(flagC.Enabled()).To(BeFalse())
+
+	// EXERCISE
+	changed, err = SetOverrides("-a +b")
+
+	// VERIFY
+	g.Expect(err).To(BeNil())
+	g.Expect(changed).To(BeFalse())
+
+	// EXERCISE
+	changed, err = SetOverrides("")
+
+	// VERIFY
+	g.Expect(err).To(BeNil())
+	g.Expect(changed).To(BeTrue())
+	g.Expect(Values()).To(Equal(map[string]bool{"a": true, "b": false, "c": false}))
+}
+
+func Test_SetOverrides_UnknownFlags(t *testing.T) {
+	// SETUP
+	g := NewGomegaWithT(t)
+
+	origFlags := flags
+	t.Cleanup(func() { flags = origFlags })
+	flags = make(map[string]*FeatureFlag)
+
+	flagA := New("a", Bool(false))
+	// set via env only, no default
+	ParseFlags("x")
+
+	// EXERCISE
+	changed, err := SetOverrides("a -x unknown1")
+
+	// VERIFY
+	g.Expect(err).To(MatchError(`unknown feature flags: "x", "unknown1"`))
+	g.Expect(changed).To(BeTrue())
+	g.Expect(flagA.Enabled()).To(BeTrue())
+	g.Expect(Values()).To(Equal(map[string]bool{"a": true, "x": true}))
+}
+
+func Test_Validate(t *testing.T) {
+	// SETUP
+	g := NewGomegaWithT(t)
+
+	origFlags := flags
+	t.Cleanup(func() { flags = origFlags })
+	flags = make(map[string]*FeatureFlag)
+
+	New("a", Bool(false))
+
+	// EXERCISE and VERIFY
+	g.Expect(Validate("")).To(BeNil())
+	g.Expect(Validate("+a")).To(BeNil())
+	g.Expect(Validate("-a b")).To(MatchError(`unknown feature flags: "b"`))
+}
+
 func getTestLoggerEntries(t *testing.T, logger logr.Logger) ktesting.Log {
 	t.Helper()
 
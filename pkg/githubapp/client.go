@@ -0,0 +1,311 @@
+package githubapp
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/SAP/stewardci-core/pkg/egress"
+	"github.com/pkg/errors"
+)
+
+const (
+	// PublicAPIURL is the URL of the GitHub REST API of github.com.
+	PublicAPIURL = "https://api.github.com"
+
+	// DefaultTimeout is the default timeout of requests to the GitHub API.
+	DefaultTimeout = 30 * time.Second
+
+	publicHost = "github.com"
+
+	// headerRequestID is a header set in all responses of the GitHub API.
+	headerRequestID = "X-GitHub-Request-Id"
+
+	// maxErrorResponseSize is the maximum number of bytes of an error
+	// response read, and maxErrorMessageLength the maximum length of an
+	// error message taken from it.
+	maxErrorResponseSize  = 64 * 1024
+	maxErrorMessageLength = 200
+
+	// GitHub rejects JWTs with a lifetime of more than 10 minutes.
+	// The issue time is set into the past to allow for clock drift.
+	jwtClockDrift = 60 * time.Second
+	jwtLifetime   = 9 * time.Minute
+)
+
+// Credentials are the credentials of a GitHub App.
+type Credentials struct {
+	// AppID is the ID of the GitHub App.
+	AppID string
+
+	// PrivateKey is the PEM-encoded RSA private key of the GitHub App.
+	PrivateKey []byte
+
+	// APIURL is the base URL of the GitHub REST API, e.g.
+	// `https://api.github.com` or `https://github.example.com/api/v3`.
+	APIURL string
+}
+
+// InstallationToken is an access token of a GitHub App installation.
+type InstallationToken struct {
+	// Token is the token value.
+	Token string `json:"token"`
+
+	// ExpiresAt is the time the token expires.
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// APIError is returned if the GitHub API responds with an unexpected
+// HTTP status.
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("GitHub API responded with HTTP status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("GitHub API responded with HTTP status %d: %s", e.StatusCode, e.Message)
+}
+
+// IsClientError returns true if the given error is an APIError with an HTTP
+// status code indicating a client error (4xx), e.g. invalid credentials or an
+// App not installed for the repository owner.
+func IsClientError(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= 400 && apiErr.StatusCode < 500
+	}
+	return false
+}
+
+type invalidCredentialsError struct {
+	cause error
+}
+
+func (e *invalidCredentialsError) Error() string {
+	return e.cause.Error()
+}
+
+func (e *invalidCredentialsError) Unwrap() error {
+	return e.cause
+}
+
+// IsInvalidCredentials returns true if the given error has been caused by
+// invalid credentials detected before calling the GitHub API, e.g. a missing
+// App ID or a malformed private key.
+func IsInvalidCredentials(err error) bool {
+	var credentialsErr *invalidCredentialsError
+	return errors.As(err, &credentialsErr)
+}
+
+// DefaultAPIURL returns the URL of the GitHub REST API of the GitHub server
+// with the given scheme and host.
+func DefaultAPIURL(scheme, host string) string {
+	if strings.EqualFold(host, publicHost) {
+		return PublicAPIURL
+	}
+	return fmt.Sprintf("%s://%s/api/v3", scheme, host)
+}
+
+// Client creates GitHub App installation tokens.
+//
+// As the API URL is taken from tenant secrets or pipeline run specs, only
+// HTTPS URLs are accepted. Error messages of the API are only taken from
+// responses which are recognizable as GitHub API responses, so that
+// responses of other servers cannot be read through the pipeline run
+// status.
+type Client struct {
+	httpClient *http.Client
+	now        func() time.Time
+}
+
+// NewClient creates a new client using the given HTTP client.
+// If httpClient is nil, a client created by egress.NewHTTPClient is used,
+// which does not follow redirects and does not connect to loopback,
+// link-local or private addresses.
+func NewClient(httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = egress.NewHTTPClient(egress.Options{Timeout: DefaultTimeout})
+	}
+	return &Client{
+		httpClient: httpClient,
+		now:        time.Now,
+	}
+}
+
+// CreateInstallationToken creates an installation token for the installation
+// of the GitHub App on the owner of the given repository.
+// The token is restricted to read access to the contents of the given
+// repository.
+func (c *Client) CreateInstallationToken(ctx context.Context, credentials Credentials, owner, repo string) (*InstallationToken, error) {
+	jwt, err := c.createJWT(credentials)
+	if err != nil {
+		return nil, &invalidCredentialsError{cause: err}
+	}
+	apiURL, err := url.Parse(credentials.APIURL)
+	if err != nil {
+		return nil, &invalidCredentialsError{cause: errors.Wrapf(err, "invalid API URL of GitHub App %q", credentials.AppID)}
+	}
+	if err := egress.CheckScheme(apiURL); err != nil {
+		return nil, &invalidCredentialsError{cause: errors.Wrapf(err, "invalid API URL of GitHub App %q", credentials.AppID)}
+	}
+
+	var installation struct {
+		ID int64 `json:"id"`
+	}
+	installationURL := fmt.Sprintf("%s/repos/%s/%s/installation",
+		strings.TrimSuffix(credentials.APIURL, "/"),
+		url.PathEscape(owner), url.PathEscape(repo),
+	)
+	err = c.call(ctx, http.MethodGet, installationURL, jwt, nil, http.StatusOK, &installation)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get installation of GitHub App %q for repository %s/%s", credentials.AppID, owner, repo)
+	}
+
+	tokenRequest := map[string]interface{}{
+		"repositories": []string{repo},
+		"permissions":  map[string]string{"contents": "read"},
+	}
+	token := &InstallationToken{}
+	tokenURL := fmt.Sprintf("%s/app/installations/%d/access_tokens",
+		strings.TrimSuffix(credentials.APIURL, "/"),
+		installation.ID,
+	)
+	err = c.call(ctx, http.MethodPost, tokenURL, jwt, tokenRequest, http.StatusCreated, token)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create installation token for GitHub App %q", credentials.AppID)
+	}
+	if token.Token == "" {
+		return nil, fmt.Errorf("failed to create installation token for GitHub App %q: response does not contain a token", credentials.AppID)
+	}
+	return token, nil
+}
+
+func (c *Client) call(ctx context.Context, method, url, jwt string, requestBody interface{}, expectedStatus int, responseBody interface{}) error {
+	var body io.Reader
+	if requestBody != nil {
+		requestJSON, err := json.Marshal(requestBody)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(requestJSON)
+	}
+	request, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Accept", "application/vnd.github+json")
+	request.Header.Set("Authorization", "Bearer "+jwt)
+	if requestBody != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != expectedStatus {
+		apiErr := &APIError{StatusCode: response.StatusCode}
+		// the message is for information only and taken from GitHub
+		// API responses only
+		if response.Header.Get(headerRequestID) != "" {
+			var errorResponse struct {
+				Message string `json:"message"`
+			}
+			_ = json.NewDecoder(io.LimitReader(response.Body, maxErrorResponseSize)).Decode(&errorResponse)
+			apiErr.Message = truncate(errorResponse.Message, maxErrorMessageLength)
+		}
+		return apiErr
+	}
+	if err := json.NewDecoder(response.Body).Decode(responseBody); err != nil {
+		return errors.Wrap(err, "failed to decode response")
+	}
+	return nil
+}
+
+// truncate returns the given string shortened to at most maxLength bytes
+// at the start of a UTF-8 character.
+func truncate(s string, maxLength int) string {
+	if len(s) <= maxLength {
+		return s
+	}
+	end := maxLength
+	for end > 0 && !utf8.RuneStart(s[end]) {
+		end--
+	}
+	return s[:end]
+}
+
+// createJWT creates a JSON Web Token signed with the App's private key
+// to authenticate as the GitHub App.
+func (c *Client) createJWT(credentials Credentials) (string, error) {
+	if credentials.AppID == "" {
+		return "", errors.New("GitHub App ID is empty")
+	}
+	privateKey, err := parsePrivateKey(credentials.PrivateKey)
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid private key of GitHub App %q", credentials.AppID)
+	}
+
+	now := c.now()
+	header, err := json.Marshal(map[string]string{
+		"alg": "RS256",
+		"typ": "JWT",
+	})
+	if err != nil {
+		return "", err
+	}
+	claims, err := json.Marshal(map[string]interface{}{
+		"iat": now.Add(-jwtClockDrift).Unix(),
+		"exp": now.Add(jwtLifetime).Unix(),
+		"iss": credentials.AppID,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, privateKey, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", err
+	}
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+func parsePrivateKey(privateKeyPEM []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(privateKeyPEM)
+	if block == nil {
+		return nil, errors.New("no PEM data found")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("not an RSA private key")
+	}
+	return rsaKey, nil
+}
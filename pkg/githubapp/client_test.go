@@ -0,0 +1,320 @@
+package githubapp
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/SAP/stewardci-core/pkg/egress"
+	"github.com/SAP/stewardci-core/pkg/githubapp/fake"
+	"github.com/pkg/errors"
+	"gotest.tools/v3/assert"
+)
+
+func newFakeServer(t *testing.T) *fake.Server {
+	t.Helper()
+	server, err := fake.NewServer("1234")
+	assert.NilError(t, err)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func Test_Client_CreateInstallationToken_Success(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	server := newFakeServer(t)
+	server.AddInstallation("owner1", 42)
+	examinee := NewClient(server.Client())
+	credentials := Credentials{
+		AppID:      server.AppID,
+		PrivateKey: server.PrivateKey,
+		APIURL:     server.URL + "/",
+	}
+
+	// EXERCISE
+	token, err := examinee.CreateInstallationToken(context.Background(), credentials, "owner1", "repo1")
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Equal(t, server.Token, token.Token)
+	assert.Assert(t, !token.ExpiresAt.IsZero())
+	assert.DeepEqual(t, []fake.TokenRequest{
+		{
+			InstallationID: 42,
+			Repositories:   []string{"repo1"},
+			Permissions:    map[string]string{"contents": "read"},
+		},
+	}, server.TokenRequests())
+}
+
+func Test_Client_CreateInstallationToken_NotInstalled(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	server := newFakeServer(t)
+	server.AddInstallation("owner1", 42)
+	examinee := NewClient(server.Client())
+	credentials := Credentials{
+		AppID:      server.AppID,
+		PrivateKey: server.PrivateKey,
+		APIURL:     server.URL,
+	}
+
+	// EXERCISE
+	_, err := examinee.CreateInstallationToken(context.Background(), credentials, "owner2", "repo1")
+
+	// VERIFY
+	assert.ErrorContains(t, err, `failed to get installation of GitHub App "1234" for repository owner2/repo1: GitHub API responded with HTTP status 404: Not Found`)
+	assert.Assert(t, IsClientError(err))
+}
+
+func Test_Client_CreateInstallationToken_WrongAppID(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	server := newFakeServer(t)
+	server.AddInstallation("owner1", 42)
+	examinee := NewClient(server.Client())
+	credentials := Credentials{
+		AppID:      "4711",
+		PrivateKey: server.PrivateKey,
+		APIURL:     server.URL,
+	}
+
+	// EXERCISE
+	_, err := examinee.CreateInstallationToken(context.Background(), credentials, "owner1", "repo1")
+
+	// VERIFY
+	assert.ErrorContains(t, err, "HTTP status 401")
+	assert.Assert(t, IsClientError(err))
+}
+
+func Test_Client_CreateInstallationToken_ServerUnavailable(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	server := newFakeServer(t)
+	examinee := NewClient(server.Client())
+	credentials := Credentials{
+		AppID:      server.AppID,
+		PrivateKey: server.PrivateKey,
+		APIURL:     server.URL,
+	}
+	server.Close()
+
+	// EXERCISE
+	_, err := examinee.CreateInstallationToken(context.Background(), credentials, "owner1", "repo1")
+
+	// VERIFY
+	assert.Assert(t, err != nil)
+	assert.Assert(t, !IsClientError(err))
+	assert.Assert(t, !IsInvalidCredentials(err))
+}
+
+func Test_Client_CreateInstallationToken_InvalidPrivateKey(t *testing.T) {
+	t.Parallel()
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NilError(t, err)
+	ecKeyDER, err := x509.MarshalPKCS8PrivateKey(ecKey)
+	assert.NilError(t, err)
+
+	for _, tc := range []struct {
+		name          string
+		privateKey    []byte
+		expectedError string
+	}{
+		{"empty", nil, `invalid private key of GitHub App "1234": no PEM data found`},
+		{"no_pem", []byte("foo"), `invalid private key of GitHub App "1234": no PEM data found`},
+		{"garbage", pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: []byte("foo")}), `invalid private key of GitHub App "1234"`},
+		{"no_rsa", pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: ecKeyDER}), `invalid private key of GitHub App "1234": not an RSA private key`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			examinee := NewClient(nil)
+			credentials := Credentials{
+				AppID:      "1234",
+				PrivateKey: tc.privateKey,
+				APIURL:     "http://localhost:1",
+			}
+
+			// EXERCISE
+			_, err := examinee.CreateInstallationToken(context.Background(), credentials, "owner1", "repo1")
+
+			// VERIFY
+			assert.ErrorContains(t, err, tc.expectedError)
+			assert.Assert(t, IsInvalidCredentials(err))
+		})
+	}
+}
+
+func Test_Client_CreateInstallationToken_InvalidAPIURL(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name          string
+		apiURL        string
+		expectedError string
+	}{
+		{"http", "http://github.example.com/api/v3", `invalid API URL of GitHub App "1234": URL must use scheme https`},
+		{"no_scheme", "github.example.com/api/v3", `invalid API URL of GitHub App "1234": URL must use scheme https`},
+		{"malformed", "https://github example com", `invalid API URL of GitHub App "1234"`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			server := newFakeServer(t)
+			examinee := NewClient(server.Client())
+			credentials := Credentials{
+				AppID:      server.AppID,
+				PrivateKey: server.PrivateKey,
+				APIURL:     tc.apiURL,
+			}
+
+			// EXERCISE
+			_, err := examinee.CreateInstallationToken(context.Background(), credentials, "owner1", "repo1")
+
+			// VERIFY
+			assert.ErrorContains(t, err, tc.expectedError)
+			assert.Assert(t, IsInvalidCredentials(err))
+		})
+	}
+}
+
+func Test_Client_CreateInstallationToken_DefaultClientRejectsInternalAddress(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	server := newFakeServer(t)
+	server.AddInstallation("owner1", 42)
+	examinee := NewClient(nil)
+	credentials := Credentials{
+		AppID:      server.AppID,
+		PrivateKey: server.PrivateKey,
+		APIURL:     server.URL,
+	}
+
+	// EXERCISE
+	_, err := examinee.CreateInstallationToken(context.Background(), credentials, "owner1", "repo1")
+
+	// VERIFY
+	assert.ErrorContains(t, err, "address 127.0.0.1 is not allowed")
+	assert.Assert(t, !IsClientError(err))
+	assert.Equal(t, 0, len(server.TokenRequests()))
+}
+
+func Test_Client_CreateInstallationToken_DoesNotFollowRedirects(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	server := newFakeServer(t)
+	server.AddInstallation("owner1", 42)
+	var requests atomic.Int32
+	redirector := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		http.Redirect(w, r, server.URL+r.URL.Path, http.StatusTemporaryRedirect)
+	}))
+	t.Cleanup(redirector.Close)
+	httpClient := egress.NewHTTPClient(egress.Options{
+		AllowedNetworks: []*net.IPNet{{IP: net.IPv4(127, 0, 0, 0), Mask: net.CIDRMask(8, 32)}},
+	})
+	httpClient.Transport.(*http.Transport).TLSClientConfig = redirector.Client().Transport.(*http.Transport).TLSClientConfig
+	examinee := NewClient(httpClient)
+	credentials := Credentials{
+		AppID:      server.AppID,
+		PrivateKey: server.PrivateKey,
+		APIURL:     redirector.URL,
+	}
+
+	// EXERCISE
+	_, err := examinee.CreateInstallationToken(context.Background(), credentials, "owner1", "repo1")
+
+	// VERIFY
+	assert.ErrorContains(t, err, "GitHub API responded with HTTP status 307")
+	assert.Equal(t, int32(1), requests.Load())
+	assert.Equal(t, 0, len(server.TokenRequests()))
+}
+
+func Test_Client_CreateInstallationToken_MessageOnlyFromGitHub(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name          string
+		header        http.Header
+		message       string
+		expectedError string
+	}{
+		{
+			name:          "github",
+			header:        http.Header{"X-Github-Request-Id": []string{"id1"}},
+			message:       "Not Found",
+			expectedError: "GitHub API responded with HTTP status 404: Not Found",
+		},
+		{
+			name:          "github_long_message",
+			header:        http.Header{"X-Github-Request-Id": []string{"id1"}},
+			message:       strings.Repeat("ä", 150),
+			expectedError: "GitHub API responded with HTTP status 404: " + strings.Repeat("ä", 100),
+		},
+		{
+			name:          "other_server",
+			message:       "internal data",
+			expectedError: "GitHub API responded with HTTP status 404",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				for key, values := range tc.header {
+					w.Header()[key] = values
+				}
+				w.WriteHeader(http.StatusNotFound)
+				_ = json.NewEncoder(w).Encode(map[string]string{"message": tc.message})
+			}))
+			t.Cleanup(server.Close)
+			fakeServer := newFakeServer(t)
+			examinee := NewClient(server.Client())
+			credentials := Credentials{
+				AppID:      fakeServer.AppID,
+				PrivateKey: fakeServer.PrivateKey,
+				APIURL:     server.URL,
+			}
+
+			// EXERCISE
+			_, err := examinee.CreateInstallationToken(context.Background(), credentials, "owner1", "repo1")
+
+			// VERIFY
+			var apiErr *APIError
+			assert.Assert(t, errors.As(err, &apiErr))
+			assert.Equal(t, tc.expectedError, apiErr.Error())
+		})
+	}
+}
+
+func Test_DefaultAPIURL(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "https://api.github.com", DefaultAPIURL("https", "github.com"))
+	assert.Equal(t, "https://api.github.com", DefaultAPIURL("https", "GitHub.com"))
+	assert.Equal(t, "https://github.example.com/api/v3", DefaultAPIURL("https", "github.example.com"))
+	assert.Equal(t, "http://github.example.com:8080/api/v3", DefaultAPIURL("http", "github.example.com:8080"))
+}
@@ -0,0 +1,188 @@
+package fake
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Server is a fake GitHub API serving the endpoints required to create
+// installation tokens of a single GitHub App.
+type Server struct {
+	*httptest.Server
+
+	// AppID is the ID of the GitHub App.
+	AppID string
+
+	// PrivateKey is the PEM-encoded private key of the GitHub App.
+	PrivateKey []byte
+
+	// Token is the installation token returned by the server.
+	Token string
+
+	publicKey     *rsa.PublicKey
+	mutex         sync.Mutex
+	installations map[string]int64
+	tokenRequests []TokenRequest
+}
+
+// TokenRequest is a request for an installation token received by the
+// server.
+type TokenRequest struct {
+	InstallationID int64
+	Repositories   []string          `json:"repositories"`
+	Permissions    map[string]string `json:"permissions"`
+}
+
+// NewServer starts a new fake GitHub API for a GitHub App with the given ID
+// and a newly generated private key.
+// The server uses HTTPS with a self-signed certificate trusted by the
+// client returned by `Client`.
+// The caller must call `Close` when done.
+func NewServer(appID string) (*Server, error) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, err
+	}
+	s := &Server{
+		AppID: appID,
+		PrivateKey: pem.EncodeToMemory(&pem.Block{
+			Type:  "RSA PRIVATE KEY",
+			Bytes: x509.MarshalPKCS1PrivateKey(privateKey),
+		}),
+		Token:         "fake-installation-token",
+		publicKey:     &privateKey.PublicKey,
+		installations: map[string]int64{},
+	}
+	s.Server = httptest.NewTLSServer(http.HandlerFunc(s.handle))
+	return s, nil
+}
+
+// AddInstallation installs the GitHub App on the given owner (user or
+// organization).
+func (s *Server) AddInstallation(owner string, installationID int64) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.installations[owner] = installationID
+}
+
+// TokenRequests returns the token requests received so far.
+func (s *Server) TokenRequests() []TokenRequest {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]TokenRequest{}, s.tokenRequests...)
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	if err := s.verifyJWT(r); err != nil {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"message": err.Error()})
+		return
+	}
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	switch {
+	case r.Method == http.MethodGet && len(segments) == 4 && segments[0] == "repos" && segments[3] == "installation":
+		s.handleGetInstallation(w, segments[1])
+	case r.Method == http.MethodPost && len(segments) == 4 && segments[0] == "app" && segments[1] == "installations" && segments[3] == "access_tokens":
+		s.handleCreateToken(w, r, segments[2])
+	default:
+		writeJSON(w, http.StatusNotFound, map[string]string{"message": "Not Found"})
+	}
+}
+
+func (s *Server) handleGetInstallation(w http.ResponseWriter, owner string) {
+	s.mutex.Lock()
+	installationID, found := s.installations[owner]
+	s.mutex.Unlock()
+	if !found {
+		writeJSON(w, http.StatusNotFound, map[string]string{"message": "Not Found"})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]int64{"id": installationID})
+}
+
+func (s *Server) handleCreateToken(w http.ResponseWriter, r *http.Request, installationIDString string) {
+	installationID, err := strconv.ParseInt(installationIDString, 10, 64)
+	if err != nil || !s.isInstallation(installationID) {
+		writeJSON(w, http.StatusNotFound, map[string]string{"message": "Not Found"})
+		return
+	}
+	tokenRequest := TokenRequest{InstallationID: installationID}
+	if err := json.NewDecoder(r.Body).Decode(&tokenRequest); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"message": "Problems parsing JSON"})
+		return
+	}
+	s.mutex.Lock()
+	s.tokenRequests = append(s.tokenRequests, tokenRequest)
+	s.mutex.Unlock()
+	writeJSON(w, http.StatusCreated, map[string]interface{}{
+		"token":      s.Token,
+		"expires_at": time.Now().Add(time.Hour).UTC().Format(time.RFC3339),
+	})
+}
+
+func (s *Server) isInstallation(installationID int64) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for _, id := range s.installations {
+		if id == installationID {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) verifyJWT(r *http.Request) error {
+	jwt, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !found {
+		return fmt.Errorf("missing bearer token")
+	}
+	parts := strings.Split(jwt, ".")
+	if len(parts) != 3 {
+		return fmt.Errorf("malformed JSON web token")
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return fmt.Errorf("malformed JSON web token signature")
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(s.publicKey, crypto.SHA256, digest[:], signature); err != nil {
+		return fmt.Errorf("invalid JSON web token signature")
+	}
+	claimsJSON, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return fmt.Errorf("malformed JSON web token claims")
+	}
+	var claims struct {
+		Issuer    string `json:"iss"`
+		ExpiresAt int64  `json:"exp"`
+	}
+	if err := json.Unmarshal(claimsJSON, &claims); err != nil {
+		return fmt.Errorf("malformed JSON web token claims")
+	}
+	if claims.Issuer != s.AppID {
+		return fmt.Errorf("unexpected issuer %q", claims.Issuer)
+	}
+	if time.Unix(claims.ExpiresAt, 0).Before(time.Now()) {
+		return fmt.Errorf("JSON web token expired")
+	}
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-GitHub-Request-Id", "0000:1111:2222:3333:4444")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -11,7 +11,9 @@ import (
 	tektoninformers "github.com/SAP/stewardci-core/pkg/tektonclient/informers/externalversions"
 	"github.com/go-logr/logr"
 	dynamic "k8s.io/client-go/dynamic"
+	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
+	coordinationv1client "k8s.io/client-go/kubernetes/typed/coordination/v1"
 	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
 	networkingv1client "k8s.io/client-go/kubernetes/typed/networking/v1"
 	rbacv1client "k8s.io/client-go/kubernetes/typed/rbac/v1"
@@ -23,6 +25,13 @@ type ClientFactory interface {
 	// CoreV1 returns the core/v1 Kubernetes client
 	CoreV1() corev1client.CoreV1Interface
 
+	// KubernetesInformerFactory returns the informer factory for
+	// Kubernetes core resources
+	KubernetesInformerFactory() informers.SharedInformerFactory
+
+	// CoordinationV1 returns the coordination.k8s.io/v1 Kubernetes client
+	CoordinationV1() coordinationv1client.CoordinationV1Interface
+
 	// NetworkingV1 returns the networking/v1 Kubernetes client
 	NetworkingV1() networkingv1client.NetworkingV1Interface
 
@@ -46,12 +55,13 @@ type ClientFactory interface {
 }
 
 type clientFactory struct {
-	kubernetesClientset    *kubernetes.Clientset
-	dynamicClient          dynamic.Interface
-	stewardClientset       *stewardclients.Clientset
-	stewardInformerFactory stewardinformers.SharedInformerFactory
-	tektonClientset        *tektonclients.Clientset
-	tektonInformerFactory  tektoninformers.SharedInformerFactory
+	kubernetesClientset       *kubernetes.Clientset
+	kubernetesInformerFactory informers.SharedInformerFactory
+	dynamicClient             dynamic.Interface
+	stewardClientset          *stewardclients.Clientset
+	stewardInformerFactory    stewardinformers.SharedInformerFactory
+	tektonClientset           *tektonclients.Clientset
+	tektonInformerFactory     tektoninformers.SharedInformerFactory
 }
 
 // NewClientFactory creates new client factory based on rest config
@@ -68,6 +78,7 @@ func NewClientFactory(logger logr.Logger, config *rest.Config, resyncPeriod time
 		logger.Error(err, "Failed to create Kubernetes clientset")
 		return nil
 	}
+	kubernetesInformerFactory := informers.NewSharedInformerFactory(kubernetesClientset, resyncPeriod)
 
 	dynamicClient, err := dynamic.NewForConfig(config)
 	if err != nil {
@@ -83,12 +94,13 @@ func NewClientFactory(logger logr.Logger, config *rest.Config, resyncPeriod time
 	tektonInformerFactory := tektoninformers.NewSharedInformerFactory(tektonClientset, resyncPeriod)
 
 	return &clientFactory{
-		kubernetesClientset:    kubernetesClientset,
-		dynamicClient:          dynamicClient,
-		stewardClientset:       stewardClientset,
-		stewardInformerFactory: stewardInformerFactory,
-		tektonClientset:        tektonClientset,
-		tektonInformerFactory:  tektonInformerFactory,
+		kubernetesClientset:       kubernetesClientset,
+		kubernetesInformerFactory: kubernetesInformerFactory,
+		dynamicClient:             dynamicClient,
+		stewardClientset:          stewardClientset,
+		stewardInformerFactory:    stewardInformerFactory,
+		tektonClientset:           tektonClientset,
+		tektonInformerFactory:     tektonInformerFactory,
 	}
 }
 
@@ -107,6 +119,16 @@ func (f *clientFactory) CoreV1() corev1client.CoreV1Interface {
 	return f.kubernetesClientset.CoreV1()
 }
 
+// KubernetesInformerFactory implements interface ClientFactory
+func (f *clientFactory) KubernetesInformerFactory() informers.SharedInformerFactory {
+	return f.kubernetesInformerFactory
+}
+
+// CoordinationV1 implements interface ClientFactory
+func (f *clientFactory) CoordinationV1() coordinationv1client.CoordinationV1Interface {
+	return f.kubernetesClientset.CoordinationV1()
+}
+
 // Dynamic implements interface ClientFactory
 func (f *clientFactory) Dynamic() dynamic.Interface {
 	return f.dynamicClient
@@ -17,7 +17,9 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	dynamic "k8s.io/client-go/dynamic"
 	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/informers"
 	k8sclientfake "k8s.io/client-go/kubernetes/fake"
+	coordinationv1client "k8s.io/client-go/kubernetes/typed/coordination/v1"
 	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
 	networkingv1client "k8s.io/client-go/kubernetes/typed/networking/v1"
 	rbacv1client "k8s.io/client-go/kubernetes/typed/rbac/v1"
@@ -26,13 +28,14 @@ import (
 
 // ClientFactory is a factory for fake clients.
 type ClientFactory struct {
-	kubernetesClientset    *k8sclientfake.Clientset
-	DynamicClient          *dynamicfake.FakeDynamicClient
-	stewardClientset       *stewardclientfake.Clientset
-	stewardInformerFactory stewardinformer.SharedInformerFactory
-	tektonClientset        *tektonclientfake.Clientset
-	tektonInformerFactory  tektoninformers.SharedInformerFactory
-	sleepDuration          time.Duration
+	kubernetesClientset       *k8sclientfake.Clientset
+	kubernetesInformerFactory informers.SharedInformerFactory
+	DynamicClient             *dynamicfake.FakeDynamicClient
+	stewardClientset          *stewardclientfake.Clientset
+	stewardInformerFactory    stewardinformer.SharedInformerFactory
+	tektonClientset           *tektonclientfake.Clientset
+	tektonInformerFactory     tektoninformers.SharedInformerFactory
+	sleepDuration             time.Duration
 
 	// logger *must* be initialized when creating a ClientFactory instance,
 	// otherwise logging functions will access a nil sink and panic.
@@ -46,17 +49,20 @@ func NewClientFactory(objects ...runtime.Object) *ClientFactory {
 	stewardInformerFactory := stewardinformer.NewSharedInformerFactory(stewardClientset, 10*time.Minute)
 	tektonClientset := tektonclientfake.NewSimpleClientset(tektonObjects...)
 	tektonInformerFactory := tektoninformers.NewSharedInformerFactory(tektonClientset, 10*time.Minute)
+	kubernetesClientset := k8sclientfake.NewSimpleClientset(kubernetesObjects...)
+	kubernetesInformerFactory := informers.NewSharedInformerFactory(kubernetesClientset, 10*time.Minute)
 	logger := klog.FromContext(context.Background())
 
 	return &ClientFactory{
-		kubernetesClientset:    k8sclientfake.NewSimpleClientset(kubernetesObjects...),
-		DynamicClient:          dynamicfake.NewSimpleDynamicClient(runtime.NewScheme()),
-		stewardClientset:       stewardClientset,
-		stewardInformerFactory: stewardInformerFactory,
-		tektonClientset:        tektonClientset,
-		tektonInformerFactory:  tektonInformerFactory,
-		sleepDuration:          300 * time.Millisecond,
-		logger:                 logger,
+		kubernetesClientset:       kubernetesClientset,
+		kubernetesInformerFactory: kubernetesInformerFactory,
+		DynamicClient:             dynamicfake.NewSimpleDynamicClient(runtime.NewScheme()),
+		stewardClientset:          stewardClientset,
+		stewardInformerFactory:    stewardInformerFactory,
+		tektonClientset:           tektonClientset,
+		tektonInformerFactory:     tektonInformerFactory,
+		sleepDuration:             300 * time.Millisecond,
+		logger:                    logger,
 	}
 }
 
@@ -101,11 +107,21 @@ func (f *ClientFactory) KubernetesClientset() *k8sclientfake.Clientset {
 	return f.kubernetesClientset
 }
 
+// KubernetesInformerFactory implements interface "github.com/SAP/stewardci-core/pkg/k8s".ClientFactory
+func (f *ClientFactory) KubernetesInformerFactory() informers.SharedInformerFactory {
+	return f.kubernetesInformerFactory
+}
+
 // CoreV1 implements interface "github.com/SAP/stewardci-core/pkg/k8s".ClientFactory
 func (f *ClientFactory) CoreV1() corev1client.CoreV1Interface {
 	return f.kubernetesClientset.CoreV1()
 }
 
+// CoordinationV1 implements interface "github.com/SAP/stewardci-core/pkg/k8s".ClientFactory
+func (f *ClientFactory) CoordinationV1() coordinationv1client.CoordinationV1Interface {
+	return f.kubernetesClientset.CoordinationV1()
+}
+
 // Dynamic implements interface "github.com/SAP/stewardci-core/pkg/k8s".ClientFactory
 func (f *ClientFactory) Dynamic() dynamic.Interface {
 	return f.DynamicClient
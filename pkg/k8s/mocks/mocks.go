@@ -40,9 +40,11 @@ import (
 	v1 "k8s.io/api/core/v1"
 	v10 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	dynamic "k8s.io/client-go/dynamic"
-	v11 "k8s.io/client-go/kubernetes/typed/core/v1"
-	v12 "k8s.io/client-go/kubernetes/typed/networking/v1"
-	v13 "k8s.io/client-go/kubernetes/typed/rbac/v1"
+	informers "k8s.io/client-go/informers"
+	v11 "k8s.io/client-go/kubernetes/typed/coordination/v1"
+	v12 "k8s.io/client-go/kubernetes/typed/core/v1"
+	v13 "k8s.io/client-go/kubernetes/typed/networking/v1"
+	v14 "k8s.io/client-go/kubernetes/typed/rbac/v1"
 )
 
 // MockClientFactory is a mock of ClientFactory interface.
@@ -68,11 +70,25 @@ func (m *MockClientFactory) EXPECT() *MockClientFactoryMockRecorder {
 	return m.recorder
 }
 
+// CoordinationV1 mocks base method.
+func (m *MockClientFactory) CoordinationV1() v11.CoordinationV1Interface {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CoordinationV1")
+	ret0, _ := ret[0].(v11.CoordinationV1Interface)
+	return ret0
+}
+
+// CoordinationV1 indicates an expected call of CoordinationV1.
+func (mr *MockClientFactoryMockRecorder) CoordinationV1() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CoordinationV1", reflect.TypeOf((*MockClientFactory)(nil).CoordinationV1))
+}
+
 // CoreV1 mocks base method.
-func (m *MockClientFactory) CoreV1() v11.CoreV1Interface {
+func (m *MockClientFactory) CoreV1() v12.CoreV1Interface {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "CoreV1")
-	ret0, _ := ret[0].(v11.CoreV1Interface)
+	ret0, _ := ret[0].(v12.CoreV1Interface)
 	return ret0
 }
 
@@ -96,11 +112,25 @@ func (mr *MockClientFactoryMockRecorder) Dynamic() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Dynamic", reflect.TypeOf((*MockClientFactory)(nil).Dynamic))
 }
 
+// KubernetesInformerFactory mocks base method.
+func (m *MockClientFactory) KubernetesInformerFactory() informers.SharedInformerFactory {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "KubernetesInformerFactory")
+	ret0, _ := ret[0].(informers.SharedInformerFactory)
+	return ret0
+}
+
+// KubernetesInformerFactory indicates an expected call of KubernetesInformerFactory.
+func (mr *MockClientFactoryMockRecorder) KubernetesInformerFactory() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "KubernetesInformerFactory", reflect.TypeOf((*MockClientFactory)(nil).KubernetesInformerFactory))
+}
+
 // NetworkingV1 mocks base method.
-func (m *MockClientFactory) NetworkingV1() v12.NetworkingV1Interface {
+func (m *MockClientFactory) NetworkingV1() v13.NetworkingV1Interface {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "NetworkingV1")
-	ret0, _ := ret[0].(v12.NetworkingV1Interface)
+	ret0, _ := ret[0].(v13.NetworkingV1Interface)
 	return ret0
 }
 
@@ -111,10 +141,10 @@ func (mr *MockClientFactoryMockRecorder) NetworkingV1() *gomock.Call {
 }
 
 // RbacV1 mocks base method.
-func (m *MockClientFactory) RbacV1() v13.RbacV1Interface {
+func (m *MockClientFactory) RbacV1() v14.RbacV1Interface {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "RbacV1")
-	ret0, _ := ret[0].(v13.RbacV1Interface)
+	ret0, _ := ret[0].(v14.RbacV1Interface)
 	return ret0
 }
 
@@ -203,6 +233,20 @@ func (m *MockPipelineRun) EXPECT() *MockPipelineRunMockRecorder {
 	return m.recorder
 }
 
+// AddAnnotationAndCommitIfNotPresent mocks base method.
+func (m *MockPipelineRun) AddAnnotationAndCommitIfNotPresent(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddAnnotationAndCommitIfNotPresent", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddAnnotationAndCommitIfNotPresent indicates an expected call of AddAnnotationAndCommitIfNotPresent.
+func (mr *MockPipelineRunMockRecorder) AddAnnotationAndCommitIfNotPresent(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAnnotationAndCommitIfNotPresent", reflect.TypeOf((*MockPipelineRun)(nil).AddAnnotationAndCommitIfNotPresent), arg0, arg1, arg2)
+}
+
 // AddFinalizerAndCommitIfNotPresent mocks base method.
 func (m *MockPipelineRun) AddFinalizerAndCommitIfNotPresent(arg0 context.Context) error {
 	m.ctrl.T.Helper()
@@ -217,6 +261,18 @@ func (mr *MockPipelineRunMockRecorder) AddFinalizerAndCommitIfNotPresent(arg0 in
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddFinalizerAndCommitIfNotPresent", reflect.TypeOf((*MockPipelineRun)(nil).AddFinalizerAndCommitIfNotPresent), arg0)
 }
 
+// AddNotifications mocks base method.
+func (m *MockPipelineRun) AddNotifications(arg0 []v1alpha1.NotificationStatus) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "AddNotifications", arg0)
+}
+
+// AddNotifications indicates an expected call of AddNotifications.
+func (mr *MockPipelineRunMockRecorder) AddNotifications(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddNotifications", reflect.TypeOf((*MockPipelineRun)(nil).AddNotifications), arg0)
+}
+
 // CommitStatus mocks base method.
 func (m *MockPipelineRun) CommitStatus(arg0 context.Context) ([]*v1alpha1.StateItem, error) {
 	m.ctrl.T.Helper()
@@ -481,6 +537,42 @@ func (mr *MockPipelineRunMockRecorder) UpdateContainer(arg0, arg1 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateContainer", reflect.TypeOf((*MockPipelineRun)(nil).UpdateContainer), arg0, arg1)
 }
 
+// UpdateCopiedSecrets mocks base method.
+func (m *MockPipelineRun) UpdateCopiedSecrets(arg0 []v1alpha1.CopiedSecret) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UpdateCopiedSecrets", arg0)
+}
+
+// UpdateCopiedSecrets indicates an expected call of UpdateCopiedSecrets.
+func (mr *MockPipelineRunMockRecorder) UpdateCopiedSecrets(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCopiedSecrets", reflect.TypeOf((*MockPipelineRun)(nil).UpdateCopiedSecrets), arg0)
+}
+
+// UpdateEffectiveConfig mocks base method.
+func (m *MockPipelineRun) UpdateEffectiveConfig(arg0 *v1alpha1.EffectiveConfig) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UpdateEffectiveConfig", arg0)
+}
+
+// UpdateEffectiveConfig indicates an expected call of UpdateEffectiveConfig.
+func (mr *MockPipelineRunMockRecorder) UpdateEffectiveConfig(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEffectiveConfig", reflect.TypeOf((*MockPipelineRun)(nil).UpdateEffectiveConfig), arg0)
+}
+
+// UpdateLogTail mocks base method.
+func (m *MockPipelineRun) UpdateLogTail(arg0 string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "UpdateLogTail", arg0)
+}
+
+// UpdateLogTail indicates an expected call of UpdateLogTail.
+func (mr *MockPipelineRunMockRecorder) UpdateLogTail(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLogTail", reflect.TypeOf((*MockPipelineRun)(nil).UpdateLogTail), arg0)
+}
+
 // UpdateMessage mocks base method.
 func (m *MockPipelineRun) UpdateMessage(arg0 string) {
 	m.ctrl.T.Helper()
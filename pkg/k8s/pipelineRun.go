@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"regexp"
+	"strings"
 	"time"
 
 	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
@@ -67,6 +69,8 @@ type PipelineRun interface {
 
 	// GetValidatedJenkinsfileRepoServerURL validates the Jenkinsfile
 	// Git repository URL and returns the URL without path.
+	// SSH URLs, including SCP-style URLs like `git@host:org/repo.git`,
+	// are returned as `ssh://<host>[:<port>]`.
 	GetValidatedJenkinsfileRepoServerURL() (string, error)
 
 	// HasDeletionTimestamp return whether the underlying PipelineRun API
@@ -84,6 +88,14 @@ type PipelineRun interface {
 	// There must not be any other pending changes.
 	AddFinalizerAndCommitIfNotPresent(ctx context.Context) error
 
+	// AddAnnotationAndCommitIfNotPresent sets the annotation with the given
+	// key to the given value at the underlying PipelineRun API object if
+	// the annotation is not present already. The change is immediately
+	// committed. If the commit fails, the annotation is removed again.
+	//
+	// There must not be any other pending changes.
+	AddAnnotationAndCommitIfNotPresent(ctx context.Context, key, value string) error
+
 	// CommitStatus writes the status of the underlying PipelineRun object to
 	// storage.
 	//
@@ -129,6 +141,7 @@ type PipelineRun interface {
 	UpdateResult(ctx context.Context, result api.Result, finishedAt metav1.Time)
 
 	// UpdateContainer updates the container info in the status.
+	// If the container has terminated, its exit code is recorded as well.
 	UpdateContainer(ctx context.Context, newContainerState *corev1.ContainerState)
 
 	// StoreErrorAsMessage stores err with prefix as message in the status.
@@ -144,6 +157,22 @@ type PipelineRun interface {
 
 	// UpdateMessage sets msg as message in the status.
 	UpdateMessage(msg string)
+
+	// UpdateCopiedSecrets sets the list of secrets copied into the run
+	// namespace in the status.
+	UpdateCopiedSecrets(copiedSecrets []api.CopiedSecret)
+
+	// UpdateLogTail sets the log tail in the status.
+	UpdateLogTail(logTail string)
+
+	// UpdateEffectiveConfig sets the effective configuration of the
+	// pipeline run in the status.
+	UpdateEffectiveConfig(effectiveConfig *api.EffectiveConfig)
+
+	// AddNotifications adds the given notification delivery records to
+	// the status. Records for a target and event already recorded are
+	// skipped.
+	AddNotifications(notifications []api.NotificationStatus)
 }
 
 // pipelineRun is the (only) implementation of interface PipelineRun.
@@ -238,16 +267,72 @@ func (r *pipelineRun) GetNamespace() string {
 // GetValidatedJenkinsfileRepoServerURL implements part of interface `PipelineRun`.
 func (r *pipelineRun) GetValidatedJenkinsfileRepoServerURL() (string, error) {
 	urlString := r.GetSpec().JenkinsFile.URL
+	if isSCPStyleGitURL(urlString) {
+		host, err := parseSCPStyleGitURL(urlString)
+		if err != nil {
+			return "", errors.Wrapf(err, "value %q of field spec.jenkinsFile.url is invalid [%s]", urlString, r.String())
+		}
+		return fmt.Sprintf("ssh://%s", host), nil
+	}
 	repoURL, err := url.Parse(urlString)
 	if err != nil {
 		return "", errors.Wrapf(err, "value %q of field spec.jenkinsFile.url is invalid [%s]", urlString, r.String())
 	}
-	if repoURL.Scheme != "http" && repoURL.Scheme != "https" {
+	switch repoURL.Scheme {
+	case "http", "https":
+	case "ssh":
+		if repoURL.Hostname() == "" || strings.Trim(repoURL.Path, "/") == "" {
+			return "", fmt.Errorf("value %q of field spec.jenkinsFile.url is invalid [%s]: malformed SSH URL: host and path required", urlString, r.String())
+		}
+	default:
 		return "", fmt.Errorf("value %q of field spec.jenkinsFile.url is invalid [%s]: scheme not supported: %q", urlString, r.String(), repoURL.Scheme)
 	}
 	return fmt.Sprintf("%s://%s", repoURL.Scheme, repoURL.Host), nil
 }
 
+// scpStyleGitURLHostPattern matches valid host names and bracketed
+// IPv6 addresses.
+var scpStyleGitURLHostPattern = regexp.MustCompile(`^([A-Za-z0-9]([A-Za-z0-9.-]*[A-Za-z0-9])?|\[[0-9A-Fa-f:.]+\])$`)
+
+// isSCPStyleGitURL returns whether the given Git repository URL is
+// an SCP-style SSH URL like `[user@]host:path`.
+// Like Git, a string is considered as SCP-style URL if it does not
+// contain `://` and has a colon before the first slash.
+func isSCPStyleGitURL(urlString string) bool {
+	if strings.Contains(urlString, "://") {
+		return false
+	}
+	colon := strings.Index(urlString, ":")
+	slash := strings.Index(urlString, "/")
+	return colon >= 0 && (slash < 0 || colon < slash)
+}
+
+// parseSCPStyleGitURL parses an SCP-style SSH URL `[user@]host:path`
+// and returns the host.
+func parseSCPStyleGitURL(urlString string) (string, error) {
+	userAndHost, path := urlString, ""
+	if end := strings.Index(urlString, "]:"); end >= 0 {
+		// bracketed IPv6 address
+		userAndHost, path = urlString[:end+1], urlString[end+2:]
+	} else if i := strings.Index(urlString, ":"); i >= 0 {
+		userAndHost, path = urlString[:i], urlString[i+1:]
+	}
+	host := userAndHost
+	if i := strings.LastIndex(userAndHost, "@"); i >= 0 {
+		if i == 0 {
+			return "", errors.New("malformed SSH URL: empty user")
+		}
+		host = userAndHost[i+1:]
+	}
+	if !scpStyleGitURLHostPattern.MatchString(host) {
+		return "", fmt.Errorf("malformed SSH URL: invalid host %q", host)
+	}
+	if strings.Trim(path, "/") == "" {
+		return "", errors.New("malformed SSH URL: path required")
+	}
+	return host, nil
+}
+
 // GetName implements part of interface `PipelineRun`.
 func (r *pipelineRun) GetName() string {
 	return r.apiObj.GetName()
@@ -344,6 +429,10 @@ func (r *pipelineRun) UpdateContainer(ctx context.Context, newContainerState *co
 	r.ensureCopy()
 	r.mustChangeStatusAndStoreForRetry(func(s *api.PipelineStatus) (commitRecorderFunc, error) {
 		s.Container = *newContainerState
+		if terminated := newContainerState.Terminated; terminated != nil {
+			exitCode := terminated.ExitCode
+			s.ExitCode = &exitCode
+		}
 		return nil, nil
 	})
 }
@@ -392,6 +481,53 @@ func (r *pipelineRun) UpdateAuxNamespace(ns string) {
 	})
 }
 
+// UpdateCopiedSecrets implements part of interface `PipelineRun`.
+func (r *pipelineRun) UpdateCopiedSecrets(copiedSecrets []api.CopiedSecret) {
+	r.ensureCopy()
+	r.mustChangeStatusAndStoreForRetry(func(s *api.PipelineStatus) (commitRecorderFunc, error) {
+		s.CopiedSecrets = append([]api.CopiedSecret(nil), copiedSecrets...)
+		return nil, nil
+	})
+}
+
+// UpdateLogTail implements part of interface `PipelineRun`.
+func (r *pipelineRun) UpdateLogTail(logTail string) {
+	r.ensureCopy()
+	r.mustChangeStatusAndStoreForRetry(func(s *api.PipelineStatus) (commitRecorderFunc, error) {
+		s.LogTail = logTail
+		return nil, nil
+	})
+}
+
+// UpdateEffectiveConfig implements part of interface `PipelineRun`.
+func (r *pipelineRun) UpdateEffectiveConfig(effectiveConfig *api.EffectiveConfig) {
+	r.ensureCopy()
+	r.mustChangeStatusAndStoreForRetry(func(s *api.PipelineStatus) (commitRecorderFunc, error) {
+		s.EffectiveConfig = effectiveConfig.DeepCopy()
+		return nil, nil
+	})
+}
+
+// AddNotifications implements part of interface `PipelineRun`.
+func (r *pipelineRun) AddNotifications(notifications []api.NotificationStatus) {
+	r.ensureCopy()
+	r.mustChangeStatusAndStoreForRetry(func(s *api.PipelineStatus) (commitRecorderFunc, error) {
+		for _, notification := range notifications {
+			found := false
+			for _, existing := range s.Notifications {
+				if existing.Name == notification.Name && existing.Event == notification.Event {
+					found = true
+					break
+				}
+			}
+			if !found {
+				s.Notifications = append(s.Notifications, *notification.DeepCopy())
+			}
+		}
+		return nil, nil
+	})
+}
+
 // HasDeletionTimestamp implements part of interface `PipelineRun`.
 func (r *pipelineRun) HasDeletionTimestamp() bool {
 	return !r.apiObj.ObjectMeta.DeletionTimestamp.IsZero()
@@ -415,6 +551,33 @@ func (r *pipelineRun) AddFinalizerAndCommitIfNotPresent(ctx context.Context) err
 	return nil
 }
 
+// AddAnnotationAndCommitIfNotPresent implements part of interface `PipelineRun`.
+func (r *pipelineRun) AddAnnotationAndCommitIfNotPresent(ctx context.Context, key, value string) error {
+	logger := klog.FromContext(ctx)
+
+	r.mustBeChangeable()
+	r.mustNotHavePendingChanges()
+
+	if _, found := r.apiObj.ObjectMeta.Annotations[key]; found {
+		return nil
+	}
+
+	r.ensureCopy()
+	start := time.Now()
+	metav1.SetMetaDataAnnotation(&r.apiObj.ObjectMeta, key, value)
+	result, err := r.client.Update(ctx, r.apiObj, metav1.UpdateOptions{})
+	elapsed := time.Since(start)
+	logger.V(4).Info("Updated annotations", "duration", elapsed)
+	if err != nil {
+		// do not let later commits store the annotation
+		delete(r.apiObj.ObjectMeta.Annotations, key)
+		return errors.Wrap(err,
+			fmt.Sprintf("failed to update annotation %q [%s]", key, r.String()))
+	}
+	r.apiObj = result
+	return nil
+}
+
 // Panics if the instance holds any uncommitted changes.
 func (r *pipelineRun) DeleteFinalizerAndCommitIfExists(ctx context.Context) error {
 	r.mustBeChangeable()
@@ -11,6 +11,7 @@ import (
 	"gotest.tools/v3/assert"
 	"gotest.tools/v3/assert/cmp"
 	is "gotest.tools/v3/assert/cmp"
+	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
@@ -199,6 +200,69 @@ func Test_pipelineRun_HasDeletionTimestamp_true(t *testing.T) {
 	assert.Assert(t, deleted == true)
 }
 
+func Test_pipelineRun_AddAnnotationAndCommitIfNotPresent(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name          string
+		annotations   map[string]string
+		expectedValue string
+	}{
+		{"no_annotations", nil, "value1"},
+		{"other_annotation", map[string]string{"other": "x"}, "value1"},
+		{"present", map[string]string{"key1": "existing"}, "existing"},
+		{"present_empty", map[string]string{"key1": ""}, ""},
+	} {
+		tc := tc // capture current value before going parallel
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			ctx := context.Background()
+			run := newPipelineRunWithEmptySpec(ns1, run1)
+			run.SetAnnotations(tc.annotations)
+			factory := fake.NewClientFactory(run)
+			examinee, err := NewPipelineRun(ctx, run, factory)
+			assert.NilError(t, err)
+
+			// EXERCISE
+			resultErr := examinee.AddAnnotationAndCommitIfNotPresent(ctx, "key1", "value1")
+
+			// VERIFY
+			assert.NilError(t, resultErr)
+			assert.Equal(t, tc.expectedValue, examinee.GetAPIObject().GetAnnotations()["key1"])
+			client := factory.StewardV1alpha1().PipelineRuns(ns1)
+			stored, err := client.Get(ctx, run1, metav1.GetOptions{})
+			assert.NilError(t, err)
+			assert.Equal(t, tc.expectedValue, stored.GetAnnotations()["key1"])
+			if tc.annotations != nil {
+				assert.DeepEqual(t, tc.annotations, run.GetAnnotations())
+			}
+		})
+	}
+}
+
+func Test_pipelineRun_AddAnnotationAndCommitIfNotPresent_UpdateFails(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	run := newPipelineRunWithEmptySpec(ns1, run1)
+	run.SetAnnotations(map[string]string{"other": "x"})
+	factory := fake.NewClientFactory(run)
+	examinee, err := NewPipelineRun(ctx, run, factory)
+	assert.NilError(t, err)
+	expectedError := fmt.Errorf("expected")
+	factory.StewardClientset().PrependReactor("update", "*", fake.NewErrorReactor(expectedError))
+
+	// EXERCISE
+	resultErr := examinee.AddAnnotationAndCommitIfNotPresent(ctx, "key1", "value1")
+
+	// VERIFY
+	assert.ErrorContains(t, resultErr, "expected")
+	assert.DeepEqual(t, map[string]string{"other": "x"}, examinee.GetAPIObject().GetAnnotations())
+}
+
 func Test_pipelineRun_UpdateMessage_GoodCase(t *testing.T) {
 	t.Parallel()
 
@@ -216,6 +280,146 @@ func Test_pipelineRun_UpdateMessage_GoodCase(t *testing.T) {
 	assert.Equal(t, message, examinee.GetStatus().Message)
 }
 
+func Test_pipelineRun_UpdateCopiedSecrets(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	run := newPipelineRunWithEmptySpec(ns1, run1)
+	factory := fake.NewClientFactory(run)
+	examinee, err := NewPipelineRun(ctx, run, factory)
+	assert.NilError(t, err)
+	copiedSecrets := []api.CopiedSecret{
+		{
+			Purpose:               api.SecretPurposePipeline,
+			SourceName:            "foo",
+			SourceUID:             "uid1",
+			SourceResourceVersion: "rv1",
+			TargetName:            "bar",
+			Type:                  corev1.SecretTypeOpaque,
+		},
+	}
+
+	// EXERCISE
+	examinee.UpdateCopiedSecrets(copiedSecrets)
+	_, err = examinee.CommitStatus(ctx)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.DeepEqual(t, copiedSecrets, examinee.GetStatus().CopiedSecrets)
+	stored, err := factory.StewardV1alpha1().PipelineRuns(ns1).Get(ctx, run1, metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, copiedSecrets, stored.Status.CopiedSecrets)
+}
+
+func Test_pipelineRun_UpdateLogTail(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	run := newPipelineRunWithEmptySpec(ns1, run1)
+	factory := fake.NewClientFactory(run)
+	examinee, err := NewPipelineRun(ctx, run, factory)
+	assert.NilError(t, err)
+
+	// EXERCISE
+	examinee.UpdateLogTail("line1\nline2\n")
+	_, err = examinee.CommitStatus(ctx)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Equal(t, "line1\nline2\n", examinee.GetStatus().LogTail)
+	stored, err := factory.StewardV1alpha1().PipelineRuns(ns1).Get(ctx, run1, metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, "line1\nline2\n", stored.Status.LogTail)
+}
+
+func Test_pipelineRun_UpdateEffectiveConfig(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	run := newPipelineRunWithEmptySpec(ns1, run1)
+	factory := fake.NewClientFactory(run)
+	examinee, err := NewPipelineRun(ctx, run, factory)
+	assert.NilError(t, err)
+	effectiveConfig := &api.EffectiveConfig{
+		JenkinsfileRunnerImage: "image1",
+		NetworkProfile:         "profile1",
+		NetworkPolicyHash:      "hash1",
+	}
+
+	// EXERCISE
+	examinee.UpdateEffectiveConfig(effectiveConfig)
+	_, err = examinee.CommitStatus(ctx)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.DeepEqual(t, effectiveConfig, examinee.GetStatus().EffectiveConfig)
+	stored, err := factory.StewardV1alpha1().PipelineRuns(ns1).Get(ctx, run1, metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, effectiveConfig, stored.Status.EffectiveConfig)
+}
+
+func Test_pipelineRun_UpdateContainer_RecordsExitCode(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	run := newPipelineRunWithEmptySpec(ns1, run1)
+	factory := fake.NewClientFactory(run)
+	examinee, err := NewPipelineRun(ctx, run, factory)
+	assert.NilError(t, err)
+
+	// EXERCISE
+	examinee.UpdateContainer(ctx, &corev1.ContainerState{Running: &corev1.ContainerStateRunning{}})
+	exitCodeWhileRunning := examinee.GetStatus().ExitCode
+	examinee.UpdateContainer(ctx, &corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 4}})
+	_, err = examinee.CommitStatus(ctx)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Assert(t, exitCodeWhileRunning == nil)
+	stored, err := factory.StewardV1alpha1().PipelineRuns(ns1).Get(ctx, run1, metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Assert(t, stored.Status.ExitCode != nil)
+	assert.Equal(t, int32(4), *stored.Status.ExitCode)
+}
+
+func Test_pipelineRun_AddNotifications(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	run := newPipelineRunWithEmptySpec(ns1, run1)
+	run.Status.Notifications = []api.NotificationStatus{
+		{Name: "target1", Event: api.NotificationEventStarted, State: api.NotificationStateDelivered, Attempts: 1},
+	}
+	factory := fake.NewClientFactory(run)
+	examinee, err := NewPipelineRun(ctx, run, factory)
+	assert.NilError(t, err)
+
+	// EXERCISE
+	examinee.AddNotifications([]api.NotificationStatus{
+		{Name: "target1", Event: api.NotificationEventStarted, State: api.NotificationStatePending},
+		{Name: "target1", Event: api.NotificationEventFinished, State: api.NotificationStatePending},
+		{Name: "target2", Event: api.NotificationEventFailed, State: api.NotificationStatePending},
+	})
+	_, err = examinee.CommitStatus(ctx)
+
+	// VERIFY
+	assert.NilError(t, err)
+	expected := []api.NotificationStatus{
+		{Name: "target1", Event: api.NotificationEventStarted, State: api.NotificationStateDelivered, Attempts: 1},
+		{Name: "target1", Event: api.NotificationEventFinished, State: api.NotificationStatePending},
+		{Name: "target2", Event: api.NotificationEventFailed, State: api.NotificationStatePending},
+	}
+	assert.DeepEqual(t, expected, examinee.GetStatus().Notifications)
+	stored, err := factory.StewardV1alpha1().PipelineRuns(ns1).Get(ctx, run1, metav1.GetOptions{})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, expected, stored.Status.Notifications)
+}
+
 func Test_pipelineRun_InitState(t *testing.T) {
 	t.Parallel()
 
@@ -450,6 +654,12 @@ func Test_pipelineRun_GetValidatedJenkinsfileRepoServerURL_CorrectURLs(t *testin
 		{url: "HTTPS://foo.com/Path", expectedURL: "https://foo.com"},
 		{url: "https://foo.com:1234/Path", expectedURL: "https://foo.com:1234"},
 		{url: "http://foo.com:1234/Path", expectedURL: "http://foo.com:1234"},
+		{url: "ssh://foo.com/org/repo.git", expectedURL: "ssh://foo.com"},
+		{url: "ssh://git@foo.com:2222/org/repo.git", expectedURL: "ssh://foo.com:2222"},
+		{url: "git@foo.com:org/repo.git", expectedURL: "ssh://foo.com"},
+		{url: "foo.com:org/repo.git", expectedURL: "ssh://foo.com"},
+		{url: "git@foo.com:/org/repo.git", expectedURL: "ssh://foo.com"},
+		{url: "git@[::1]:org/repo.git", expectedURL: "ssh://[::1]"},
 	} {
 		t.Run(test.url, func(t *testing.T) {
 			// SETUP
@@ -476,6 +686,12 @@ func Test_pipelineRun_GetValidatedJenkinsfileRepoServerURL_WrongURLs(t *testing.
 	}{
 		{url: "&:", expectedErrorPattern: `value "&:" of field spec.jenkinsFile.url is invalid \[.*\]: .*`},
 		{url: "ftp://foo/bar", expectedErrorPattern: `value "ftp://foo/bar" of field spec.jenkinsFile.url is invalid \[.*\]: scheme not supported: .*`},
+		{url: "ssh://foo.com", expectedErrorPattern: `value "ssh://foo.com" of field spec.jenkinsFile.url is invalid \[.*\]: malformed SSH URL: host and path required`},
+		{url: "ssh:///org/repo.git", expectedErrorPattern: `value "ssh:///org/repo.git" of field spec.jenkinsFile.url is invalid \[.*\]: malformed SSH URL: host and path required`},
+		{url: "git@foo.com:", expectedErrorPattern: `value "git@foo.com:" of field spec.jenkinsFile.url is invalid \[.*\]: malformed SSH URL: path required`},
+		{url: "@foo.com:org/repo.git", expectedErrorPattern: `value "@foo.com:org/repo.git" of field spec.jenkinsFile.url is invalid \[.*\]: malformed SSH URL: empty user`},
+		{url: "git@foo_bar.com:org/repo.git", expectedErrorPattern: `value "git@foo_bar.com:org/repo.git" of field spec.jenkinsFile.url is invalid \[.*\]: malformed SSH URL: invalid host "foo_bar.com"`},
+		{url: "git@:org/repo.git", expectedErrorPattern: `value "git@:org/repo.git" of field spec.jenkinsFile.url is invalid \[.*\]: malformed SSH URL: invalid host ""`},
 	} {
 		t.Run(test.url, func(t *testing.T) {
 			// SETUP
@@ -31,6 +31,7 @@ import (
 	context "context"
 	reflect "reflect"
 
+	v1alpha1 "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
 	gomock "github.com/golang/mock/gomock"
 	v1 "k8s.io/api/core/v1"
 )
@@ -58,15 +59,35 @@ func (m *MockSecretHelper) EXPECT() *MockSecretHelperMockRecorder {
 	return m.recorder
 }
 
+// CopySecret mocks base method.
+func (m *MockSecretHelper) CopySecret(arg0 context.Context, arg1 *v1.Secret, arg2 ...func(*v1.Secret)) (*v1alpha1.CopiedSecret, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CopySecret", varargs...)
+	ret0, _ := ret[0].(*v1alpha1.CopiedSecret)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CopySecret indicates an expected call of CopySecret.
+func (mr *MockSecretHelperMockRecorder) CopySecret(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CopySecret", reflect.TypeOf((*MockSecretHelper)(nil).CopySecret), varargs...)
+}
+
 // CopySecrets mocks base method.
-func (m *MockSecretHelper) CopySecrets(arg0 context.Context, arg1 []string, arg2 func(*v1.Secret) bool, arg3 ...func(*v1.Secret)) ([]string, error) {
+func (m *MockSecretHelper) CopySecrets(arg0 context.Context, arg1 []string, arg2 func(*v1.Secret) bool, arg3 ...func(*v1.Secret)) ([]v1alpha1.CopiedSecret, error) {
 	m.ctrl.T.Helper()
 	varargs := []interface{}{arg0, arg1, arg2}
 	for _, a := range arg3 {
 		varargs = append(varargs, a)
 	}
 	ret := m.ctrl.Call(m, "CopySecrets", varargs...)
-	ret0, _ := ret[0].([]string)
+	ret0, _ := ret[0].([]v1alpha1.CopiedSecret)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -93,6 +114,21 @@ func (mr *MockSecretHelperMockRecorder) CreateSecret(arg0, arg1 interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSecret", reflect.TypeOf((*MockSecretHelper)(nil).CreateSecret), arg0, arg1)
 }
 
+// GetSecret mocks base method.
+func (m *MockSecretHelper) GetSecret(arg0 context.Context, arg1 string) (*v1.Secret, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSecret", arg0, arg1)
+	ret0, _ := ret[0].(*v1.Secret)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSecret indicates an expected call of GetSecret.
+func (mr *MockSecretHelperMockRecorder) GetSecret(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSecret", reflect.TypeOf((*MockSecretHelper)(nil).GetSecret), arg0, arg1)
+}
+
 // IsNotFound mocks base method.
 func (m *MockSecretHelper) IsNotFound(arg0 error) bool {
 	m.ctrl.T.Helper()
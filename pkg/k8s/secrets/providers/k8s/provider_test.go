@@ -52,9 +52,11 @@ func Test_provider_GetSecret_Existing(t *testing.T) {
 
 	expectedSecret := &v1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:        storedSecret.GetName(),
-			Labels:      storedSecret.GetLabels(),
-			Annotations: storedSecret.GetAnnotations(),
+			Name:            storedSecret.GetName(),
+			UID:             storedSecret.GetUID(),
+			ResourceVersion: storedSecret.GetResourceVersion(),
+			Labels:          storedSecret.GetLabels(),
+			Annotations:     storedSecret.GetAnnotations(),
 		},
 		Type: v1.SecretTypeOpaque,
 	}
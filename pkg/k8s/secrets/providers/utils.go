@@ -5,11 +5,15 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-// StripMetadata strips the metadata from a secret
+// StripMetadata strips the metadata from a secret.
+// UID and resource version are kept to identify the
+// version of the secret that has been provided.
 func StripMetadata(secret *v1.Secret) {
 	secret.ObjectMeta = metav1.ObjectMeta{
-		Name:        secret.GetName(),
-		Labels:      secret.GetLabels(),
-		Annotations: secret.GetAnnotations(),
+		Name:            secret.GetName(),
+		UID:             secret.GetUID(),
+		ResourceVersion: secret.GetResourceVersion(),
+		Labels:          secret.GetLabels(),
+		Annotations:     secret.GetAnnotations(),
 	}
 }
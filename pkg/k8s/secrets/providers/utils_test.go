@@ -43,9 +43,11 @@ func Test_StripMetadata(t *testing.T) {
 	// VERIFY
 	expectedSecret := &v1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:        origSecret.GetName(),
-			Labels:      origSecret.GetLabels(),
-			Annotations: origSecret.GetAnnotations(),
+			Name:            origSecret.GetName(),
+			UID:             origSecret.GetUID(),
+			ResourceVersion: origSecret.GetResourceVersion(),
+			Labels:          origSecret.GetLabels(),
+			Annotations:     origSecret.GetAnnotations(),
 		},
 		Type: v1.SecretTypeOpaque,
 	}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
 	"github.com/pkg/errors"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -12,7 +13,9 @@ import (
 
 // SecretHelper copies secrets
 type SecretHelper interface {
-	CopySecrets(ctx context.Context, secretNames []string, filter SecretFilter, transformers ...SecretTransformer) ([]string, error)
+	CopySecrets(ctx context.Context, secretNames []string, filter SecretFilter, transformers ...SecretTransformer) ([]api.CopiedSecret, error)
+	CopySecret(ctx context.Context, secret *v1.Secret, transformers ...SecretTransformer) (*api.CopiedSecret, error)
+	GetSecret(ctx context.Context, secretName string) (*v1.Secret, error)
 	CreateSecret(ctx context.Context, secret *v1.Secret) (*v1.Secret, error)
 	IsNotFound(err error) bool
 }
@@ -40,32 +43,63 @@ func NewSecretHelper(provider SecretProvider, namespace string, client corev1.Se
 // CopySecrets copies a set of secrets with defined names
 // filter can be defined to copy only dedicated secrets
 // transformers can be defined to transform the secrets before they are stored
-// returns a list of the secret names (after transformation) which were stored
+// returns a list of the secrets which were stored (without secret values
+// and without purpose)
 // In case of an error the copying is stopped. The result list contains the secrets already copied
 // before the error occurred. There is no rollback done by this function.
-func (h *secretHelper) CopySecrets(ctx context.Context, secretNames []string, filter SecretFilter, transformers ...SecretTransformer) ([]string, error) {
-	var storedSecretNames []string
+func (h *secretHelper) CopySecrets(ctx context.Context, secretNames []string, filter SecretFilter, transformers ...SecretTransformer) ([]api.CopiedSecret, error) {
+	var copiedSecrets []api.CopiedSecret
 	for _, secretName := range secretNames {
-		secret, err := h.provider.GetSecret(ctx, secretName)
+		secret, err := h.GetSecret(ctx, secretName)
 		if err != nil {
-			return storedSecretNames, err
-		}
-		if secret == nil {
-			return storedSecretNames, NewNotFoundError(secretName)
+			return copiedSecrets, err
 		}
 		if filter != nil && !filter(secret) {
 			continue
 		}
-		for _, transformer := range transformers {
-			transformer(secret)
-		}
-		storedSecret, err := h.CreateSecret(ctx, secret)
+		copiedSecret, err := h.CopySecret(ctx, secret, transformers...)
 		if err != nil {
-			return storedSecretNames, err
+			return copiedSecrets, err
 		}
-		storedSecretNames = append(storedSecretNames, storedSecret.GetName())
+		copiedSecrets = append(copiedSecrets, *copiedSecret)
+	}
+	return copiedSecrets, nil
+}
+
+// CopySecret stores a copy of the given secret after applying the given
+// transformers to it. The given secret gets modified by the transformers.
+// returns the secret which was stored (without secret values
+// and without purpose)
+func (h *secretHelper) CopySecret(ctx context.Context, secret *v1.Secret, transformers ...SecretTransformer) (*api.CopiedSecret, error) {
+	copiedSecret := &api.CopiedSecret{
+		SourceName:            secret.GetName(),
+		SourceUID:             secret.GetUID(),
+		SourceResourceVersion: secret.GetResourceVersion(),
+		Type:                  secret.Type,
+	}
+	for _, transformer := range transformers {
+		transformer(secret)
+	}
+	storedSecret, err := h.CreateSecret(ctx, secret)
+	if err != nil {
+		return nil, err
+	}
+	copiedSecret.TargetName = storedSecret.GetName()
+	return copiedSecret, nil
+}
+
+// GetSecret returns the secret with the given name from the secret provider.
+// If the secret does not exist, an error is returned for which `IsNotFound`
+// returns true.
+func (h *secretHelper) GetSecret(ctx context.Context, secretName string) (*v1.Secret, error) {
+	secret, err := h.provider.GetSecret(ctx, secretName)
+	if err != nil {
+		return nil, err
+	}
+	if secret == nil {
+		return nil, NewNotFoundError(secretName)
 	}
-	return storedSecretNames, nil
+	return secret, nil
 }
 
 type notFoundError struct {
@@ -7,6 +7,7 @@ import (
 	"strings"
 	"testing"
 
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
 	"github.com/SAP/stewardci-core/pkg/k8s/fake"
 	secretMocks "github.com/SAP/stewardci-core/pkg/k8s/secrets/mocks"
 	fakesecretprovider "github.com/SAP/stewardci-core/pkg/k8s/secrets/providers/fake"
@@ -44,6 +45,14 @@ func initSecretHelperWithMock(
 	return helper, mockSecretHelper
 }
 
+func copiedSecretNames(copiedSecrets []api.CopiedSecret) []string {
+	var names []string
+	for _, copiedSecret := range copiedSecrets {
+		names = append(names, copiedSecret.TargetName)
+	}
+	return names
+}
+
 func Test_CopySecrets_NoFilter(t *testing.T) {
 	t.Parallel()
 
@@ -64,7 +73,43 @@ func Test_CopySecrets_NoFilter(t *testing.T) {
 
 	// VERIFY
 	assert.NilError(t, resultErr)
-	assert.DeepEqual(t, []string{"foo"}, resultList)
+	assert.DeepEqual(t, []string{"foo"}, copiedSecretNames(resultList))
+}
+
+func Test_CopySecrets_RecordsSourceAndTarget(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	secret := fake.SecretWithType("foo", namespace, v1.SecretTypeBasicAuth)
+	secret.SetUID(types.UID("uid1"))
+	secret.SetResourceVersion("rv1")
+	examinee, mockSecretHelper := initSecretHelperWithMock(t, mockCtrl, secret)
+
+	// EXPECT
+	mockSecretHelper.EXPECT().CreateSecret(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, secret *v1.Secret) (*v1.Secret, error) {
+			assert.Equal(t, "bar", secret.GetName())
+			return secret, nil
+		})
+
+	// EXERCISE
+	resultList, resultErr := examinee.CopySecrets(ctx, []string{"foo"}, nil, RenameTransformer("bar"))
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	assert.DeepEqual(t, []api.CopiedSecret{
+		{
+			SourceName:            "foo",
+			SourceUID:             types.UID("uid1"),
+			SourceResourceVersion: "rv1",
+			TargetName:            "bar",
+			Type:                  v1.SecretTypeBasicAuth,
+		},
+	}, resultList)
 }
 
 func Test_CopySecrets_WithFilter(t *testing.T) {
@@ -102,7 +147,7 @@ func Test_CopySecrets_WithFilter(t *testing.T) {
 
 	// VERIFY
 	assert.NilError(t, resultErr)
-	assert.DeepEqual(t, []string{"bar", "baz"}, resultList)
+	assert.DeepEqual(t, []string{"bar", "baz"}, copiedSecretNames(resultList))
 }
 
 func Test_CopySecrets_NotExisting(t *testing.T) {
@@ -131,7 +176,48 @@ func Test_CopySecrets_NotExisting(t *testing.T) {
 
 	// VERIFY
 	assert.Assert(t, examinee.IsNotFound(resultErr))
-	assert.DeepEqual(t, []string{"foo"}, resultList)
+	assert.DeepEqual(t, []string{"foo"}, copiedSecretNames(resultList))
+}
+
+func Test_CopySecret_Error(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	examinee, mockSecretHelper := initSecretHelperWithMock(t, mockCtrl)
+	secret := fake.SecretOpaque("foo", namespace)
+	expectedError := fmt.Errorf("err1")
+
+	// EXPECT
+	mockSecretHelper.EXPECT().CreateSecret(ctx, gomock.Any()).Return(nil, expectedError)
+
+	// EXERCISE
+	result, resultErr := examinee.CopySecret(ctx, secret, RenameTransformer("bar"))
+
+	// VERIFY
+	assert.Equal(t, expectedError, resultErr)
+	assert.Assert(t, result == nil)
+}
+
+func Test_GetSecret_NotExisting(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	examinee, _ := initSecretHelperWithMock(t, mockCtrl, fake.SecretOpaque("foo", namespace))
+
+	// EXERCISE
+	result, resultErr := examinee.GetSecret(ctx, "notExistingSecret1")
+
+	// VERIFY
+	assert.Assert(t, examinee.IsNotFound(resultErr))
+	assert.Assert(t, result == nil)
 }
 
 func initSecretHelperWithClient(secrets ...*v1.Secret) (SecretHelper, corev1.SecretInterface) {
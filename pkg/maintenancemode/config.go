@@ -6,6 +6,7 @@ import (
 	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
 	"github.com/SAP/stewardci-core/pkg/k8s"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"knative.dev/pkg/system"
@@ -29,12 +30,46 @@ func IsMaintenanceMode(ctx context.Context, clientFactory k8s.ClientFactory) (bo
 		return true, wrapError(err)
 	}
 
-	if configMap != nil {
-		if !configMap.ObjectMeta.DeletionTimestamp.IsZero() {
-			return false, nil
+	if k8serrors.IsNotFound(err) {
+		configMap = nil
+	}
+	return IsMaintenanceModeConfigMap(configMap), nil
+}
+
+// LoadConfig loads the maintenance configuration.
+// If the maintenance windows cannot be parsed, an error is returned
+// together with a config without windows.
+func LoadConfig(ctx context.Context, clientFactory k8s.ClientFactory) (*Config, error) {
+	wrapError := func(cause error) error {
+		return errors.Wrapf(cause,
+			"invalid configuration: ConfigMap %q in namespace %q",
+			api.MaintenanceModeConfigMapName,
+			system.Namespace(),
+		)
+	}
+
+	configMapIfce := clientFactory.CoreV1().ConfigMaps(system.Namespace())
+
+	configMap, err := configMapIfce.Get(ctx, api.MaintenanceModeConfigMapName, metav1.GetOptions{})
+	if err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return nil, wrapError(err)
 		}
-		data := configMap.Data
-		return data[api.MaintenanceModeKeyName] == "true", nil
+		configMap = nil
+	}
+
+	config, err := ParseConfigMap(configMap)
+	if err != nil {
+		return config, wrapError(err)
+	}
+	return config, nil
+}
+
+// IsMaintenanceModeConfigMap returns true if the given maintenance mode
+// ConfigMap enables maintenance mode. configMap may be nil.
+func IsMaintenanceModeConfigMap(configMap *corev1.ConfigMap) bool {
+	if configMap == nil || !configMap.ObjectMeta.DeletionTimestamp.IsZero() {
+		return false
 	}
-	return false, nil
+	return configMap.Data[api.MaintenanceModeKeyName] == "true"
 }
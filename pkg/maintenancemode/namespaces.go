@@ -0,0 +1,32 @@
+package maintenancemode
+
+import (
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// IsNamespaceInMaintenance returns true if the given namespace is in
+// maintenance mode, either because it is listed in the configuration or
+// because it is labelled accordingly. namespace may be nil if only the
+// configuration should be checked.
+func (c *Config) IsNamespaceInMaintenance(namespaceName string, namespace *corev1.Namespace) bool {
+	if contains(c.Namespaces, namespaceName) {
+		return true
+	}
+	return namespace != nil && namespace.GetLabels()[api.LabelMaintenanceMode] == "true"
+}
+
+// IsBypassAllowed returns true if pipeline runs in the given namespace may
+// bypass maintenance mode.
+func (c *Config) IsBypassAllowed(namespaceName string) bool {
+	return contains(c.BypassNamespaces, namespaceName)
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
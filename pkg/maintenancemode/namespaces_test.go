@@ -0,0 +1,100 @@
+package maintenancemode
+
+import (
+	"testing"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_ParseConfigMap_Namespaces(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	configMap := newMaintenanceModeConfigMap(map[string]string{
+		api.MaintenanceNamespacesKeyName:       "[ns1, ns2]",
+		api.MaintenanceBypassNamespacesKeyName: "- ns3\n",
+	})
+
+	// EXERCISE
+	result, resultErr := ParseConfigMap(configMap)
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	assert.DeepEqual(t, []string{"ns1", "ns2"}, result.Namespaces)
+	assert.DeepEqual(t, []string{"ns3"}, result.BypassNamespaces)
+}
+
+func Test_ParseConfigMap_InvalidNamespaces(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	configMap := newMaintenanceModeConfigMap(map[string]string{
+		api.MaintenanceModeKeyName:             "true",
+		api.MaintenanceNamespacesKeyName:       "ns1: foo",
+		api.MaintenanceBypassNamespacesKeyName: "[ns3]",
+	})
+
+	// EXERCISE
+	result, resultErr := ParseConfigMap(configMap)
+
+	// VERIFY
+	assert.ErrorContains(t, resultErr, `key "namespaces": `)
+	assert.Assert(t, result.Enabled)
+	assert.Equal(t, 0, len(result.Namespaces))
+	assert.DeepEqual(t, []string{"ns3"}, result.BypassNamespaces)
+}
+
+func Test_Config_IsNamespaceInMaintenance(t *testing.T) {
+	t.Parallel()
+
+	labelled := func(value string) *corev1.Namespace {
+		return &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "ns1",
+				Labels: map[string]string{api.LabelMaintenanceMode: value},
+			},
+		}
+	}
+
+	for _, tc := range []struct {
+		name       string
+		namespaces []string
+		namespace  *corev1.Namespace
+		expected   bool
+	}{
+		{"not_listed", []string{"other"}, nil, false},
+		{"listed", []string{"other", "ns1"}, nil, true},
+		{"labelled_true", nil, labelled("true"), true},
+		{"labelled_false", nil, labelled("false"), false},
+		{"unlabelled", nil, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns1"}}, false},
+	} {
+		tc := tc // capture current value before going parallel
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			examinee := &Config{Namespaces: tc.namespaces}
+
+			// EXERCISE
+			result := examinee.IsNamespaceInMaintenance("ns1", tc.namespace)
+
+			// VERIFY
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func Test_Config_IsBypassAllowed(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	examinee := &Config{BypassNamespaces: []string{"ns1", "ns2"}}
+
+	// VERIFY
+	assert.Assert(t, examinee.IsBypassAllowed("ns2"))
+	assert.Assert(t, !examinee.IsBypassAllowed("ns3"))
+	assert.Assert(t, !(&Config{}).IsBypassAllowed("ns1"))
+}
@@ -0,0 +1,252 @@
+package maintenancemode
+
+import (
+	"fmt"
+	"time"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/pkg/errors"
+	"github.com/robfig/cron/v3"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// State is the maintenance state of the system.
+type State string
+
+const (
+	// StateInactive - no maintenance
+	StateInactive State = "inactive"
+
+	// StateAnnounced - a maintenance window starts soon. Pipeline runs are
+	// still started, but users are warned.
+	StateAnnounced State = "announced"
+
+	// StateActive - maintenance is in progress. New pipeline runs are not
+	// started. Active pipeline runs are processed to the end.
+	StateActive State = "active"
+
+	// StateAborting - maintenance is in progress and the drain grace period
+	// is over. New pipeline runs are not started and active pipeline runs
+	// are aborted.
+	StateAborting State = "aborting"
+)
+
+// severity orders states by their impact on pipeline runs.
+var severity = map[State]int{
+	StateInactive:  0,
+	StateAnnounced: 1,
+	StateActive:    2,
+	StateAborting:  3,
+}
+
+// BlocksNewPipelineRuns returns true if new pipeline runs must not be
+// started in this state.
+func (s State) BlocksNewPipelineRuns() bool {
+	return s == StateActive || s == StateAborting
+}
+
+// Window is a scheduled maintenance window.
+type Window struct {
+	// Name identifies the window in events and log messages.
+	Name string `json:"name"`
+
+	// Start is the start time of the window.
+	Start metav1.Time `json:"start"`
+
+	// End is the end time of the window. It must be after Start.
+	End metav1.Time `json:"end"`
+
+	// Recurrence is an optional cron expression. If set, the window recurs
+	// at each time matched by the expression at or after Start, lasting
+	// End minus Start each time. The expression is evaluated in UTC unless
+	// prefixed with "CRON_TZ=<time zone>".
+	// +optional
+	Recurrence string `json:"recurrence,omitempty"`
+
+	// Announce is the period before the start of the window during which
+	// users are warned when starting pipeline runs.
+	// +optional
+	Announce metav1.Duration `json:"announce,omitempty"`
+
+	// Drain enables aborting pipeline runs still active after the drain
+	// grace period.
+	// +optional
+	Drain bool `json:"drain,omitempty"`
+
+	// DrainGracePeriod is the period after the start of the window after
+	// which active pipeline runs are aborted if Drain is enabled.
+	// +optional
+	DrainGracePeriod metav1.Duration `json:"drainGracePeriod,omitempty"`
+
+	schedule cron.Schedule
+}
+
+// Config is the maintenance configuration.
+type Config struct {
+	// Enabled is true if maintenance mode is switched on explicitly.
+	Enabled bool
+
+	// Windows are the scheduled maintenance windows.
+	Windows []Window
+
+	// Namespaces are the namespaces in maintenance mode in addition to
+	// those labelled accordingly.
+	Namespaces []string
+
+	// BypassNamespaces are the namespaces in which pipeline runs may
+	// bypass maintenance mode.
+	BypassNamespaces []string
+}
+
+// Status is the maintenance status at a certain point in time.
+type Status struct {
+	// State is the maintenance state.
+	State State
+
+	// Window is the maintenance window causing the state, or nil if the
+	// state is inactive or maintenance mode is enabled explicitly.
+	Window *Window
+
+	// Start and End are the boundaries of the occurrence of Window which is
+	// in progress or announced.
+	Start, End time.Time
+}
+
+// ParseConfigMap parses the maintenance mode ConfigMap. configMap may be
+// nil.
+// If a key cannot be parsed, the first error is returned together with a
+// config in which the respective fields are empty.
+func ParseConfigMap(configMap *corev1.ConfigMap) (*Config, error) {
+	config := &Config{
+		Enabled: IsMaintenanceModeConfigMap(configMap),
+	}
+	if configMap == nil || !configMap.ObjectMeta.DeletionTimestamp.IsZero() {
+		return config, nil
+	}
+	var firstErr error
+	setErr := func(key string, err error) {
+		if firstErr == nil {
+			firstErr = errors.Wrapf(err, "key %q", key)
+		}
+	}
+	windows, err := parseWindows(configMap.Data[api.MaintenanceWindowsKeyName])
+	if err != nil {
+		setErr(api.MaintenanceWindowsKeyName, err)
+	} else {
+		config.Windows = windows
+	}
+	for _, item := range []struct {
+		key    string
+		target *[]string
+	}{
+		{api.MaintenanceNamespacesKeyName, &config.Namespaces},
+		{api.MaintenanceBypassNamespacesKeyName, &config.BypassNamespaces},
+	} {
+		if err := yaml.UnmarshalStrict([]byte(configMap.Data[item.key]), item.target); err != nil {
+			setErr(item.key, err)
+			*item.target = nil
+		}
+	}
+	return config, firstErr
+}
+
+func parseWindows(windowsYAML string) ([]Window, error) {
+	var windows []Window
+	if err := yaml.UnmarshalStrict([]byte(windowsYAML), &windows); err != nil {
+		return nil, err
+	}
+	names := map[string]bool{}
+	for i := range windows {
+		w := &windows[i]
+		if w.Name == "" {
+			return nil, fmt.Errorf("window %d: name is missing", i)
+		}
+		if names[w.Name] {
+			return nil, fmt.Errorf("window %q: name is not unique", w.Name)
+		}
+		names[w.Name] = true
+		if w.Start.IsZero() || w.End.IsZero() {
+			return nil, fmt.Errorf("window %q: start and end are required", w.Name)
+		}
+		if !w.End.After(w.Start.Time) {
+			return nil, fmt.Errorf("window %q: end must be after start", w.Name)
+		}
+		if w.Announce.Duration < 0 || w.DrainGracePeriod.Duration < 0 {
+			return nil, fmt.Errorf("window %q: durations must not be negative", w.Name)
+		}
+		if w.Recurrence != "" {
+			schedule, err := cron.ParseStandard(w.Recurrence)
+			if err != nil {
+				return nil, errors.Wrapf(err, "window %q: invalid recurrence %q", w.Name, w.Recurrence)
+			}
+			w.schedule = schedule
+		}
+	}
+	return windows, nil
+}
+
+// StatusAt returns the maintenance status at the given time.
+// If multiple windows apply, the one with the highest impact on pipeline
+// runs determines the status.
+func (c *Config) StatusAt(now time.Time) Status {
+	status := Status{State: StateInactive}
+	if c.Enabled {
+		status.State = StateActive
+	}
+	for i := range c.Windows {
+		if windowStatus := c.Windows[i].statusAt(now); severity[windowStatus.State] > severity[status.State] {
+			status = windowStatus
+		}
+	}
+	return status
+}
+
+func (w *Window) statusAt(now time.Time) Status {
+	start, end := w.occurrence(now)
+	if start.IsZero() {
+		return Status{State: StateInactive}
+	}
+	status := Status{State: StateInactive, Window: w, Start: start, End: end}
+	switch {
+	case !now.Before(start) && now.Before(end):
+		status.State = StateActive
+		if w.Drain && !now.Before(start.Add(w.DrainGracePeriod.Duration)) {
+			status.State = StateAborting
+		}
+	case now.Before(start) && !now.Before(start.Add(-w.Announce.Duration)):
+		status.State = StateAnnounced
+	default:
+		return Status{State: StateInactive}
+	}
+	return status
+}
+
+// occurrence returns the occurrence of the window in progress at the given
+// time or, if there is none, the next occurrence. It returns zero times if
+// there is no such occurrence.
+func (w *Window) occurrence(now time.Time) (time.Time, time.Time) {
+	start, end := w.Start.Time, w.End.Time
+	if w.schedule == nil {
+		if now.Before(end) {
+			return start, end
+		}
+		return time.Time{}, time.Time{}
+	}
+	duration := end.Sub(start)
+	// the first occurrence starting after now-duration is either in
+	// progress or the next one
+	after := now.Add(-duration)
+	if after.Before(start) {
+		// cron schedules return times strictly after the given one
+		after = start.Add(-time.Second)
+	}
+	// cron expressions are evaluated in UTC unless they specify a
+	// time zone via prefix "CRON_TZ="
+	next := w.schedule.Next(after.UTC())
+	if next.IsZero() {
+		return time.Time{}, time.Time{}
+	}
+	return next, next.Add(duration)
+}
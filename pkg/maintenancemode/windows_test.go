@@ -0,0 +1,231 @@
+package maintenancemode
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/k8s/fake"
+	"gotest.tools/v3/assert"
+)
+
+func Test_ParseConfigMap(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name            string
+		windows         string
+		expectedWindows []string
+		expectedError   string
+	}{
+		{
+			name:            "no_windows",
+			windows:         "",
+			expectedWindows: nil,
+		},
+		{
+			name: "valid",
+			windows: `
+- name: w1
+  start: "2023-01-01T20:00:00Z"
+  end: "2023-01-01T22:00:00Z"
+- name: w2
+  start: "2023-01-07T20:00:00Z"
+  end: "2023-01-07T22:00:00Z"
+  recurrence: "0 20 * * 6"
+  announce: 1h
+  drain: true
+  drainGracePeriod: 15m
+`,
+			expectedWindows: []string{"w1", "w2"},
+		},
+		{
+			name:          "invalid_yaml",
+			windows:       "{",
+			expectedError: `key "windows": `,
+		},
+		{
+			name:          "unknown_field",
+			windows:       `[{name: w1, start: "2023-01-01T20:00:00Z", end: "2023-01-01T22:00:00Z", foo: bar}]`,
+			expectedError: `unknown field "foo"`,
+		},
+		{
+			name:          "name_missing",
+			windows:       `[{start: "2023-01-01T20:00:00Z", end: "2023-01-01T22:00:00Z"}]`,
+			expectedError: `key "windows": window 0: name is missing`,
+		},
+		{
+			name:          "name_not_unique",
+			windows:       `[{name: w1, start: "2023-01-01T20:00:00Z", end: "2023-01-01T22:00:00Z"}, {name: w1, start: "2023-01-02T20:00:00Z", end: "2023-01-02T22:00:00Z"}]`,
+			expectedError: `key "windows": window "w1": name is not unique`,
+		},
+		{
+			name:          "end_missing",
+			windows:       `[{name: w1, start: "2023-01-01T20:00:00Z"}]`,
+			expectedError: `key "windows": window "w1": start and end are required`,
+		},
+		{
+			name:          "end_before_start",
+			windows:       `[{name: w1, start: "2023-01-01T20:00:00Z", end: "2023-01-01T20:00:00Z"}]`,
+			expectedError: `key "windows": window "w1": end must be after start`,
+		},
+		{
+			name:          "negative_duration",
+			windows:       `[{name: w1, start: "2023-01-01T20:00:00Z", end: "2023-01-01T22:00:00Z", announce: -1h}]`,
+			expectedError: `key "windows": window "w1": durations must not be negative`,
+		},
+		{
+			name:          "invalid_recurrence",
+			windows:       `[{name: w1, start: "2023-01-01T20:00:00Z", end: "2023-01-01T22:00:00Z", recurrence: "every saturday"}]`,
+			expectedError: `key "windows": window "w1": invalid recurrence "every saturday"`,
+		},
+	} {
+		tc := tc // capture current value before going parallel
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			configMap := newMaintenanceModeConfigMap(map[string]string{
+				api.MaintenanceModeKeyName:    "true",
+				api.MaintenanceWindowsKeyName: tc.windows,
+			})
+
+			// EXERCISE
+			result, resultErr := ParseConfigMap(configMap)
+
+			// VERIFY
+			assert.Assert(t, result != nil)
+			assert.Assert(t, result.Enabled)
+			if tc.expectedError != "" {
+				assert.ErrorContains(t, resultErr, tc.expectedError)
+				assert.Equal(t, 0, len(result.Windows))
+				return
+			}
+			assert.NilError(t, resultErr)
+			var names []string
+			for _, w := range result.Windows {
+				names = append(names, w.Name)
+			}
+			assert.DeepEqual(t, tc.expectedWindows, names)
+		})
+	}
+}
+
+func Test_ParseConfigMap_Nil(t *testing.T) {
+	t.Parallel()
+
+	// EXERCISE
+	result, resultErr := ParseConfigMap(nil)
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	assert.Assert(t, !result.Enabled)
+	assert.Equal(t, 0, len(result.Windows))
+}
+
+func Test_Config_StatusAt(t *testing.T) {
+	t.Parallel()
+
+	windows := `
+- name: once
+  start: "2023-01-02T10:00:00Z"
+  end: "2023-01-02T12:00:00Z"
+  announce: 1h
+- name: weekly
+  # Saturdays 20:00 to 22:00
+  start: "2023-01-07T20:00:00Z"
+  end: "2023-01-07T22:00:00Z"
+  recurrence: "0 20 * * 6"
+  announce: 2h
+  drain: true
+  drainGracePeriod: 30m
+`
+
+	for _, tc := range []struct {
+		name           string
+		enabled        bool
+		now            string
+		expectedState  State
+		expectedWindow string
+		expectedStart  string
+	}{
+		{"before_announce", false, "2023-01-02T08:59:59Z", StateInactive, "", ""},
+		{"announced", false, "2023-01-02T09:00:00Z", StateAnnounced, "once", "2023-01-02T10:00:00Z"},
+		{"active", false, "2023-01-02T10:00:00Z", StateActive, "once", "2023-01-02T10:00:00Z"},
+		{"after_end", false, "2023-01-02T12:00:00Z", StateInactive, "", ""},
+		{"recurring/before_first", false, "2023-01-07T17:59:59Z", StateInactive, "", ""},
+		{"recurring/announced", false, "2023-01-07T18:00:00Z", StateAnnounced, "weekly", "2023-01-07T20:00:00Z"},
+		{"recurring/active_in_grace_period", false, "2023-01-07T20:29:59Z", StateActive, "weekly", "2023-01-07T20:00:00Z"},
+		{"recurring/aborting", false, "2023-01-07T20:30:00Z", StateAborting, "weekly", "2023-01-07T20:00:00Z"},
+		{"recurring/after_first", false, "2023-01-07T22:00:00Z", StateInactive, "", ""},
+		{"recurring/second_announced", false, "2023-01-14T19:00:00Z", StateAnnounced, "weekly", "2023-01-14T20:00:00Z"},
+		{"recurring/second_aborting", false, "2023-01-14T21:59:59Z", StateAborting, "weekly", "2023-01-14T20:00:00Z"},
+		{"enabled", true, "2023-01-02T08:00:00Z", StateActive, "", ""},
+		{"enabled/announced", true, "2023-01-02T09:30:00Z", StateActive, "", ""},
+		{"enabled/aborting", true, "2023-01-14T21:00:00Z", StateAborting, "weekly", "2023-01-14T20:00:00Z"},
+	} {
+		tc := tc // capture current value before going parallel
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			config, err := ParseConfigMap(newMaintenanceModeConfigMap(map[string]string{
+				api.MaintenanceWindowsKeyName: windows,
+			}))
+			assert.NilError(t, err)
+			config.Enabled = tc.enabled
+			now := mustParseTime(t, tc.now)
+
+			// EXERCISE
+			result := config.StatusAt(now)
+
+			// VERIFY
+			assert.Equal(t, tc.expectedState, result.State)
+			if tc.expectedWindow == "" {
+				assert.Assert(t, result.Window == nil)
+				return
+			}
+			assert.Equal(t, tc.expectedWindow, result.Window.Name)
+			assert.Assert(t, mustParseTime(t, tc.expectedStart).Equal(result.Start), result.Start)
+			assert.Assert(t, result.Start.Add(2*time.Hour).Equal(result.End), result.End)
+		})
+	}
+}
+
+func Test_State_BlocksNewPipelineRuns(t *testing.T) {
+	t.Parallel()
+
+	assert.Assert(t, !StateInactive.BlocksNewPipelineRuns())
+	assert.Assert(t, !StateAnnounced.BlocksNewPipelineRuns())
+	assert.Assert(t, StateActive.BlocksNewPipelineRuns())
+	assert.Assert(t, StateAborting.BlocksNewPipelineRuns())
+}
+
+func Test_LoadConfig_InvalidWindows(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	cf := fake.NewClientFactory(
+		newMaintenanceModeConfigMap(map[string]string{
+			api.MaintenanceModeKeyName:    "true",
+			api.MaintenanceWindowsKeyName: "{",
+		}),
+	)
+
+	// EXERCISE
+	result, resultErr := LoadConfig(ctx, cf)
+
+	// VERIFY
+	assert.ErrorContains(t, resultErr, `invalid configuration: ConfigMap "steward-maintenance-mode" in namespace "knative-testing": key "windows": `)
+	assert.Assert(t, result.Enabled)
+	assert.Equal(t, 0, len(result.Windows))
+}
+
+func mustParseTime(t *testing.T, value string) time.Time {
+	t.Helper()
+	result, err := time.Parse(time.RFC3339, value)
+	assert.NilError(t, err)
+	return result
+}
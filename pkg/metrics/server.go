@@ -8,12 +8,26 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
-// StartServer starts the HTTP server providing the metrics for scraping.
-func StartServer(logger logr.Logger, port uint16) {
+// HealthCheck returns nil if the checked aspect is healthy, or an error
+// describing the problem otherwise.
+type HealthCheck func() error
+
+// HealthChecks are the checks served by the metrics server.
+type HealthChecks struct {
+	// Liveness is served at `/healthz`. If nil, the endpoint always
+	// reports healthy.
+	Liveness HealthCheck
+
+	// Readiness is served at `/readyz`. If nil, the endpoint always
+	// reports ready.
+	Readiness HealthCheck
+}
+
+// StartServer starts the HTTP server providing the metrics for scraping
+// and the given health checks.
+func StartServer(logger logr.Logger, port uint16, healthChecks HealthChecks) {
 	go func() {
-		handler := promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
-		serveMux := http.NewServeMux()
-		serveMux.Handle("/metrics", handler)
+		serveMux := newServeMux(logger, healthChecks)
 
 		for {
 			err := http.ListenAndServe(fmt.Sprintf(":%d", port), serveMux)
@@ -26,3 +40,29 @@ func StartServer(logger logr.Logger, port uint16) {
 		}
 	}()
 }
+
+func newServeMux(logger logr.Logger, healthChecks HealthChecks) *http.ServeMux {
+	serveMux := http.NewServeMux()
+	serveMux.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+	serveMux.Handle("/healthz", healthCheckHandler(logger, "liveness", healthChecks.Liveness))
+	serveMux.Handle("/readyz", healthCheckHandler(logger, "readiness", healthChecks.Readiness))
+	return serveMux
+}
+
+// healthCheckHandler responds with status 200 if the check succeeds and
+// with status 503 and the error message otherwise.
+func healthCheckHandler(logger logr.Logger, name string, check HealthCheck) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		if check != nil {
+			if err := check(); err != nil {
+				logger.V(3).Info("Health check failed", "check", name, "reason", err.Error())
+				w.WriteHeader(http.StatusServiceUnavailable)
+				fmt.Fprintf(w, "%s check failed: %s\n", name, err.Error())
+				return
+			}
+		}
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintln(w, "ok")
+	})
+}
@@ -0,0 +1,49 @@
+package metrics
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	klog "k8s.io/klog/v2"
+)
+
+func Test_newServeMux_HealthChecks(t *testing.T) {
+	t.Parallel()
+
+	failing := func() error { return errors.New("error1") }
+	succeeding := func() error { return nil }
+
+	for _, tc := range []struct {
+		name           string
+		checks         HealthChecks
+		path           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{"liveness_nil", HealthChecks{}, "/healthz", http.StatusOK, "ok\n"},
+		{"liveness_ok", HealthChecks{Liveness: succeeding, Readiness: failing}, "/healthz", http.StatusOK, "ok\n"},
+		{"liveness_failed", HealthChecks{Liveness: failing, Readiness: succeeding}, "/healthz", http.StatusServiceUnavailable, "liveness check failed: error1\n"},
+		{"readiness_nil", HealthChecks{}, "/readyz", http.StatusOK, "ok\n"},
+		{"readiness_ok", HealthChecks{Liveness: failing, Readiness: succeeding}, "/readyz", http.StatusOK, "ok\n"},
+		{"readiness_failed", HealthChecks{Liveness: succeeding, Readiness: failing}, "/readyz", http.StatusServiceUnavailable, "readiness check failed: error1\n"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			examinee := newServeMux(klog.Background(), tc.checks)
+			recorder := httptest.NewRecorder()
+
+			// EXERCISE
+			examinee.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, tc.path, nil))
+
+			// VERIFY
+			assert.Equal(t, tc.expectedStatus, recorder.Code)
+			assert.Equal(t, tc.expectedBody, recorder.Body.String())
+		})
+	}
+}
@@ -3,15 +3,19 @@ package cfg
 import (
 	"context"
 	"fmt"
+	"net/url"
 	"strconv"
 	"strings"
 	"time"
+	"unicode"
 
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
 	serrors "github.com/SAP/stewardci-core/pkg/errors"
 	"github.com/SAP/stewardci-core/pkg/featureflag"
 	"github.com/SAP/stewardci-core/pkg/k8s"
 	customlog "github.com/SAP/stewardci-core/pkg/runctl/log/custom"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"knative.dev/pkg/system"
@@ -31,9 +35,19 @@ const (
 	mainConfigKeyPSCFSGroup           = "jenkinsfileRunner.podSecurityContext.fsGroup"
 	mainConfigKeyTektonTaskName       = "tektonTaskName"
 	mainConfigKeyTektonTaskNamespace  = "tektonTaskNamespace"
+	mainConfigKeyMaskingExcludedTypes = "secretMasking.excludedSecretTypes"
+	mainConfigKeyDefaultPullSecrets   = "defaultImagePullSecrets"
+	mainConfigKeyCloudEventsSinkURL   = "cloudEvents.sinkURL"
+	mainConfigKeyFailureResults       = "failureResults"
+	mainConfigKeyExitCodeResults      = "jenkinsfileRunner.exitCodeResults"
+	mainConfigKeyLogTailLines         = "logTail.lines"
+	mainConfigKeyLogTailMaxBytes      = "logTail.maxBytes"
 
 	networkPoliciesConfigMapName    = "steward-pipelineruns-network-policies"
 	networkPoliciesConfigKeyDefault = "_default"
+
+	featureFlagsConfigMapName  = "steward-feature-flags"
+	featureFlagsConfigKeyFlags = "flags"
 )
 
 // PipelineRunsConfigStruct is a struct holding the pipeline runs configuration.
@@ -100,6 +114,46 @@ type PipelineRunsConfigStruct struct {
 	// TektonTaskNamespace is the name of the namespace containing
 	// the Tekton task to run the Jenkinsfile Runner.
 	TektonTaskNamespace string
+
+	// SecretMaskingExcludedSecretTypes is a list of secret types whose
+	// values are not added to the secret masking list of pipeline runs
+	// in addition to the types that are always excluded.
+	SecretMaskingExcludedSecretTypes []corev1.SecretType
+
+	// DefaultImagePullSecrets is a list of names of image pull secrets
+	// in the Steward system namespace, which are provided to all pipeline
+	// runs in addition to the image pull secrets specified in the
+	// pipeline run.
+	DefaultImagePullSecrets []string
+
+	// CloudEventsSinkURL is the HTTP(S) URL CloudEvents about lifecycle
+	// transitions of pipeline runs are sent to, unless a pipeline run
+	// specifies its own sink.
+	// If empty, CloudEvents are sent only for pipeline runs specifying
+	// a sink.
+	CloudEventsSinkURL string
+
+	// JenkinsfileRunnerExitCodeResults maps exit codes of the Jenkinsfile
+	// Runner to the result of the pipeline run.
+	// Exit codes not contained result in the default result for the
+	// respective exit code.
+	JenkinsfileRunnerExitCodeResults map[int32]api.Result
+
+	// FailureResults maps reasons of resource-related run failures,
+	// like `OOMKilled`, to the result of the affected pipeline runs.
+	// Reasons not contained use the default result `error_resources`.
+	FailureResults map[string]api.Result
+
+	// LogTailLines is the maximum number of lines at the end of the
+	// Jenkinsfile Runner log captured in the status of failed pipeline
+	// runs.
+	// If zero, no log tail is captured.
+	LogTailLines int64
+
+	// LogTailMaxBytes is the maximum size in bytes of the log tail
+	// captured in the status of failed pipeline runs.
+	// If zero, a default is used.
+	LogTailMaxBytes int64
 }
 
 type configDataMap map[string]string
@@ -115,6 +169,17 @@ func (cd configDataMap) parseInt64(key string) (*int64, error) {
 	return nil, nil
 }
 
+func (cd configDataMap) parseNonNegativeInt64(key string) (int64, error) {
+	intVal, err := cd.parseInt64(key)
+	if err != nil || intVal == nil {
+		return 0, err
+	}
+	if *intVal < 0 {
+		return 0, wrapParseError(errors.New("must not be negative"), key, cd[key])
+	}
+	return *intVal, nil
+}
+
 func (cd configDataMap) parseDuration(key string) (*metav1.Duration, error) {
 	if strVal, ok := cd[key]; ok && strVal != "" {
 		d, err := time.ParseDuration(strVal)
@@ -126,8 +191,125 @@ func (cd configDataMap) parseDuration(key string) (*metav1.Duration, error) {
 	return nil, nil
 }
 
+func (cd configDataMap) parseHTTPURL(key string) (string, error) {
+	if strVal, ok := cd[key]; ok && strVal != "" {
+		if err := ValidateHTTPURL(strVal); err != nil {
+			return "", wrapParseError(err, key, strVal)
+		}
+		return strVal, nil
+	}
+	return "", nil
+}
+
+// ValidateHTTPURL returns an error if the given string is not an absolute
+// HTTP or HTTPS URL.
+func ValidateHTTPURL(strVal string) error {
+	u, err := url.Parse(strVal)
+	if err != nil {
+		return err
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("not an absolute HTTP(S) URL")
+	}
+	return nil
+}
+
+func (cd configDataMap) parseSecretTypes(key string) []corev1.SecretType {
+	var secretTypes []corev1.SecretType
+	for _, item := range cd.parseList(key) {
+		secretTypes = append(secretTypes, corev1.SecretType(item))
+	}
+	return secretTypes
+}
+
+func (cd configDataMap) parseList(key string) []string {
+	var items []string
+	for _, item := range strings.FieldsFunc(cd[key], isListSeparator) {
+		items = append(items, item)
+	}
+	return items
+}
+
+// failureResultValues are the results failure reasons can be mapped to.
+var failureResultValues = map[api.Result]bool{
+	api.ResultErrorInfra:     true,
+	api.ResultErrorContent:   true,
+	api.ResultErrorConfig:    true,
+	api.ResultErrorResources: true,
+	api.ResultTimeout:        true,
+}
+
+// exitCodeResultValues are the results exit codes can be mapped to.
+var exitCodeResultValues = map[api.Result]bool{
+	api.ResultSuccess:        true,
+	api.ResultUnstable:       true,
+	api.ResultErrorInfra:     true,
+	api.ResultErrorContent:   true,
+	api.ResultErrorConfig:    true,
+	api.ResultErrorResources: true,
+	api.ResultTimeout:        true,
+}
+
+// parseFailureResults parses a list of `<reason>=<result>` pairs.
+func (cd configDataMap) parseFailureResults(key string) (map[string]api.Result, error) {
+	return cd.parseResultPairs(key, failureResultValues)
+}
+
+// parseExitCodeResults parses a list of `<exit code>=<result>` pairs.
+func (cd configDataMap) parseExitCodeResults(key string) (map[int32]api.Result, error) {
+	pairs, err := cd.parseResultPairs(key, exitCodeResultValues)
+	if err != nil || pairs == nil {
+		return nil, err
+	}
+	exitCodeResults := make(map[int32]api.Result, len(pairs))
+	for exitCodeStr, result := range pairs {
+		exitCode, err := strconv.ParseInt(exitCodeStr, 10, 32)
+		if err != nil {
+			return nil, wrapParseError(errors.Errorf("invalid exit code %q", exitCodeStr), key, cd[key])
+		}
+		exitCodeResults[int32(exitCode)] = result
+	}
+	return exitCodeResults, nil
+}
+
+// parseResultPairs parses a list of `<name>=<result>` pairs.
+// Results not contained in `validResults` are rejected.
+func (cd configDataMap) parseResultPairs(key string, validResults map[api.Result]bool) (map[string]api.Result, error) {
+	items := cd.parseList(key)
+	if len(items) == 0 {
+		return nil, nil
+	}
+	pairs := make(map[string]api.Result, len(items))
+	for _, item := range items {
+		name, result, found := strings.Cut(item, "=")
+		if !found || name == "" {
+			return nil, wrapParseError(errors.Errorf("invalid mapping %q", item), key, cd[key])
+		}
+		if !validResults[api.Result(result)] {
+			return nil, wrapParseError(errors.Errorf("invalid result %q for %q", result, name), key, cd[key])
+		}
+		pairs[name] = api.Result(result)
+	}
+	return pairs, nil
+}
+
+func isListSeparator(r rune) bool {
+	return r == ',' || unicode.IsSpace(r)
+}
+
 // LoadPipelineRunsConfig loads the pipeline run's configuration and returns it.
 func LoadPipelineRunsConfig(ctx context.Context, clientFactory k8s.ClientFactory) (*PipelineRunsConfigStruct, error) {
+	configMapIfce := clientFactory.CoreV1().ConfigMaps(system.Namespace())
+	return loadPipelineRunsConfig(func(configMapName string) (*corev1.ConfigMap, error) {
+		return configMapIfce.Get(ctx, configMapName, metav1.GetOptions{})
+	})
+}
+
+// configMapGetter returns the ConfigMap with the given name in the system
+// namespace. If the ConfigMap does not exist, it returns a NotFound error.
+type configMapGetter func(configMapName string) (*corev1.ConfigMap, error)
+
+func loadPipelineRunsConfig(getConfigMap configMapGetter) (*PipelineRunsConfigStruct, error) {
 	dest := &PipelineRunsConfigStruct{}
 
 	for _, p := range []struct {
@@ -147,9 +329,8 @@ func LoadPipelineRunsConfig(ctx context.Context, clientFactory k8s.ClientFactory
 		},
 	} {
 		err := processConfigMap(
-			ctx,
 			p.configMapName, p.optional, p.processFunc,
-			dest, clientFactory,
+			dest, getConfigMap,
 		)
 		if err != nil {
 			return nil, err
@@ -173,28 +354,27 @@ with contextual information.
 It gets passed to `processFunc`.
 */
 func processConfigMap(
-	ctx context.Context,
 	configMapName string,
 	optional bool,
 	processFunc func(configDataMap, *PipelineRunsConfigStruct) error,
 	dest *PipelineRunsConfigStruct,
-	clientFactory k8s.ClientFactory,
+	getConfigMap configMapGetter,
 ) error {
 
 	wrapError := func(cause error) error {
-		return errors.Wrapf(cause,
-			"invalid configuration: ConfigMap %q in namespace %q",
-			configMapName,
-			system.Namespace(),
-		)
+		return &configMapError{
+			configMapName: configMapName,
+			cause:         cause,
+		}
 	}
 
-	configMapIfce := clientFactory.CoreV1().ConfigMaps(system.Namespace())
-
 	var err error
-	configMap, err := configMapIfce.Get(ctx, configMapName, metav1.GetOptions{})
-	if err != nil && !k8serrors.IsNotFound(err) {
-		return withRecoverability(wrapError(err), true)
+	configMap, err := getConfigMap(configMapName)
+	if err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return withRecoverability(wrapError(err), true)
+		}
+		configMap = nil
 	}
 
 	if configMap != nil {
@@ -209,6 +389,32 @@ func processConfigMap(
 	return nil
 }
 
+// configMapError is an error related to a certain ConfigMap in the
+// system namespace.
+type configMapError struct {
+	configMapName string
+	cause         error
+}
+
+func (e *configMapError) Error() string {
+	return fmt.Sprintf(
+		"invalid configuration: ConfigMap %q in namespace %q: %s",
+		e.configMapName,
+		system.Namespace(),
+		e.cause.Error(),
+	)
+}
+
+// Cause returns the cause of the error as required by
+// `github.com/pkg/errors`.
+func (e *configMapError) Cause() error {
+	return e.cause
+}
+
+func (e *configMapError) Unwrap() error {
+	return e.cause
+}
+
 func wrapParseError(cause error, key, strVal string) error {
 	return errors.Wrapf(cause,
 		"key %q: cannot parse value %q",
@@ -224,6 +430,8 @@ func processMainConfig(configData configDataMap, dest *PipelineRunsConfigStruct)
 	dest.JenkinsfileRunnerImagePullPolicy = configData[mainConfigKeyImagePullPolicy]
 	dest.TektonTaskName = configData[mainConfigKeyTektonTaskName]
 	dest.TektonTaskNamespace = configData[mainConfigKeyTektonTaskNamespace]
+	dest.SecretMaskingExcludedSecretTypes = configData.parseSecretTypes(mainConfigKeyMaskingExcludedTypes)
+	dest.DefaultImagePullSecrets = configData.parseList(mainConfigKeyDefaultPullSecrets)
 
 	var err error
 
@@ -257,6 +465,31 @@ func processMainConfig(configData configDataMap, dest *PipelineRunsConfigStruct)
 		return err
 	}
 
+	if dest.CloudEventsSinkURL, err =
+		configData.parseHTTPURL(mainConfigKeyCloudEventsSinkURL); err != nil {
+		return err
+	}
+
+	if dest.JenkinsfileRunnerExitCodeResults, err =
+		configData.parseExitCodeResults(mainConfigKeyExitCodeResults); err != nil {
+		return err
+	}
+
+	if dest.FailureResults, err =
+		configData.parseFailureResults(mainConfigKeyFailureResults); err != nil {
+		return err
+	}
+
+	if dest.LogTailLines, err =
+		configData.parseNonNegativeInt64(mainConfigKeyLogTailLines); err != nil {
+		return err
+	}
+
+	if dest.LogTailMaxBytes, err =
+		configData.parseNonNegativeInt64(mainConfigKeyLogTailMaxBytes); err != nil {
+		return err
+	}
+
 	return nil
 }
 
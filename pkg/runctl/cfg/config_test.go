@@ -8,6 +8,7 @@ import (
 	"testing"
 	"time"
 
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
 	serrors "github.com/SAP/stewardci-core/pkg/errors"
 	featureflag "github.com/SAP/stewardci-core/pkg/featureflag"
 	featureflagtesting "github.com/SAP/stewardci-core/pkg/featureflag/testing"
@@ -206,6 +207,13 @@ func Test_LoadPipelineRunsConfig_CompleteConfig(t *testing.T) {
 				mainConfigKeyTektonTaskName:       "taskName1",
 				mainConfigKeyTektonTaskNamespace:  "taskNamespace1",
 				mainConfigKeyCustomLoggingDetails: "[{logKey: logKey1, kind: label, spec: {key: label1}}]",
+				mainConfigKeyMaskingExcludedTypes: "type1,type2",
+				mainConfigKeyDefaultPullSecrets:   "pullSecret1,pullSecret2",
+				mainConfigKeyCloudEventsSinkURL:   "https://sink1.example.com/events",
+				mainConfigKeyFailureResults:       "OOMKilled=error_content, Evicted=error_infra",
+				mainConfigKeyExitCodeResults:      "2=error_content 4=unstable 5=error_content",
+				mainConfigKeyLogTailLines:         "50",
+				mainConfigKeyLogTailMaxBytes:      "4096",
 				"someKeyThatShouldBeIgnored":      "34957349",
 			},
 		),
@@ -246,6 +254,21 @@ func Test_LoadPipelineRunsConfig_CompleteConfig(t *testing.T) {
 		},
 		TektonTaskName:      "taskName1",
 		TektonTaskNamespace: "taskNamespace1",
+
+		SecretMaskingExcludedSecretTypes: []corev1.SecretType{"type1", "type2"},
+		DefaultImagePullSecrets:          []string{"pullSecret1", "pullSecret2"},
+		CloudEventsSinkURL:               "https://sink1.example.com/events",
+		JenkinsfileRunnerExitCodeResults: map[int32]api.Result{
+			2: api.ResultErrorContent,
+			4: api.ResultUnstable,
+			5: api.ResultErrorContent,
+		},
+		FailureResults: map[string]api.Result{
+			"OOMKilled": api.ResultErrorContent,
+			"Evicted":   api.ResultErrorInfra,
+		},
+		LogTailLines:    50,
+		LogTailMaxBytes: 4096,
 	}
 	g.Expect(resultConfig).To(Equal(expectedConfig))
 }
@@ -272,6 +295,28 @@ func Test_LoadPipelineRunsConfig_InvalidValues(t *testing.T) {
 		{mainConfigKeyTimeoutWait, "1a"},
 
 		{mainConfigKeyCustomLoggingDetails, "a"},
+
+		{mainConfigKeyCloudEventsSinkURL, "sink1"},
+		{mainConfigKeyCloudEventsSinkURL, "ftp://sink1"},
+		{mainConfigKeyCloudEventsSinkURL, "http://"},
+		{mainConfigKeyCloudEventsSinkURL, "http://a b"},
+
+		{mainConfigKeyFailureResults, "OOMKilled"},
+		{mainConfigKeyFailureResults, "=error_content"},
+		{mainConfigKeyFailureResults, "OOMKilled=success"},
+		{mainConfigKeyFailureResults, "OOMKilled=foo"},
+		{mainConfigKeyFailureResults, "OOMKilled=unstable"},
+
+		{mainConfigKeyExitCodeResults, "4"},
+		{mainConfigKeyExitCodeResults, "a=unstable"},
+		{mainConfigKeyExitCodeResults, "4294967296=unstable"},
+		{mainConfigKeyExitCodeResults, "4=aborted"},
+
+		{mainConfigKeyLogTailLines, "a"},
+		{mainConfigKeyLogTailLines, "-1"},
+
+		{mainConfigKeyLogTailMaxBytes, "a"},
+		{mainConfigKeyLogTailMaxBytes, "-1"},
 	} {
 		t.Run(strconv.Itoa(i), func(t *testing.T) {
 			tc := tc // capture current value before going parallel
@@ -348,6 +393,9 @@ func Test_processMainConfig(t *testing.T) {
 				mainConfigKeyPSCRunAsGroup:   "2222",
 				mainConfigKeyPSCFSGroup:      "3333",
 
+				mainConfigKeyMaskingExcludedTypes: " type1, type2\ntype3 ,, ",
+				mainConfigKeyDefaultPullSecrets:   "pullSecret1 pullSecret2",
+
 				"someKeyThatShouldBeIgnored": "34957349",
 			},
 			&PipelineRunsConfigStruct{
@@ -361,6 +409,9 @@ func Test_processMainConfig(t *testing.T) {
 				JenkinsfileRunnerPodSecurityContextRunAsUser:  int64Ptr(1111),
 				JenkinsfileRunnerPodSecurityContextRunAsGroup: int64Ptr(2222),
 				JenkinsfileRunnerPodSecurityContextFSGroup:    int64Ptr(3333),
+
+				SecretMaskingExcludedSecretTypes: []corev1.SecretType{"type1", "type2", "type3"},
+				DefaultImagePullSecrets:          []string{"pullSecret1", "pullSecret2"},
 			},
 		},
 		{
@@ -376,6 +427,9 @@ func Test_processMainConfig(t *testing.T) {
 				mainConfigKeyPSCRunAsUser:    "",
 				mainConfigKeyPSCRunAsGroup:   "",
 				mainConfigKeyPSCFSGroup:      "",
+
+				mainConfigKeyMaskingExcludedTypes: "",
+				mainConfigKeyDefaultPullSecrets:   "",
 			},
 			&PipelineRunsConfigStruct{},
 		},
@@ -33,6 +33,16 @@ func NewContext(ctx context.Context, factory k8s.ClientFactory) context.Context
 	return context.WithValue(ctx, contextKey{}, loader)
 }
 
+// NewContextWithStore creates a new context with a store providing the
+// pipeline run configuration. The configuration is taken from the
+// store's current snapshot when it is accessed.
+func NewContextWithStore(ctx context.Context, store *Store) context.Context {
+	loader := &configLoader{
+		store: store,
+	}
+	return context.WithValue(ctx, contextKey{}, loader)
+}
+
 // NewContextWithConfig creates a context containing a pipeline run configuration
 func NewContextWithConfig(ctx context.Context, config *PipelineRunsConfigStruct) context.Context {
 	loader := &configLoader{
@@ -43,6 +53,7 @@ func NewContextWithConfig(ctx context.Context, config *PipelineRunsConfigStruct)
 
 type configLoader struct {
 	factory k8s.ClientFactory
+	store   *Store
 	config  *PipelineRunsConfigStruct
 }
 
@@ -50,5 +61,8 @@ func (c *configLoader) loadConfig(ctx context.Context) (*PipelineRunsConfigStruc
 	if c.config != nil {
 		return c.config, nil
 	}
+	if c.store != nil {
+		return c.store.Snapshot().PipelineRunsConfig()
+	}
 	return LoadPipelineRunsConfig(ctx, c.factory)
 }
@@ -0,0 +1,139 @@
+package cfg
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+)
+
+// NewEffectiveConfig resolves the configuration relevant for a pipeline run
+// with the given spec.
+// Settings in the spec take precedence over the pipeline runs configuration.
+func NewEffectiveConfig(config *PipelineRunsConfigStruct, spec *api.PipelineSpec) *api.EffectiveConfig {
+	effectiveConfig := &api.EffectiveConfig{
+		JenkinsfileRunnerImage:                        config.JenkinsfileRunnerImage,
+		JenkinsfileRunnerImagePullPolicy:              config.JenkinsfileRunnerImagePullPolicy,
+		JenkinsfileRunnerPodSecurityContextRunAsUser:  copyInt64Ptr(config.JenkinsfileRunnerPodSecurityContextRunAsUser),
+		JenkinsfileRunnerPodSecurityContextRunAsGroup: copyInt64Ptr(config.JenkinsfileRunnerPodSecurityContextRunAsGroup),
+		JenkinsfileRunnerPodSecurityContextFSGroup:    copyInt64Ptr(config.JenkinsfileRunnerPodSecurityContextFSGroup),
+		Timeout:                          config.Timeout.DeepCopy(),
+		TimeoutWait:                      config.TimeoutWait.DeepCopy(),
+		NetworkProfile:                   config.DefaultNetworkProfile,
+		LimitRangeHash:                   hashManifest(config.LimitRange),
+		ResourceQuotaHash:                hashManifest(config.ResourceQuota),
+		TektonTaskName:                   config.TektonTaskName,
+		TektonTaskNamespace:              config.TektonTaskNamespace,
+		CloudEventsSinkURL:               config.CloudEventsSinkURL,
+		JenkinsfileRunnerExitCodeResults: formatExitCodeResults(config.JenkinsfileRunnerExitCodeResults),
+		FailureResults:                   copyResults(config.FailureResults),
+		LogTailLines:                     config.LogTailLines,
+		LogTailMaxBytes:                  config.LogTailMaxBytes,
+	}
+
+	if spec != nil {
+		if jfrSpec := spec.JenkinsfileRunner; jfrSpec != nil && jfrSpec.Image != "" {
+			effectiveConfig.JenkinsfileRunnerImage = jfrSpec.Image
+			effectiveConfig.JenkinsfileRunnerImagePullPolicy = jfrSpec.ImagePullPolicy
+			if effectiveConfig.JenkinsfileRunnerImagePullPolicy == "" {
+				effectiveConfig.JenkinsfileRunnerImagePullPolicy = "IfNotPresent"
+			}
+		}
+		if spec.Timeout != nil {
+			effectiveConfig.Timeout = spec.Timeout.DeepCopy()
+		}
+		if spec.Profiles != nil && spec.Profiles.Network != "" {
+			effectiveConfig.NetworkProfile = spec.Profiles.Network
+		}
+	}
+
+	if effectiveConfig.NetworkProfile != "" {
+		effectiveConfig.NetworkPolicyHash = hashManifest(config.NetworkPolicies[effectiveConfig.NetworkProfile])
+	}
+
+	return effectiveConfig
+}
+
+// FromEffectiveConfig returns a pipeline runs configuration containing the
+// settings of the given effective configuration of a pipeline run.
+// Settings not recorded in the effective configuration, e.g. the manifests
+// applied to the run namespace, are not set.
+func FromEffectiveConfig(effectiveConfig *api.EffectiveConfig) *PipelineRunsConfigStruct {
+	return &PipelineRunsConfigStruct{
+		Timeout:                          effectiveConfig.Timeout.DeepCopy(),
+		TimeoutWait:                      effectiveConfig.TimeoutWait.DeepCopy(),
+		JenkinsfileRunnerImage:           effectiveConfig.JenkinsfileRunnerImage,
+		JenkinsfileRunnerImagePullPolicy: effectiveConfig.JenkinsfileRunnerImagePullPolicy,
+		JenkinsfileRunnerPodSecurityContextRunAsUser:  copyInt64Ptr(effectiveConfig.JenkinsfileRunnerPodSecurityContextRunAsUser),
+		JenkinsfileRunnerPodSecurityContextRunAsGroup: copyInt64Ptr(effectiveConfig.JenkinsfileRunnerPodSecurityContextRunAsGroup),
+		JenkinsfileRunnerPodSecurityContextFSGroup:    copyInt64Ptr(effectiveConfig.JenkinsfileRunnerPodSecurityContextFSGroup),
+		DefaultNetworkProfile:                         effectiveConfig.NetworkProfile,
+		TektonTaskName:                                effectiveConfig.TektonTaskName,
+		TektonTaskNamespace:                           effectiveConfig.TektonTaskNamespace,
+		CloudEventsSinkURL:                            effectiveConfig.CloudEventsSinkURL,
+		JenkinsfileRunnerExitCodeResults:              parseExitCodeResults(effectiveConfig.JenkinsfileRunnerExitCodeResults),
+		FailureResults:                                copyResults(effectiveConfig.FailureResults),
+		LogTailLines:                                  effectiveConfig.LogTailLines,
+		LogTailMaxBytes:                               effectiveConfig.LogTailMaxBytes,
+	}
+}
+
+// formatExitCodeResults converts the keys of the given exit code mapping
+// to decimal strings.
+func formatExitCodeResults(exitCodeResults map[int32]api.Result) map[string]api.Result {
+	if len(exitCodeResults) == 0 {
+		return nil
+	}
+	result := make(map[string]api.Result, len(exitCodeResults))
+	for exitCode, value := range exitCodeResults {
+		result[strconv.FormatInt(int64(exitCode), 10)] = value
+	}
+	return result
+}
+
+// parseExitCodeResults is the inverse of formatExitCodeResults.
+// Keys which are not decimal exit codes are ignored.
+func parseExitCodeResults(exitCodeResults map[string]api.Result) map[int32]api.Result {
+	if len(exitCodeResults) == 0 {
+		return nil
+	}
+	result := make(map[int32]api.Result, len(exitCodeResults))
+	for key, value := range exitCodeResults {
+		exitCode, err := strconv.ParseInt(key, 10, 32)
+		if err != nil {
+			continue
+		}
+		result[int32(exitCode)] = value
+	}
+	return result
+}
+
+func copyResults(results map[string]api.Result) map[string]api.Result {
+	if len(results) == 0 {
+		return nil
+	}
+	result := make(map[string]api.Result, len(results))
+	for key, value := range results {
+		result[key] = value
+	}
+	return result
+}
+
+// hashManifest returns a hash of the given manifest or an empty string if
+// the manifest is empty.
+func hashManifest(manifest string) string {
+	if manifest == "" {
+		return ""
+	}
+	hash := sha256.Sum256([]byte(manifest))
+	return hex.EncodeToString(hash[:])[:hashLength]
+}
+
+func copyInt64Ptr(ptr *int64) *int64 {
+	if ptr == nil {
+		return nil
+	}
+	value := *ptr
+	return &value
+}
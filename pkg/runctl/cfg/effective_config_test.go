@@ -0,0 +1,234 @@
+package cfg
+
+import (
+	"testing"
+	"time"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/utils"
+	"gotest.tools/v3/assert"
+)
+
+func newTestPipelineRunsConfig() *PipelineRunsConfigStruct {
+	return &PipelineRunsConfigStruct{
+		Timeout:                          utils.Metav1Duration(2 * time.Hour),
+		TimeoutWait:                      utils.Metav1Duration(5 * time.Minute),
+		LimitRange:                       "limitRange1",
+		ResourceQuota:                    "resourceQuota1",
+		JenkinsfileRunnerImage:           "image1",
+		JenkinsfileRunnerImagePullPolicy: "Always",
+		JenkinsfileRunnerPodSecurityContextRunAsUser:  int64Ptr(1000),
+		JenkinsfileRunnerPodSecurityContextRunAsGroup: int64Ptr(1001),
+		JenkinsfileRunnerPodSecurityContextFSGroup:    int64Ptr(1002),
+		DefaultNetworkProfile:                         "profile1",
+		NetworkPolicies: map[string]string{
+			"profile1": "networkPolicy1",
+			"profile2": "networkPolicy2",
+		},
+		TektonTaskName:      "task1",
+		TektonTaskNamespace: "taskNamespace1",
+		CloudEventsSinkURL:  "https://sink.example.com",
+		JenkinsfileRunnerExitCodeResults: map[int32]api.Result{
+			3: api.ResultUnstable,
+		},
+		FailureResults: map[string]api.Result{
+			"OOMKilled": api.ResultErrorContent,
+		},
+		LogTailLines:    50,
+		LogTailMaxBytes: 4096,
+	}
+}
+
+func Test_NewEffectiveConfig_FromConfig(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	config := newTestPipelineRunsConfig()
+
+	// EXERCISE
+	result := NewEffectiveConfig(config, &api.PipelineSpec{})
+
+	// VERIFY
+	assert.DeepEqual(t, &api.EffectiveConfig{
+		JenkinsfileRunnerImage:                        "image1",
+		JenkinsfileRunnerImagePullPolicy:              "Always",
+		JenkinsfileRunnerPodSecurityContextRunAsUser:  int64Ptr(1000),
+		JenkinsfileRunnerPodSecurityContextRunAsGroup: int64Ptr(1001),
+		JenkinsfileRunnerPodSecurityContextFSGroup:    int64Ptr(1002),
+		Timeout:             utils.Metav1Duration(2 * time.Hour),
+		TimeoutWait:         utils.Metav1Duration(5 * time.Minute),
+		NetworkProfile:      "profile1",
+		NetworkPolicyHash:   hashManifest("networkPolicy1"),
+		LimitRangeHash:      hashManifest("limitRange1"),
+		ResourceQuotaHash:   hashManifest("resourceQuota1"),
+		TektonTaskName:      "task1",
+		TektonTaskNamespace: "taskNamespace1",
+		CloudEventsSinkURL:  "https://sink.example.com",
+		JenkinsfileRunnerExitCodeResults: map[string]api.Result{
+			"3": api.ResultUnstable,
+		},
+		FailureResults: map[string]api.Result{
+			"OOMKilled": api.ResultErrorContent,
+		},
+		LogTailLines:    50,
+		LogTailMaxBytes: 4096,
+	}, result)
+}
+
+func Test_NewEffectiveConfig_SpecOverridesConfig(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name                    string
+		spec                    *api.PipelineSpec
+		expectedImage           string
+		expectedImagePullPolicy string
+		expectedTimeout         time.Duration
+		expectedNetworkProfile  string
+		expectedNetworkHash     string
+	}{
+		{
+			name: "image_without_pull_policy",
+			spec: &api.PipelineSpec{
+				JenkinsfileRunner: &api.JenkinsfileRunnerSpec{Image: "image2"},
+			},
+			expectedImage:           "image2",
+			expectedImagePullPolicy: "IfNotPresent",
+			expectedTimeout:         2 * time.Hour,
+			expectedNetworkProfile:  "profile1",
+			expectedNetworkHash:     hashManifest("networkPolicy1"),
+		},
+		{
+			name: "image_with_pull_policy",
+			spec: &api.PipelineSpec{
+				JenkinsfileRunner: &api.JenkinsfileRunnerSpec{Image: "image2", ImagePullPolicy: "Never"},
+			},
+			expectedImage:           "image2",
+			expectedImagePullPolicy: "Never",
+			expectedTimeout:         2 * time.Hour,
+			expectedNetworkProfile:  "profile1",
+			expectedNetworkHash:     hashManifest("networkPolicy1"),
+		},
+		{
+			name: "pull_policy_without_image",
+			spec: &api.PipelineSpec{
+				JenkinsfileRunner: &api.JenkinsfileRunnerSpec{ImagePullPolicy: "Never"},
+			},
+			expectedImage:           "image1",
+			expectedImagePullPolicy: "Always",
+			expectedTimeout:         2 * time.Hour,
+			expectedNetworkProfile:  "profile1",
+			expectedNetworkHash:     hashManifest("networkPolicy1"),
+		},
+		{
+			name: "timeout",
+			spec: &api.PipelineSpec{
+				Timeout: utils.Metav1Duration(10 * time.Minute),
+			},
+			expectedImage:           "image1",
+			expectedImagePullPolicy: "Always",
+			expectedTimeout:         10 * time.Minute,
+			expectedNetworkProfile:  "profile1",
+			expectedNetworkHash:     hashManifest("networkPolicy1"),
+		},
+		{
+			name: "network_profile",
+			spec: &api.PipelineSpec{
+				Profiles: &api.Profiles{Network: "profile2"},
+			},
+			expectedImage:           "image1",
+			expectedImagePullPolicy: "Always",
+			expectedTimeout:         2 * time.Hour,
+			expectedNetworkProfile:  "profile2",
+			expectedNetworkHash:     hashManifest("networkPolicy2"),
+		},
+		{
+			name: "unknown_network_profile",
+			spec: &api.PipelineSpec{
+				Profiles: &api.Profiles{Network: "unknown"},
+			},
+			expectedImage:           "image1",
+			expectedImagePullPolicy: "Always",
+			expectedTimeout:         2 * time.Hour,
+			expectedNetworkProfile:  "unknown",
+			expectedNetworkHash:     "",
+		},
+	} {
+		tc := tc // capture current value before going parallel
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// EXERCISE
+			result := NewEffectiveConfig(newTestPipelineRunsConfig(), tc.spec)
+
+			// VERIFY
+			assert.Equal(t, tc.expectedImage, result.JenkinsfileRunnerImage)
+			assert.Equal(t, tc.expectedImagePullPolicy, result.JenkinsfileRunnerImagePullPolicy)
+			assert.Equal(t, tc.expectedTimeout, result.Timeout.Duration)
+			assert.Equal(t, tc.expectedNetworkProfile, result.NetworkProfile)
+			assert.Equal(t, tc.expectedNetworkHash, result.NetworkPolicyHash)
+		})
+	}
+}
+
+func Test_NewEffectiveConfig_EmptyConfig(t *testing.T) {
+	t.Parallel()
+
+	// EXERCISE
+	result := NewEffectiveConfig(&PipelineRunsConfigStruct{}, nil)
+
+	// VERIFY
+	assert.DeepEqual(t, &api.EffectiveConfig{}, result)
+}
+
+func Test_FromEffectiveConfig(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	config := newTestPipelineRunsConfig()
+	effectiveConfig := NewEffectiveConfig(config, &api.PipelineSpec{})
+
+	// EXERCISE
+	result := FromEffectiveConfig(effectiveConfig)
+
+	// VERIFY
+	assert.DeepEqual(t, &PipelineRunsConfigStruct{
+		Timeout:                          utils.Metav1Duration(2 * time.Hour),
+		TimeoutWait:                      utils.Metav1Duration(5 * time.Minute),
+		JenkinsfileRunnerImage:           "image1",
+		JenkinsfileRunnerImagePullPolicy: "Always",
+		JenkinsfileRunnerPodSecurityContextRunAsUser:  int64Ptr(1000),
+		JenkinsfileRunnerPodSecurityContextRunAsGroup: int64Ptr(1001),
+		JenkinsfileRunnerPodSecurityContextFSGroup:    int64Ptr(1002),
+		DefaultNetworkProfile:                         "profile1",
+		TektonTaskName:                                "task1",
+		TektonTaskNamespace:                           "taskNamespace1",
+		CloudEventsSinkURL:                            "https://sink.example.com",
+		JenkinsfileRunnerExitCodeResults: map[int32]api.Result{
+			3: api.ResultUnstable,
+		},
+		FailureResults: map[string]api.Result{
+			"OOMKilled": api.ResultErrorContent,
+		},
+		LogTailLines:    50,
+		LogTailMaxBytes: 4096,
+	}, result)
+}
+
+func Test_FromEffectiveConfig_InvalidExitCodeIgnored(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	effectiveConfig := &api.EffectiveConfig{
+		JenkinsfileRunnerExitCodeResults: map[string]api.Result{
+			"3":   api.ResultUnstable,
+			"foo": api.ResultErrorConfig,
+		},
+	}
+
+	// EXERCISE
+	result := FromEffectiveConfig(effectiveConfig)
+
+	// VERIFY
+	assert.DeepEqual(t, map[int32]api.Result{3: api.ResultUnstable}, result.JenkinsfileRunnerExitCodeResults)
+}
@@ -0,0 +1,30 @@
+package cfg
+
+import (
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	yamlserial "k8s.io/apimachinery/pkg/runtime/serializer/yaml"
+)
+
+// DecodeManifest decodes a configured manifest in YAML format and verifies
+// that it denotes an object of the expected group and kind.
+// resourceDisplayName is used in error messages.
+func DecodeManifest(manifest string, expectedGroupKind schema.GroupKind, resourceDisplayName string) (*unstructured.Unstructured, error) {
+	// We don't assume a specific resource version so that users can configure
+	// whatever the K8s apiserver understands.
+	yamlSerializer := yamlserial.NewDecodingSerializer(unstructured.UnstructuredJSONScheme)
+	o, err := runtime.Decode(yamlSerializer, []byte(manifest))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to decode configured %s", resourceDisplayName)
+	}
+	gvk := o.GetObjectKind().GroupVersionKind()
+	if gvk.GroupKind() != expectedGroupKind {
+		return nil, errors.Errorf(
+			"configured %s does not denote a %q but a %q",
+			resourceDisplayName, expectedGroupKind.String(), gvk.GroupKind().String(),
+		)
+	}
+	return o.(*unstructured.Unstructured), nil
+}
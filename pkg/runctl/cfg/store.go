@@ -0,0 +1,291 @@
+package cfg
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/client/clientset/versioned/scheme"
+	"github.com/SAP/stewardci-core/pkg/featureflag"
+	"github.com/SAP/stewardci-core/pkg/maintenancemode"
+	"github.com/SAP/stewardci-core/pkg/runctl/metrics"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"knative.dev/pkg/system"
+)
+
+// hashLength is the number of hex characters of the configuration hash.
+const hashLength = 16
+
+// watchedConfigMapNames are the names of the ConfigMaps in the system
+// namespace the Store parses into snapshots.
+var watchedConfigMapNames = []string{
+	mainConfigMapName,
+	networkPoliciesConfigMapName,
+	api.MaintenanceModeConfigMapName,
+	featureFlagsConfigMapName,
+}
+
+// loadFailedEventReasons are the reasons of the events recording parse
+// errors, by name of the ConfigMap they relate to.
+var loadFailedEventReasons = map[string]string{
+	mainConfigMapName:                api.EventReasonLoadPipelineRunsConfigFailed,
+	networkPoliciesConfigMapName:     api.EventReasonLoadPipelineRunsConfigFailed,
+	api.MaintenanceModeConfigMapName: api.EventReasonLoadMaintenanceModeConfigFailed,
+	featureFlagsConfigMapName:        api.EventReasonLoadFeatureFlagsConfigFailed,
+}
+
+// Snapshot is the configuration parsed from the ConfigMaps at a certain
+// point in time. It must not be modified.
+type Snapshot struct {
+	pipelineRunsConfig    *PipelineRunsConfigStruct
+	pipelineRunsConfigErr error
+	maintenanceConfig     *maintenancemode.Config
+	hash                  string
+}
+
+// PipelineRunsConfig returns the pipeline runs configuration or the error
+// that occurred when parsing it.
+// The returned configuration must not be modified.
+func (s *Snapshot) PipelineRunsConfig() (*PipelineRunsConfigStruct, error) {
+	return s.pipelineRunsConfig, s.pipelineRunsConfigErr
+}
+
+// MaintenanceMode returns whether maintenance mode is enabled explicitly.
+func (s *Snapshot) MaintenanceMode() bool {
+	return s.maintenanceConfig.Enabled
+}
+
+// MaintenanceConfig returns the maintenance configuration. Invalid
+// maintenance windows are not contained.
+// The returned configuration must not be modified.
+func (s *Snapshot) MaintenanceConfig() *maintenancemode.Config {
+	return s.maintenanceConfig
+}
+
+// Hash returns a hash of the content of the configuration ConfigMaps.
+func (s *Snapshot) Hash() string {
+	return s.hash
+}
+
+// Store provides the configuration of the run controller from the
+// ConfigMaps in the system namespace.
+// It watches the ConfigMaps via an informer and parses them into a new
+// immutable Snapshot each time one of them changes. This avoids fetching
+// the ConfigMaps from the API server for each reconciliation.
+// Parse errors are reported as events on the ConfigMaps.
+type Store struct {
+	informer      cache.SharedIndexInformer
+	lister        corev1listers.ConfigMapNamespaceLister
+	eventRecorder record.EventRecorder
+	logger        logr.Logger
+
+	// updateMutex serializes snapshot updates.
+	updateMutex sync.Mutex
+	snapshot    atomic.Pointer[Snapshot]
+}
+
+// NewStore creates a new Store watching the ConfigMaps in the system
+// namespace.
+func NewStore(logger logr.Logger, client corev1client.CoreV1Interface, resyncPeriod time.Duration) *Store {
+	namespace := system.Namespace()
+	configMaps := client.ConfigMaps(namespace)
+	informer := cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				return configMaps.List(context.Background(), options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				return configMaps.Watch(context.Background(), options)
+			},
+		},
+		&corev1.ConfigMap{},
+		resyncPeriod,
+		cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
+	)
+
+	eventBroadcaster := record.NewBroadcaster()
+	eventBroadcaster.StartRecordingToSink(&corev1client.EventSinkImpl{Interface: client.Events(namespace)})
+
+	s := &Store{
+		informer:      informer,
+		lister:        corev1listers.NewConfigMapLister(informer.GetIndexer()).ConfigMaps(namespace),
+		eventRecorder: eventBroadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: "runController"}),
+		logger:        logger.WithName("configStore"),
+	}
+	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    s.onChange,
+		UpdateFunc: func(old, new interface{}) { s.onChange(new) },
+		DeleteFunc: s.onChange,
+	})
+	return s
+}
+
+// Start starts watching the ConfigMaps until stopCh is closed.
+func (s *Store) Start(stopCh <-chan struct{}) {
+	go s.informer.Run(stopCh)
+}
+
+// HasSynced returns true if the initial list of ConfigMaps has been
+// loaded.
+func (s *Store) HasSynced() bool {
+	return s.informer.HasSynced()
+}
+
+// Snapshot returns the current configuration snapshot.
+func (s *Store) Snapshot() *Snapshot {
+	if snapshot := s.snapshot.Load(); snapshot != nil {
+		return snapshot
+	}
+	// no ConfigMap event received yet
+	s.update()
+	return s.snapshot.Load()
+}
+
+func (s *Store) onChange(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	configMap, ok := obj.(*corev1.ConfigMap)
+	if !ok || !isWatchedConfigMap(configMap.GetName()) {
+		return
+	}
+	s.update()
+}
+
+func isWatchedConfigMap(name string) bool {
+	for _, watched := range watchedConfigMapNames {
+		if name == watched {
+			return true
+		}
+	}
+	return false
+}
+
+// update parses the ConfigMaps from the informer cache into a new snapshot.
+func (s *Store) update() {
+	s.updateMutex.Lock()
+	defer s.updateMutex.Unlock()
+
+	snapshot := &Snapshot{
+		hash: hashConfigMaps(s.lister),
+	}
+	if previous := s.snapshot.Load(); previous != nil && previous.hash == snapshot.hash {
+		return
+	}
+
+	snapshot.pipelineRunsConfig, snapshot.pipelineRunsConfigErr = loadPipelineRunsConfig(s.lister.Get)
+	if snapshot.pipelineRunsConfigErr != nil {
+		s.reportError(snapshot.pipelineRunsConfigErr)
+	}
+
+	maintenanceModeConfigMap, err := s.lister.Get(api.MaintenanceModeConfigMapName)
+	if err != nil {
+		maintenanceModeConfigMap = nil
+	}
+	var maintenanceConfigErr error
+	snapshot.maintenanceConfig, maintenanceConfigErr = maintenancemode.ParseConfigMap(maintenanceModeConfigMap)
+	if maintenanceConfigErr != nil {
+		s.reportError(&configMapError{
+			configMapName: api.MaintenanceModeConfigMapName,
+			cause:         maintenanceConfigErr,
+		})
+	}
+
+	featureFlagsErr := s.applyFeatureFlags()
+	if featureFlagsErr != nil {
+		s.reportError(featureFlagsErr)
+	}
+
+	valid := snapshot.pipelineRunsConfigErr == nil && maintenanceConfigErr == nil && featureFlagsErr == nil
+	s.snapshot.Store(snapshot)
+	metrics.ConfigInfo.Set(snapshot.hash, valid)
+	s.logger.Info("Loaded configuration",
+		"hash", snapshot.hash,
+		"valid", valid,
+		"maintenanceMode", snapshot.maintenanceConfig.Enabled,
+		"maintenanceWindows", len(snapshot.maintenanceConfig.Windows),
+	)
+}
+
+// applyFeatureFlags overrides the feature flags with the values from the
+// feature flags ConfigMap. If the ConfigMap does not exist, the values set
+// via environment variable or the defaults apply.
+func (s *Store) applyFeatureFlags() error {
+	value := ""
+	if configMap, err := s.lister.Get(featureFlagsConfigMapName); err == nil && configMap.DeletionTimestamp.IsZero() {
+		value = configMap.Data[featureFlagsConfigKeyFlags]
+	}
+	changed, err := featureflag.SetOverrides(value)
+	if changed {
+		s.logger.Info("Feature flags changed")
+		featureflag.Log(s.logger)
+	}
+	metrics.FeatureFlagsInfo.Set(featureflag.Values())
+	if err != nil {
+		return &configMapError{
+			configMapName: featureFlagsConfigMapName,
+			cause:         errors.Wrapf(err, "key %q", featureFlagsConfigKeyFlags),
+		}
+	}
+	return nil
+}
+
+// reportError logs the given parse error and records it as event on the
+// ConfigMap it relates to.
+func (s *Store) reportError(err error) {
+	s.logger.Error(err, "Invalid configuration")
+	for _, name := range watchedConfigMapNames {
+		configMap, getErr := s.lister.Get(name)
+		if getErr != nil || !errorRelatesToConfigMap(err, name) {
+			continue
+		}
+		s.eventRecorder.Event(configMap, corev1.EventTypeWarning, loadFailedEventReasons[name], err.Error())
+	}
+}
+
+func errorRelatesToConfigMap(err error, configMapName string) bool {
+	var configErr *configMapError
+	return errors.As(err, &configErr) && configErr.configMapName == configMapName
+}
+
+// hashConfigMaps returns a hash of the names and data of the watched
+// ConfigMaps.
+func hashConfigMaps(lister corev1listers.ConfigMapNamespaceLister) string {
+	hash := sha256.New()
+	for _, name := range watchedConfigMapNames {
+		configMap, err := lister.Get(name)
+		if err != nil {
+			// the lister only returns NotFound errors
+			continue
+		}
+		if !configMap.DeletionTimestamp.IsZero() {
+			// relevant for maintenance mode
+			hash.Write([]byte("deleting\x00"))
+		}
+		hash.Write([]byte(name + "\x00"))
+		keys := make([]string, 0, len(configMap.Data))
+		for key := range configMap.Data {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			hash.Write([]byte(key + "\x00" + configMap.Data[key] + "\x00"))
+		}
+		hash.Write([]byte("\x00"))
+	}
+	return hex.EncodeToString(hash.Sum(nil))[:hashLength]
+}
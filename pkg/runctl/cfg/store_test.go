@@ -0,0 +1,276 @@
+package cfg
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/featureflag"
+	"github.com/SAP/stewardci-core/pkg/k8s/fake"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	klog "k8s.io/klog/v2"
+)
+
+const storeTestTimeout = 10 * time.Second
+
+func startTestStore(t *testing.T, objects ...runtime.Object) (*Store, *fake.ClientFactory, *record.FakeRecorder) {
+	t.Helper()
+	cf := fake.NewClientFactory(objects...)
+	examinee := NewStore(klog.Background(), cf.CoreV1(), 0)
+	eventRecorder := record.NewFakeRecorder(10)
+	examinee.eventRecorder = eventRecorder
+	stopCh := make(chan struct{})
+	t.Cleanup(func() { close(stopCh) })
+	examinee.Start(stopCh)
+	waitForStore(t, "store to sync", examinee.HasSynced)
+	return examinee, cf, eventRecorder
+}
+
+func waitForStore(t *testing.T, what string, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(storeTestTimeout)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timeout waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func newMaintenanceModeConfigMap(enabled string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      api.MaintenanceModeConfigMapName,
+			Namespace: testSystemNamespaceName,
+		},
+		Data: map[string]string{api.MaintenanceModeKeyName: enabled},
+	}
+}
+
+func Test_Store_Snapshot_ValidConfig(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	examinee, _, eventRecorder := startTestStore(t,
+		newMainConfigMap(map[string]string{mainConfigKeyTimeout: "10m"}),
+		newNetworkPolicyConfigMap(map[string]string{
+			networkPoliciesConfigKeyDefault: "key1",
+			"key1":                          "policy1",
+		}),
+	)
+
+	// EXERCISE
+	snapshot := examinee.Snapshot()
+
+	// VERIFY
+	config, err := snapshot.PipelineRunsConfig()
+	assert.NilError(t, err)
+	assert.Equal(t, 10*time.Minute, config.Timeout.Duration)
+	assert.Equal(t, "key1", config.DefaultNetworkProfile)
+	assert.Assert(t, !snapshot.MaintenanceMode())
+	assert.Equal(t, hashLength, len(snapshot.Hash()))
+	assert.Equal(t, 0, len(eventRecorder.Events))
+}
+
+func Test_Store_Snapshot_UpdatedOnChange(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	networkPolicyConfigMap := newNetworkPolicyConfigMap(map[string]string{
+		networkPoliciesConfigKeyDefault: "key1",
+		"key1":                          "policy1",
+	})
+	examinee, cf, _ := startTestStore(t, networkPolicyConfigMap)
+	ctx := context.Background()
+	configMaps := cf.CoreV1().ConfigMaps(testSystemNamespaceName)
+	initial := examinee.Snapshot()
+
+	// EXERCISE
+	_, err := configMaps.Create(ctx, newMaintenanceModeConfigMap("true"), metav1.CreateOptions{})
+	assert.NilError(t, err)
+	waitForStore(t, "maintenance mode", func() bool { return examinee.Snapshot().MaintenanceMode() })
+
+	networkPolicyConfigMap.Data["key1"] = "policy2"
+	_, err = configMaps.Update(ctx, networkPolicyConfigMap, metav1.UpdateOptions{})
+	assert.NilError(t, err)
+	waitForStore(t, "updated network policy", func() bool {
+		config, err := examinee.Snapshot().PipelineRunsConfig()
+		return err == nil && config.NetworkPolicies["key1"] == "policy2"
+	})
+
+	// VERIFY
+	assert.Assert(t, examinee.Snapshot().Hash() != initial.Hash())
+	// previous snapshot is unchanged
+	config, err := initial.PipelineRunsConfig()
+	assert.NilError(t, err)
+	assert.Equal(t, "policy1", config.NetworkPolicies["key1"])
+	assert.Assert(t, !initial.MaintenanceMode())
+}
+
+func Test_Store_Snapshot_InvalidConfigIsReportedAsEvent(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	examinee, _, eventRecorder := startTestStore(t,
+		newMainConfigMap(map[string]string{mainConfigKeyTimeout: "invalid1"}),
+		newNetworkPolicyConfigMap(map[string]string{
+			networkPoliciesConfigKeyDefault: "key1",
+			"key1":                          "policy1",
+		}),
+	)
+
+	// EXERCISE
+	snapshot := examinee.Snapshot()
+
+	// VERIFY
+	config, err := snapshot.PipelineRunsConfig()
+	assert.Assert(t, config == nil)
+	assert.ErrorContains(t, err, `invalid configuration: ConfigMap "steward-pipelineruns" in namespace "steward-testing": key "timeout": cannot parse value "invalid1"`)
+	select {
+	case event := <-eventRecorder.Events:
+		assert.Assert(t, strings.HasPrefix(event, "Warning "+api.EventReasonLoadPipelineRunsConfigFailed+" "), event)
+	case <-time.After(storeTestTimeout):
+		t.Fatal("timeout waiting for event")
+	}
+}
+
+func Test_Store_Snapshot_MaintenanceWindows(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	maintenanceModeConfigMap := newMaintenanceModeConfigMap("false")
+	maintenanceModeConfigMap.Data[api.MaintenanceWindowsKeyName] = `[{name: w1, start: "2023-01-01T20:00:00Z", end: "2023-01-01T22:00:00Z"}]`
+	examinee, _, eventRecorder := startTestStore(t, maintenanceModeConfigMap)
+
+	// EXERCISE
+	snapshot := examinee.Snapshot()
+
+	// VERIFY
+	config := snapshot.MaintenanceConfig()
+	assert.Assert(t, !config.Enabled)
+	assert.Equal(t, 1, len(config.Windows))
+	assert.Equal(t, "w1", config.Windows[0].Name)
+	assert.Equal(t, 0, len(eventRecorder.Events))
+}
+
+func Test_Store_Snapshot_InvalidMaintenanceWindowsAreReportedAsEvent(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	maintenanceModeConfigMap := newMaintenanceModeConfigMap("true")
+	maintenanceModeConfigMap.Data[api.MaintenanceWindowsKeyName] = `[{name: w1}]`
+	examinee, _, eventRecorder := startTestStore(t, maintenanceModeConfigMap)
+
+	// EXERCISE
+	snapshot := examinee.Snapshot()
+
+	// VERIFY
+	config := snapshot.MaintenanceConfig()
+	assert.Assert(t, config.Enabled)
+	assert.Equal(t, 0, len(config.Windows))
+	select {
+	case event := <-eventRecorder.Events:
+		assert.Assert(t, strings.HasPrefix(event, "Warning "+api.EventReasonLoadMaintenanceModeConfigFailed+" "), event)
+		assert.Assert(t, strings.Contains(event, `window "w1": start and end are required`), event)
+	case <-time.After(storeTestTimeout):
+		t.Fatal("timeout waiting for event")
+	}
+}
+
+func Test_Store_FeatureFlagsAreOverridden(t *testing.T) {
+	// no parallel: changes global feature flags
+
+	// SETUP
+	t.Cleanup(func() { featureflag.SetOverrides("") })
+	featureFlagsConfigMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      featureFlagsConfigMapName,
+			Namespace: testSystemNamespaceName,
+		},
+		Data: map[string]string{featureFlagsConfigKeyFlags: "+Dummy"},
+	}
+	assert.Assert(t, !featureflag.Dummy.Enabled())
+
+	// EXERCISE
+	_, cf, _ := startTestStore(t, featureFlagsConfigMap)
+
+	// VERIFY
+	assert.Assert(t, featureflag.Dummy.Enabled())
+
+	// EXERCISE
+	err := cf.CoreV1().ConfigMaps(testSystemNamespaceName).Delete(context.Background(), featureFlagsConfigMapName, metav1.DeleteOptions{})
+	assert.NilError(t, err)
+
+	// VERIFY
+	waitForStore(t, "feature flag reset", func() bool { return !featureflag.Dummy.Enabled() })
+}
+
+func Test_Store_UnknownFeatureFlagsAreReportedAsEvent(t *testing.T) {
+	// no parallel: changes global feature flags
+
+	// SETUP
+	t.Cleanup(func() { featureflag.SetOverrides("") })
+	featureFlagsConfigMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      featureFlagsConfigMapName,
+			Namespace: testSystemNamespaceName,
+		},
+		Data: map[string]string{featureFlagsConfigKeyFlags: "Dummy Unknown1"},
+	}
+
+	// EXERCISE
+	_, _, eventRecorder := startTestStore(t, featureFlagsConfigMap)
+
+	// VERIFY
+	assert.Assert(t, featureflag.Dummy.Enabled())
+	select {
+	case event := <-eventRecorder.Events:
+		assert.Assert(t, strings.HasPrefix(event, "Warning "+api.EventReasonLoadFeatureFlagsConfigFailed+" "), event)
+		assert.Assert(t, strings.Contains(event, `ConfigMap "steward-feature-flags" in namespace "steward-testing": key "flags": unknown feature flags: "Unknown1"`), event)
+	case <-time.After(storeTestTimeout):
+		t.Fatal("timeout waiting for event")
+	}
+}
+
+func Test_Store_Snapshot_MissingNetworkPolicies(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	examinee, _, eventRecorder := startTestStore(t)
+
+	// EXERCISE
+	snapshot := examinee.Snapshot()
+
+	// VERIFY
+	_, err := snapshot.PipelineRunsConfig()
+	assert.ErrorContains(t, err, `ConfigMap "steward-pipelineruns-network-policies" in namespace "steward-testing": is missing`)
+	// no ConfigMap to record an event for
+	assert.Equal(t, 0, len(eventRecorder.Events))
+}
+
+func Test_NewContextWithStore(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	store, _, _ := startTestStore(t,
+		newNetworkPolicyConfigMap(map[string]string{
+			networkPoliciesConfigKeyDefault: "key1",
+			"key1":                          "policy1",
+		}),
+	)
+	ctx := NewContextWithStore(context.Background(), store)
+
+	// EXERCISE
+	config, err := FromContext(ctx)
+
+	// VERIFY
+	assert.NilError(t, err)
+	expected, _ := store.Snapshot().PipelineRunsConfig()
+	assert.Equal(t, expected, config)
+}
@@ -0,0 +1,107 @@
+package cfg
+
+import (
+	"fmt"
+	"sort"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/featureflag"
+	"github.com/SAP/stewardci-core/pkg/maintenancemode"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var (
+	limitRangeGroupKind    = schema.GroupKind{Group: "", Kind: "LimitRange"}
+	resourceQuotaGroupKind = schema.GroupKind{Group: "", Kind: "ResourceQuota"}
+	networkPolicyGroupKind = schema.GroupKind{Group: networkingv1.GroupName, Kind: "NetworkPolicy"}
+)
+
+// IsConfigMapName returns true if name is the name of one of the
+// ConfigMaps in the system namespace that contain the configuration of
+// the run controller.
+func IsConfigMapName(name string) bool {
+	return isWatchedConfigMap(name)
+}
+
+// ValidateConfigMap checks the given configuration ConfigMap in the same
+// way as the run controller does when loading it. In addition, the
+// configured manifests are decoded and checked for the expected kind.
+// It returns an error describing the first problem found, or nil if the
+// ConfigMap is valid or does not contain run controller configuration
+// (see IsConfigMapName).
+func ValidateConfigMap(configMap *corev1.ConfigMap) error {
+	configData := configDataMap(configMap.Data)
+
+	switch configMap.GetName() {
+	case mainConfigMapName:
+		return validateMainConfig(configData)
+	case networkPoliciesConfigMapName:
+		return validateNetworkPoliciesConfig(configData)
+	case api.MaintenanceModeConfigMapName:
+		return validateMaintenanceModeConfig(configMap)
+	case featureFlagsConfigMapName:
+		if err := featureflag.Validate(configMap.Data[featureFlagsConfigKeyFlags]); err != nil {
+			return errors.Wrapf(err, "key %q", featureFlagsConfigKeyFlags)
+		}
+	}
+	return nil
+}
+
+func validateMainConfig(configData configDataMap) error {
+	config := &PipelineRunsConfigStruct{}
+	if err := processMainConfig(configData, config); err != nil {
+		return err
+	}
+
+	for _, m := range []struct {
+		key         string
+		manifest    string
+		groupKind   schema.GroupKind
+		displayName string
+	}{
+		{mainConfigKeyLimitRange, config.LimitRange, limitRangeGroupKind, "limit range"},
+		{mainConfigKeyResourceQuota, config.ResourceQuota, resourceQuotaGroupKind, "resource quota"},
+	} {
+		if m.manifest == "" {
+			continue
+		}
+		if _, err := DecodeManifest(m.manifest, m.groupKind, m.displayName); err != nil {
+			return errors.Wrapf(err, "key %q", m.key)
+		}
+	}
+	return nil
+}
+
+func validateNetworkPoliciesConfig(configData configDataMap) error {
+	config := &PipelineRunsConfigStruct{}
+	if err := processNetworkPoliciesConfig(configData, config); err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(config.NetworkPolicies))
+	for key := range config.NetworkPolicies {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if _, err := DecodeManifest(config.NetworkPolicies[key], networkPolicyGroupKind, "network policy"); err != nil {
+			return errors.Wrapf(err, "key %q", key)
+		}
+	}
+	return nil
+}
+
+func validateMaintenanceModeConfig(configMap *corev1.ConfigMap) error {
+	if value, found := configMap.Data[api.MaintenanceModeKeyName]; found && value != "true" && value != "false" {
+		return fmt.Errorf(
+			"key %q: value %q is neither \"true\" nor \"false\"",
+			api.MaintenanceModeKeyName,
+			value,
+		)
+	}
+	_, err := maintenancemode.ParseConfigMap(configMap)
+	return err
+}
@@ -0,0 +1,184 @@
+package cfg
+
+import (
+	"testing"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	validLimitRange = `
+apiVersion: v1
+kind: LimitRange
+spec: {}
+`
+	validResourceQuota = `
+apiVersion: v1
+kind: ResourceQuota
+spec: {}
+`
+	validNetworkPolicy = `
+apiVersion: networking.k8s.io/v1
+kind: NetworkPolicy
+spec: {}
+`
+)
+
+func Test_ValidateConfigMap(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name          string
+		configMap     *corev1.ConfigMap
+		expectedError string
+	}{
+		{
+			name: "main/valid",
+			configMap: newMainConfigMap(map[string]string{
+				mainConfigKeyTimeout:              "10m",
+				mainConfigKeyLimitRange:           validLimitRange,
+				mainConfigKeyResourceQuota:        validResourceQuota,
+				mainConfigKeyCustomLoggingDetails: "[{logKey: logKey1, kind: label, spec: {key: label1}}]",
+			}),
+		},
+		{
+			name:      "main/empty",
+			configMap: newMainConfigMap(nil),
+		},
+		{
+			name: "main/invalid_duration",
+			configMap: newMainConfigMap(map[string]string{
+				mainConfigKeyTimeoutWait: "1x",
+			}),
+			expectedError: `key "waitTimeout": cannot parse value "1x": `,
+		},
+		{
+			name: "main/invalid_custom_logging_details",
+			configMap: newMainConfigMap(map[string]string{
+				mainConfigKeyCustomLoggingDetails: "{",
+			}),
+			expectedError: "did not find expected node content",
+		},
+		{
+			name: "main/invalid_limit_range_yaml",
+			configMap: newMainConfigMap(map[string]string{
+				mainConfigKeyLimitRange: "{",
+			}),
+			expectedError: `key "limitRange": failed to decode configured limit range: `,
+		},
+		{
+			name: "main/limit_range_wrong_kind",
+			configMap: newMainConfigMap(map[string]string{
+				mainConfigKeyLimitRange: validResourceQuota,
+			}),
+			expectedError: `key "limitRange": configured limit range does not denote a "LimitRange" but a "ResourceQuota"`,
+		},
+		{
+			name: "main/resource_quota_wrong_kind",
+			configMap: newMainConfigMap(map[string]string{
+				mainConfigKeyResourceQuota: validLimitRange,
+			}),
+			expectedError: `key "resourceQuota": configured resource quota does not denote a "ResourceQuota" but a "LimitRange"`,
+		},
+		{
+			name: "network/valid",
+			configMap: newNetworkPolicyConfigMap(map[string]string{
+				networkPoliciesConfigKeyDefault: "profile1",
+				"profile1":                      validNetworkPolicy,
+			}),
+		},
+		{
+			name: "network/default_missing",
+			configMap: newNetworkPolicyConfigMap(map[string]string{
+				"profile1": validNetworkPolicy,
+			}),
+			expectedError: `key "_default" is missing`,
+		},
+		{
+			name: "network/default_pointing_nowhere",
+			configMap: newNetworkPolicyConfigMap(map[string]string{
+				networkPoliciesConfigKeyDefault: "profile2",
+				"profile1":                      validNetworkPolicy,
+			}),
+			expectedError: `key "_default": value "profile2" does not denote an existing network policy key`,
+		},
+		{
+			name: "network/wrong_kind",
+			configMap: newNetworkPolicyConfigMap(map[string]string{
+				networkPoliciesConfigKeyDefault: "profile1",
+				"profile1":                      validNetworkPolicy,
+				"profile2":                      validLimitRange,
+			}),
+			expectedError: `key "profile2": configured network policy does not denote a "NetworkPolicy.networking.k8s.io" but a "LimitRange"`,
+		},
+		{
+			name:      "maintenance_mode/valid",
+			configMap: newMaintenanceModeConfigMap("false"),
+		},
+		{
+			name:          "maintenance_mode/invalid",
+			configMap:     newMaintenanceModeConfigMap("yes"),
+			expectedError: `key "maintenanceMode": value "yes" is neither "true" nor "false"`,
+		},
+		{
+			name: "maintenance_mode/invalid_windows",
+			configMap: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: api.MaintenanceModeConfigMapName},
+				Data: map[string]string{
+					api.MaintenanceWindowsKeyName: `[{name: w1, start: "2023-01-01T20:00:00Z", end: "2023-01-01T19:00:00Z"}]`,
+				},
+			},
+			expectedError: `key "windows": window "w1": end must be after start`,
+		},
+		{
+			name: "feature_flags/valid",
+			configMap: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: featureFlagsConfigMapName},
+				Data:       map[string]string{featureFlagsConfigKeyFlags: "-Dummy, +RetryOnInvalidPipelineRunsConfig"},
+			},
+		},
+		{
+			name: "feature_flags/unknown",
+			configMap: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: featureFlagsConfigMapName},
+				Data:       map[string]string{featureFlagsConfigKeyFlags: "Dummy Unknown1"},
+			},
+			expectedError: `key "flags": unknown feature flags: "Unknown1"`,
+		},
+		{
+			name: "other_config_map",
+			configMap: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "other"},
+				Data:       map[string]string{mainConfigKeyTimeout: "1x"},
+			},
+		},
+	} {
+		tc := tc // capture current value before going parallel
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// EXERCISE
+			resultErr := ValidateConfigMap(tc.configMap)
+
+			// VERIFY
+			if tc.expectedError == "" {
+				assert.NilError(t, resultErr)
+			} else {
+				assert.ErrorContains(t, resultErr, tc.expectedError)
+			}
+		})
+	}
+}
+
+func Test_IsConfigMapName(t *testing.T) {
+	t.Parallel()
+
+	assert.Assert(t, IsConfigMapName(mainConfigMapName))
+	assert.Assert(t, IsConfigMapName(networkPoliciesConfigMapName))
+	assert.Assert(t, IsConfigMapName(api.MaintenanceModeConfigMapName))
+	assert.Assert(t, IsConfigMapName(featureFlagsConfigMapName))
+	assert.Assert(t, !IsConfigMapName("other"))
+}
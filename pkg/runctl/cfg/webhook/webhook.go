@@ -0,0 +1,124 @@
+package webhook
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/SAP/stewardci-core/pkg/runctl/cfg"
+	"github.com/go-logr/logr"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/system"
+)
+
+// Path is the URL path the validating admission webhook is served at.
+const Path = "/validate-config"
+
+// maxRequestBodySize is the maximum size of an admission review request.
+const maxRequestBodySize = 3 * 1024 * 1024
+
+// StartServer starts the HTTPS server serving the validating admission
+// webhook for the run controller configuration ConfigMaps.
+// The certificate and key are read from the given files on each TLS
+// handshake, so that renewed certificates are picked up without restart.
+func StartServer(logger logr.Logger, port uint16, certFile, keyFile string) {
+	go func() {
+		serveMux := http.NewServeMux()
+		serveMux.Handle(Path, NewHandler(logger))
+		server := &http.Server{
+			Addr:    fmt.Sprintf(":%d", port),
+			Handler: serveMux,
+			TLSConfig: &tls.Config{
+				MinVersion: tls.VersionTLS12,
+				GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+					cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+					if err != nil {
+						return nil, err
+					}
+					return &cert, nil
+				},
+			},
+		}
+
+		for {
+			err := server.ListenAndServeTLS("", "")
+			if err == http.ErrServerClosed {
+				break
+			}
+			if err != nil {
+				logger.Error(err, "Config webhook server terminated unexpectedly and will be restarted")
+			}
+		}
+	}()
+}
+
+// NewHandler returns an HTTP handler for admission reviews of ConfigMaps.
+// Operations on the run controller configuration ConfigMaps in the system
+// namespace are denied if the resulting configuration is invalid. All
+// other requests are allowed.
+func NewHandler(logger logr.Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxRequestBodySize))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		review := &admissionv1.AdmissionReview{}
+		if err := json.Unmarshal(body, review); err != nil || review.Request == nil {
+			http.Error(w, "invalid admission review", http.StatusBadRequest)
+			return
+		}
+
+		review.Response = reviewRequest(logger, review.Request)
+		review.Response.UID = review.Request.UID
+		review.Request = nil
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(review); err != nil {
+			logger.Error(err, "Failed to write admission review response")
+		}
+	})
+}
+
+func reviewRequest(logger logr.Logger, request *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	if request.Kind.Group != "" || request.Kind.Kind != "ConfigMap" ||
+		request.Namespace != system.Namespace() ||
+		!cfg.IsConfigMapName(request.Name) ||
+		(request.Operation != admissionv1.Create && request.Operation != admissionv1.Update) {
+		return &admissionv1.AdmissionResponse{Allowed: true}
+	}
+
+	configMap := &corev1.ConfigMap{}
+	if err := json.Unmarshal(request.Object.Raw, configMap); err != nil {
+		return denied(http.StatusBadRequest, fmt.Sprintf("cannot decode ConfigMap: %s", err.Error()))
+	}
+
+	if err := cfg.ValidateConfigMap(configMap); err != nil {
+		logger.Info("Denied invalid configuration",
+			"configMap", request.Name,
+			"operation", request.Operation,
+			"user", request.UserInfo.Username,
+			"reason", err.Error(),
+		)
+		return denied(http.StatusUnprocessableEntity, fmt.Sprintf(
+			"invalid configuration: ConfigMap %q in namespace %q: %s",
+			request.Name, request.Namespace, err.Error(),
+		))
+	}
+	return &admissionv1.AdmissionResponse{Allowed: true}
+}
+
+func denied(code int32, message string) *admissionv1.AdmissionResponse {
+	return &admissionv1.AdmissionResponse{
+		Allowed: false,
+		Result: &metav1.Status{
+			Status:  metav1.StatusFailure,
+			Code:    code,
+			Message: message,
+		},
+	}
+}
@@ -0,0 +1,160 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/assert/cmp"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	klog "k8s.io/klog/v2"
+	"knative.dev/pkg/system"
+)
+
+const testSystemNamespaceName = "steward-testing"
+
+func init() {
+	os.Setenv(system.NamespaceEnvKey, testSystemNamespaceName)
+}
+
+func newAdmissionReview(t *testing.T, operation admissionv1.Operation, namespace, name string, data map[string]string) *admissionv1.AdmissionReview {
+	t.Helper()
+	configMap := &corev1.ConfigMap{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Data:       data,
+	}
+	raw, err := json.Marshal(configMap)
+	assert.NilError(t, err)
+	return &admissionv1.AdmissionReview{
+		TypeMeta: metav1.TypeMeta{APIVersion: "admission.k8s.io/v1", Kind: "AdmissionReview"},
+		Request: &admissionv1.AdmissionRequest{
+			UID:       types.UID("uid1"),
+			Kind:      metav1.GroupVersionKind{Version: "v1", Kind: "ConfigMap"},
+			Name:      name,
+			Namespace: namespace,
+			Operation: operation,
+			Object:    runtime.RawExtension{Raw: raw},
+		},
+	}
+}
+
+func postAdmissionReview(t *testing.T, review *admissionv1.AdmissionReview) *admissionv1.AdmissionResponse {
+	t.Helper()
+	body, err := json.Marshal(review)
+	assert.NilError(t, err)
+	recorder := httptest.NewRecorder()
+
+	NewHandler(klog.Background()).ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, Path, bytes.NewReader(body)))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	result := &admissionv1.AdmissionReview{}
+	assert.NilError(t, json.Unmarshal(recorder.Body.Bytes(), result))
+	assert.Assert(t, result.Response != nil)
+	assert.Equal(t, types.UID("uid1"), result.Response.UID)
+	return result.Response
+}
+
+func Test_Handler(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name            string
+		operation       admissionv1.Operation
+		namespace       string
+		configMapName   string
+		data            map[string]string
+		expectedAllowed bool
+		expectedMessage string
+	}{
+		{
+			name:            "valid",
+			operation:       admissionv1.Update,
+			namespace:       testSystemNamespaceName,
+			configMapName:   "steward-pipelineruns",
+			data:            map[string]string{"timeout": "10m"},
+			expectedAllowed: true,
+		},
+		{
+			name:            "invalid_on_create",
+			operation:       admissionv1.Create,
+			namespace:       testSystemNamespaceName,
+			configMapName:   "steward-pipelineruns",
+			data:            map[string]string{"timeout": "1x"},
+			expectedAllowed: false,
+			expectedMessage: `invalid configuration: ConfigMap "steward-pipelineruns" in namespace "steward-testing": key "timeout": cannot parse value "1x"`,
+		},
+		{
+			name:            "invalid_on_update",
+			operation:       admissionv1.Update,
+			namespace:       testSystemNamespaceName,
+			configMapName:   "steward-pipelineruns-network-policies",
+			data:            map[string]string{"_default": "missing"},
+			expectedAllowed: false,
+			expectedMessage: `invalid configuration: ConfigMap "steward-pipelineruns-network-policies" in namespace "steward-testing": key "_default": value "missing" does not denote an existing network policy key`,
+		},
+		{
+			name:            "delete",
+			operation:       admissionv1.Delete,
+			namespace:       testSystemNamespaceName,
+			configMapName:   "steward-pipelineruns",
+			data:            map[string]string{"timeout": "1x"},
+			expectedAllowed: true,
+		},
+		{
+			name:            "other_namespace",
+			operation:       admissionv1.Update,
+			namespace:       "other",
+			configMapName:   "steward-pipelineruns",
+			data:            map[string]string{"timeout": "1x"},
+			expectedAllowed: true,
+		},
+		{
+			name:            "other_config_map",
+			operation:       admissionv1.Update,
+			namespace:       testSystemNamespaceName,
+			configMapName:   "other",
+			data:            map[string]string{"timeout": "1x"},
+			expectedAllowed: true,
+		},
+	} {
+		tc := tc // capture current value before going parallel
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			review := newAdmissionReview(t, tc.operation, tc.namespace, tc.configMapName, tc.data)
+
+			// EXERCISE
+			response := postAdmissionReview(t, review)
+
+			// VERIFY
+			assert.Equal(t, tc.expectedAllowed, response.Allowed)
+			if !tc.expectedAllowed {
+				assert.Equal(t, int32(http.StatusUnprocessableEntity), response.Result.Code)
+				assert.Assert(t, cmp.Contains(response.Result.Message, tc.expectedMessage))
+			}
+		})
+	}
+}
+
+func Test_Handler_InvalidRequest(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	recorder := httptest.NewRecorder()
+
+	// EXERCISE
+	NewHandler(klog.Background()).ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, Path, bytes.NewReader([]byte("{}"))))
+
+	// VERIFY
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
@@ -0,0 +1,354 @@
+package cloudevents
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/SAP/stewardci-core/pkg/egress"
+	"github.com/SAP/stewardci-core/pkg/runctl/metrics"
+	ce "github.com/cloudevents/sdk-go/v2"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+	"github.com/go-logr/logr"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultQueueSize is the default maximum number of events waiting
+	// for delivery.
+	DefaultQueueSize = 1000
+
+	defaultWorkers        = 4
+	defaultMaxRetries     = 5
+	defaultRetryDelay     = 500 * time.Millisecond
+	defaultRequestTimeout = 10 * time.Second
+
+	defaultRunSinkQueueSize      = 20
+	defaultRunSinkMaxRetries     = 2
+	defaultRunSinkRequestTimeout = 3 * time.Second
+)
+
+// Options are the options for creating an Emitter.
+type Options struct {
+	// QueueSize is the maximum number of events waiting for delivery.
+	// Events emitted while the queue is full are dropped.
+	// If zero or negative, DefaultQueueSize is used.
+	QueueSize int
+
+	// Workers is the number of events delivered concurrently.
+	// If zero or negative, a default is used.
+	Workers int
+
+	// MaxRetries is the maximum number of retries of a failed delivery.
+	// If zero or negative, a default is used.
+	MaxRetries int
+
+	// RetryDelay is the delay before the first retry of a failed
+	// delivery. It doubles with each further retry.
+	// If zero or negative, a default is used.
+	RetryDelay time.Duration
+
+	// RequestTimeout is the timeout of a single delivery attempt.
+	// If zero or negative, a default is used.
+	RequestTimeout time.Duration
+
+	// RunSinkQueueSize is the maximum number of events waiting for
+	// delivery to a single host of sinks specified in pipeline runs.
+	// Events emitted while the queue is full are dropped.
+	// If zero or negative, a default is used.
+	RunSinkQueueSize int
+
+	// RunSinkMaxRetries is the maximum number of retries of a failed
+	// delivery to a sink specified in a pipeline run.
+	// If zero or negative, a default is used.
+	RunSinkMaxRetries int
+
+	// RunSinkRequestTimeout is the timeout of a single delivery attempt
+	// to a sink specified in a pipeline run.
+	// If zero or negative, a default is used.
+	RunSinkRequestTimeout time.Duration
+
+	// AllowedNetworks are the networks sinks specified in pipeline runs
+	// may be located in although their addresses are loopback, link-local
+	// or private addresses.
+	AllowedNetworks []*net.IPNet
+
+	// RunSinkHTTPClient is the HTTP client used for deliveries to sinks
+	// specified in pipeline runs.
+	// If nil, a client created by egress.NewHTTPClient with
+	// RunSinkRequestTimeout and AllowedNetworks is used.
+	RunSinkHTTPClient *http.Client
+}
+
+type delivery struct {
+	sinkURL string
+	event   ce.Event
+}
+
+// runSink is the queue of deliveries to a host of sinks specified in
+// pipeline runs.
+type runSink struct {
+	queue []delivery
+
+	// active is true while a goroutine delivers the queued events.
+	active bool
+}
+
+// Emitter sends CloudEvents in HTTP binary content mode to sinks.
+// Events are queued and delivered asynchronously, so that emitting an
+// event never blocks. Failed deliveries are retried with exponential
+// backoff. The outcome of each delivery is metered.
+//
+// Events to sinks specified in pipeline runs are delivered separately
+// from events to the configured sinks: Each sink host has its own small
+// queue served by its own goroutine, and deliveries are retried less
+// often with a shorter timeout. Therefore a slow or unreachable sink of
+// one pipeline run only delays events to the same host. The requests to
+// such sinks are restricted like those of notifications (see package
+// egress).
+type Emitter struct {
+	client    ce.Client
+	runClient ce.Client
+	queue     chan delivery
+	opts      Options
+	logger    logr.Logger
+
+	// mutex guards the fields below.
+	mutex sync.Mutex
+
+	// ctx is the context of the deliveries. It is nil until the Emitter
+	// is started.
+	ctx context.Context
+
+	// runSinks are the queues of deliveries to sinks specified in
+	// pipeline runs by host.
+	runSinks map[string]*runSink
+
+	// runSinksQueued is the total number of events waiting for delivery
+	// to sinks specified in pipeline runs.
+	runSinksQueued int
+}
+
+// NewEmitter creates a new Emitter. Events are delivered only after the
+// Emitter has been started.
+func NewEmitter(logger logr.Logger, opts Options) (*Emitter, error) {
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = DefaultQueueSize
+	}
+	if opts.Workers <= 0 {
+		opts.Workers = defaultWorkers
+	}
+	if opts.MaxRetries <= 0 {
+		opts.MaxRetries = defaultMaxRetries
+	}
+	if opts.RetryDelay <= 0 {
+		opts.RetryDelay = defaultRetryDelay
+	}
+	if opts.RequestTimeout <= 0 {
+		opts.RequestTimeout = defaultRequestTimeout
+	}
+	if opts.RunSinkQueueSize <= 0 {
+		opts.RunSinkQueueSize = defaultRunSinkQueueSize
+	}
+	if opts.RunSinkMaxRetries <= 0 {
+		opts.RunSinkMaxRetries = defaultRunSinkMaxRetries
+	}
+	if opts.RunSinkRequestTimeout <= 0 {
+		opts.RunSinkRequestTimeout = defaultRunSinkRequestTimeout
+	}
+	if opts.RunSinkHTTPClient == nil {
+		opts.RunSinkHTTPClient = egress.NewHTTPClient(egress.Options{
+			Timeout:         opts.RunSinkRequestTimeout,
+			AllowedNetworks: opts.AllowedNetworks,
+			FollowRedirects: true,
+		})
+	}
+
+	client, err := newClient(http.Client{Timeout: opts.RequestTimeout})
+	if err != nil {
+		return nil, err
+	}
+	runClient, err := newClient(*opts.RunSinkHTTPClient)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Emitter{
+		client:    client,
+		runClient: runClient,
+		queue:     make(chan delivery, opts.QueueSize),
+		opts:      opts,
+		logger:    logger.WithName("cloudEvents"),
+		runSinks:  map[string]*runSink{},
+	}, nil
+}
+
+func newClient(httpClient http.Client) (ce.Client, error) {
+	protocol, err := cehttp.New(cehttp.WithClient(httpClient))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create CloudEvents HTTP protocol")
+	}
+	client, err := ce.NewClient(protocol, ce.WithTimeNow())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create CloudEvents client")
+	}
+	return client, nil
+}
+
+// Start starts the delivery of queued events. It does not block.
+// Delivery stops when the given channel is closed. Events still queued
+// then are not delivered.
+func (e *Emitter) Start(stopCh <-chan struct{}) {
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-stopCh
+		cancel()
+	}()
+	for i := 0; i < e.opts.Workers; i++ {
+		go e.runWorker(ctx)
+	}
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.ctx = ctx
+	for host, sink := range e.runSinks {
+		sink.active = true
+		go e.runRunSinkWorker(ctx, host)
+	}
+}
+
+// Emit queues the given event for delivery to the given sink URL
+// configured by the Steward administrator.
+// A unique ID is assigned to the event. If the queue is full, the event
+// is dropped.
+func (e *Emitter) Emit(sinkURL string, event ce.Event) {
+	event.SetID(uuid.New().String())
+	select {
+	case e.queue <- delivery{sinkURL: sinkURL, event: event}:
+	default:
+		metrics.CloudEventsDeliveries.Observe(metrics.DeliveryOutcomeDropped)
+		e.logger.Info("Dropped CloudEvent because the delivery queue is full",
+			"type", event.Type(),
+			"subject", event.Subject(),
+			"queueSize", e.opts.QueueSize,
+		)
+	}
+}
+
+// EmitToRunSink queues the given event for delivery to the given sink URL
+// specified in a pipeline run. A unique ID is assigned to the event.
+// If the URL is not an HTTPS URL, the event is not delivered. If the queue
+// of the sink host is full, the event is dropped.
+func (e *Emitter) EmitToRunSink(sinkURL string, event ce.Event) {
+	event.SetID(uuid.New().String())
+	u, err := url.Parse(sinkURL)
+	if err == nil {
+		err = egress.CheckScheme(u)
+	}
+	if err != nil {
+		metrics.CloudEventsDeliveries.Observe(metrics.DeliveryOutcomeFailed)
+		e.logger.Info("Did not deliver CloudEvent to invalid sink",
+			"type", event.Type(),
+			"subject", event.Subject(),
+			"sinkURL", redactURL(sinkURL),
+			"error", err.Error(),
+		)
+		return
+	}
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	sink := e.runSinks[u.Host]
+	if sink == nil {
+		sink = &runSink{}
+	}
+	if len(sink.queue) >= e.opts.RunSinkQueueSize || e.runSinksQueued >= e.opts.QueueSize {
+		metrics.CloudEventsDeliveries.Observe(metrics.DeliveryOutcomeDropped)
+		e.logger.Info("Dropped CloudEvent because the delivery queue of the sink is full",
+			"type", event.Type(),
+			"subject", event.Subject(),
+			"sinkURL", redactURL(sinkURL),
+		)
+		return
+	}
+	sink.queue = append(sink.queue, delivery{sinkURL: sinkURL, event: event})
+	e.runSinks[u.Host] = sink
+	e.runSinksQueued++
+	if e.ctx != nil && !sink.active {
+		sink.active = true
+		go e.runRunSinkWorker(e.ctx, u.Host)
+	}
+}
+
+func (e *Emitter) runWorker(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case d := <-e.queue:
+			e.deliver(ctx, e.client, e.opts.MaxRetries, d)
+		}
+	}
+}
+
+// runRunSinkWorker delivers the events queued for the given sink host
+// until the queue is empty.
+func (e *Emitter) runRunSinkWorker(ctx context.Context, host string) {
+	for {
+		e.mutex.Lock()
+		sink := e.runSinks[host]
+		if len(sink.queue) == 0 || ctx.Err() != nil {
+			sink.active = false
+			if len(sink.queue) == 0 {
+				delete(e.runSinks, host)
+			}
+			e.mutex.Unlock()
+			return
+		}
+		d := sink.queue[0]
+		sink.queue[0] = delivery{}
+		sink.queue = sink.queue[1:]
+		e.runSinksQueued--
+		e.mutex.Unlock()
+
+		e.deliver(ctx, e.runClient, e.opts.RunSinkMaxRetries, d)
+	}
+}
+
+func (e *Emitter) deliver(ctx context.Context, client ce.Client, maxRetries int, d delivery) {
+	ctx = ce.ContextWithTarget(ctx, d.sinkURL)
+	ctx = ce.WithEncodingBinary(ctx)
+	ctx = ce.ContextWithRetriesExponentialBackoff(ctx, e.opts.RetryDelay, maxRetries)
+
+	result := client.Send(ctx, d.event)
+	if ce.IsACK(result) {
+		metrics.CloudEventsDeliveries.Observe(metrics.DeliveryOutcomeSent)
+		e.logger.V(5).Info("Delivered CloudEvent",
+			"type", d.event.Type(),
+			"subject", d.event.Subject(),
+			"id", d.event.ID(),
+		)
+		return
+	}
+	metrics.CloudEventsDeliveries.Observe(metrics.DeliveryOutcomeFailed)
+	e.logger.Error(result, "Failed to deliver CloudEvent",
+		"type", d.event.Type(),
+		"subject", d.event.Subject(),
+		"id", d.event.ID(),
+		"sinkURL", redactURL(d.sinkURL),
+	)
+}
+
+// redactURL returns the given URL with the password replaced, so that it
+// can be logged.
+func redactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "<invalid URL>"
+	}
+	return u.Redacted()
+}
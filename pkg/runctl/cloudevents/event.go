@@ -0,0 +1,69 @@
+package cloudevents
+
+import (
+	"fmt"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	ce "github.com/cloudevents/sdk-go/v2"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// EventTypeStateChanged is the type of events emitted when a pipeline
+	// run has entered a new state.
+	EventTypeStateChanged = "com.sap.steward.pipelinerun.statechanged"
+
+	// EventTypeResult is the type of events emitted when the result of a
+	// pipeline run has been set.
+	EventTypeResult = "com.sap.steward.pipelinerun.result"
+)
+
+// PipelineRunEventData is the data of CloudEvents about pipeline runs.
+type PipelineRunEventData struct {
+	// Key is the key of the pipeline run in the format
+	// `<namespace>/<name>`.
+	Key string `json:"key"`
+
+	Namespace string    `json:"namespace"`
+	Name      string    `json:"name"`
+	UID       types.UID `json:"uid"`
+
+	State   api.State  `json:"state"`
+	Result  api.Result `json:"result,omitempty"`
+	Message string     `json:"message,omitempty"`
+
+	RunDetails *api.PipelineRunDetails `json:"runDetails,omitempty"`
+}
+
+// NewPipelineRunEvent returns a CloudEvent of the given type carrying the
+// current status of the given pipeline run.
+// The event ID is left empty to be set by the Emitter.
+func NewPipelineRunEvent(eventType string, pipelineRun *api.PipelineRun) (ce.Event, error) {
+	namespace, name := pipelineRun.GetNamespace(), pipelineRun.GetName()
+	status := &pipelineRun.Status
+
+	event := ce.NewEvent()
+	event.SetType(eventType)
+	event.SetSource(fmt.Sprintf("/apis/%s/namespaces/%s/pipelineruns/%s",
+		api.SchemeGroupVersion.String(), namespace, name))
+	event.SetSubject(namespace + "/" + name)
+	if !status.StateDetails.StartedAt.IsZero() {
+		event.SetTime(status.StateDetails.StartedAt.Time)
+	}
+
+	data := &PipelineRunEventData{
+		Key:        namespace + "/" + name,
+		Namespace:  namespace,
+		Name:       name,
+		UID:        pipelineRun.GetUID(),
+		State:      status.State,
+		Result:     status.Result,
+		Message:    status.Message,
+		RunDetails: pipelineRun.Spec.RunDetails,
+	}
+	if err := event.SetData(ce.ApplicationJSON, data); err != nil {
+		return event, errors.Wrap(err, "failed to encode event data")
+	}
+	return event, nil
+}
@@ -0,0 +1,86 @@
+package cloudevents
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	fake "github.com/SAP/stewardci-core/pkg/k8s/fake"
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_NewPipelineRunEvent(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	startedAt := metav1.NewTime(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+	pipelineRun := fake.PipelineRun("run1", "ns1", api.PipelineSpec{
+		RunDetails: &api.PipelineRunDetails{
+			JobName:        "job1",
+			SequenceNumber: 42,
+			Cause:          "cause1",
+		},
+	})
+	pipelineRun.SetUID("uid1")
+	pipelineRun.Status.State = api.StateCleaning
+	pipelineRun.Status.StateDetails = api.StateItem{State: api.StateCleaning, StartedAt: startedAt}
+	pipelineRun.Status.Result = api.ResultErrorContent
+	pipelineRun.Status.Message = "message1"
+
+	// EXERCISE
+	result, resultErr := NewPipelineRunEvent(EventTypeResult, pipelineRun)
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	assert.Equal(t, "", result.ID())
+	assert.Equal(t, "com.sap.steward.pipelinerun.result", result.Type())
+	assert.Equal(t, "/apis/steward.sap.com/v1alpha1/namespaces/ns1/pipelineruns/run1", result.Source())
+	assert.Equal(t, "ns1/run1", result.Subject())
+	assert.Equal(t, startedAt.Time, result.Time())
+	assert.Equal(t, "application/json", result.DataContentType())
+
+	var data map[string]interface{}
+	assert.NilError(t, json.Unmarshal(result.Data(), &data))
+	assert.DeepEqual(t, map[string]interface{}{
+		"key":       "ns1/run1",
+		"namespace": "ns1",
+		"name":      "run1",
+		"uid":       "uid1",
+		"state":     "cleaning",
+		"result":    "error_content",
+		"message":   "message1",
+		"runDetails": map[string]interface{}{
+			"jobName":        "job1",
+			"sequenceNumber": float64(42),
+			"cause":          "cause1",
+		},
+	}, data)
+}
+
+func Test_NewPipelineRunEvent_Minimal(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	pipelineRun := fake.PipelineRun("run1", "ns1", api.PipelineSpec{})
+	pipelineRun.Status.State = api.StateNew
+
+	// EXERCISE
+	result, resultErr := NewPipelineRunEvent(EventTypeStateChanged, pipelineRun)
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	assert.Equal(t, "com.sap.steward.pipelinerun.statechanged", result.Type())
+	assert.Assert(t, result.Time().IsZero())
+
+	var data map[string]interface{}
+	assert.NilError(t, json.Unmarshal(result.Data(), &data))
+	assert.DeepEqual(t, map[string]interface{}{
+		"key":       "ns1/run1",
+		"namespace": "ns1",
+		"name":      "run1",
+		"uid":       "",
+		"state":     "new",
+	}, data)
+}
@@ -0,0 +1,175 @@
+package runctl
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/egress"
+	fake "github.com/SAP/stewardci-core/pkg/k8s/fake"
+	cfg "github.com/SAP/stewardci-core/pkg/runctl/cfg"
+	"github.com/SAP/stewardci-core/pkg/runctl/cloudevents"
+	runmocks "github.com/SAP/stewardci-core/pkg/runctl/run/mocks"
+	"github.com/go-logr/logr"
+	gomock "github.com/golang/mock/gomock"
+	assert "gotest.tools/v3/assert"
+)
+
+type receivedCloudEvent struct {
+	eventType string
+	data      cloudevents.PipelineRunEventData
+}
+
+// newCloudEventsReceiver starts a local HTTP server receiving CloudEvents
+// and an emitter delivering events one by one, so that the order of the
+// received events is deterministic.
+// If runSink is true, the server uses HTTPS and the emitter is allowed to
+// deliver events of sinks specified in pipeline runs to it.
+func newCloudEventsReceiver(t *testing.T, runSink bool) (*httptest.Server, *cloudevents.Emitter, <-chan receivedCloudEvent) {
+	t.Helper()
+	received := make(chan receivedCloudEvent, 10)
+	newServer := httptest.NewServer
+	if runSink {
+		newServer = httptest.NewTLSServer
+	}
+	server := newServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		event := receivedCloudEvent{eventType: r.Header.Get("ce-type")}
+		if err := json.Unmarshal(body, &event.data); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		received <- event
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	t.Cleanup(server.Close)
+
+	runSinkHTTPClient := egress.NewHTTPClient(egress.Options{
+		AllowedNetworks: []*net.IPNet{{IP: net.IPv4(127, 0, 0, 0), Mask: net.CIDRMask(8, 32)}},
+	})
+	// trust the self-signed certificate of the local server
+	runSinkHTTPClient.Transport.(*http.Transport).TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	emitter, err := cloudevents.NewEmitter(logr.Discard(), cloudevents.Options{
+		Workers:           1,
+		RunSinkHTTPClient: runSinkHTTPClient,
+	})
+	assert.NilError(t, err)
+	stopCh := make(chan struct{})
+	t.Cleanup(func() { close(stopCh) })
+	emitter.Start(stopCh)
+
+	return server, emitter, received
+}
+
+func awaitCloudEvent(t *testing.T, received <-chan receivedCloudEvent) receivedCloudEvent {
+	t.Helper()
+	select {
+	case event := <-received:
+		return event
+	case <-time.After(10 * time.Second):
+		t.Fatal("no CloudEvent received")
+	}
+	return receivedCloudEvent{}
+}
+
+func Test__Controller_syncHandler__CloudEvents_SinkInPipelineRun(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	server, emitter, received := newCloudEventsReceiver(t, true)
+
+	pipelineRun := fake.PipelineRun("foo", "ns1", api.PipelineSpec{
+		RunDetails:  &api.PipelineRunDetails{JobName: "job1"},
+		CloudEvents: &api.CloudEventsSpec{SinkURL: server.URL},
+	})
+	pipelineRun.Status.State = api.StateNew
+
+	controller, _ := newController(t, pipelineRun)
+	controller.cloudEvents = emitter
+
+	runManager := runmocks.NewMockManager(mockCtrl)
+	runManager.EXPECT().
+		CreateEnv(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return("runNamespace1", "", nil)
+
+	controller.testing = &controllerTesting{
+		createRunManagerStub: newSimpleCreateRunManagerStub(runManager),
+		loadPipelineRunsConfigStub: func(ctx context.Context) (*cfg.PipelineRunsConfigStruct, error) {
+			return &cfg.PipelineRunsConfigStruct{CloudEventsSinkURL: "http://sink-not-used.example.com"}, nil
+		},
+		getMaintenanceStatusStub: newMaintenanceStatusStub(false, nil),
+	}
+
+	// EXERCISE
+	resultErr := controller.syncHandler(context.Background(), "ns1/foo")
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	for _, expectedState := range []api.State{api.StatePreparing, api.StateWaiting} {
+		event := awaitCloudEvent(t, received)
+		assert.Equal(t, cloudevents.EventTypeStateChanged, event.eventType)
+		assert.Equal(t, "ns1/foo", event.data.Key)
+		assert.Equal(t, expectedState, event.data.State)
+		assert.Equal(t, api.ResultUndefined, event.data.Result)
+		assert.DeepEqual(t, &api.PipelineRunDetails{JobName: "job1"}, event.data.RunDetails)
+	}
+}
+
+func Test__Controller_syncHandler__CloudEvents_ConfiguredSink(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	server, emitter, received := newCloudEventsReceiver(t, false)
+
+	pipelineRun := fake.PipelineRun("foo", "ns1", api.PipelineSpec{
+		Intent: api.IntentAbort,
+	})
+	pipelineRun.Status.State = api.StatePreparing
+
+	controller, _ := newController(t, pipelineRun)
+	controller.cloudEvents = emitter
+
+	runManager := runmocks.NewMockManager(mockCtrl)
+	runManager.EXPECT().DeleteEnv(gomock.Any(), gomock.Any()).Return(nil)
+
+	controller.testing = &controllerTesting{
+		createRunManagerStub: newSimpleCreateRunManagerStub(runManager),
+		loadPipelineRunsConfigStub: func(ctx context.Context) (*cfg.PipelineRunsConfigStruct, error) {
+			return &cfg.PipelineRunsConfigStruct{CloudEventsSinkURL: server.URL}, nil
+		},
+		getMaintenanceStatusStub: newMaintenanceStatusStub(false, nil),
+	}
+
+	// EXERCISE
+	resultErr := controller.syncHandler(context.Background(), "ns1/foo")
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	for _, expected := range []struct {
+		eventType string
+		state     api.State
+	}{
+		{cloudevents.EventTypeStateChanged, api.StateCleaning},
+		{cloudevents.EventTypeResult, api.StateCleaning},
+		{cloudevents.EventTypeStateChanged, api.StateFinished},
+	} {
+		event := awaitCloudEvent(t, received)
+		assert.Equal(t, expected.eventType, event.eventType)
+		assert.Equal(t, expected.state, event.data.State)
+		assert.Equal(t, api.ResultAborted, event.data.Result)
+		assert.Equal(t, "Aborted", event.data.Message)
+	}
+}
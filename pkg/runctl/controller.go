@@ -8,28 +8,41 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
 	"github.com/SAP/stewardci-core/pkg/client/clientset/versioned/scheme"
+	"github.com/SAP/stewardci-core/pkg/egress"
 	serrors "github.com/SAP/stewardci-core/pkg/errors"
+	"github.com/SAP/stewardci-core/pkg/githubapp"
 	"github.com/SAP/stewardci-core/pkg/k8s"
 	"github.com/SAP/stewardci-core/pkg/k8s/secrets"
 	k8ssecretprovider "github.com/SAP/stewardci-core/pkg/k8s/secrets/providers/k8s"
 	"github.com/SAP/stewardci-core/pkg/maintenancemode"
 	"github.com/SAP/stewardci-core/pkg/runctl/cfg"
+	"github.com/SAP/stewardci-core/pkg/runctl/cloudevents"
 	"github.com/SAP/stewardci-core/pkg/runctl/log"
 	"github.com/SAP/stewardci-core/pkg/runctl/metrics"
+	"github.com/SAP/stewardci-core/pkg/runctl/notifications"
 	run "github.com/SAP/stewardci-core/pkg/runctl/run"
 	"github.com/SAP/stewardci-core/pkg/runctl/runmgr"
+	"github.com/SAP/stewardci-core/pkg/runctl/sharding"
 	"github.com/SAP/stewardci-core/pkg/stewardlabels"
+	"github.com/SAP/stewardci-core/pkg/tracing"
 	"github.com/SAP/stewardci-core/pkg/utils"
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
 	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
@@ -53,6 +66,19 @@ const (
 	errorMessageWaitingFailed   = "waiting failed"
 	errorMessagePreparingFailed = "preparing failed"
 	errorMessageRunningFailed   = "running failed"
+
+	// pendingMessagePrefix is the prefix of the status message of
+	// pipeline runs whose execution has not started yet for a known
+	// reason.
+	pendingMessagePrefix = "waiting for main pod to start: "
+
+	// drainPollInterval is the interval for checking whether in-flight
+	// reconciliations have finished while draining.
+	drainPollInterval = 100 * time.Millisecond
+
+	// notificationWorkers is the number of notifications delivered
+	// concurrently.
+	notificationWorkers = 2
 )
 
 var (
@@ -60,6 +86,10 @@ var (
 	meteringInterval = 1 * time.Minute
 
 	defaultWaitTimeout = 10 * time.Minute
+
+	// defaultLogTailMaxBytes is the maximum size of the log tail captured
+	// for failed pipeline runs if not configured.
+	defaultLogTailMaxBytes int64 = 8 * 1024
 )
 
 // Controller processes PipelineRun resources
@@ -68,6 +98,8 @@ type Controller struct {
 	pipelineRunFetcher   k8s.PipelineRunFetcher
 	pipelineRunsSynced   cache.InformerSynced
 	tektonTaskRunsSynced cache.InformerSynced
+	namespaceLister      corev1listers.NamespaceLister
+	namespacesSynced     cache.InformerSynced
 	workqueue            workqueue.RateLimitingInterface
 	testing              *controllerTesting
 	eventRecorder        record.EventRecorder
@@ -76,6 +108,38 @@ type Controller struct {
 	heartbeatInterval       time.Duration
 	heartbeatLoggingEnabled bool
 	heartbeatLogLevel       int
+	heartbeatLivenessFactor int
+
+	// lastHeartbeat is the time of the last processed heartbeat stimulus
+	// in Unix nanoseconds. It is zero while the workers are not running.
+	lastHeartbeat atomic.Int64
+
+	drainTimeout time.Duration
+
+	// draining is true after the controller has been stopped. No new
+	// work items are processed then. Protected by inFlightMutex.
+	draining bool
+
+	// inFlight contains the work items currently processed by workers
+	// with the start time of processing. Protected by inFlightMutex.
+	inFlight      map[interface{}]time.Time
+	inFlightMutex sync.Mutex
+
+	// shards is nil if sharding is disabled.
+	shards sharding.Ownership
+
+	// configStore is nil if the configuration is fetched from the API
+	// server for each reconciliation.
+	configStore *cfg.Store
+
+	// cloudEvents is nil if no CloudEvents are emitted.
+	cloudEvents *cloudevents.Emitter
+
+	// notifier delivers the notifications specified in pipeline runs.
+	notifier *notifications.Notifier
+
+	// gitHubApp exchanges GitHub App secrets for installation tokens.
+	gitHubApp *githubapp.Client
 
 	// logger *must* be initialized when creating Controller,
 	// otherwise logging functions will access a nil sink and
@@ -87,7 +151,7 @@ type controllerTesting struct {
 	createRunManagerStub       func(k8s.PipelineRun) run.Manager
 	newRunManagerStub          func(k8s.ClientFactory, secrets.SecretProvider) run.Manager
 	loadPipelineRunsConfigStub func(ctx context.Context) (*cfg.PipelineRunsConfigStruct, error)
-	isMaintenanceModeStub      func(ctx context.Context) (bool, error)
+	getMaintenanceStatusStub   func(ctx context.Context) (maintenancemode.Status, error)
 }
 
 // ControllerOpts stores options for the construction of a Controller
@@ -103,6 +167,47 @@ type ControllerOpts struct {
 
 	// HeartbeatLogLevel is the log level to be used for logging heartbeats.
 	HeartbeatLogLevel int
+
+	// HeartbeatLivenessFactor is the multiple of HeartbeatInterval after
+	// which the liveness check fails if no heartbeat has been processed.
+	// If zero or negative, or if heartbeats are disabled, the liveness
+	// check always succeeds.
+	HeartbeatLivenessFactor int
+
+	// DrainTimeout is the maximum time to wait for in-flight
+	// reconciliations to finish when the controller is stopped.
+	// If zero or negative, the controller does not wait.
+	DrainTimeout time.Duration
+
+	// ConfigStore provides the configuration from watched ConfigMaps.
+	// It must be started by the caller.
+	// If nil, the configuration is fetched from the API server for each
+	// reconciliation.
+	ConfigStore *cfg.Store
+
+	// Shards restricts processing to pipeline runs belonging to shards
+	// owned by this instance.
+	// If nil, sharding is disabled and all pipeline runs are processed.
+	Shards sharding.Ownership
+
+	// CloudEvents emits CloudEvents about lifecycle transitions of
+	// pipeline runs to the configured sinks.
+	// It must be started by the caller.
+	// If nil, no CloudEvents are emitted.
+	CloudEvents *cloudevents.Emitter
+
+	// GitHubApp is the client used to exchange GitHub App secrets for
+	// installation tokens.
+	// If nil, a client connecting to the GitHub API via an HTTP client
+	// created by egress.NewHTTPClient with EgressAllowedNetworks is used.
+	GitHubApp *githubapp.Client
+
+	// EgressAllowedNetworks are the networks notification targets and
+	// GitHub API servers may be located in although their addresses are
+	// loopback, link-local or private addresses.
+	// The networks CloudEvents sinks may be located in are configured
+	// when creating the emitter.
+	EgressAllowedNetworks []*net.IPNet
 }
 
 // NewController creates new Controller
@@ -112,6 +217,7 @@ func NewController(logger logr.Logger, factory k8s.ClientFactory, opts Controlle
 	pipelineRunInformer := factory.StewardInformerFactory().Steward().V1alpha1().PipelineRuns()
 	pipelineRunFetcher := k8s.NewListerBasedPipelineRunFetcher(pipelineRunInformer.Lister())
 	tektonTaskRunInformer := factory.TektonInformerFactory().Tekton().V1beta1().TaskRuns()
+	namespaceInformer := factory.KubernetesInformerFactory().Core().V1().Namespaces()
 
 	controller := &Controller{
 		factory:            factory,
@@ -119,8 +225,15 @@ func NewController(logger logr.Logger, factory k8s.ClientFactory, opts Controlle
 		pipelineRunsSynced: pipelineRunInformer.Informer().HasSynced,
 
 		tektonTaskRunsSynced: tektonTaskRunInformer.Informer().HasSynced,
+		namespaceLister:      namespaceInformer.Lister(),
+		namespacesSynced:     namespaceInformer.Informer().HasSynced,
 		workqueue:            workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), metrics.WorkqueueName),
 		pipelineRunStore:     pipelineRunInformer.Informer().GetStore(),
+		drainTimeout:         opts.DrainTimeout,
+		configStore:          opts.ConfigStore,
+		cloudEvents:          opts.CloudEvents,
+		gitHubApp:            opts.GitHubApp,
+		inFlight:             map[interface{}]time.Time{},
 		logger:               logger,
 	}
 
@@ -142,9 +255,27 @@ func NewController(logger logr.Logger, factory k8s.ClientFactory, opts Controlle
 	)
 	controller.eventRecorder = eventBroadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: "runController"})
 
+	if controller.gitHubApp == nil {
+		controller.gitHubApp = githubapp.NewClient(egress.NewHTTPClient(egress.Options{
+			Timeout:         githubapp.DefaultTimeout,
+			AllowedNetworks: opts.EgressAllowedNetworks,
+		}))
+	}
+
+	controller.notifier = notifications.NewNotifier(logger, factory, notifications.Options{
+		AllowedNetworks: opts.EgressAllowedNetworks,
+		IsOwned:         controller.isOwnedKey,
+	})
+
 	controller.heartbeatInterval = opts.HeartbeatInterval
 	controller.heartbeatLoggingEnabled = opts.HeartbeatLoggingEnabled
 	controller.heartbeatLogLevel = opts.HeartbeatLogLevel
+	controller.heartbeatLivenessFactor = opts.HeartbeatLivenessFactor
+
+	if opts.Shards != nil {
+		controller.shards = opts.Shards
+		controller.shards.SetOnAcquired(controller.addAllToWorkqueue)
+	}
 
 	pipelineRunInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc: controller.addToWorkqueue,
@@ -163,9 +294,13 @@ func NewController(logger logr.Logger, factory k8s.ClientFactory, opts Controlle
 }
 
 // meterAllPipelineRunsPeriodic observes certain metrics of all existing pipeline runs (in the informer cache).
+// The number of pipeline runs is metered only for pipeline runs belonging
+// to shards owned by this instance, so that the sum over all instances is
+// correct.
 func (c *Controller) meterAllPipelineRunsPeriodic() {
 	c.logger.V(4).Info("Metering all pipeline runs")
 	objs := c.pipelineRunStore.List()
+	ownedPipelineRuns := make([]*api.PipelineRun, 0, len(objs))
 	for _, obj := range objs {
 		pipelineRun := obj.(*api.PipelineRun)
 
@@ -173,16 +308,27 @@ func (c *Controller) meterAllPipelineRunsPeriodic() {
 		if pipelineRun.DeletionTimestamp.IsZero() {
 			metrics.PipelineRunsPeriodic.Observe(pipelineRun)
 		}
+
+		if c.shards == nil || c.shards.Owns(c.getWorkqueueKey(pipelineRun)) {
+			ownedPipelineRuns = append(ownedPipelineRuns, pipelineRun)
+		}
 	}
+	metrics.PipelineRunsCount.Set(ownedPipelineRuns)
 }
 
-// Run runs the controller
-func (c *Controller) Run(threadiness int, stopCh <-chan struct{}) error {
+// Run runs the controller.
+// ctx is the parent context of all reconciliations.
+// After stopCh has been closed, Run drains the controller: No new work
+// items are processed and Run waits for in-flight reconciliations to
+// finish, at most for the drain timeout. If ctx has been cancelled, e.g.
+// because the leadership has been lost, in-flight reconciliations are
+// cancelled and Run does not wait for them.
+func (c *Controller) Run(ctx context.Context, threadiness int, stopCh <-chan struct{}) error {
 	defer utilruntime.HandleCrash()
 	defer c.workqueue.ShutDown()
 
 	c.logger.V(2).Info("Waiting for informer caches to sync")
-	if ok := cache.WaitForCacheSync(stopCh, c.pipelineRunsSynced, c.tektonTaskRunsSynced); !ok {
+	if ok := cache.WaitForCacheSync(stopCh, c.cacheSyncFuncs()...); !ok {
 		return fmt.Errorf("failed to wait for caches to sync")
 	}
 
@@ -196,29 +342,108 @@ func (c *Controller) Run(threadiness int, stopCh <-chan struct{}) error {
 		c.logger.V(2).Info("Controller heartbeat stimulus is disabled")
 	}
 
+	c.logger.V(2).Info("Starting notification workers", "workers", notificationWorkers)
+	go c.notifier.Run(notificationWorkers, stopCh)
+
 	c.logger.V(2).Info("Starting workers", "threadiness", threadiness)
+	c.lastHeartbeat.Store(time.Now().UnixNano())
+	defer c.lastHeartbeat.Store(0)
 	for i := 0; i < threadiness; i++ {
-		go wait.Until(c.runWorker, time.Second, stopCh)
+		go wait.Until(func() { c.runWorker(ctx) }, time.Second, stopCh)
 	}
 	c.logger.V(2).Info("Workers are running")
 
 	<-stopCh
+	c.drain(ctx)
 	c.logger.V(2).Info("Workers are stopped")
 	return nil
 }
 
-func (c *Controller) runWorker() {
-	for c.processNextWorkItem() {
+// drain stops processing of new work items and waits for in-flight
+// reconciliations to finish, at most for the drain timeout.
+// It does not wait if ctx, the parent context of the reconciliations, is
+// or gets cancelled.
+func (c *Controller) drain(ctx context.Context) {
+	c.inFlightMutex.Lock()
+	c.draining = true
+	c.inFlightMutex.Unlock()
+	// wake up idle workers, remaining items are not processed anymore
+	c.workqueue.ShutDown()
+
+	if c.drainTimeout <= 0 {
+		return
+	}
+	c.logger.Info("Draining in-flight reconciliations", "timeout", c.drainTimeout)
+	deadline := time.Now().Add(c.drainTimeout)
+	for {
+		keys := c.inFlightKeys()
+		if len(keys) == 0 {
+			c.logger.Info("Drained all in-flight reconciliations")
+			return
+		}
+		if ctx.Err() != nil {
+			c.logger.Info("Not draining, in-flight reconciliations have been cancelled", "keys", keys)
+			return
+		}
+		if time.Now().After(deadline) {
+			c.logger.Error(nil, "Drain timeout exceeded, reconciliations still in progress", "keys", keys)
+			return
+		}
+		time.Sleep(drainPollInterval)
+	}
+}
+
+// startInFlight records the given work item as in-flight. It returns
+// false if the controller is draining and the item must not be processed.
+func (c *Controller) startInFlight(obj interface{}) bool {
+	c.inFlightMutex.Lock()
+	defer c.inFlightMutex.Unlock()
+	if c.draining {
+		return false
+	}
+	c.inFlight[obj] = time.Now()
+	return true
+}
+
+func (c *Controller) doneInFlight(obj interface{}) {
+	c.inFlightMutex.Lock()
+	defer c.inFlightMutex.Unlock()
+	delete(c.inFlight, obj)
+}
+
+// inFlightKeys returns the sorted keys of the work items currently
+// processed by workers.
+func (c *Controller) inFlightKeys() []string {
+	c.inFlightMutex.Lock()
+	defer c.inFlightMutex.Unlock()
+	keys := make([]string, 0, len(c.inFlight))
+	for obj := range c.inFlight {
+		keys = append(keys, fmt.Sprint(obj))
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (c *Controller) runWorker(ctx context.Context) {
+	for c.processNextWorkItem(ctx) {
 	}
 }
 
 // processNextWorkItem will read a single work item off the workqueue and
 // attempt to process it, by calling the syncHandler.
-func (c *Controller) processNextWorkItem() bool {
+// ctx is the parent context of the reconciliation.
+func (c *Controller) processNextWorkItem(ctx context.Context) bool {
 	obj, shutdown := c.workqueue.Get()
 	if shutdown {
 		return false
 	}
+	if !c.startInFlight(obj) {
+		// The controller is draining. Remaining items are picked up
+		// again after restart.
+		c.workqueue.Done(obj)
+		return false
+	}
+	defer c.doneInFlight(obj)
 
 	// We wrap this block in a func so we can defer c.workqueue.Done.
 	err := func(obj interface{}) error {
@@ -245,9 +470,24 @@ func (c *Controller) processNextWorkItem() bool {
 			return nil
 		}
 
+		// Keys of shards not owned (anymore) are dropped. They are enqueued
+		// again by the instance acquiring the shard.
+		// The context is cancelled if the shard is lost while processing.
+		if c.shards != nil && key != heartbeatStimulusKey {
+			var done func()
+			var owned bool
+			ctx, done, owned = c.shards.StartProcessing(ctx, key)
+			if !owned {
+				c.workqueue.Forget(obj)
+				c.logger.V(4).Info("Dropped item of shard not owned", "key", key)
+				return nil
+			}
+			defer done()
+		}
+
 		// Run the syncHandler, passing it the namespace/name string of the
 		// Foo resource to be synced.
-		if err := c.syncHandler(key); err != nil {
+		if err := c.syncHandler(ctx, key); err != nil {
 			// Put the item back on the workqueue to handle any transient errors.
 			c.workqueue.AddRateLimited(key)
 			return fmt.Errorf("error syncing '%s': %s, requeuing", key, err.Error())
@@ -276,6 +516,60 @@ func (c *Controller) heartbeat() {
 		c.logger.V(c.heartbeatLogLevel).Info("heartbeat")
 	}
 	metrics.ControllerHeartbeats.Inc()
+	if c.lastHeartbeat.Load() != 0 {
+		c.lastHeartbeat.Store(time.Now().UnixNano())
+	}
+	if c.configStore != nil {
+		// keep the maintenance state metric up to date even if no
+		// pipeline runs are processed
+		c.getMaintenanceStatus(context.Background())
+	}
+}
+
+// CheckLiveness returns an error if the workers are running but no
+// heartbeat stimulus has been processed within HeartbeatLivenessFactor
+// times the heartbeat interval, i.e. the workers do not drain the work
+// queue anymore.
+// Standby instances, whose workers are not running, are always alive.
+func (c *Controller) CheckLiveness() error {
+	if c.heartbeatInterval <= 0 || c.heartbeatLivenessFactor <= 0 {
+		return nil
+	}
+	last := c.lastHeartbeat.Load()
+	if last == 0 {
+		return nil
+	}
+	threshold := time.Duration(c.heartbeatLivenessFactor) * c.heartbeatInterval
+	if age := time.Since(time.Unix(0, last)); age > threshold {
+		return fmt.Errorf("no heartbeat processed for %s (threshold %s)", age.Round(time.Second), threshold)
+	}
+	return nil
+}
+
+// CheckReadiness returns an error if the informer caches have not been
+// synced yet.
+func (c *Controller) CheckReadiness() error {
+	if !c.pipelineRunsSynced() {
+		return errors.New("pipeline run informer cache not synced")
+	}
+	if !c.tektonTaskRunsSynced() {
+		return errors.New("Tekton task run informer cache not synced")
+	}
+	if !c.namespacesSynced() {
+		return errors.New("namespace informer cache not synced")
+	}
+	if c.configStore != nil && !c.configStore.HasSynced() {
+		return errors.New("configuration informer cache not synced")
+	}
+	return nil
+}
+
+func (c *Controller) cacheSyncFuncs() []cache.InformerSynced {
+	funcs := []cache.InformerSynced{c.pipelineRunsSynced, c.tektonTaskRunsSynced, c.namespacesSynced}
+	if c.configStore != nil {
+		funcs = append(funcs, c.configStore.HasSynced)
+	}
+	return funcs
 }
 
 func (c *Controller) changeState(ctx context.Context, pipelineRun k8s.PipelineRun, state api.State, ts metav1.Time) error {
@@ -292,13 +586,16 @@ func (c *Controller) changeState(ctx context.Context, pipelineRun k8s.PipelineRu
 }
 
 func (c *Controller) createRunManager(pipelineRun k8s.PipelineRun) run.Manager {
+	var runManager run.Manager
 	if c.testing != nil && c.testing.createRunManagerStub != nil {
-		return c.testing.createRunManagerStub(pipelineRun)
+		runManager = c.testing.createRunManagerStub(pipelineRun)
+	} else {
+		namespace := pipelineRun.GetNamespace()
+		secretsClient := c.factory.CoreV1().Secrets(namespace)
+		secretProvider := k8ssecretprovider.NewProvider(secretsClient, namespace)
+		runManager = c.newRunManager(c.factory, secretProvider)
 	}
-	namespace := pipelineRun.GetNamespace()
-	secretsClient := c.factory.CoreV1().Secrets(namespace)
-	secretProvider := k8ssecretprovider.NewProvider(secretsClient, namespace)
-	return c.newRunManager(c.factory, secretProvider)
+	return &instrumentedRunManager{delegate: &tracingRunManager{delegate: runManager}}
 }
 
 func (c *Controller) newRunManager(workFactory k8s.ClientFactory, secretProvider secrets.SecretProvider) run.Manager {
@@ -306,7 +603,7 @@ func (c *Controller) newRunManager(workFactory k8s.ClientFactory, secretProvider
 		return c.testing.newRunManagerStub(workFactory, secretProvider)
 
 	}
-	return runmgr.NewTektonRunManager(workFactory, secretProvider)
+	return runmgr.NewTektonRunManager(workFactory, secretProvider, c.gitHubApp)
 }
 
 func (c *Controller) loadPipelineRunsConfig(ctx context.Context) (*cfg.PipelineRunsConfigStruct, error) {
@@ -316,74 +613,185 @@ func (c *Controller) loadPipelineRunsConfig(ctx context.Context) (*cfg.PipelineR
 	return cfg.FromContext(ctx)
 }
 
-func (c *Controller) isMaintenanceMode(ctx context.Context) (bool, error) {
-	if c.testing != nil && c.testing.isMaintenanceModeStub != nil {
-		return c.testing.isMaintenanceModeStub(ctx)
+// getMaintenanceConfig returns the current maintenance configuration.
+// Invalid parts of the configuration are ignored.
+func (c *Controller) getMaintenanceConfig(ctx context.Context) (*maintenancemode.Config, error) {
+	if c.configStore != nil {
+		return c.configStore.Snapshot().MaintenanceConfig(), nil
 	}
-	return maintenancemode.IsMaintenanceMode(ctx, c.factory)
+	config, err := maintenancemode.LoadConfig(ctx, c.factory)
+	if config == nil {
+		return nil, err
+	}
+	if err != nil {
+		klog.FromContext(ctx).Error(err, "Ignoring invalid maintenance configuration")
+	}
+	return config, nil
+}
+
+// getMaintenanceStatus returns the current global maintenance status and
+// exposes it via metric.
+func (c *Controller) getMaintenanceStatus(ctx context.Context) (maintenancemode.Status, error) {
+	if c.testing != nil && c.testing.getMaintenanceStatusStub != nil {
+		return c.testing.getMaintenanceStatusStub(ctx)
+	}
+	config, err := c.getMaintenanceConfig(ctx)
+	if err != nil {
+		return maintenancemode.Status{}, err
+	}
+	status := config.StatusAt(time.Now())
+	metrics.MaintenanceState.Set(string(status.State))
+	return status, nil
+}
+
+// isNamespaceInMaintenance returns true if the namespace of the given
+// pipeline run is in maintenance mode.
+// The namespace labels are read from the informer cache.
+func (c *Controller) isNamespaceInMaintenance(ctx context.Context, pipelineRun k8s.PipelineRun) (bool, error) {
+	config, err := c.getMaintenanceConfig(ctx)
+	if err != nil {
+		return false, err
+	}
+	namespaceName := pipelineRun.GetNamespace()
+	if config.IsNamespaceInMaintenance(namespaceName, nil) {
+		return true, nil
+	}
+	namespace, err := c.namespaceLister.Get(namespaceName)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return config.IsNamespaceInMaintenance(namespaceName, namespace), nil
+}
+
+// isMaintenanceBypassGranted returns true if the given pipeline run
+// requests to bypass maintenance mode and is allowed to.
+// If record is true, each granted or denied request is logged and
+// recorded as event.
+func (c *Controller) isMaintenanceBypassGranted(ctx context.Context, pipelineRun k8s.PipelineRun, record bool) (bool, error) {
+	reason, found := pipelineRun.GetAPIObject().GetAnnotations()[api.AnnotationMaintenanceBypass]
+	if !found {
+		return false, nil
+	}
+	config, err := c.getMaintenanceConfig(ctx)
+	if err != nil {
+		return false, err
+	}
+	granted := config.IsBypassAllowed(pipelineRun.GetNamespace())
+	if !record {
+		return granted, nil
+	}
+
+	logger := klog.FromContext(ctx)
+	if !granted {
+		logger.Info("Denied bypass of maintenance mode: namespace not allowed", "reason", reason)
+		c.eventRecorder.Eventf(pipelineRun.GetReference(), corev1.EventTypeWarning, api.EventReasonMaintenanceBypassDenied,
+			"bypass of maintenance mode is not allowed in namespace %q", pipelineRun.GetNamespace())
+		return false, nil
+	}
+	logger.Info("Bypassing maintenance mode", "reason", reason)
+	c.eventRecorder.Eventf(pipelineRun.GetReference(), corev1.EventTypeWarning, api.EventReasonMaintenanceBypass,
+		"pipeline run bypasses maintenance mode: %s", reason)
+	return true, nil
 }
 
 // syncHandler compares the actual state with the desired, and attempts to
 // converge the two. It then updates the Status block of the Foo resource
 // with the current status of the resource.
-func (c *Controller) syncHandler(key string) error {
+func (c *Controller) syncHandler(ctx context.Context, key string) error {
 
 	if key == heartbeatStimulusKey {
 		c.heartbeat()
 		return nil
 	}
 
-	ctx := context.Background()
-
 	pipelineRun, err := c.getPipelineRunToProcess(ctx, key)
 	if pipelineRun == nil {
 		// If pipelineRun is not found there is nothing to sync
 		return err
 	}
 
-	ctx = cfg.NewContext(ctx, c.factory)
+	if c.configStore != nil {
+		ctx = cfg.NewContextWithStore(ctx, c.configStore)
+	} else {
+		ctx = cfg.NewContext(ctx, c.factory)
+	}
 
 	logger := c.logger.WithName(reconcilerLoggerName)
 	ctx = klog.NewContext(ctx, logger)
 
-	doReturn, err := c.handlePipelineRunFinalizerAndDeletion(ctx, pipelineRun)
+	ctx, span := startReconcileSpan(ctx, pipelineRun)
+	err = c.reconcile(ctx, pipelineRun)
+	tracing.End(span, err)
+	return err
+}
+
+// reconcile calls the reconciliation handlers for the given pipeline run.
+// Each call is traced and metered.
+func (c *Controller) reconcile(ctx context.Context, pipelineRun k8s.PipelineRun) error {
+	doReturn, err := callHandler(ctx, pipelineRun, "handlePipelineRunFinalizerAndDeletion", func(ctx context.Context) (bool, error) {
+		return c.handlePipelineRunFinalizerAndDeletion(ctx, pipelineRun)
+	})
 	if doReturn || err != nil {
 		return err
 	}
 
-	err = c.handlePipelineRunAbort(ctx, pipelineRun)
+	_, err = callHandler(ctx, pipelineRun, "handlePipelineRunAbort", func(ctx context.Context) (bool, error) {
+		return false, c.handlePipelineRunAbort(ctx, pipelineRun)
+	})
 	if err != nil {
 		return err
 	}
 
-	err = c.handlePipelineRunResultExistsButNotCleaned(ctx, pipelineRun)
+	_, err = callHandler(ctx, pipelineRun, "handlePipelineRunMaintenanceAbort", func(ctx context.Context) (bool, error) {
+		return false, c.handlePipelineRunMaintenanceAbort(ctx, pipelineRun)
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = callHandler(ctx, pipelineRun, "handlePipelineRunResultExistsButNotCleaned", func(ctx context.Context) (bool, error) {
+		return false, c.handlePipelineRunResultExistsButNotCleaned(ctx, pipelineRun)
+	})
 	if err != nil {
 		return err
 	}
 
-	doReturn, err = c.handlePipelineRunNew(ctx, pipelineRun)
+	doReturn, err = callHandler(ctx, pipelineRun, "handlePipelineRunNew", func(ctx context.Context) (bool, error) {
+		return c.handlePipelineRunNew(ctx, pipelineRun)
+	})
 	if doReturn || err != nil {
 		return err
 	}
 
 	runManager := c.createRunManager(pipelineRun)
 
-	doReturn, err = c.handlePipelineRunPrepare(ctx, runManager, pipelineRun)
+	doReturn, err = callHandler(ctx, pipelineRun, "handlePipelineRunPrepare", func(ctx context.Context) (bool, error) {
+		return c.handlePipelineRunPrepare(ctx, runManager, pipelineRun)
+	})
 	if doReturn || err != nil {
 		return err
 	}
 
-	doReturn, err = c.handlePipelineRunWaiting(ctx, runManager, pipelineRun)
+	doReturn, err = callHandler(ctx, pipelineRun, "handlePipelineRunWaiting", func(ctx context.Context) (bool, error) {
+		return c.handlePipelineRunWaiting(ctx, runManager, pipelineRun)
+	})
 	if doReturn || err != nil {
 		return err
 	}
 
-	doReturn, err = c.handlePipelineRunRunning(ctx, runManager, pipelineRun)
+	doReturn, err = callHandler(ctx, pipelineRun, "handlePipelineRunRunning", func(ctx context.Context) (bool, error) {
+		return c.handlePipelineRunRunning(ctx, runManager, pipelineRun)
+	})
 	if doReturn || err != nil {
 		return err
 	}
 
-	doReturn, err = c.handlePipelineRunCleaning(ctx, runManager, pipelineRun)
+	doReturn, err = callHandler(ctx, pipelineRun, "handlePipelineRunCleaning", func(ctx context.Context) (bool, error) {
+		return c.handlePipelineRunCleaning(ctx, runManager, pipelineRun)
+	})
 	if doReturn || err != nil {
 		return err
 	}
@@ -492,16 +900,40 @@ func (c *Controller) handlePipelineRunNew(
 	}
 
 	if pipelineRun.GetStatus().State == api.StateNew {
-		maintenanceMode, err := c.isMaintenanceMode(ctx)
+		maintenanceStatus, err := c.getMaintenanceStatus(ctx)
+		if err != nil {
+			return true, err
+		}
+		if maintenanceStatus.State.BlocksNewPipelineRuns() {
+			bypass, err := c.isMaintenanceBypassGranted(ctx, pipelineRun, true)
+			if err != nil {
+				return true, err
+			}
+			if !bypass {
+				err := fmt.Errorf("pipeline execution is paused while the system is in maintenance mode")
+				c.eventRecorder.Event(pipelineRun.GetReference(), corev1.EventTypeNormal, api.EventReasonMaintenanceMode, err.Error())
+				// Return error that the pipeline stays in the queue and will be processed after switching back to normal mode.
+				return true, err
+			}
+		}
+		namespaceInMaintenance, err := c.isNamespaceInMaintenance(ctx, pipelineRun)
 		if err != nil {
 			return true, err
 		}
-		if maintenanceMode {
-			err := fmt.Errorf("pipeline execution is paused while the system is in maintenance mode")
+		if namespaceInMaintenance {
+			err := fmt.Errorf("pipeline execution is paused while namespace %q is in maintenance mode", pipelineRun.GetNamespace())
 			c.eventRecorder.Event(pipelineRun.GetReference(), corev1.EventTypeNormal, api.EventReasonMaintenanceMode, err.Error())
 			// Return error that the pipeline stays in the queue and will be processed after switching back to normal mode.
 			return true, err
 		}
+		if maintenanceStatus.State == maintenancemode.StateAnnounced {
+			c.eventRecorder.Eventf(pipelineRun.GetReference(), corev1.EventTypeWarning, api.EventReasonMaintenanceAnnounced,
+				"maintenance window %q starts at %s and ends at %s",
+				maintenanceStatus.Window.Name,
+				maintenanceStatus.Start.UTC().Format(time.RFC3339),
+				maintenanceStatus.End.UTC().Format(time.RFC3339),
+			)
+		}
 		if err = c.changeAndCommitStateAndMeter(ctx, pipelineRun, api.StatePreparing, metav1.Now()); err != nil {
 			return true, err
 		}
@@ -532,7 +964,9 @@ func (c *Controller) handlePipelineRunPrepare(
 					"failed to load configuration for pipeline runs",
 				)
 		}
+		pipelineRun.UpdateEffectiveConfig(cfg.NewEffectiveConfig(pipelineRunsConfig, pipelineRun.GetSpec()))
 		namespace, auxNamespace, err := runManager.CreateEnv(ctx, pipelineRun, pipelineRunsConfig)
+		c.recordCopiedSecretsEvent(pipelineRun, namespace)
 		if err != nil {
 			c.eventRecorder.Event(pipelineRun.GetReference(), corev1.EventTypeWarning, api.EventReasonPreparingFailed, err.Error())
 			resultClass := serrors.GetClass(err)
@@ -559,6 +993,26 @@ func (c *Controller) handlePipelineRunPrepare(
 	return false, nil
 }
 
+// recordCopiedSecretsEvent emits an event listing the secrets that have been
+// copied into the run namespace, if any. Secret values are never included.
+func (c *Controller) recordCopiedSecretsEvent(pipelineRun k8s.PipelineRun, runNamespace string) {
+	copiedSecrets := pipelineRun.GetStatus().CopiedSecrets
+	if len(copiedSecrets) == 0 {
+		return
+	}
+	entries := make([]string, 0, len(copiedSecrets))
+	for _, s := range copiedSecrets {
+		entries = append(entries, fmt.Sprintf(
+			"%s secret %q (uid: %s, resourceVersion: %s, type: %s) as %q",
+			s.Purpose, s.SourceName, s.SourceUID, s.SourceResourceVersion, s.Type, s.TargetName,
+		))
+	}
+	c.eventRecorder.Eventf(
+		pipelineRun.GetReference(), corev1.EventTypeNormal, api.EventReasonSecretsCopied,
+		"Copied secrets into run namespace %q: %s", runNamespace, strings.Join(entries, "; "),
+	)
+}
+
 func (c *Controller) handlePipelineRunWaiting(
 	ctx context.Context,
 	runManager run.Manager,
@@ -569,12 +1023,15 @@ func (c *Controller) handlePipelineRunWaiting(
 	if pipelineRun.GetStatus().State == api.StateWaiting {
 		logger.V(3).Info("Waiting for pipeline execution")
 
+		// the reason why the pipeline execution has not started yet, if known
+		var pendingReason *run.PendingReason
+
 		run, err := runManager.GetRun(ctx, pipelineRun)
 		if err != nil {
 			return true, c.updateStateOnError(ctx, pipelineRun, err, api.StateCleaning, api.ResultErrorInfra, errorMessageWaitingFailed)
 		}
 
-		pipelineRunsConfig, err := c.loadPipelineRunsConfig(ctx)
+		pipelineRunsConfig, err := c.getEffectivePipelineRunsConfig(ctx, pipelineRun)
 		if err != nil {
 			return true,
 				c.updateStateOnError(
@@ -599,7 +1056,18 @@ func (c *Controller) handlePipelineRunWaiting(
 				"main pod has not started after %s",
 				waitingTimeout.Duration,
 			)
-			return true, c.handleResultError(ctx, pipelineRun, api.ResultErrorInfra, errorMessageWaitingFailed, err)
+			result := api.ResultErrorInfra
+			if run.GetFailureReason() != "" {
+				err = fmt.Errorf("%w: %s", err, run.GetMessage())
+				result = c.classifyResult(ctx, pipelineRun, run, api.ResultErrorResources)
+			} else if pendingReason != nil {
+				err = fmt.Errorf("%w: %s", err, pendingReason)
+			}
+			err = c.handleResultError(ctx, pipelineRun, result, errorMessageWaitingFailed, err)
+			if err == nil {
+				metrics.PipelineRunsStart.ObserveWaitTimeout()
+			}
+			return true, err
 		}
 
 		if run == nil {
@@ -619,28 +1087,73 @@ func (c *Controller) handlePipelineRunWaiting(
 
 		startTime := run.GetStartTime()
 		if startTime != nil {
-			return true, c.changeAndCommitStateAndMeter(ctx, pipelineRun, api.StateRunning, *startTime)
+			if strings.HasPrefix(pipelineRun.GetStatus().Message, pendingMessagePrefix) {
+				pipelineRun.UpdateMessage("")
+			}
+			pipelineRun.AddNotifications(notifications.ForStart(pipelineRun.GetSpec().Notifications))
+			err = c.changeAndCommitStateAndMeter(ctx, pipelineRun, api.StateRunning, *startTime)
+			if err == nil {
+				metrics.PipelineRunsStart.ObserveStarted(pipelineRun.GetAPIObject(), startTime.Time)
+			}
+			return true, err
 		}
 
+		pendingReason = c.getPendingReason(ctx, runManager, pipelineRun)
 		if isWaitingTimeout() {
 			return failOnWaitingTimeout()
 		}
 
-		taskRunFinished, _ := run.IsFinished()
+		taskRunFinished, result := run.IsFinished()
 		if taskRunFinished /* without having started */ {
 			if run.IsRestartable() {
 				c.eventRecorder.Event(pipelineRun.GetReference(), corev1.EventTypeWarning, api.EventReasonWaitingFailed, "restarting")
 				return c.restartPipelineRun(ctx, runManager, pipelineRun)
 			}
 			err := errors.New("failed to start task run")
+			if run.GetFailureReason() != "" {
+				err = fmt.Errorf("%w: %s", err, run.GetMessage())
+				return true, c.handleResultError(ctx, pipelineRun, c.classifyResult(ctx, pipelineRun, run, result), errorMessageWaitingFailed, err)
+			}
 			return true, c.handleResultError(ctx, pipelineRun, api.ResultErrorInfra, errorMessageWaitingFailed, err)
 		}
 
+		if pendingReason != nil {
+			return true, c.reportPendingReason(ctx, pipelineRun, pendingReason)
+		}
 		return true, nil
 	}
 	return false, nil
 }
 
+// getPendingReason returns the most significant known reason why the
+// pipeline execution has not started yet, or nil if none is known.
+// Failures are logged only, as they must not affect the pipeline run.
+func (c *Controller) getPendingReason(ctx context.Context, runManager run.Manager, pipelineRun k8s.PipelineRun) *run.PendingReason {
+	pendingReason, err := runManager.GetPendingReason(ctx, pipelineRun)
+	if err != nil {
+		klog.FromContext(ctx).V(3).Info("Failed to determine why the pipeline execution has not started yet", "err", err.Error())
+		return nil
+	}
+	return pendingReason
+}
+
+// reportPendingReason reflects the given reason why the pipeline
+// execution has not started yet in the status message of the pipeline
+// run and emits it as warning event for the pipeline run.
+// Nothing is done if the reason has been reported already.
+func (c *Controller) reportPendingReason(ctx context.Context, pipelineRun k8s.PipelineRun, pendingReason *run.PendingReason) error {
+	message := utils.Trim(pendingMessagePrefix + pendingReason.String())
+	if pipelineRun.GetStatus().Message == message {
+		return nil
+	}
+	pipelineRun.UpdateMessage(message)
+	if err := c.commitStatusAndMeter(ctx, pipelineRun); err != nil {
+		return err
+	}
+	c.eventRecorder.Event(pipelineRun.GetReference(), corev1.EventTypeWarning, api.EventReasonRunPending, pendingReason.String())
+	return nil
+}
+
 func (c *Controller) startPipelineRun(
 	ctx context.Context,
 	runManager run.Manager,
@@ -698,6 +1211,7 @@ func (c *Controller) handlePipelineRunRunning(
 		containerInfo := run.GetContainerInfo()
 		pipelineRun.UpdateContainer(ctx, containerInfo)
 		if finished, result := run.IsFinished(); finished {
+			result = c.classifyResult(ctx, pipelineRun, run, result)
 			pipelineRun.UpdateMessage(run.GetMessage())
 			return true, c.updateStateAndResult(ctx, pipelineRun, api.StateCleaning, result, *run.GetCompletionTime())
 		}
@@ -713,6 +1227,40 @@ func (c *Controller) handlePipelineRunRunning(
 	return false, nil
 }
 
+// classifyResult returns the result of a pipeline run whose run has
+// the given result. The result configured in the effective pipeline runs
+// configuration takes precedence if the run failed for a known
+// resource-related reason, or otherwise if the Jenkinsfile Runner
+// exited unsuccessfully with a configured exit code.
+// Failures to load the configuration are logged only.
+func (c *Controller) classifyResult(ctx context.Context, pipelineRun k8s.PipelineRun, r run.Run, result api.Result) api.Result {
+	reason := r.GetFailureReason()
+	var exitCode *int32
+	if reason == "" {
+		if result == api.ResultSuccess || result == api.ResultTimeout {
+			return result
+		}
+		if exitCode = r.GetExitCode(); exitCode == nil {
+			return result
+		}
+	}
+	pipelineRunsConfig, err := c.getEffectivePipelineRunsConfig(ctx, pipelineRun)
+	if err != nil {
+		klog.FromContext(ctx).Error(err, "Failed to load configuration for classifying pipeline run result")
+		return result
+	}
+	if reason != "" {
+		if mappedResult, ok := pipelineRunsConfig.FailureResults[reason]; ok {
+			return mappedResult
+		}
+		return result
+	}
+	if mappedResult, ok := pipelineRunsConfig.JenkinsfileRunnerExitCodeResults[*exitCode]; ok {
+		return mappedResult
+	}
+	return result
+}
+
 func (c *Controller) handlePipelineRunCleaning(
 	ctx context.Context,
 	runManager run.Manager,
@@ -724,6 +1272,7 @@ func (c *Controller) handlePipelineRunCleaning(
 	if pipelineRun.GetStatus().State == api.StateCleaning {
 		logger.V(3).Info("Cleaning up pipeline execution")
 
+		c.captureLogTail(ctx, runManager, pipelineRun)
 		err := runManager.DeleteEnv(ctx, pipelineRun)
 		if err != nil {
 			c.eventRecorder.Event(pipelineRun.GetReference(), corev1.EventTypeWarning, api.EventReasonCleaningFailed, err.Error())
@@ -740,6 +1289,52 @@ func (c *Controller) handlePipelineRunCleaning(
 	return false, nil
 }
 
+// captureLogTail records the end of the Jenkinsfile Runner log in the
+// status of a failed pipeline run, if enabled in the effective pipeline
+// runs configuration. The status is committed with the next state change.
+// Failures are logged only, as they must not prevent the cleanup.
+func (c *Controller) captureLogTail(ctx context.Context, runManager run.Manager, pipelineRun k8s.PipelineRun) {
+	status := pipelineRun.GetStatus()
+	switch status.Result {
+	case api.ResultUndefined, api.ResultSuccess, api.ResultAborted, api.ResultDeleted:
+		return
+	}
+	if status.LogTail != "" {
+		return
+	}
+	logger := klog.FromContext(ctx)
+	pipelineRunsConfig, err := c.getEffectivePipelineRunsConfig(ctx, pipelineRun)
+	if err != nil {
+		logger.Error(err, "Failed to load configuration for capturing log tail")
+		return
+	}
+	if pipelineRunsConfig.LogTailLines <= 0 {
+		return
+	}
+	maxBytes := pipelineRunsConfig.LogTailMaxBytes
+	if maxBytes <= 0 {
+		maxBytes = defaultLogTailMaxBytes
+	}
+	logTail, err := runManager.GetLogTail(ctx, pipelineRun, pipelineRunsConfig.LogTailLines, maxBytes)
+	if err != nil {
+		logger.V(3).Info("Failed to capture log tail", "err", err.Error())
+		return
+	}
+	pipelineRun.UpdateLogTail(logTail)
+}
+
+// getEffectivePipelineRunsConfig returns the configuration frozen in the
+// status of the pipeline run when it has been prepared.
+// Pipeline runs prepared by a previous version of the run controller do not
+// have an effective configuration. For those the current configuration is
+// loaded.
+func (c *Controller) getEffectivePipelineRunsConfig(ctx context.Context, pipelineRun k8s.PipelineRun) (*cfg.PipelineRunsConfigStruct, error) {
+	if effectiveConfig := pipelineRun.GetStatus().EffectiveConfig; effectiveConfig != nil {
+		return cfg.FromEffectiveConfig(effectiveConfig), nil
+	}
+	return c.loadPipelineRunsConfig(ctx)
+}
+
 func (c *Controller) getWaitTimeout(pipelineRunsConfig *cfg.PipelineRunsConfigStruct) *metav1.Duration {
 	timeout := pipelineRunsConfig.TimeoutWait
 	if utils.IsZeroDuration(timeout) {
@@ -787,10 +1382,13 @@ func (c *Controller) changeAndCommitStateAndMeter(ctx context.Context, pipelineR
 
 func (c *Controller) updateStateAndResult(ctx context.Context, pipelineRun k8s.PipelineRun, state api.State, result api.Result, ts metav1.Time) error {
 	pipelineRun.UpdateResult(ctx, result, ts)
+	pipelineRun.AddNotifications(notifications.ForCompletion(pipelineRun.GetSpec().Notifications, result))
 	if err := c.changeAndCommitStateAndMeter(ctx, pipelineRun, state, ts); err != nil {
 		return err
 	}
 	metrics.PipelineRunsResult.Observe(pipelineRun.GetStatus().Result)
+	metrics.PipelineRunsFinished.Observe(pipelineRun.GetNamespace(), pipelineRun.GetStatus().Result)
+	c.emitCloudEvent(ctx, pipelineRun, cloudevents.EventTypeResult)
 	if state == api.StateFinished {
 		return c.finalizePipelineRun(ctx, pipelineRun)
 	}
@@ -800,8 +1398,10 @@ func (c *Controller) updateStateAndResult(ctx context.Context, pipelineRun k8s.P
 func (c *Controller) commitStatusAndMeter(ctx context.Context, pipelineRun k8s.PipelineRun) error {
 	logger := klog.FromContext(ctx)
 
+	ctx, span := tracing.Start(ctx, spanNameCommitStatus)
 	start := time.Now()
 	finishedStates, err := pipelineRun.CommitStatus(ctx)
+	tracing.End(span, err)
 	if err != nil {
 		logger.V(6).Info("Failed to commit pipeline run status", "err", err.Error())
 		return err
@@ -813,9 +1413,55 @@ func (c *Controller) commitStatusAndMeter(ctx context.Context, pipelineRun k8s.P
 	for _, finishedState := range finishedStates {
 		metrics.PipelineRunsStateFinished.Observe(finishedState)
 	}
+	if len(finishedStates) > 0 {
+		c.emitCloudEvent(ctx, pipelineRun, cloudevents.EventTypeStateChanged)
+	}
 	return nil
 }
 
+// emitCloudEvent queues a CloudEvent of the given type about the given
+// pipeline run for delivery to the sink specified in the pipeline run or,
+// if not specified, to the sink in the effective pipeline runs
+// configuration.
+// If there's no sink, no event is emitted.
+// Failures are logged only and never affect the reconciliation.
+func (c *Controller) emitCloudEvent(ctx context.Context, pipelineRun k8s.PipelineRun, eventType string) {
+	if c.cloudEvents == nil {
+		return
+	}
+	logger := klog.FromContext(ctx)
+
+	var runSinkURL, sinkURL string
+	if cloudEventsSpec := pipelineRun.GetSpec().CloudEvents; cloudEventsSpec != nil {
+		runSinkURL = cloudEventsSpec.SinkURL
+	}
+	if runSinkURL == "" {
+		pipelineRunsConfig, err := c.getEffectivePipelineRunsConfig(ctx, pipelineRun)
+		if err != nil {
+			logger.Error(err, "Failed to load configuration for emitting CloudEvent", "type", eventType)
+			return
+		}
+		if pipelineRunsConfig != nil {
+			sinkURL = pipelineRunsConfig.CloudEventsSinkURL
+		}
+		if sinkURL == "" {
+			return
+		}
+	}
+
+	event, err := cloudevents.NewPipelineRunEvent(eventType, pipelineRun.GetAPIObject())
+	if err != nil {
+		logger.Error(err, "Failed to create CloudEvent", "type", eventType)
+		return
+	}
+	if runSinkURL != "" {
+		// specified by the tenant
+		c.cloudEvents.EmitToRunSink(runSinkURL, event)
+		return
+	}
+	c.cloudEvents.Emit(sinkURL, event)
+}
+
 // handlePipelineRunAbort checks if pipeline run should be aborted.
 // If the user requested abortion it updates message, result and state
 // to trigger a cleanup.
@@ -830,11 +1476,72 @@ func (c *Controller) handlePipelineRunAbort(ctx context.Context, pipelineRun k8s
 	return nil
 }
 
+// handlePipelineRunMaintenanceAbort aborts active pipeline runs if a
+// maintenance window with drain mode is in progress and its drain grace
+// period is over. Pipeline runs granted a maintenance bypass are not
+// aborted.
+func (c *Controller) handlePipelineRunMaintenanceAbort(ctx context.Context, pipelineRun k8s.PipelineRun) error {
+	status := pipelineRun.GetStatus()
+	if status.Result != api.ResultUndefined {
+		return nil
+	}
+	switch status.State {
+	case api.StatePreparing, api.StateWaiting, api.StateRunning:
+	default:
+		return nil
+	}
+
+	maintenanceStatus, err := c.getMaintenanceStatus(ctx)
+	if err != nil {
+		return err
+	}
+	if maintenanceStatus.State != maintenancemode.StateAborting {
+		return nil
+	}
+	// the bypass has been recorded when the pipeline run was started
+	bypass, err := c.isMaintenanceBypassGranted(ctx, pipelineRun, false)
+	if err != nil || bypass {
+		return err
+	}
+
+	ctx, logger := log.ExtendContextLoggerWithPipelineRunInfo(ctx, pipelineRun.GetAPIObject())
+	logger.V(3).Info("Pipeline run is aborted due to maintenance window", "maintenanceWindow", maintenanceStatus.Window.Name)
+	message := fmt.Sprintf("Aborted due to maintenance window %q", maintenanceStatus.Window.Name)
+	pipelineRun.UpdateMessage(message)
+	c.eventRecorder.Event(pipelineRun.GetReference(), corev1.EventTypeWarning, api.EventReasonMaintenanceAbort, message)
+	return c.updateStateAndResult(ctx, pipelineRun, api.StateCleaning, api.ResultAborted, metav1.Now())
+}
+
 func (c *Controller) addToWorkqueue(obj interface{}) {
-	if key := c.getWorkqueueKey(obj); key != "" {
+	if key := c.getWorkqueueKey(obj); key != "" && c.isOwnedKey(key) {
 		c.workqueue.Add(key)
 		c.logger.V(4).Info("Added item to workqueue", "key", key)
+
+		// also resumes the delivery of pending notifications after a
+		// restart or the acquisition of a shard
+		if pipelineRun, ok := obj.(*api.PipelineRun); ok && notifications.HasPending(pipelineRun) {
+			c.notifier.Enqueue(key)
+		}
+	}
+}
+
+// addAllToWorkqueue adds all pipeline runs in the informer cache to the
+// work queue which belong to shards owned by this instance.
+func (c *Controller) addAllToWorkqueue() {
+	c.logger.V(3).Info("Adding all pipeline runs of owned shards to workqueue")
+	for _, obj := range c.pipelineRunStore.List() {
+		c.addToWorkqueue(obj)
+	}
+}
+
+// isOwnedKey returns whether the pipeline run with the given key belongs
+// to a shard owned by this instance. Without sharding, all keys are owned.
+func (c *Controller) isOwnedKey(key string) bool {
+	if c.shards == nil || c.shards.Owns(key) {
+		return true
 	}
+	c.logger.V(5).Info("Skipped item of shard not owned", "key", key)
+	return false
 }
 
 func (c *Controller) getWorkqueueKey(obj interface{}) string {
@@ -867,7 +1574,7 @@ func (c *Controller) addToWorkqueueFromAssociated(obj interface{}) {
 	}
 
 	runKey := runmgr.GetPipelineRunKeyAnnotation(object)
-	if runKey != "" {
+	if runKey != "" && c.isOwnedKey(runKey) {
 		c.workqueue.Add(runKey)
 
 		triggerObjectType := (interface{})("unknown")
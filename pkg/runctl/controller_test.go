@@ -5,8 +5,10 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 	"testing"
+	"time"
 
 	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
 	serrors "github.com/SAP/stewardci-core/pkg/errors"
@@ -14,12 +16,14 @@ import (
 	fake "github.com/SAP/stewardci-core/pkg/k8s/fake"
 	mocks "github.com/SAP/stewardci-core/pkg/k8s/mocks"
 	"github.com/SAP/stewardci-core/pkg/k8s/secrets"
+	"github.com/SAP/stewardci-core/pkg/maintenancemode"
 	cfg "github.com/SAP/stewardci-core/pkg/runctl/cfg"
 	metricstesting "github.com/SAP/stewardci-core/pkg/runctl/metrics/testing"
 	run "github.com/SAP/stewardci-core/pkg/runctl/run"
 	runmocks "github.com/SAP/stewardci-core/pkg/runctl/run/mocks"
 	"github.com/SAP/stewardci-core/pkg/runctl/runmgr"
 	runctltesting "github.com/SAP/stewardci-core/pkg/runctl/testing"
+	"github.com/SAP/stewardci-core/pkg/utils"
 	gomock "github.com/golang/mock/gomock"
 	tekton "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
 	assert "gotest.tools/v3/assert"
@@ -28,9 +32,11 @@ import (
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2/ktesting"
 	"knative.dev/pkg/apis"
+	"knative.dev/pkg/system"
 
 	_ "knative.dev/pkg/system/testing"
 )
@@ -70,6 +76,38 @@ func Test__Controller_meterAllPipelineRunsPeriodic(t *testing.T) {
 	c.meterAllPipelineRunsPeriodic()
 }
 
+func Test__Controller_meterAllPipelineRunsPeriodic__CountsOwnedPipelineRuns(t *testing.T) {
+	// no parallel: patching global state
+
+	// SETUP
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockCountMetric := metricstesting.NewMockPipelineRunsCountMetric(mockCtrl)
+	defer metricstesting.PatchPipelineRunsCount(mockCountMetric)()
+
+	ownedRun := fake.PipelineRun("owned", "ns1", api.PipelineSpec{})
+	deletedRun := fake.PipelineRun("deleted", "ns1", api.PipelineSpec{})
+	now := metav1.Now()
+	deletedRun.SetDeletionTimestamp(&now)
+	foreignRun := fake.PipelineRun("foreign", "ns1", api.PipelineSpec{})
+	shards := &fakeShards{owned: map[string]bool{"ns1/owned": true, "ns1/deleted": true}}
+	examinee := newShardedController(t, shards, ownedRun, deletedRun, foreignRun)
+
+	// VERIFY
+	mockCountMetric.EXPECT().Set(gomock.Any()).Do(func(pipelineRuns []*api.PipelineRun) {
+		var names []string
+		for _, run := range pipelineRuns {
+			names = append(names, run.GetName())
+		}
+		sort.Strings(names)
+		assert.DeepEqual(t, []string{"deleted", "owned"}, names)
+	})
+
+	// EXERCISE
+	examinee.meterAllPipelineRunsPeriodic()
+}
+
 func Test__Controller__Success(t *testing.T) {
 	t.Parallel()
 
@@ -207,7 +245,7 @@ func Test__Controller_syncHandler__PipelineRunNotFound(t *testing.T) {
 	examinee.pipelineRunFetcher = mockPipelineRunFetcher
 
 	// EXERCISE
-	err := examinee.syncHandler("foo/bar")
+	err := examinee.syncHandler(context.Background(), "foo/bar")
 
 	// VERIFY
 	assert.NilError(t, err)
@@ -335,7 +373,7 @@ func Test__Controller_syncHandler__PipelineRunIsDeletedAndUnfinished(t *testing.
 				}
 
 				// EXERCISE
-				resultErr := controller.syncHandler("ns1/foo")
+				resultErr := controller.syncHandler(context.Background(), "ns1/foo")
 
 				// VERIFY
 				if test.expectError {
@@ -402,7 +440,7 @@ func Test__Controller_syncHandler__PipelineRunIsDeletedAndFinished(t *testing.T)
 				}
 
 				// EXERCISE
-				resultErr := controller.syncHandler("ns1/foo")
+				resultErr := controller.syncHandler(context.Background(), "ns1/foo")
 
 				// VERIFY
 				assert.NilError(t, resultErr)
@@ -431,7 +469,7 @@ func Test__Controller_syncHandler__PipelineRunIsNew(t *testing.T) {
 			pipelineRunSpec            api.PipelineSpec
 			runManagerExpectation      func(*runmocks.MockManager, *runmocks.MockRun)
 			loadPipelineRunsConfigStub func(ctx context.Context) (*cfg.PipelineRunsConfigStruct, error)
-			isMaintenanceModeStub      func(ctx context.Context) (bool, error)
+			getMaintenanceStatusStub   func(ctx context.Context) (maintenancemode.Status, error)
 			expectedError              error
 			expectedState              api.State
 			expectedResult             api.Result
@@ -460,28 +498,28 @@ func Test__Controller_syncHandler__PipelineRunIsNew(t *testing.T) {
 				expectedResult: api.ResultUndefined,
 			},
 			{
-				name:                  "maintenance_mode_check_fails",
-				pipelineRunSpec:       api.PipelineSpec{},
-				isMaintenanceModeStub: newIsMaintenanceModeStub(false, error1),
-				expectedError:         error1,
-				expectedState:         currentState,
-				expectedResult:        api.ResultUndefined,
+				name:                     "maintenance_mode_check_fails",
+				pipelineRunSpec:          api.PipelineSpec{},
+				getMaintenanceStatusStub: newMaintenanceStatusStub(false, error1),
+				expectedError:            error1,
+				expectedState:            currentState,
+				expectedResult:           api.ResultUndefined,
 			},
 			{
-				name:                  "maintenance_mode_check_fails_but_returns_true",
-				pipelineRunSpec:       api.PipelineSpec{},
-				isMaintenanceModeStub: newIsMaintenanceModeStub(true, error1),
-				expectedError:         error1,
-				expectedState:         currentState,
-				expectedResult:        api.ResultUndefined,
+				name:                     "maintenance_mode_check_fails_but_returns_true",
+				pipelineRunSpec:          api.PipelineSpec{},
+				getMaintenanceStatusStub: newMaintenanceStatusStub(true, error1),
+				expectedError:            error1,
+				expectedState:            currentState,
+				expectedResult:           api.ResultUndefined,
 			},
 			{
-				name:                  "maintenance_mode",
-				pipelineRunSpec:       api.PipelineSpec{},
-				isMaintenanceModeStub: newIsMaintenanceModeStub(true, nil),
-				expectedError:         errors.New("pipeline execution is paused while the system is in maintenance mode"),
-				expectedState:         currentState,
-				expectedResult:        api.ResultUndefined,
+				name:                     "maintenance_mode",
+				pipelineRunSpec:          api.PipelineSpec{},
+				getMaintenanceStatusStub: newMaintenanceStatusStub(true, nil),
+				expectedError:            errors.New("pipeline execution is paused while the system is in maintenance mode"),
+				expectedState:            currentState,
+				expectedResult:           api.ResultUndefined,
 			},
 			{
 				name:            "get_pipelineruns_config_fails/unrecoverable",
@@ -521,18 +559,18 @@ func Test__Controller_syncHandler__PipelineRunIsNew(t *testing.T) {
 				if test.loadPipelineRunsConfigStub == nil {
 					test.loadPipelineRunsConfigStub = newEmptyRunsConfig
 				}
-				if test.isMaintenanceModeStub == nil {
-					test.isMaintenanceModeStub = newIsMaintenanceModeStub(false, nil)
+				if test.getMaintenanceStatusStub == nil {
+					test.getMaintenanceStatusStub = newMaintenanceStatusStub(false, nil)
 				}
 
 				controller.testing = &controllerTesting{
 					createRunManagerStub:       newSimpleCreateRunManagerStub(runManager),
 					loadPipelineRunsConfigStub: test.loadPipelineRunsConfigStub,
-					isMaintenanceModeStub:      test.isMaintenanceModeStub,
+					getMaintenanceStatusStub:   test.getMaintenanceStatusStub,
 				}
 
 				// EXERCISE
-				resultErr := controller.syncHandler("ns1/foo")
+				resultErr := controller.syncHandler(context.Background(), "ns1/foo")
 
 				// VERIFY
 				if test.expectedError != nil {
@@ -727,6 +765,10 @@ func Test__Controller_syncHandler__PipelineRunIsUnfinished(t *testing.T) {
 				},
 				startedAt: longAgo,
 				runManagerExpectation: func(rm *runmocks.MockManager, run *runmocks.MockRun) {
+					run.EXPECT().
+						GetFailureReason().
+						Return("").
+						AnyTimes()
 					rm.EXPECT().
 						GetRun(gomock.Any(), gomock.Any()).
 						Return(run, nil)
@@ -842,6 +884,9 @@ func Test__Controller_syncHandler__PipelineRunIsUnfinished(t *testing.T) {
 					rm.EXPECT().
 						GetRun(gomock.Any(), gomock.Any()).
 						Return(run, nil)
+					rm.EXPECT().
+						GetPendingReason(gomock.Any(), gomock.Any()).
+						Return(nil, nil)
 					run.EXPECT().
 						IsDeleted().
 						Return(false).
@@ -867,9 +912,16 @@ func Test__Controller_syncHandler__PipelineRunIsUnfinished(t *testing.T) {
 				},
 				startedAt: longAgo,
 				runManagerExpectation: func(rm *runmocks.MockManager, run *runmocks.MockRun) {
+					run.EXPECT().
+						GetFailureReason().
+						Return("").
+						AnyTimes()
 					rm.EXPECT().
 						GetRun(gomock.Any(), gomock.Any()).
 						Return(run, nil)
+					rm.EXPECT().
+						GetPendingReason(gomock.Any(), gomock.Any()).
+						Return(nil, nil)
 					run.EXPECT().
 						IsDeleted().
 						Return(false).
@@ -950,6 +1002,9 @@ func Test__Controller_syncHandler__PipelineRunIsUnfinished(t *testing.T) {
 					rm.EXPECT().
 						GetRun(gomock.Any(), gomock.Any()).
 						Return(run, nil)
+					rm.EXPECT().
+						GetPendingReason(gomock.Any(), gomock.Any()).
+						Return(nil, nil)
 
 					run.EXPECT().
 						IsDeleted().
@@ -985,6 +1040,9 @@ func Test__Controller_syncHandler__PipelineRunIsUnfinished(t *testing.T) {
 					rm.EXPECT().
 						GetRun(gomock.Any(), gomock.Any()).
 						Return(run, nil)
+					rm.EXPECT().
+						GetPendingReason(gomock.Any(), gomock.Any()).
+						Return(nil, nil)
 
 					run.EXPECT().
 						IsDeleted().
@@ -1021,6 +1079,9 @@ func Test__Controller_syncHandler__PipelineRunIsUnfinished(t *testing.T) {
 					rm.EXPECT().
 						GetRun(gomock.Any(), gomock.Any()).
 						Return(run, nil)
+					rm.EXPECT().
+						GetPendingReason(gomock.Any(), gomock.Any()).
+						Return(nil, nil)
 
 					run.EXPECT().
 						IsDeleted().
@@ -1054,9 +1115,16 @@ func Test__Controller_syncHandler__PipelineRunIsUnfinished(t *testing.T) {
 					State: api.StateWaiting,
 				},
 				runManagerExpectation: func(rm *runmocks.MockManager, run *runmocks.MockRun) {
+					run.EXPECT().
+						GetFailureReason().
+						Return("").
+						AnyTimes()
 					rm.EXPECT().
 						GetRun(gomock.Any(), gomock.Any()).
 						Return(run, nil)
+					rm.EXPECT().
+						GetPendingReason(gomock.Any(), gomock.Any()).
+						Return(nil, nil)
 
 					run.EXPECT().
 						IsDeleted().
@@ -1157,6 +1225,10 @@ func Test__Controller_syncHandler__PipelineRunIsUnfinished(t *testing.T) {
 					State: api.StateRunning,
 				},
 				runManagerExpectation: func(rm *runmocks.MockManager, run *runmocks.MockRun) {
+					run.EXPECT().
+						GetFailureReason().
+						Return("").
+						AnyTimes()
 					run.EXPECT().
 						GetContainerInfo().
 						Return(&corev1.ContainerState{
@@ -1185,6 +1257,10 @@ func Test__Controller_syncHandler__PipelineRunIsUnfinished(t *testing.T) {
 					State: api.StateRunning,
 				},
 				runManagerExpectation: func(rm *runmocks.MockManager, run *runmocks.MockRun) {
+					run.EXPECT().
+						GetFailureReason().
+						Return("").
+						AnyTimes()
 					run.EXPECT().
 						GetContainerInfo().
 						Return(&corev1.ContainerState{
@@ -1310,11 +1386,11 @@ func Test__Controller_syncHandler__PipelineRunIsUnfinished(t *testing.T) {
 				controller.testing = &controllerTesting{
 					createRunManagerStub:       newSimpleCreateRunManagerStub(runManager),
 					loadPipelineRunsConfigStub: test.loadPipelineRunsConfigStub,
-					isMaintenanceModeStub:      newIsMaintenanceModeStub(maintenanceMode, nil),
+					getMaintenanceStatusStub:   newMaintenanceStatusStub(maintenanceMode, nil),
 				}
 
 				// EXERCISE
-				resultErr := controller.syncHandler("ns1/foo")
+				resultErr := controller.syncHandler(context.Background(), "ns1/foo")
 
 				// VERIFY
 				if test.expectedError != nil {
@@ -1344,245 +1420,1697 @@ func Test__Controller_syncHandler__PipelineRunIsUnfinished(t *testing.T) {
 	}
 }
 
-func Test__Controller_syncHandler__PipelineRunFetchFails_InternalServerError(t *testing.T) {
+func Test__Controller_syncHandler__PipelineRunIsPreparing_RecordsCopiedSecretsEvent(t *testing.T) {
 	t.Parallel()
 
 	// SETUP
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
 
-	ctx := context.Background()
-
-	cf := newFakeClientFactory()
-
-	mockPipelineRunFetcher := mocks.NewMockPipelineRunFetcher(mockCtrl)
-	message := "internal server error 1"
-	mockPipelineRunFetcher.EXPECT().
-		ByKey(ctx, gomock.Any()).
-		Return(nil, k8serrors.NewInternalError(errors.New(message)))
+	pipelineRun := fake.PipelineRun("foo", "ns1", api.PipelineSpec{})
+	pipelineRun.Status.State = api.StatePreparing
+
+	controller, cf := newController(t, pipelineRun)
+	recorder := record.NewFakeRecorder(20)
+	controller.eventRecorder = recorder
+
+	runManager := runmocks.NewMockManager(mockCtrl)
+	runManager.EXPECT().
+		CreateEnv(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, pipelineRun k8s.PipelineRun, _ *cfg.PipelineRunsConfigStruct) (string, string, error) {
+			pipelineRun.UpdateCopiedSecrets([]api.CopiedSecret{
+				{
+					Purpose:               api.SecretPurposePipeline,
+					SourceName:            "secret1",
+					SourceUID:             "uid1",
+					SourceResourceVersion: "42",
+					TargetName:            "secret1-copy",
+					Type:                  corev1.SecretTypeOpaque,
+				},
+			})
+			return "runNamespace1", "", nil
+		})
 
-	examinee := NewController(
-		ktesting.NewLogger(t, ktesting.DefaultConfig),
-		cf,
-		ControllerOpts{},
-	)
-	examinee.pipelineRunFetcher = mockPipelineRunFetcher
+	controller.testing = &controllerTesting{
+		createRunManagerStub:       newSimpleCreateRunManagerStub(runManager),
+		loadPipelineRunsConfigStub: newEmptyRunsConfig,
+		getMaintenanceStatusStub:   newMaintenanceStatusStub(false, nil),
+	}
 
 	// EXERCISE
-	err := examinee.syncHandler("foo/bar")
+	resultErr := controller.syncHandler(context.Background(), "ns1/foo")
 
 	// VERIFY
-	assert.ErrorContains(t, err, message)
+	assert.NilError(t, resultErr)
+
+	result, err := getAPIPipelineRun(cf, "foo", "ns1")
+	assert.NilError(t, err)
+	assert.Equal(t, 1, len(result.Status.CopiedSecrets))
+	assert.Equal(t, "secret1-copy", result.Status.CopiedSecrets[0].TargetName)
+
+	assert.Equal(t, 1, len(recorder.Events))
+	event := <-recorder.Events
+	assert.Equal(t,
+		`Normal SecretsCopied Copied secrets into run namespace "runNamespace1": `+
+			`pipeline secret "secret1" (uid: uid1, resourceVersion: 42, type: Opaque) as "secret1-copy"`,
+		event,
+	)
 }
 
-func Test__Controller_syncHandler__OnTimeout(t *testing.T) {
+func Test__Controller_syncHandler__PipelineRunIsPreparing_StoresEffectiveConfig(t *testing.T) {
 	t.Parallel()
 
 	// SETUP
-	cf := newFakeClientFactory(
-
-		// the content namespace
-		fake.Namespace("content-ns-1"),
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
 
-		// the Steward PipelineRun in status running
-		runctltesting.StewardObjectFromJSON(t, `{
-			"apiVersion": "steward.sap.com/v1alpha1",
-			"kind": "PipelineRun",
-			"metadata": {
-				"name": "run1",
-				"namespace": "content-ns-1",
-				"uid": "a9e79ee8-69a8-4d8b-8a29-f51b53ada9b7"
-			},
-			"spec": {},
-			"status": {
-				"namespace": "steward-run-ns-1",
-				"state": "running"
-			}
-		}`),
+	pipelineRun := fake.PipelineRun("foo", "ns1", api.PipelineSpec{})
+	pipelineRun.Status.State = api.StatePreparing
 
-		// the run namespace
-		// label is required for deletion
-		runctltesting.CoreV1ObjectFromJSON(t, `{
-			"apiVersion": "v1",
-			"kind": "Namespace",
-			"metadata": {
-				"name": "steward-run-ns-1",
-				"labels": {
-					"id": "content1",
-					"prefix": "steward-run"
-				}
-			}
-		}`),
+	controller, cf := newController(t, pipelineRun)
 
-		// the Tekton TaskRun
-		runctltesting.TektonObjectFromJSON(t, `{
-			"apiVersion": "tekton.dev/v1beta1",
-			"kind": "TaskRun",
-			"metadata": {
-				"name": "steward-jenkinsfile-runner",
-				"namespace": "steward-run-ns-1"
-			},
-			"spec": {},
-			"status": {
-				"conditions": [
-					{
-						"lastTransitionTime": "2019-09-16T12:55:40Z",
-						"message": "message from Succeeded condition",
-						"reason": "TaskRunTimeout",
-						"status": "False",
-						"type": "Succeeded"
-					}
-				],
-				"startTime": "2019-09-16T12:45:40Z",
-				"completionTime": "2019-09-16T12:55:40Z"
-			}
-		}`),
+	runManager := runmocks.NewMockManager(mockCtrl)
+	runManager.EXPECT().
+		CreateEnv(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return("runNamespace1", "", nil)
 
-		runctltesting.FakeClusterRole(),
-	)
+	controller.testing = &controllerTesting{
+		createRunManagerStub: newSimpleCreateRunManagerStub(runManager),
+		loadPipelineRunsConfigStub: func(ctx context.Context) (*cfg.PipelineRunsConfigStruct, error) {
+			return &cfg.PipelineRunsConfigStruct{
+				JenkinsfileRunnerImage: "image1",
+				TimeoutWait:            utils.Metav1Duration(3 * time.Minute),
+			}, nil
+		},
+		getMaintenanceStatusStub: newMaintenanceStatusStub(false, nil),
+	}
 
 	// EXERCISE
-	stopCh := startController(t, cf)
-	defer stopController(t, stopCh)
+	resultErr := controller.syncHandler(context.Background(), "ns1/foo")
 
 	// VERIFY
-	// TODO avoid race condition with controller
-	run := getPipelineRun(t, "run1", "content-ns-1", cf)
-	status := run.GetStatus()
+	assert.NilError(t, resultErr)
 
-	assert.Assert(t, status != nil)
-	assert.Equal(t, api.StateFinished, status.State)
-	assert.Equal(t, status.State, status.StateDetails.State)
-	assert.Equal(t, api.ResultTimeout, status.Result)
-	assert.Equal(t, "message from Succeeded condition", status.Message)
+	result, err := getAPIPipelineRun(cf, "foo", "ns1")
+	assert.NilError(t, err)
+	assert.Equal(t, api.StateWaiting, result.Status.State)
+	assert.DeepEqual(t, &api.EffectiveConfig{
+		JenkinsfileRunnerImage: "image1",
+		TimeoutWait:            utils.Metav1Duration(3 * time.Minute),
+	}, result.Status.EffectiveConfig)
 }
 
-func newTestRunManager(workFactory k8s.ClientFactory, secretProvider secrets.SecretProvider) run.Manager {
-	return runmgr.NewTektonRunManager(workFactory, secretProvider)
-}
+func Test__Controller_syncHandler__PipelineRunIsWaiting_MetersStart(t *testing.T) {
+	// no parallel: patching global metric
 
-func startController(t *testing.T, cf *fake.ClientFactory) chan struct{} {
-	stopCh := make(chan struct{})
+	// SETUP
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	pipelineRun := fake.PipelineRun("foo", "ns1", api.PipelineSpec{})
+	pipelineRun.Status.State = api.StateWaiting
+	pipelineRun.Status.StateDetails = api.StateItem{State: api.StateWaiting, StartedAt: metav1.Now()}
+
+	controller, _ := newController(t, pipelineRun)
+
+	startTime := metav1.Now()
+	runMock := runmocks.NewMockRun(mockCtrl)
+	runMock.EXPECT().IsDeleted().Return(false).AnyTimes()
+	runMock.EXPECT().GetStartTime().Return(&startTime).AnyTimes()
+	runManager := runmocks.NewMockManager(mockCtrl)
+	runManager.EXPECT().GetRun(gomock.Any(), gomock.Any()).Return(runMock, nil)
 
-	controller := NewController(
-		ktesting.NewLogger(t, ktesting.DefaultConfig),
-		cf,
-		ControllerOpts{},
-	)
 	controller.testing = &controllerTesting{
-		newRunManagerStub:          newTestRunManager,
+		createRunManagerStub:       newSimpleCreateRunManagerStub(runManager),
 		loadPipelineRunsConfigStub: newEmptyRunsConfig,
-		isMaintenanceModeStub:      newIsMaintenanceModeStub(false, nil),
+		getMaintenanceStatusStub:   newMaintenanceStatusStub(false, nil),
 	}
-	controller.pipelineRunFetcher = k8s.NewClientBasedPipelineRunFetcher(cf.StewardV1alpha1())
 
-	cf.StewardInformerFactory().Start(stopCh)
-	cf.TektonInformerFactory().Start(stopCh)
-	go start(t, controller, stopCh)
-	cf.Sleep("Wait for controller")
-	return stopCh
-}
+	mockMetric := metricstesting.NewMockPipelineRunsStartMetric(mockCtrl)
+	defer metricstesting.PatchPipelineRunsStart(mockMetric)()
 
-func stopController(t *testing.T, stopCh chan struct{}) {
-	t.Log("Trigger controller stop")
-	stopCh <- struct{}{}
-}
+	// VERIFY
+	mockMetric.EXPECT().
+		ObserveStarted(gomock.Any(), startTime.Time).
+		Do(func(run *api.PipelineRun, _ time.Time) {
+			assert.Equal(t, api.StateRunning, run.Status.State)
+			assert.Equal(t, api.StateWaiting, run.Status.StateHistory[len(run.Status.StateHistory)-1].State)
+		})
 
-func start(t *testing.T, controller *Controller, stopCh chan struct{}) {
-	t.Helper()
-	if err := controller.Run(1, stopCh); err != nil {
-		t.Logf("Error running controller %s", err.Error())
-	}
+	// EXERCISE
+	resultErr := controller.syncHandler(context.Background(), "ns1/foo")
+	assert.NilError(t, resultErr)
 }
 
-func getPipelineRun(t *testing.T, name string, namespace string, cf *fake.ClientFactory) k8s.PipelineRun {
-	t.Helper()
-	ctx := context.Background()
-	key := fake.ObjectKey(name, namespace)
-	fetcher := k8s.NewClientBasedPipelineRunFetcher(cf.StewardV1alpha1())
-	pipelineRun, err := fetcher.ByKey(ctx, key)
-	if err != nil {
-		t.Fatalf("could not get pipeline run: %s", err.Error())
-	}
-	wrapper, err := k8s.NewPipelineRun(ctx, pipelineRun, cf)
-	if err != nil {
-		t.Fatalf("could not get pipeline run: %s", err.Error())
-	}
-	return wrapper
-}
+func Test__Controller_syncHandler__PipelineRunIsWaiting_MetersWaitTimeout(t *testing.T) {
+	// no parallel: patching global metric
 
-func createRun(t *testing.T, run *api.PipelineRun, cf *fake.ClientFactory) {
-	t.Helper()
-	ctx := context.Background()
-	_, err := cf.StewardV1alpha1().PipelineRuns(run.GetNamespace()).Create(ctx, run, metav1.CreateOptions{})
-	if err != nil {
-		t.Fatalf("failed to create pipeline run: %s", err.Error())
-	}
-	cf.Sleep("wait for controller to pick up run")
-}
+	// SETUP
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
 
-func getRun(t *testing.T, name, namespace string, cf *fake.ClientFactory) *api.PipelineRun {
-	t.Helper()
-	ctx := context.Background()
-	run, err := cf.StewardV1alpha1().PipelineRuns(namespace).Get(ctx, name, metav1.GetOptions{})
-	if err != nil {
-		t.Fatalf("could not get run: %s", err.Error())
+	pipelineRun := fake.PipelineRun("foo", "ns1", api.PipelineSpec{})
+	pipelineRun.Status.State = api.StateWaiting
+	pipelineRun.Status.StateDetails = api.StateItem{
+		State:     api.StateWaiting,
+		StartedAt: metav1.NewTime(time.Now().Add(-24 * time.Hour)),
 	}
-	return run
-}
 
-func updateRun(t *testing.T, run *api.PipelineRun, namespace string, cf *fake.ClientFactory) *api.PipelineRun {
-	t.Helper()
-	ctx := context.Background()
-	updated, err := cf.StewardV1alpha1().PipelineRuns(namespace).Update(ctx, run, metav1.UpdateOptions{})
-	if err != nil {
-		t.Fatalf("could not update run: %s", err.Error())
-	}
-	return updated
-}
+	controller, cf := newController(t, pipelineRun)
 
-func getTektonTaskRun(t *testing.T, namespace string, cf *fake.ClientFactory) *tekton.TaskRun {
-	t.Helper()
-	ctx := context.Background()
-	taskRun, err := cf.TektonV1beta1().TaskRuns(namespace).Get(ctx, runmgr.JFRTaskRunName, metav1.GetOptions{})
-	if err != nil {
-		t.Fatalf("could not get Tekton task run: %s", err.Error())
-	}
-	return taskRun
-}
+	runMock := runmocks.NewMockRun(mockCtrl)
+	runMock.EXPECT().IsDeleted().Return(false).AnyTimes()
+	runMock.EXPECT().GetStartTime().Return(nil).AnyTimes()
+	runMock.EXPECT().GetFailureReason().Return("").AnyTimes()
+	runManager := runmocks.NewMockManager(mockCtrl)
+	runManager.EXPECT().GetRun(gomock.Any(), gomock.Any()).Return(runMock, nil)
+	runManager.EXPECT().GetPendingReason(gomock.Any(), gomock.Any()).Return(nil, nil)
 
-func updateTektonTaskRun(t *testing.T, taskRun *tekton.TaskRun, namespace string, cf *fake.ClientFactory) *tekton.TaskRun {
-	t.Helper()
-	ctx := context.Background()
-	updated, err := cf.TektonV1beta1().TaskRuns(namespace).Update(ctx, taskRun, metav1.UpdateOptions{})
-	if err != nil {
-		t.Fatalf("could not update Tekton task run: %s", err.Error())
+	controller.testing = &controllerTesting{
+		createRunManagerStub:       newSimpleCreateRunManagerStub(runManager),
+		loadPipelineRunsConfigStub: newEmptyRunsConfig,
+		getMaintenanceStatusStub:   newMaintenanceStatusStub(false, nil),
 	}
-	return updated
-}
 
-func newFakeClientFactory(objects ...runtime.Object) *fake.ClientFactory {
-	cf := fake.NewClientFactory(objects...)
+	mockMetric := metricstesting.NewMockPipelineRunsStartMetric(mockCtrl)
+	defer metricstesting.PatchPipelineRunsStart(mockMetric)()
 
-	cf.KubernetesClientset().PrependReactor("create", "*", fake.GenerateNameReactor(0))
+	// VERIFY
+	mockMetric.EXPECT().ObserveWaitTimeout()
 
-	cf.StewardClientset().PrependReactor("create", "*", fake.NewCreationTimestampReactor())
+	// EXERCISE
+	resultErr := controller.syncHandler(context.Background(), "ns1/foo")
+	assert.NilError(t, resultErr)
 
-	return cf
+	result, err := getAPIPipelineRun(cf, "foo", "ns1")
+	assert.NilError(t, err)
+	assert.Equal(t, api.ResultErrorInfra, result.Status.Result)
 }
 
-func newSimpleCreateRunManagerStub(runManager run.Manager) func(k8s.PipelineRun) run.Manager {
-	return func(k8s.PipelineRun) run.Manager {
-		return runManager
-	}
-}
-func newIsMaintenanceModeStub(maintenanceMode bool, err error) func(ctx context.Context) (bool, error) {
-	return func(ctx context.Context) (bool, error) {
-		return maintenanceMode, err
-	}
-}
+func Test__Controller_syncHandler__PipelineRunIsWaiting_ReportsPendingReason(t *testing.T) {
+	t.Parallel()
 
-func newEmptyRunsConfig(ctx context.Context) (*cfg.PipelineRunsConfigStruct, error) {
-	return &cfg.PipelineRunsConfigStruct{},
-		nil
+	// SETUP
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	pipelineRun := fake.PipelineRun("foo", "ns1", api.PipelineSpec{})
+	pipelineRun.Status.State = api.StateWaiting
+	pipelineRun.Status.StateDetails = api.StateItem{State: api.StateWaiting, StartedAt: metav1.Now()}
+
+	controller, cf := newController(t, pipelineRun)
+	recorder := record.NewFakeRecorder(20)
+	controller.eventRecorder = recorder
+
+	runMock := runmocks.NewMockRun(mockCtrl)
+	runMock.EXPECT().IsDeleted().Return(false).AnyTimes()
+	runMock.EXPECT().GetStartTime().Return(nil).AnyTimes()
+	runMock.EXPECT().IsFinished().Return(false, api.ResultUndefined).AnyTimes()
+	runManager := runmocks.NewMockManager(mockCtrl)
+	runManager.EXPECT().GetRun(gomock.Any(), gomock.Any()).Return(runMock, nil).Times(2)
+	runManager.EXPECT().GetPendingReason(gomock.Any(), gomock.Any()).
+		Return(&run.PendingReason{Reason: "ErrImagePull", Message: "message1"}, nil).Times(2)
+
+	controller.testing = &controllerTesting{
+		createRunManagerStub:       newSimpleCreateRunManagerStub(runManager),
+		loadPipelineRunsConfigStub: newEmptyRunsConfig,
+		getMaintenanceStatusStub:   newMaintenanceStatusStub(false, nil),
+	}
+
+	// EXERCISE
+	resultErr1 := controller.syncHandler(context.Background(), "ns1/foo")
+	resultErr2 := controller.syncHandler(context.Background(), "ns1/foo")
+
+	// VERIFY
+	assert.NilError(t, resultErr1)
+	assert.NilError(t, resultErr2)
+
+	result, err := getAPIPipelineRun(cf, "foo", "ns1")
+	assert.NilError(t, err)
+	assert.Equal(t, api.StateWaiting, result.Status.State)
+	assert.Equal(t, "waiting for main pod to start: ErrImagePull: message1", result.Status.Message)
+
+	// the event is emitted once only as long as the reason is unchanged
+	assert.Equal(t, 1, len(recorder.Events))
+	assert.Equal(t, "Warning RunPending ErrImagePull: message1", <-recorder.Events)
+}
+
+func Test__Controller_syncHandler__PipelineRunIsWaiting_TimeoutMessageContainsPendingReason(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	pipelineRun := fake.PipelineRun("foo", "ns1", api.PipelineSpec{})
+	pipelineRun.Status.State = api.StateWaiting
+	pipelineRun.Status.StateDetails = api.StateItem{
+		State:     api.StateWaiting,
+		StartedAt: metav1.NewTime(time.Now().Add(-24 * time.Hour)),
+	}
+
+	controller, cf := newController(t, pipelineRun)
+
+	runMock := runmocks.NewMockRun(mockCtrl)
+	runMock.EXPECT().IsDeleted().Return(false).AnyTimes()
+	runMock.EXPECT().GetStartTime().Return(nil).AnyTimes()
+	runMock.EXPECT().GetFailureReason().Return("").AnyTimes()
+	runManager := runmocks.NewMockManager(mockCtrl)
+	runManager.EXPECT().GetRun(gomock.Any(), gomock.Any()).Return(runMock, nil)
+	runManager.EXPECT().GetPendingReason(gomock.Any(), gomock.Any()).
+		Return(&run.PendingReason{Reason: "FailedScheduling", Message: "0/3 nodes are available"}, nil)
+
+	controller.testing = &controllerTesting{
+		createRunManagerStub:       newSimpleCreateRunManagerStub(runManager),
+		loadPipelineRunsConfigStub: newEmptyRunsConfig,
+		getMaintenanceStatusStub:   newMaintenanceStatusStub(false, nil),
+	}
+
+	// EXERCISE
+	resultErr := controller.syncHandler(context.Background(), "ns1/foo")
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+
+	result, err := getAPIPipelineRun(cf, "foo", "ns1")
+	assert.NilError(t, err)
+	assert.Equal(t, api.ResultErrorInfra, result.Status.Result)
+	assert.Equal(t,
+		"ERROR: waiting failed: main pod has not started after 10m0s: FailedScheduling: 0/3 nodes are available",
+		result.Status.Message,
+	)
+}
+
+func Test__Controller_syncHandler__PipelineRunIsWaiting_TimeoutWithFailureReason(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	pipelineRun := fake.PipelineRun("foo", "ns1", api.PipelineSpec{})
+	pipelineRun.Status.State = api.StateWaiting
+	pipelineRun.Status.StateDetails = api.StateItem{
+		State:     api.StateWaiting,
+		StartedAt: metav1.NewTime(time.Now().Add(-24 * time.Hour)),
+	}
+
+	controller, cf := newController(t, pipelineRun)
+
+	runMock := runmocks.NewMockRun(mockCtrl)
+	runMock.EXPECT().IsDeleted().Return(false).AnyTimes()
+	runMock.EXPECT().GetStartTime().Return(nil).AnyTimes()
+	runMock.EXPECT().GetFailureReason().Return(run.FailureReasonExceededResourceQuota).AnyTimes()
+	runMock.EXPECT().GetMessage().Return("message1").AnyTimes()
+	runManager := runmocks.NewMockManager(mockCtrl)
+	runManager.EXPECT().GetRun(gomock.Any(), gomock.Any()).Return(runMock, nil)
+	runManager.EXPECT().GetPendingReason(gomock.Any(), gomock.Any()).Return(nil, nil)
+
+	controller.testing = &controllerTesting{
+		createRunManagerStub:       newSimpleCreateRunManagerStub(runManager),
+		loadPipelineRunsConfigStub: newEmptyRunsConfig,
+		getMaintenanceStatusStub:   newMaintenanceStatusStub(false, nil),
+	}
+
+	// EXERCISE
+	resultErr := controller.syncHandler(context.Background(), "ns1/foo")
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+
+	result, err := getAPIPipelineRun(cf, "foo", "ns1")
+	assert.NilError(t, err)
+	assert.Equal(t, api.ResultErrorResources, result.Status.Result)
+	assert.Equal(t,
+		"ERROR: waiting failed: main pod has not started after 10m0s: message1",
+		result.Status.Message,
+	)
+}
+
+func Test__Controller_syncHandler__PipelineRunIsRunning_ConfiguredResult(t *testing.T) {
+	t.Parallel()
+
+	exitCode := func(code int32) *int32 { return &code }
+
+	for _, tc := range []struct {
+		name           string
+		failureReason  string
+		exitCode       *int32
+		runResult      api.Result
+		expectedResult api.Result
+	}{
+		{"no_reason_no_exit_code", "", nil, api.ResultErrorInfra, api.ResultErrorInfra},
+		{"configured_reason", run.FailureReasonOOMKilled, exitCode(137), api.ResultErrorResources, api.ResultErrorContent},
+		{"unconfigured_reason", run.FailureReasonEvicted, nil, api.ResultErrorResources, api.ResultErrorResources},
+		{"configured_exit_code", "", exitCode(4), api.ResultErrorInfra, api.ResultUnstable},
+		{"unconfigured_exit_code", "", exitCode(2), api.ResultErrorContent, api.ResultErrorContent},
+		{"configured_exit_code_success", "", exitCode(0), api.ResultSuccess, api.ResultSuccess},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			pipelineRun := fake.PipelineRun("foo", "ns1", api.PipelineSpec{})
+			pipelineRun.Status.State = api.StateRunning
+
+			controller, cf := newController(t, pipelineRun)
+
+			now := metav1.Now()
+			runMock := runmocks.NewMockRun(mockCtrl)
+			runMock.EXPECT().GetContainerInfo().Return(nil)
+			runMock.EXPECT().IsFinished().Return(true, tc.runResult)
+			runMock.EXPECT().GetFailureReason().Return(tc.failureReason).AnyTimes()
+			runMock.EXPECT().GetExitCode().Return(tc.exitCode).AnyTimes()
+			runMock.EXPECT().GetMessage().Return("message1")
+			runMock.EXPECT().GetCompletionTime().Return(&now)
+			runManager := runmocks.NewMockManager(mockCtrl)
+			runManager.EXPECT().GetRun(gomock.Any(), gomock.Any()).Return(runMock, nil)
+
+			controller.testing = &controllerTesting{
+				createRunManagerStub: newSimpleCreateRunManagerStub(runManager),
+				loadPipelineRunsConfigStub: func(ctx context.Context) (*cfg.PipelineRunsConfigStruct, error) {
+					return &cfg.PipelineRunsConfigStruct{
+						JenkinsfileRunnerExitCodeResults: map[int32]api.Result{
+							0: api.ResultErrorInfra,
+							4: api.ResultUnstable,
+						},
+						FailureResults: map[string]api.Result{
+							run.FailureReasonOOMKilled: api.ResultErrorContent,
+						},
+					}, nil
+				},
+				getMaintenanceStatusStub: newMaintenanceStatusStub(false, nil),
+			}
+
+			// EXERCISE
+			resultErr := controller.syncHandler(context.Background(), "ns1/foo")
+
+			// VERIFY
+			assert.NilError(t, resultErr)
+
+			result, err := getAPIPipelineRun(cf, "foo", "ns1")
+			assert.NilError(t, err)
+			assert.Equal(t, tc.expectedResult, result.Status.Result)
+			assert.Equal(t, "message1", result.Status.Message)
+		})
+	}
+}
+
+func Test__Controller_syncHandler__PipelineRunIsWaiting_StartClearsPendingReason(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	pipelineRun := fake.PipelineRun("foo", "ns1", api.PipelineSpec{})
+	pipelineRun.Status.State = api.StateWaiting
+	pipelineRun.Status.StateDetails = api.StateItem{State: api.StateWaiting, StartedAt: metav1.Now()}
+	pipelineRun.Status.Message = "waiting for main pod to start: ErrImagePull: message1"
+
+	controller, cf := newController(t, pipelineRun)
+
+	now := metav1.Now()
+	runMock := runmocks.NewMockRun(mockCtrl)
+	runMock.EXPECT().IsDeleted().Return(false).AnyTimes()
+	runMock.EXPECT().GetStartTime().Return(&now).AnyTimes()
+	runMock.EXPECT().IsFinished().Return(false, api.ResultUndefined).AnyTimes()
+	runManager := runmocks.NewMockManager(mockCtrl)
+	runManager.EXPECT().GetRun(gomock.Any(), gomock.Any()).Return(runMock, nil).AnyTimes()
+
+	controller.testing = &controllerTesting{
+		createRunManagerStub:       newSimpleCreateRunManagerStub(runManager),
+		loadPipelineRunsConfigStub: newEmptyRunsConfig,
+		getMaintenanceStatusStub:   newMaintenanceStatusStub(false, nil),
+	}
+
+	// EXERCISE
+	resultErr := controller.syncHandler(context.Background(), "ns1/foo")
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+
+	result, err := getAPIPipelineRun(cf, "foo", "ns1")
+	assert.NilError(t, err)
+	assert.Equal(t, api.StateRunning, result.Status.State)
+	assert.Equal(t, "", result.Status.Message)
+}
+
+func Test__Controller_syncHandler__PipelineRunIsWaiting_UsesEffectiveConfig(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	pipelineRun := fake.PipelineRun("foo", "ns1", api.PipelineSpec{})
+	pipelineRun.Status.State = api.StateWaiting
+	pipelineRun.Status.StateDetails.StartedAt = metav1.Now()
+	pipelineRun.Status.EffectiveConfig = &api.EffectiveConfig{
+		JenkinsfileRunnerImage: "image1",
+		TimeoutWait:            utils.Metav1Duration(3 * time.Minute),
+	}
+
+	controller, _ := newController(t, pipelineRun)
+
+	runManager := runmocks.NewMockManager(mockCtrl)
+	runManager.EXPECT().
+		GetRun(gomock.Any(), gomock.Any()).
+		Return(nil, nil)
+	runManager.EXPECT().
+		CreateRun(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ k8s.PipelineRun, pipelineRunsConfig *cfg.PipelineRunsConfigStruct) error {
+			assert.Equal(t, "image1", pipelineRunsConfig.JenkinsfileRunnerImage)
+			assert.DeepEqual(t, utils.Metav1Duration(3*time.Minute), pipelineRunsConfig.TimeoutWait)
+			return nil
+		})
+
+	controller.testing = &controllerTesting{
+		createRunManagerStub: newSimpleCreateRunManagerStub(runManager),
+		loadPipelineRunsConfigStub: func(ctx context.Context) (*cfg.PipelineRunsConfigStruct, error) {
+			t.Error("configuration must not be loaded")
+			return nil, errors.New("unexpected call")
+		},
+		getMaintenanceStatusStub: newMaintenanceStatusStub(false, nil),
+	}
+
+	// EXERCISE
+	resultErr := controller.syncHandler(context.Background(), "ns1/foo")
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+}
+
+func Test__Controller_syncHandler__PipelineRunIsCleaning_CapturesLogTail(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name            string
+		result          api.Result
+		existingLogTail string
+		logTailLines    int64
+		expectCapture   bool
+		expectedLogTail string
+	}{
+		{"failed", api.ResultErrorContent, "", 50, true, "tail1"},
+		{"failed_disabled", api.ResultErrorContent, "", 0, false, ""},
+		{"failed_captured_already", api.ResultErrorContent, "tail0", 50, false, "tail0"},
+		{"success", api.ResultSuccess, "", 50, false, ""},
+		{"aborted", api.ResultAborted, "", 50, false, ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			pipelineRun := fake.PipelineRun("foo", "ns1", api.PipelineSpec{})
+			pipelineRun.Status.State = api.StateCleaning
+			pipelineRun.Status.Result = tc.result
+			pipelineRun.Status.LogTail = tc.existingLogTail
+
+			controller, cf := newController(t, pipelineRun)
+
+			runManager := runmocks.NewMockManager(mockCtrl)
+			if tc.expectCapture {
+				runManager.EXPECT().GetLogTail(gomock.Any(), gomock.Any(), tc.logTailLines, defaultLogTailMaxBytes).Return("tail1", nil)
+			}
+			runManager.EXPECT().DeleteEnv(gomock.Any(), gomock.Any()).Return(nil)
+
+			controller.testing = &controllerTesting{
+				createRunManagerStub: newSimpleCreateRunManagerStub(runManager),
+				loadPipelineRunsConfigStub: func(ctx context.Context) (*cfg.PipelineRunsConfigStruct, error) {
+					return &cfg.PipelineRunsConfigStruct{LogTailLines: tc.logTailLines}, nil
+				},
+				getMaintenanceStatusStub: newMaintenanceStatusStub(false, nil),
+			}
+
+			// EXERCISE
+			resultErr := controller.syncHandler(context.Background(), "ns1/foo")
+
+			// VERIFY
+			assert.NilError(t, resultErr)
+
+			result, err := getAPIPipelineRun(cf, "foo", "ns1")
+			assert.NilError(t, err)
+			assert.Equal(t, api.StateFinished, result.Status.State)
+			assert.Equal(t, tc.expectedLogTail, result.Status.LogTail)
+		})
+	}
+}
+
+func Test__Controller_syncHandler__PipelineRunIsCleaning_LogTailFailureDoesNotPreventCleanup(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	pipelineRun := fake.PipelineRun("foo", "ns1", api.PipelineSpec{})
+	pipelineRun.Status.State = api.StateCleaning
+	pipelineRun.Status.Result = api.ResultErrorInfra
+
+	controller, cf := newController(t, pipelineRun)
+
+	runManager := runmocks.NewMockManager(mockCtrl)
+	runManager.EXPECT().GetLogTail(gomock.Any(), gomock.Any(), int64(50), int64(100)).Return("", errors.New("error1"))
+	runManager.EXPECT().DeleteEnv(gomock.Any(), gomock.Any()).Return(nil)
+
+	controller.testing = &controllerTesting{
+		createRunManagerStub: newSimpleCreateRunManagerStub(runManager),
+		loadPipelineRunsConfigStub: func(ctx context.Context) (*cfg.PipelineRunsConfigStruct, error) {
+			return &cfg.PipelineRunsConfigStruct{LogTailLines: 50, LogTailMaxBytes: 100}, nil
+		},
+		getMaintenanceStatusStub: newMaintenanceStatusStub(false, nil),
+	}
+
+	// EXERCISE
+	resultErr := controller.syncHandler(context.Background(), "ns1/foo")
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+
+	result, err := getAPIPipelineRun(cf, "foo", "ns1")
+	assert.NilError(t, err)
+	assert.Equal(t, api.StateFinished, result.Status.State)
+	assert.Equal(t, "", result.Status.LogTail)
+}
+
+func Test__Controller_syncHandler__PipelineRunIsCleaning_LogTailUsesEffectiveConfig(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	pipelineRun := fake.PipelineRun("foo", "ns1", api.PipelineSpec{})
+	pipelineRun.Status.State = api.StateCleaning
+	pipelineRun.Status.Result = api.ResultErrorContent
+	pipelineRun.Status.EffectiveConfig = &api.EffectiveConfig{LogTailLines: 20, LogTailMaxBytes: 200}
+
+	controller, cf := newController(t, pipelineRun)
+
+	runManager := runmocks.NewMockManager(mockCtrl)
+	runManager.EXPECT().GetLogTail(gomock.Any(), gomock.Any(), int64(20), int64(200)).Return("tail1", nil)
+	runManager.EXPECT().DeleteEnv(gomock.Any(), gomock.Any()).Return(nil)
+
+	controller.testing = &controllerTesting{
+		createRunManagerStub: newSimpleCreateRunManagerStub(runManager),
+		loadPipelineRunsConfigStub: func(ctx context.Context) (*cfg.PipelineRunsConfigStruct, error) {
+			// changed after the pipeline run has been prepared
+			return &cfg.PipelineRunsConfigStruct{LogTailLines: 50, LogTailMaxBytes: 100}, nil
+		},
+		getMaintenanceStatusStub: newMaintenanceStatusStub(false, nil),
+	}
+
+	// EXERCISE
+	resultErr := controller.syncHandler(context.Background(), "ns1/foo")
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+
+	result, err := getAPIPipelineRun(cf, "foo", "ns1")
+	assert.NilError(t, err)
+	assert.Equal(t, "tail1", result.Status.LogTail)
+}
+
+func Test__Controller_classifyResult__UsesEffectiveConfig(t *testing.T) {
+	t.Parallel()
+
+	exitCode := func(code int32) *int32 { return &code }
+
+	for _, tc := range []struct {
+		name            string
+		effectiveConfig *api.EffectiveConfig
+		failureReason   string
+		exitCode        *int32
+		expectedResult  api.Result
+	}{
+		{
+			name: "failure_reason",
+			effectiveConfig: &api.EffectiveConfig{
+				FailureResults: map[string]api.Result{"OOMKilled": api.ResultErrorContent},
+			},
+			failureReason:  "OOMKilled",
+			expectedResult: api.ResultErrorContent,
+		},
+		{
+			name: "exit_code",
+			effectiveConfig: &api.EffectiveConfig{
+				JenkinsfileRunnerExitCodeResults: map[string]api.Result{"3": api.ResultUnstable},
+			},
+			exitCode:       exitCode(3),
+			expectedResult: api.ResultUnstable,
+		},
+		{
+			name:            "not_mapped",
+			effectiveConfig: &api.EffectiveConfig{},
+			failureReason:   "OOMKilled",
+			expectedResult:  api.ResultErrorResources,
+		},
+		{
+			name:           "no_effective_config",
+			failureReason:  "OOMKilled",
+			expectedResult: api.ResultErrorInfra,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			ctx := context.Background()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			apiObj := fake.PipelineRun("foo", "ns1", api.PipelineSpec{})
+			apiObj.Status.EffectiveConfig = tc.effectiveConfig
+			controller, cf := newController(t, apiObj)
+			pipelineRun, err := k8s.NewPipelineRun(ctx, apiObj, cf)
+			assert.NilError(t, err)
+
+			controller.testing = &controllerTesting{
+				loadPipelineRunsConfigStub: func(ctx context.Context) (*cfg.PipelineRunsConfigStruct, error) {
+					return &cfg.PipelineRunsConfigStruct{
+						FailureResults:                   map[string]api.Result{"OOMKilled": api.ResultErrorInfra},
+						JenkinsfileRunnerExitCodeResults: map[int32]api.Result{3: api.ResultErrorInfra},
+					}, nil
+				},
+			}
+
+			run := runmocks.NewMockRun(mockCtrl)
+			run.EXPECT().GetFailureReason().Return(tc.failureReason).AnyTimes()
+			run.EXPECT().GetExitCode().Return(tc.exitCode).AnyTimes()
+
+			// EXERCISE
+			result := controller.classifyResult(ctx, pipelineRun, run, api.ResultErrorResources)
+
+			// VERIFY
+			assert.Equal(t, tc.expectedResult, result)
+		})
+	}
+}
+
+func Test__Controller_syncHandler__PipelineRunIsNew_MaintenanceAnnounced(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	pipelineRun := fake.PipelineRun("foo", "ns1", api.PipelineSpec{})
+	pipelineRun.Status.State = api.StateNew
+
+	controller, cf := newController(t, pipelineRun)
+	recorder := record.NewFakeRecorder(20)
+	controller.eventRecorder = recorder
+
+	runManager := runmocks.NewMockManager(mockCtrl)
+	runManager.EXPECT().
+		CreateEnv(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return("runNamespace1", "", nil)
+
+	controller.testing = &controllerTesting{
+		createRunManagerStub:       newSimpleCreateRunManagerStub(runManager),
+		loadPipelineRunsConfigStub: newEmptyRunsConfig,
+		getMaintenanceStatusStub:   newMaintenanceWindowStatusStub(maintenancemode.StateAnnounced, "window1"),
+	}
+
+	// EXERCISE
+	resultErr := controller.syncHandler(context.Background(), "ns1/foo")
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+
+	result, err := getAPIPipelineRun(cf, "foo", "ns1")
+	assert.NilError(t, err)
+	assert.Equal(t, api.StateWaiting, result.Status.State)
+
+	assert.Equal(t, 1, len(recorder.Events))
+	event := <-recorder.Events
+	assert.Equal(t,
+		`Warning MaintenanceAnnounced maintenance window "window1" starts at 2023-01-01T20:00:00Z and ends at 2023-01-01T22:00:00Z`,
+		event,
+	)
+}
+
+func Test__Controller_syncHandler__PipelineRunIsNew_NamespaceInMaintenance(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name           string
+		configData     map[string]string
+		namespaceLabel string
+	}{
+		{
+			name:       "listed_in_config",
+			configData: map[string]string{api.MaintenanceNamespacesKeyName: "[ns1]"},
+		},
+		{
+			name:           "labelled",
+			namespaceLabel: "true",
+		},
+	} {
+		tc := tc // capture current value before going parallel
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			ctx := context.Background()
+			pipelineRun := fake.PipelineRun("foo", "ns1", api.PipelineSpec{})
+			pipelineRun.Status.State = api.StateNew
+
+			controller, cf := newController(t, pipelineRun)
+			recorder := record.NewFakeRecorder(20)
+			controller.eventRecorder = recorder
+			controller.testing = &controllerTesting{
+				getMaintenanceStatusStub: newMaintenanceStatusStub(false, nil),
+			}
+
+			_, err := cf.CoreV1().ConfigMaps(system.Namespace()).Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: api.MaintenanceModeConfigMapName},
+				Data:       tc.configData,
+			}, metav1.CreateOptions{})
+			assert.NilError(t, err)
+			namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns1"}}
+			if tc.namespaceLabel != "" {
+				namespace.Labels = map[string]string{api.LabelMaintenanceMode: tc.namespaceLabel}
+			}
+			err = cf.KubernetesInformerFactory().Core().V1().Namespaces().Informer().GetIndexer().Add(namespace)
+			assert.NilError(t, err)
+
+			// EXERCISE
+			resultErr := controller.syncHandler(context.Background(), "ns1/foo")
+
+			// VERIFY
+			assert.Error(t, resultErr, `pipeline execution is paused while namespace "ns1" is in maintenance mode`)
+
+			result, err := getAPIPipelineRun(cf, "foo", "ns1")
+			assert.NilError(t, err)
+			assert.Equal(t, api.StateNew, result.Status.State)
+
+			assert.Equal(t, 1, len(recorder.Events))
+			assert.Equal(t, `Normal MaintenanceMode pipeline execution is paused while namespace "ns1" is in maintenance mode`, <-recorder.Events)
+		})
+	}
+}
+
+func Test__Controller_isNamespaceInMaintenance__ReadsNamespaceFromCache(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	ctx := context.Background()
+	pipelineRun := fake.PipelineRun("foo", "ns1", api.PipelineSpec{})
+	controller, cf := newController(t, pipelineRun)
+
+	// only known to the API server, not yet to the informer cache
+	_, err := cf.CoreV1().Namespaces().Create(ctx, &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "ns1",
+			Labels: map[string]string{api.LabelMaintenanceMode: "true"},
+		},
+	}, metav1.CreateOptions{})
+	assert.NilError(t, err)
+	cf.KubernetesClientset().ClearActions()
+
+	run, err := k8s.NewPipelineRun(ctx, pipelineRun, cf)
+	assert.NilError(t, err)
+
+	// EXERCISE
+	result, resultErr := controller.isNamespaceInMaintenance(ctx, run)
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	assert.Assert(t, !result)
+	for _, action := range cf.KubernetesClientset().Actions() {
+		assert.Assert(t, action.GetResource().Resource != "namespaces", "unexpected action: %v", action)
+	}
+}
+
+func Test__Controller_syncHandler__PipelineRunIsNew_MaintenanceBypass(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name             string
+		bypassNamespaces string
+		expectedState    api.State
+		expectedEvents   []string
+	}{
+		{
+			name:             "allowed",
+			bypassNamespaces: "[ns1]",
+			expectedState:    api.StateWaiting,
+			expectedEvents: []string{
+				`Warning MaintenanceBypass pipeline run bypasses maintenance mode: hotfix 123`,
+			},
+		},
+		{
+			name:             "not_allowed",
+			bypassNamespaces: "[other]",
+			expectedState:    api.StateNew,
+			expectedEvents: []string{
+				`Warning MaintenanceBypassDenied bypass of maintenance mode is not allowed in namespace "ns1"`,
+				`Normal MaintenanceMode pipeline execution is paused while the system is in maintenance mode`,
+			},
+		},
+	} {
+		tc := tc // capture current value before going parallel
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			ctx := context.Background()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			pipelineRun := fake.PipelineRun("foo", "ns1", api.PipelineSpec{})
+			pipelineRun.Status.State = api.StateNew
+			pipelineRun.Annotations = map[string]string{api.AnnotationMaintenanceBypass: "hotfix 123"}
+
+			controller, cf := newController(t, pipelineRun)
+			recorder := record.NewFakeRecorder(20)
+			controller.eventRecorder = recorder
+
+			_, err := cf.CoreV1().ConfigMaps(system.Namespace()).Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: api.MaintenanceModeConfigMapName},
+				Data: map[string]string{
+					api.MaintenanceModeKeyName:             "true",
+					api.MaintenanceBypassNamespacesKeyName: tc.bypassNamespaces,
+				},
+			}, metav1.CreateOptions{})
+			assert.NilError(t, err)
+
+			runManager := runmocks.NewMockManager(mockCtrl)
+			runManager.EXPECT().
+				CreateEnv(gomock.Any(), gomock.Any(), gomock.Any()).
+				Return("runNamespace1", "", nil).
+				AnyTimes()
+
+			controller.testing = &controllerTesting{
+				createRunManagerStub:       newSimpleCreateRunManagerStub(runManager),
+				loadPipelineRunsConfigStub: newEmptyRunsConfig,
+			}
+
+			// EXERCISE
+			controller.syncHandler(context.Background(), "ns1/foo")
+
+			// VERIFY
+			result, err := getAPIPipelineRun(cf, "foo", "ns1")
+			assert.NilError(t, err)
+			assert.Equal(t, tc.expectedState, result.Status.State)
+
+			assert.Equal(t, len(tc.expectedEvents), len(recorder.Events))
+			for _, expectedEvent := range tc.expectedEvents {
+				assert.Equal(t, expectedEvent, <-recorder.Events)
+			}
+		})
+	}
+}
+
+func Test__Controller_syncHandler__MaintenanceAborting(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name             string
+		state            api.State
+		maintenanceState maintenancemode.State
+		bypass           bool
+		expectAbort      bool
+	}{
+		{"preparing/aborting", api.StatePreparing, maintenancemode.StateAborting, false, true},
+		{"waiting/aborting", api.StateWaiting, maintenancemode.StateAborting, false, true},
+		{"running/aborting", api.StateRunning, maintenancemode.StateAborting, false, true},
+		{"running/aborting_bypass", api.StateRunning, maintenancemode.StateAborting, true, false},
+		{"new/aborting", api.StateNew, maintenancemode.StateAborting, false, false},
+		{"running/active", api.StateRunning, maintenancemode.StateActive, false, false},
+	} {
+		tc := tc // capture current value before going parallel
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			pipelineRun := fake.PipelineRun("foo", "ns1", api.PipelineSpec{})
+			pipelineRun.Status.State = tc.state
+			pipelineRun.Status.StateDetails.StartedAt = metav1.Now()
+
+			if tc.bypass {
+				pipelineRun.Annotations = map[string]string{api.AnnotationMaintenanceBypass: "hotfix"}
+			}
+
+			controller, cf := newController(t, pipelineRun)
+			recorder := record.NewFakeRecorder(20)
+			controller.eventRecorder = recorder
+
+			if tc.bypass {
+				_, err := cf.CoreV1().ConfigMaps(system.Namespace()).Create(context.Background(), &corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: api.MaintenanceModeConfigMapName},
+					Data:       map[string]string{api.MaintenanceBypassNamespacesKeyName: "[ns1]"},
+				}, metav1.CreateOptions{})
+				assert.NilError(t, err)
+			}
+
+			runManager := runmocks.NewMockManager(mockCtrl)
+			runManager.EXPECT().DeleteEnv(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+			runManager.EXPECT().CreateEnv(gomock.Any(), gomock.Any(), gomock.Any()).Return("", "", errors.New("unexpected")).AnyTimes()
+			runManager.EXPECT().GetRun(gomock.Any(), gomock.Any()).Return(nil, errors.New("unexpected")).AnyTimes()
+
+			controller.testing = &controllerTesting{
+				createRunManagerStub:       newSimpleCreateRunManagerStub(runManager),
+				loadPipelineRunsConfigStub: newEmptyRunsConfig,
+				getMaintenanceStatusStub:   newMaintenanceWindowStatusStub(tc.maintenanceState, "window1"),
+			}
+
+			// EXERCISE
+			controller.syncHandler(context.Background(), "ns1/foo")
+
+			// VERIFY
+			result, err := getAPIPipelineRun(cf, "foo", "ns1")
+			assert.NilError(t, err)
+			if tc.expectAbort {
+				assert.Equal(t, api.ResultAborted, result.Status.Result)
+				assert.Equal(t, api.StateFinished, result.Status.State)
+				assert.Equal(t, `Aborted due to maintenance window "window1"`, result.Status.Message)
+				assert.Assert(t, cmp.Contains(<-recorder.Events, `Warning MaintenanceAbort Aborted due to maintenance window "window1"`))
+			} else {
+				assert.Assert(t, result.Status.Result != api.ResultAborted)
+			}
+		})
+	}
+}
+
+func Test__Controller_syncHandler__PipelineRunFetchFails_InternalServerError(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	ctx := context.Background()
+
+	cf := newFakeClientFactory()
+
+	mockPipelineRunFetcher := mocks.NewMockPipelineRunFetcher(mockCtrl)
+	message := "internal server error 1"
+	mockPipelineRunFetcher.EXPECT().
+		ByKey(ctx, gomock.Any()).
+		Return(nil, k8serrors.NewInternalError(errors.New(message)))
+
+	examinee := NewController(
+		ktesting.NewLogger(t, ktesting.DefaultConfig),
+		cf,
+		ControllerOpts{},
+	)
+	examinee.pipelineRunFetcher = mockPipelineRunFetcher
+
+	// EXERCISE
+	err := examinee.syncHandler(context.Background(), "foo/bar")
+
+	// VERIFY
+	assert.ErrorContains(t, err, message)
+}
+
+func Test__Controller_syncHandler__OnTimeout(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	cf := newFakeClientFactory(
+
+		// the content namespace
+		fake.Namespace("content-ns-1"),
+
+		// the Steward PipelineRun in status running
+		runctltesting.StewardObjectFromJSON(t, `{
+			"apiVersion": "steward.sap.com/v1alpha1",
+			"kind": "PipelineRun",
+			"metadata": {
+				"name": "run1",
+				"namespace": "content-ns-1",
+				"uid": "a9e79ee8-69a8-4d8b-8a29-f51b53ada9b7"
+			},
+			"spec": {},
+			"status": {
+				"namespace": "steward-run-ns-1",
+				"state": "running"
+			}
+		}`),
+
+		// the run namespace
+		// label is required for deletion
+		runctltesting.CoreV1ObjectFromJSON(t, `{
+			"apiVersion": "v1",
+			"kind": "Namespace",
+			"metadata": {
+				"name": "steward-run-ns-1",
+				"labels": {
+					"id": "content1",
+					"prefix": "steward-run"
+				}
+			}
+		}`),
+
+		// the Tekton TaskRun
+		runctltesting.TektonObjectFromJSON(t, `{
+			"apiVersion": "tekton.dev/v1beta1",
+			"kind": "TaskRun",
+			"metadata": {
+				"name": "steward-jenkinsfile-runner",
+				"namespace": "steward-run-ns-1"
+			},
+			"spec": {},
+			"status": {
+				"conditions": [
+					{
+						"lastTransitionTime": "2019-09-16T12:55:40Z",
+						"message": "message from Succeeded condition",
+						"reason": "TaskRunTimeout",
+						"status": "False",
+						"type": "Succeeded"
+					}
+				],
+				"startTime": "2019-09-16T12:45:40Z",
+				"completionTime": "2019-09-16T12:55:40Z"
+			}
+		}`),
+
+		runctltesting.FakeClusterRole(),
+	)
+
+	// EXERCISE
+	stopCh := startController(t, cf)
+	defer stopController(t, stopCh)
+
+	// VERIFY
+	// TODO avoid race condition with controller
+	run := getPipelineRun(t, "run1", "content-ns-1", cf)
+	status := run.GetStatus()
+
+	assert.Assert(t, status != nil)
+	assert.Equal(t, api.StateFinished, status.State)
+	assert.Equal(t, status.State, status.StateDetails.State)
+	assert.Equal(t, api.ResultTimeout, status.Result)
+	assert.Equal(t, "message from Succeeded condition", status.Message)
+}
+
+func newTestRunManager(workFactory k8s.ClientFactory, secretProvider secrets.SecretProvider) run.Manager {
+	return runmgr.NewTektonRunManager(workFactory, secretProvider, nil)
+}
+
+func startController(t *testing.T, cf *fake.ClientFactory) chan struct{} {
+	stopCh := make(chan struct{})
+
+	controller := NewController(
+		ktesting.NewLogger(t, ktesting.DefaultConfig),
+		cf,
+		ControllerOpts{},
+	)
+	controller.testing = &controllerTesting{
+		newRunManagerStub:          newTestRunManager,
+		loadPipelineRunsConfigStub: newEmptyRunsConfig,
+		getMaintenanceStatusStub:   newMaintenanceStatusStub(false, nil),
+	}
+	controller.pipelineRunFetcher = k8s.NewClientBasedPipelineRunFetcher(cf.StewardV1alpha1())
+
+	cf.StewardInformerFactory().Start(stopCh)
+	cf.TektonInformerFactory().Start(stopCh)
+	cf.KubernetesInformerFactory().Start(stopCh)
+	go start(t, controller, stopCh)
+	cf.Sleep("Wait for controller")
+	return stopCh
+}
+
+func stopController(t *testing.T, stopCh chan struct{}) {
+	t.Log("Trigger controller stop")
+	stopCh <- struct{}{}
+}
+
+func start(t *testing.T, controller *Controller, stopCh chan struct{}) {
+	t.Helper()
+	if err := controller.Run(context.Background(), 1, stopCh); err != nil {
+		t.Logf("Error running controller %s", err.Error())
+	}
+}
+
+func getPipelineRun(t *testing.T, name string, namespace string, cf *fake.ClientFactory) k8s.PipelineRun {
+	t.Helper()
+	ctx := context.Background()
+	key := fake.ObjectKey(name, namespace)
+	fetcher := k8s.NewClientBasedPipelineRunFetcher(cf.StewardV1alpha1())
+	pipelineRun, err := fetcher.ByKey(ctx, key)
+	if err != nil {
+		t.Fatalf("could not get pipeline run: %s", err.Error())
+	}
+	wrapper, err := k8s.NewPipelineRun(ctx, pipelineRun, cf)
+	if err != nil {
+		t.Fatalf("could not get pipeline run: %s", err.Error())
+	}
+	return wrapper
+}
+
+func createRun(t *testing.T, run *api.PipelineRun, cf *fake.ClientFactory) {
+	t.Helper()
+	ctx := context.Background()
+	_, err := cf.StewardV1alpha1().PipelineRuns(run.GetNamespace()).Create(ctx, run, metav1.CreateOptions{})
+	if err != nil {
+		t.Fatalf("failed to create pipeline run: %s", err.Error())
+	}
+	cf.Sleep("wait for controller to pick up run")
+}
+
+func getRun(t *testing.T, name, namespace string, cf *fake.ClientFactory) *api.PipelineRun {
+	t.Helper()
+	ctx := context.Background()
+	run, err := cf.StewardV1alpha1().PipelineRuns(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("could not get run: %s", err.Error())
+	}
+	return run
+}
+
+func updateRun(t *testing.T, run *api.PipelineRun, namespace string, cf *fake.ClientFactory) *api.PipelineRun {
+	t.Helper()
+	ctx := context.Background()
+	updated, err := cf.StewardV1alpha1().PipelineRuns(namespace).Update(ctx, run, metav1.UpdateOptions{})
+	if err != nil {
+		t.Fatalf("could not update run: %s", err.Error())
+	}
+	return updated
+}
+
+func getTektonTaskRun(t *testing.T, namespace string, cf *fake.ClientFactory) *tekton.TaskRun {
+	t.Helper()
+	ctx := context.Background()
+	taskRun, err := cf.TektonV1beta1().TaskRuns(namespace).Get(ctx, runmgr.JFRTaskRunName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("could not get Tekton task run: %s", err.Error())
+	}
+	return taskRun
+}
+
+func updateTektonTaskRun(t *testing.T, taskRun *tekton.TaskRun, namespace string, cf *fake.ClientFactory) *tekton.TaskRun {
+	t.Helper()
+	ctx := context.Background()
+	updated, err := cf.TektonV1beta1().TaskRuns(namespace).Update(ctx, taskRun, metav1.UpdateOptions{})
+	if err != nil {
+		t.Fatalf("could not update Tekton task run: %s", err.Error())
+	}
+	return updated
+}
+
+func newFakeClientFactory(objects ...runtime.Object) *fake.ClientFactory {
+	cf := fake.NewClientFactory(objects...)
+
+	cf.KubernetesClientset().PrependReactor("create", "*", fake.GenerateNameReactor(0))
+
+	cf.StewardClientset().PrependReactor("create", "*", fake.NewCreationTimestampReactor())
+
+	return cf
+}
+
+func newSimpleCreateRunManagerStub(runManager run.Manager) func(k8s.PipelineRun) run.Manager {
+	return func(k8s.PipelineRun) run.Manager {
+		return runManager
+	}
+}
+func newMaintenanceStatusStub(maintenanceMode bool, err error) func(ctx context.Context) (maintenancemode.Status, error) {
+	state := maintenancemode.StateInactive
+	if maintenanceMode {
+		state = maintenancemode.StateActive
+	}
+	return func(ctx context.Context) (maintenancemode.Status, error) {
+		return maintenancemode.Status{State: state}, err
+	}
+}
+
+func newMaintenanceWindowStatusStub(state maintenancemode.State, windowName string) func(ctx context.Context) (maintenancemode.Status, error) {
+	start := time.Date(2023, 1, 1, 20, 0, 0, 0, time.UTC)
+	return func(ctx context.Context) (maintenancemode.Status, error) {
+		return maintenancemode.Status{
+			State:  state,
+			Window: &maintenancemode.Window{Name: windowName},
+			Start:  start,
+			End:    start.Add(2 * time.Hour),
+		}, nil
+	}
+}
+
+func newEmptyRunsConfig(ctx context.Context) (*cfg.PipelineRunsConfigStruct, error) {
+	return &cfg.PipelineRunsConfigStruct{},
+		nil
+}
+
+type fakeShards struct {
+	owned      map[string]bool
+	started    []string
+	done       []string
+	cancel     map[string]context.CancelFunc
+	onAcquired func()
+}
+
+func (s *fakeShards) Owns(key string) bool { return s.owned[key] }
+
+func (s *fakeShards) StartProcessing(ctx context.Context, key string) (context.Context, func(), bool) {
+	if !s.owned[key] {
+		return nil, nil, false
+	}
+	s.started = append(s.started, key)
+	ctx, cancel := context.WithCancel(ctx)
+	if s.cancel == nil {
+		s.cancel = map[string]context.CancelFunc{}
+	}
+	s.cancel[key] = cancel
+	return ctx, func() {
+		cancel()
+		s.done = append(s.done, key)
+	}, true
+}
+
+// lose simulates that the shard of the given key is lost.
+func (s *fakeShards) lose(key string) {
+	s.owned[key] = false
+	s.cancel[key]()
+}
+
+func (s *fakeShards) SetOnAcquired(onAcquired func()) { s.onAcquired = onAcquired }
+
+func newShardedController(t *testing.T, shards *fakeShards, runs ...*api.PipelineRun) *Controller {
+	t.Helper()
+	cf := newFakeClientFactory()
+	examinee := NewController(
+		ktesting.NewLogger(t, ktesting.DefaultConfig),
+		cf,
+		ControllerOpts{Shards: shards},
+	)
+	for _, run := range runs {
+		assert.NilError(t, examinee.pipelineRunStore.Add(run))
+	}
+	return examinee
+}
+
+func Test__Controller_addToWorkqueue__Sharding(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	shards := &fakeShards{owned: map[string]bool{"ns1/owned": true}}
+	examinee := newShardedController(t, shards)
+
+	// EXERCISE
+	examinee.addToWorkqueue(fake.PipelineRun("owned", "ns1", api.PipelineSpec{}))
+	examinee.addToWorkqueue(fake.PipelineRun("foreign", "ns1", api.PipelineSpec{}))
+
+	// VERIFY
+	assert.Equal(t, 1, examinee.workqueue.Len())
+	item, _ := examinee.workqueue.Get()
+	assert.Equal(t, "ns1/owned", item)
+}
+
+func Test__Controller_addToWorkqueueFromAssociated__Sharding(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	shards := &fakeShards{owned: map[string]bool{"ns1/owned": true}}
+	examinee := newShardedController(t, shards)
+	newTaskRun := func(runKey string) *tekton.TaskRun {
+		return &tekton.TaskRun{ObjectMeta: metav1.ObjectMeta{
+			Name:        "taskrun1",
+			Namespace:   "runns1",
+			Annotations: map[string]string{"steward.sap.com/pipeline-run-key": runKey},
+		}}
+	}
+
+	// EXERCISE
+	examinee.addToWorkqueueFromAssociated(newTaskRun("ns1/foreign"))
+	examinee.addToWorkqueueFromAssociated(newTaskRun("ns1/owned"))
+
+	// VERIFY
+	assert.Equal(t, 1, examinee.workqueue.Len())
+	item, _ := examinee.workqueue.Get()
+	assert.Equal(t, "ns1/owned", item)
+}
+
+func Test__Controller_addAllToWorkqueue__CalledOnShardAcquired(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	shards := &fakeShards{owned: map[string]bool{}}
+	examinee := newShardedController(t, shards,
+		fake.PipelineRun("run1", "ns1", api.PipelineSpec{}),
+		fake.PipelineRun("run2", "ns1", api.PipelineSpec{}),
+	)
+	assert.Assert(t, shards.onAcquired != nil)
+
+	// EXERCISE
+	shards.owned["ns1/run2"] = true
+	shards.onAcquired()
+
+	// VERIFY
+	assert.Equal(t, 1, examinee.workqueue.Len())
+	item, _ := examinee.workqueue.Get()
+	assert.Equal(t, "ns1/run2", item)
+}
+
+func Test__Controller_processNextWorkItem__Sharding(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	shards := &fakeShards{owned: map[string]bool{}}
+	examinee := newShardedController(t, shards)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockPipelineRunFetcher := mocks.NewMockPipelineRunFetcher(mockCtrl)
+	examinee.pipelineRunFetcher = mockPipelineRunFetcher
+
+	// shard of key has been handed over after the key had been enqueued
+	examinee.workqueue.Add("ns1/handedover")
+	examinee.workqueue.Add("ns1/owned")
+	shards.owned["ns1/owned"] = true
+
+	// EXPECT
+	mockPipelineRunFetcher.EXPECT().
+		ByKey(gomock.Any(), "ns1/owned").
+		Return(nil, nil).
+		Times(1)
+
+	// EXERCISE
+	examinee.processNextWorkItem(context.Background())
+	examinee.processNextWorkItem(context.Background())
+
+	// VERIFY
+	assert.DeepEqual(t, []string{"ns1/owned"}, shards.started)
+	assert.DeepEqual(t, []string{"ns1/owned"}, shards.done)
+	assert.Equal(t, 0, examinee.workqueue.Len())
+}
+
+func Test__Controller_processNextWorkItem__Sharding_ShardLostWhileProcessing(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	shards := &fakeShards{owned: map[string]bool{"ns1/run1": true}}
+	examinee := newShardedController(t, shards)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockPipelineRunFetcher := mocks.NewMockPipelineRunFetcher(mockCtrl)
+	examinee.pipelineRunFetcher = mockPipelineRunFetcher
+	examinee.workqueue.Add("ns1/run1")
+
+	// EXPECT
+	mockPipelineRunFetcher.EXPECT().
+		ByKey(gomock.Any(), "ns1/run1").
+		DoAndReturn(func(ctx context.Context, key string) (*api.PipelineRun, error) {
+			assert.NilError(t, ctx.Err())
+			shards.lose(key)
+			// processing must stop
+			assert.ErrorIs(t, ctx.Err(), context.Canceled)
+			return nil, ctx.Err()
+		})
+
+	// EXERCISE
+	examinee.processNextWorkItem(context.Background())
+
+	// VERIFY
+	assert.DeepEqual(t, []string{"ns1/run1"}, shards.done)
+}
+
+func Test__Controller_CheckLiveness(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name              string
+		heartbeatInterval time.Duration
+		livenessFactor    int
+		lastHeartbeat     time.Duration // age, zero: workers not running
+		expectedError     string
+	}{
+		{"workers_not_running", time.Minute, 5, 0, ""},
+		{"recent_heartbeat", time.Minute, 5, 4 * time.Minute, ""},
+		{"heartbeat_too_old", time.Minute, 5, 6 * time.Minute, "no heartbeat processed for 6m0s (threshold 5m0s)"},
+		{"heartbeats_disabled", 0, 5, 6 * time.Minute, ""},
+		{"liveness_check_disabled", time.Minute, 0, 6 * time.Minute, ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			examinee := NewController(
+				ktesting.NewLogger(t, ktesting.DefaultConfig),
+				newFakeClientFactory(),
+				ControllerOpts{
+					HeartbeatInterval:       tc.heartbeatInterval,
+					HeartbeatLivenessFactor: tc.livenessFactor,
+				},
+			)
+			if tc.lastHeartbeat != 0 {
+				examinee.lastHeartbeat.Store(time.Now().Add(-tc.lastHeartbeat).UnixNano())
+			}
+
+			// EXERCISE
+			err := examinee.CheckLiveness()
+
+			// VERIFY
+			if tc.expectedError == "" {
+				assert.NilError(t, err)
+			} else {
+				assert.Error(t, err, tc.expectedError)
+			}
+		})
+	}
+}
+
+func Test__Controller_heartbeat_UpdatesLastHeartbeat(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	examinee := NewController(
+		ktesting.NewLogger(t, ktesting.DefaultConfig),
+		newFakeClientFactory(),
+		ControllerOpts{HeartbeatInterval: time.Minute, HeartbeatLivenessFactor: 5},
+	)
+	examinee.lastHeartbeat.Store(time.Now().Add(-time.Hour).UnixNano())
+	assert.Assert(t, examinee.CheckLiveness() != nil)
+
+	// EXERCISE
+	err := examinee.syncHandler(context.Background(), heartbeatStimulusKey)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.NilError(t, examinee.CheckLiveness())
+}
+
+func Test__Controller_CheckReadiness(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name                 string
+		pipelineRunsSynced   bool
+		tektonTaskRunsSynced bool
+		namespacesSynced     bool
+		expectedError        string
+	}{
+		{"synced", true, true, true, ""},
+		{"pipeline_runs_not_synced", false, true, true, "pipeline run informer cache not synced"},
+		{"task_runs_not_synced", true, false, true, "Tekton task run informer cache not synced"},
+		{"namespaces_not_synced", true, true, false, "namespace informer cache not synced"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			examinee := NewController(
+				ktesting.NewLogger(t, ktesting.DefaultConfig),
+				newFakeClientFactory(),
+				ControllerOpts{},
+			)
+			examinee.pipelineRunsSynced = func() bool { return tc.pipelineRunsSynced }
+			examinee.tektonTaskRunsSynced = func() bool { return tc.tektonTaskRunsSynced }
+			examinee.namespacesSynced = func() bool { return tc.namespacesSynced }
+
+			// EXERCISE
+			err := examinee.CheckReadiness()
+
+			// VERIFY
+			if tc.expectedError == "" {
+				assert.NilError(t, err)
+			} else {
+				assert.Error(t, err, tc.expectedError)
+			}
+		})
+	}
+}
+
+func Test__Controller_drain_WaitsForInFlightReconciliations(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	examinee := NewController(
+		ktesting.NewLogger(t, ktesting.DefaultConfig),
+		newFakeClientFactory(),
+		ControllerOpts{DrainTimeout: 10 * time.Second},
+	)
+	assert.Assert(t, examinee.startInFlight("ns1/run1"))
+	drained := make(chan struct{})
+
+	// EXERCISE
+	go func() {
+		defer close(drained)
+		examinee.drain(context.Background())
+	}()
+
+	// VERIFY
+	select {
+	case <-drained:
+		t.Fatal("drain returned while reconciliation is in progress")
+	case <-time.After(300 * time.Millisecond):
+	}
+	assert.Assert(t, !examinee.startInFlight("ns1/run2"), "must not start new reconciliations while draining")
+	examinee.doneInFlight("ns1/run1")
+	select {
+	case <-drained:
+	case <-time.After(10 * time.Second):
+		t.Fatal("timeout waiting for drain to return")
+	}
+}
+
+func Test__Controller_drain_LogsInFlightKeysOnTimeout(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	logger := ktesting.NewLogger(t, ktesting.NewConfig(ktesting.BufferLogs(true)))
+	examinee := NewController(
+		logger,
+		newFakeClientFactory(),
+		ControllerOpts{DrainTimeout: 200 * time.Millisecond},
+	)
+	assert.Assert(t, examinee.startInFlight("ns1/run2"))
+	assert.Assert(t, examinee.startInFlight("ns1/run1"))
+
+	// EXERCISE
+	examinee.drain(context.Background())
+
+	// VERIFY
+	assert.DeepEqual(t, []string{"ns1/run1", "ns1/run2"}, examinee.inFlightKeys())
+	logs := logger.GetSink().(ktesting.Underlier).GetBuffer().String()
+	assert.Assert(t, cmp.Contains(logs, "Drain timeout exceeded, reconciliations still in progress"))
+	assert.Assert(t, cmp.Contains(logs, `keys=["ns1/run1","ns1/run2"]`))
+}
+
+func Test__Controller_drain_DoesNotWaitIfCancelled(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	logger := ktesting.NewLogger(t, ktesting.NewConfig(ktesting.BufferLogs(true)))
+	examinee := NewController(
+		logger,
+		newFakeClientFactory(),
+		ControllerOpts{DrainTimeout: time.Hour},
+	)
+	assert.Assert(t, examinee.startInFlight("ns1/run1"))
+	// e.g. leadership lost
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// EXERCISE
+	start := time.Now()
+	examinee.drain(ctx)
+
+	// VERIFY
+	assert.Assert(t, time.Since(start) < time.Minute)
+	logs := logger.GetSink().(ktesting.Underlier).GetBuffer().String()
+	assert.Assert(t, cmp.Contains(logs, "Not draining, in-flight reconciliations have been cancelled"))
+}
+
+func Test__Controller_processNextWorkItem__PassesContext(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	examinee := NewController(
+		ktesting.NewLogger(t, ktesting.DefaultConfig),
+		newFakeClientFactory(),
+		ControllerOpts{},
+	)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockPipelineRunFetcher := mocks.NewMockPipelineRunFetcher(mockCtrl)
+	examinee.pipelineRunFetcher = mockPipelineRunFetcher
+	examinee.workqueue.Add("ns1/run1")
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// EXPECT
+	mockPipelineRunFetcher.EXPECT().
+		ByKey(gomock.Any(), "ns1/run1").
+		DoAndReturn(func(ctx context.Context, key string) (*api.PipelineRun, error) {
+			// e.g. leadership lost while processing
+			cancel()
+			assert.ErrorIs(t, ctx.Err(), context.Canceled)
+			return nil, ctx.Err()
+		})
+
+	// EXERCISE
+	examinee.processNextWorkItem(ctx)
+}
+
+func Test__Controller_processNextWorkItem__Draining(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	examinee := NewController(
+		ktesting.NewLogger(t, ktesting.DefaultConfig),
+		newFakeClientFactory(),
+		ControllerOpts{},
+	)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockPipelineRunFetcher := mocks.NewMockPipelineRunFetcher(mockCtrl)
+	examinee.pipelineRunFetcher = mockPipelineRunFetcher
+	examinee.workqueue.Add("ns1/run1")
+	examinee.workqueue.Add("ns1/run2")
+
+	// EXPECT
+	mockPipelineRunFetcher.EXPECT().ByKey(gomock.Any(), gomock.Any()).Times(0)
+
+	// EXERCISE
+	examinee.drain(context.Background())
+	result1 := examinee.processNextWorkItem(context.Background())
+	result2 := examinee.processNextWorkItem(context.Background())
+
+	// VERIFY
+	assert.Assert(t, !result1)
+	assert.Assert(t, !result2)
+	assert.Equal(t, 0, len(examinee.inFlightKeys()))
+}
+
+func Test__Controller_getMaintenanceStatus__ConfigStore(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	cf := newFakeClientFactory(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      api.MaintenanceModeConfigMapName,
+			Namespace: system.Namespace(),
+		},
+		Data: map[string]string{api.MaintenanceModeKeyName: "true"},
+	})
+	logger := ktesting.NewLogger(t, ktesting.DefaultConfig)
+	store := cfg.NewStore(logger, cf.CoreV1(), 0)
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	store.Start(stopCh)
+	assert.Assert(t, cache.WaitForCacheSync(stopCh, store.HasSynced))
+	examinee := NewController(logger, cf, ControllerOpts{ConfigStore: store})
+
+	// EXERCISE
+	result, err := examinee.getMaintenanceStatus(context.Background())
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Equal(t, maintenancemode.StateActive, result.State)
 }
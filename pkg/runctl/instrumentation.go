@@ -0,0 +1,117 @@
+package runctl
+
+import (
+	"context"
+	"time"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/k8s"
+	"github.com/SAP/stewardci-core/pkg/runctl/cfg"
+	"github.com/SAP/stewardci-core/pkg/runctl/metrics"
+	run "github.com/SAP/stewardci-core/pkg/runctl/run"
+)
+
+// callHandler calls the given reconciliation handler for the given
+// pipeline run via traceHandler.
+//
+// If the handler processed the pipeline run, i.e. it requested to return,
+// returned an error, changed the state or set a result, the duration of
+// the call is observed by metric ReconcilePhaseDuration. The outcome is the result set by the
+// handler, if any, otherwise derived from the returned error.
+func callHandler(
+	ctx context.Context,
+	pipelineRun k8s.PipelineRun,
+	phase string,
+	handler func(context.Context) (bool, error),
+) (bool, error) {
+	stateBefore := pipelineRun.GetStatus().State
+	resultBefore := pipelineRun.GetStatus().Result
+
+	start := time.Now()
+	doReturn, err := traceHandler(ctx, phase, handler)
+	elapsed := time.Since(start)
+
+	result := pipelineRun.GetStatus().Result
+	resultChanged := result != resultBefore && result != api.ResultUndefined
+	stateChanged := pipelineRun.GetStatus().State != stateBefore
+	if doReturn || err != nil || stateChanged || resultChanged {
+		outcome := metrics.OutcomeOf(err)
+		if err == nil && resultChanged {
+			outcome = string(result)
+		}
+		metrics.ReconcilePhaseDuration.Observe(phase, outcome, elapsed)
+	}
+	return doReturn, err
+}
+
+// instrumentedRunManager is a run.Manager observing the duration of each
+// call delegated to another run.Manager by metric RunManagerCallDuration.
+type instrumentedRunManager struct {
+	delegate run.Manager
+}
+
+var _ run.Manager = (*instrumentedRunManager)(nil)
+
+// CreateEnv implements run.Manager.
+func (m *instrumentedRunManager) CreateEnv(ctx context.Context, pipelineRun k8s.PipelineRun, pipelineRunsConfig *cfg.PipelineRunsConfigStruct) (namespace string, auxNamespace string, err error) {
+	err = m.call(ctx, "CreateEnv", func(ctx context.Context) (err error) {
+		namespace, auxNamespace, err = m.delegate.CreateEnv(ctx, pipelineRun, pipelineRunsConfig)
+		return err
+	})
+	return namespace, auxNamespace, err
+}
+
+// CreateRun implements run.Manager.
+func (m *instrumentedRunManager) CreateRun(ctx context.Context, pipelineRun k8s.PipelineRun, pipelineRunsConfig *cfg.PipelineRunsConfigStruct) error {
+	return m.call(ctx, "CreateRun", func(ctx context.Context) error {
+		return m.delegate.CreateRun(ctx, pipelineRun, pipelineRunsConfig)
+	})
+}
+
+// GetRun implements run.Manager.
+func (m *instrumentedRunManager) GetRun(ctx context.Context, pipelineRun k8s.PipelineRun) (result run.Run, err error) {
+	err = m.call(ctx, "GetRun", func(ctx context.Context) (err error) {
+		result, err = m.delegate.GetRun(ctx, pipelineRun)
+		return err
+	})
+	return result, err
+}
+
+// GetPendingReason implements run.Manager.
+func (m *instrumentedRunManager) GetPendingReason(ctx context.Context, pipelineRun k8s.PipelineRun) (result *run.PendingReason, err error) {
+	err = m.call(ctx, "GetPendingReason", func(ctx context.Context) (err error) {
+		result, err = m.delegate.GetPendingReason(ctx, pipelineRun)
+		return err
+	})
+	return result, err
+}
+
+// GetLogTail implements run.Manager.
+func (m *instrumentedRunManager) GetLogTail(ctx context.Context, pipelineRun k8s.PipelineRun, maxLines, maxBytes int64) (result string, err error) {
+	err = m.call(ctx, "GetLogTail", func(ctx context.Context) (err error) {
+		result, err = m.delegate.GetLogTail(ctx, pipelineRun, maxLines, maxBytes)
+		return err
+	})
+	return result, err
+}
+
+// DeleteRun implements run.Manager.
+func (m *instrumentedRunManager) DeleteRun(ctx context.Context, pipelineRun k8s.PipelineRun) error {
+	return m.call(ctx, "DeleteRun", func(ctx context.Context) error {
+		return m.delegate.DeleteRun(ctx, pipelineRun)
+	})
+}
+
+// DeleteEnv implements run.Manager.
+func (m *instrumentedRunManager) DeleteEnv(ctx context.Context, pipelineRun k8s.PipelineRun) error {
+	return m.call(ctx, "DeleteEnv", func(ctx context.Context) error {
+		return m.delegate.DeleteEnv(ctx, pipelineRun)
+	})
+}
+
+func (m *instrumentedRunManager) call(ctx context.Context, method string, fn func(context.Context) error) error {
+	start := time.Now()
+	err := fn(ctx)
+	metrics.RunManagerCallDuration.Observe(method, metrics.OutcomeOf(err), time.Since(start))
+	return err
+}
@@ -0,0 +1,166 @@
+package runctl
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	serrors "github.com/SAP/stewardci-core/pkg/errors"
+	k8s "github.com/SAP/stewardci-core/pkg/k8s"
+	fake "github.com/SAP/stewardci-core/pkg/k8s/fake"
+	metricstesting "github.com/SAP/stewardci-core/pkg/runctl/metrics/testing"
+	runmocks "github.com/SAP/stewardci-core/pkg/runctl/run/mocks"
+	gomock "github.com/golang/mock/gomock"
+	assert "gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_callHandler(t *testing.T) {
+	// no parallel: patching global metric
+
+	error1 := errors.New("error1")
+
+	for _, tc := range []struct {
+		name            string
+		doReturn        bool
+		err             error
+		result          api.Result
+		state           api.State
+		expectedOutcome string
+	}{
+		{"not_processed", false, nil, "", "", ""},
+		{"returned", true, nil, "", "", "success"},
+		{"state_changed", false, nil, "", api.StateCleaning, "success"},
+		{"result_set", true, nil, api.ResultErrorInfra, api.StateCleaning, "error_infra"},
+		{"error", true, error1, "", "", "error"},
+		{"recoverable_error", true, serrors.Recoverable(error1), "", "", "recoverable_error"},
+		{"classified_error", true, serrors.Classify(error1, api.ResultErrorContent), "", "", "error_content"},
+		{"error_and_result_set", true, error1, api.ResultErrorInfra, "", "error"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// SETUP
+			ctx := context.Background()
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockMetric := metricstesting.NewMockOperationDurationMetric(mockCtrl)
+			defer metricstesting.PatchReconcilePhaseDuration(mockMetric)()
+			if tc.expectedOutcome != "" {
+				mockMetric.EXPECT().Observe("phase1", tc.expectedOutcome, gomock.Any())
+			}
+
+			apiObj := fake.PipelineRun("foo", "ns1", api.PipelineSpec{})
+			apiObj.Status.State = api.StateRunning
+			pipelineRun, err := k8s.NewPipelineRun(ctx, apiObj, fake.NewClientFactory(apiObj))
+			assert.NilError(t, err)
+
+			// EXERCISE
+			resultDoReturn, resultErr := callHandler(ctx, pipelineRun, "phase1", func(ctx context.Context) (bool, error) {
+				if tc.state != "" {
+					assert.NilError(t, pipelineRun.UpdateState(ctx, tc.state, metav1.Now()))
+				}
+				if tc.result != "" {
+					pipelineRun.UpdateResult(ctx, tc.result, metav1.Now())
+				}
+				return tc.doReturn, tc.err
+			})
+
+			// VERIFY
+			assert.Equal(t, tc.doReturn, resultDoReturn)
+			assert.Equal(t, tc.err, resultErr)
+		})
+	}
+}
+
+func Test_instrumentedRunManager_CreateEnv(t *testing.T) {
+	// no parallel: patching global metric
+
+	// SETUP
+	ctx := context.Background()
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	error1 := serrors.Classify(errors.New("error1"), api.ResultErrorInfra)
+	delegate := runmocks.NewMockManager(mockCtrl)
+	delegate.EXPECT().CreateEnv(gomock.Any(), nil, nil).Return("ns1", "ns2", error1)
+	mockMetric := metricstesting.NewMockOperationDurationMetric(mockCtrl)
+	defer metricstesting.PatchRunManagerCallDuration(mockMetric)()
+	mockMetric.EXPECT().Observe("CreateEnv", "error_infra", gomock.Any())
+	examinee := &instrumentedRunManager{delegate: delegate}
+
+	// EXERCISE
+	resultNamespace, resultAuxNamespace, resultErr := examinee.CreateEnv(ctx, nil, nil)
+
+	// VERIFY
+	assert.Equal(t, error1, resultErr)
+	assert.Equal(t, "ns1", resultNamespace)
+	assert.Equal(t, "ns2", resultAuxNamespace)
+}
+
+func Test_instrumentedRunManager_GetRun(t *testing.T) {
+	// no parallel: patching global metric
+
+	// SETUP
+	ctx := context.Background()
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	run1 := runmocks.NewMockRun(mockCtrl)
+	delegate := runmocks.NewMockManager(mockCtrl)
+	delegate.EXPECT().GetRun(gomock.Any(), nil).Return(run1, nil)
+	mockMetric := metricstesting.NewMockOperationDurationMetric(mockCtrl)
+	defer metricstesting.PatchRunManagerCallDuration(mockMetric)()
+	mockMetric.EXPECT().Observe("GetRun", "success", gomock.Any())
+	examinee := &instrumentedRunManager{delegate: delegate}
+
+	// EXERCISE
+	result, resultErr := examinee.GetRun(ctx, nil)
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	assert.Equal(t, run1, result)
+}
+
+func Test_instrumentedRunManager_DelegatesAllMethods(t *testing.T) {
+	// no parallel: patching global metric
+
+	// SETUP
+	ctx := context.Background()
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	error1 := serrors.Recoverable(errors.New("error1"))
+	delegate := runmocks.NewMockManager(mockCtrl)
+	delegate.EXPECT().CreateRun(gomock.Any(), nil, nil).Return(error1)
+	delegate.EXPECT().GetPendingReason(gomock.Any(), nil).Return(nil, error1)
+	delegate.EXPECT().GetLogTail(gomock.Any(), nil, int64(1), int64(2)).Return("", error1)
+	delegate.EXPECT().DeleteRun(gomock.Any(), nil).Return(error1)
+	delegate.EXPECT().DeleteEnv(gomock.Any(), nil).Return(error1)
+	mockMetric := metricstesting.NewMockOperationDurationMetric(mockCtrl)
+	defer metricstesting.PatchRunManagerCallDuration(mockMetric)()
+	for _, method := range []string{"CreateRun", "GetPendingReason", "GetLogTail", "DeleteRun", "DeleteEnv"} {
+		mockMetric.EXPECT().Observe(method, "recoverable_error", gomock.Any())
+	}
+	examinee := &instrumentedRunManager{delegate: delegate}
+
+	// EXERCISE
+	resultErrs := []error{
+		examinee.CreateRun(ctx, nil, nil),
+		func() error {
+			_, err := examinee.GetPendingReason(ctx, nil)
+			return err
+		}(),
+		func() error {
+			_, err := examinee.GetLogTail(ctx, nil, 1, 2)
+			return err
+		}(),
+		examinee.DeleteRun(ctx, nil),
+		examinee.DeleteEnv(ctx, nil),
+	}
+
+	// VERIFY
+	for _, resultErr := range resultErrs {
+		assert.Equal(t, error1, resultErr)
+	}
+}
@@ -0,0 +1,150 @@
+package leaderelection
+
+import (
+	"context"
+	"time"
+
+	"github.com/SAP/stewardci-core/pkg/runctl/metrics"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	coordinationv1client "k8s.io/client-go/kubernetes/typed/coordination/v1"
+	k8sleaderelection "k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	klog "k8s.io/klog/v2"
+)
+
+// ErrLeadershipLost is returned by Run if the leadership has been lost
+// before the stop channel was closed.
+var ErrLeadershipLost = errors.New("leadership lost")
+
+// Config is the configuration of the leader election.
+type Config struct {
+	// LeaseName is the name of the `coordination.k8s.io/v1` Lease object
+	// used as lock.
+	LeaseName string
+
+	// LeaseNamespace is the namespace of the Lease object.
+	LeaseNamespace string
+
+	// Identity is the unique identity of this instance, e.g. the pod name.
+	Identity string
+
+	// LeaseDuration, RenewDeadline and RetryPeriod have the same meaning as
+	// the respective fields of the client-go leader election config.
+	LeaseDuration, RenewDeadline, RetryPeriod time.Duration
+}
+
+// Run takes part in the leader election and calls runFunc as soon as
+// this instance has acquired the leadership. While not being the leader,
+// Run just waits.
+//
+// runFunc must return after its stop channel has been closed. The stop
+// channel is closed if stopCh is closed or the leadership is lost.
+// The context passed to runFunc is cancelled as soon as the leadership is
+// lost. Work in progress must then be cancelled instead of being
+// completed, as another instance may take over.
+// After runFunc has returned, the lease is released so that another
+// instance can take over without waiting for the lease to expire.
+//
+// Run returns nil after stopCh has been closed, the error returned by
+// runFunc, or ErrLeadershipLost if the leadership has been lost before
+// stopCh has been closed.
+func Run(logger logr.Logger, client coordinationv1client.LeasesGetter, config Config, stopCh <-chan struct{}, runFunc func(ctx context.Context, stopCh <-chan struct{}) error) error {
+	logger = logger.WithValues("lease", klog.KRef(config.LeaseNamespace, config.LeaseName), "identity", config.Identity)
+
+	ctx, cancel := context.WithCancel(klog.NewContext(context.Background(), logger))
+	defer cancel()
+
+	leading := make(chan struct{})
+	elector, err := k8sleaderelection.NewLeaderElector(k8sleaderelection.LeaderElectionConfig{
+		Lock: &resourcelock.LeaseLock{
+			LeaseMeta: metav1.ObjectMeta{
+				Name:      config.LeaseName,
+				Namespace: config.LeaseNamespace,
+			},
+			Client: client,
+			LockConfig: resourcelock.ResourceLockConfig{
+				Identity: config.Identity,
+			},
+		},
+		LeaseDuration:   config.LeaseDuration,
+		RenewDeadline:   config.RenewDeadline,
+		RetryPeriod:     config.RetryPeriod,
+		ReleaseOnCancel: true,
+		Name:            config.LeaseName,
+		Callbacks: k8sleaderelection.LeaderCallbacks{
+			OnStartedLeading: func(context.Context) {
+				logger.Info("Acquired leadership")
+				metrics.ControllerLeader.Set(1)
+				metrics.ControllerLeadershipChanges.Inc()
+				close(leading)
+			},
+			OnStoppedLeading: func() {
+				// also called if the leadership has never been acquired
+				select {
+				case <-leading:
+					logger.Info("Released or lost leadership")
+					metrics.ControllerLeader.Set(0)
+					metrics.ControllerLeadershipChanges.Inc()
+				default:
+				}
+			},
+			OnNewLeader: func(identity string) {
+				if identity != config.Identity {
+					logger.Info("Observed new leader", "leader", identity)
+				}
+			},
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "invalid leader election configuration")
+	}
+
+	metrics.ControllerLeader.Set(0)
+	electorDone := make(chan struct{})
+	go func() {
+		defer close(electorDone)
+		elector.Run(ctx)
+	}()
+
+	logger.Info("Waiting for leadership")
+	select {
+	case <-stopCh:
+		logger.V(2).Info("Stopping leader election of standby instance")
+		cancel()
+		<-electorDone
+		return nil
+	case <-electorDone:
+		return ErrLeadershipLost
+	case <-leading:
+	}
+
+	leadingCtx, cancelLeading := context.WithCancel(klog.NewContext(context.Background(), logger))
+	defer cancelLeading()
+	runStopCh := make(chan struct{})
+	go func() {
+		select {
+		case <-stopCh:
+		case <-electorDone:
+			cancelLeading()
+		}
+		close(runStopCh)
+	}()
+	runErr := runFunc(leadingCtx, runStopCh)
+
+	// step down: release the lease only after runFunc has returned
+	cancel()
+	<-electorDone
+
+	if runErr != nil {
+		return runErr
+	}
+	select {
+	case <-stopCh:
+		logger.Info("Stepped down as leader")
+		return nil
+	default:
+		return ErrLeadershipLost
+	}
+}
@@ -0,0 +1,206 @@
+package leaderelection
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sclientfake "k8s.io/client-go/kubernetes/fake"
+	coordinationv1client "k8s.io/client-go/kubernetes/typed/coordination/v1"
+	klog "k8s.io/klog/v2"
+)
+
+const (
+	testLeaseName      = "lease1"
+	testLeaseNamespace = "namespace1"
+	testTimeout        = 10 * time.Second
+)
+
+func newTestConfig(identity string) Config {
+	return Config{
+		LeaseName:      testLeaseName,
+		LeaseNamespace: testLeaseNamespace,
+		Identity:       identity,
+		LeaseDuration:  2 * time.Second,
+		RenewDeadline:  1 * time.Second,
+		RetryPeriod:    100 * time.Millisecond,
+	}
+}
+
+func getLease(t *testing.T, client coordinationv1client.LeasesGetter) *coordinationv1.Lease {
+	t.Helper()
+	lease, err := client.Leases(testLeaseNamespace).Get(context.Background(), testLeaseName, metav1.GetOptions{})
+	assert.NilError(t, err)
+	return lease
+}
+
+func newLeaseHeldBy(identity string) *coordinationv1.Lease {
+	now := metav1.NewMicroTime(time.Now())
+	durationSeconds := int32(3600)
+	return &coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      testLeaseName,
+			Namespace: testLeaseNamespace,
+		},
+		Spec: coordinationv1.LeaseSpec{
+			HolderIdentity:       &identity,
+			LeaseDurationSeconds: &durationSeconds,
+			AcquireTime:          &now,
+			RenewTime:            &now,
+		},
+	}
+}
+
+func runAsync(client coordinationv1client.LeasesGetter, config Config, stopCh <-chan struct{}, runFunc func(context.Context, <-chan struct{}) error) <-chan error {
+	result := make(chan error, 1)
+	go func() {
+		result <- Run(klog.Background(), client, config, stopCh, runFunc)
+	}()
+	return result
+}
+
+func waitFor(t *testing.T, ch <-chan struct{}, what string) {
+	t.Helper()
+	select {
+	case <-ch:
+	case <-time.After(testTimeout):
+		t.Fatalf("timeout waiting for %s", what)
+	}
+}
+
+func waitForResult(t *testing.T, result <-chan error) error {
+	t.Helper()
+	select {
+	case err := <-result:
+		return err
+	case <-time.After(testTimeout):
+		t.Fatal("timeout waiting for Run to return")
+		return nil
+	}
+}
+
+func Test_Run_AcquiresLeadershipAndStepsDownOnStop(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	client := k8sclientfake.NewSimpleClientset().CoordinationV1()
+	stopCh := make(chan struct{})
+	started := make(chan struct{})
+	stopped := make(chan struct{})
+	var ctxErrOnStop error
+	runFunc := func(ctx context.Context, runStopCh <-chan struct{}) error {
+		close(started)
+		<-runStopCh
+		ctxErrOnStop = ctx.Err()
+		close(stopped)
+		return nil
+	}
+
+	// EXERCISE
+	result := runAsync(client, newTestConfig("instance1"), stopCh, runFunc)
+	waitFor(t, started, "runFunc to be called")
+	lease := getLease(t, client)
+	close(stopCh)
+	err := waitForResult(t, result)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Equal(t, "instance1", *lease.Spec.HolderIdentity)
+	waitFor(t, stopped, "runFunc to return")
+	// stopping is no reason to cancel work in progress
+	assert.NilError(t, ctxErrOnStop)
+	// lease has been released
+	lease = getLease(t, client)
+	assert.Assert(t, lease.Spec.HolderIdentity == nil || *lease.Spec.HolderIdentity == "")
+}
+
+func Test_Run_StandbyDoesNotCallRunFunc(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	client := k8sclientfake.NewSimpleClientset(newLeaseHeldBy("other")).CoordinationV1()
+	stopCh := make(chan struct{})
+	runFunc := func(context.Context, <-chan struct{}) error {
+		t.Error("runFunc must not be called")
+		return nil
+	}
+
+	// EXERCISE
+	result := runAsync(client, newTestConfig("instance1"), stopCh, runFunc)
+	time.Sleep(500 * time.Millisecond)
+	close(stopCh)
+	err := waitForResult(t, result)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Equal(t, "other", *getLease(t, client).Spec.HolderIdentity)
+}
+
+func Test_Run_LeadershipLost(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	clientset := k8sclientfake.NewSimpleClientset()
+	client := clientset.CoordinationV1()
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	started := make(chan struct{})
+	var ctxErrOnStop error
+	runFunc := func(ctx context.Context, runStopCh <-chan struct{}) error {
+		close(started)
+		<-runStopCh
+		ctxErrOnStop = ctx.Err()
+		return nil
+	}
+
+	// EXERCISE
+	result := runAsync(client, newTestConfig("instance1"), stopCh, runFunc)
+	waitFor(t, started, "runFunc to be called")
+	// another instance takes over
+	_, err := client.Leases(testLeaseNamespace).Update(context.Background(), newLeaseHeldBy("other"), metav1.UpdateOptions{})
+	assert.NilError(t, err)
+	err = waitForResult(t, result)
+
+	// VERIFY
+	assert.Assert(t, errors.Is(err, ErrLeadershipLost))
+	// work in progress must be cancelled
+	assert.Assert(t, errors.Is(ctxErrOnStop, context.Canceled))
+}
+
+func Test_Run_ReturnsErrorOfRunFunc(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	client := k8sclientfake.NewSimpleClientset().CoordinationV1()
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	expectedErr := errors.New("error1")
+	runFunc := func(context.Context, <-chan struct{}) error {
+		return expectedErr
+	}
+
+	// EXERCISE
+	err := waitForResult(t, runAsync(client, newTestConfig("instance1"), stopCh, runFunc))
+
+	// VERIFY
+	assert.Assert(t, errors.Is(err, expectedErr))
+}
+
+func Test_Run_InvalidConfig(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	client := k8sclientfake.NewSimpleClientset().CoordinationV1()
+	config := newTestConfig("instance1")
+	config.RenewDeadline = config.LeaseDuration
+
+	// EXERCISE
+	err := Run(klog.Background(), client, config, make(chan struct{}), nil)
+
+	// VERIFY
+	assert.ErrorContains(t, err, "invalid leader election configuration")
+}
@@ -0,0 +1,60 @@
+package metrics
+
+import (
+	"sync"
+
+	"github.com/SAP/stewardci-core/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// DeliveryOutcomeSent is the outcome of a delivery that has been
+	// acknowledged by the receiver.
+	DeliveryOutcomeSent = "sent"
+
+	// DeliveryOutcomeFailed is the outcome of a delivery that failed
+	// after all retries.
+	DeliveryOutcomeFailed = "failed"
+
+	// DeliveryOutcomeDropped is the outcome of a delivery that has been
+	// dropped because the delivery queue was full.
+	DeliveryOutcomeDropped = "dropped"
+)
+
+var (
+	// CloudEventsDeliveries counts the deliveries of CloudEvents about
+	// pipeline runs by outcome.
+	CloudEventsDeliveries OutcomesMetric = &cloudEventsDeliveries{}
+)
+
+func init() {
+	CloudEventsDeliveries.(*cloudEventsDeliveries).init()
+}
+
+type cloudEventsDeliveries struct {
+	initOnlyOnce sync.Once
+	metric       *prometheus.CounterVec
+}
+
+func (m *cloudEventsDeliveries) init() {
+	m.initOnlyOnce.Do(func() {
+		m.metric = prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Subsystem: subsystem,
+				Name:      "cloudevents_deliveries_total",
+				Help: "The number of deliveries of CloudEvents about pipeline runs partitioned by outcome." +
+					"\n\nOutcome 'sent' means the event has been acknowledged by the sink," +
+					" 'failed' means the delivery failed after all retries," +
+					" 'dropped' means the event has been dropped because the delivery queue was full.",
+			},
+			[]string{
+				"outcome",
+			},
+		)
+		metrics.Registerer().MustRegister(m.metric)
+	})
+}
+
+func (m *cloudEventsDeliveries) Observe(outcome string) {
+	m.metric.WithLabelValues(outcome).Inc()
+}
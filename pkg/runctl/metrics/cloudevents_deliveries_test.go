@@ -0,0 +1,33 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"gotest.tools/v3/assert"
+)
+
+func Test_CloudEventsDeliveries_isInitialized(t *testing.T) {
+	t.Parallel()
+
+	// VERIFY
+	assert.Assert(t, CloudEventsDeliveries.(*cloudEventsDeliveries).metric != nil)
+}
+
+func Test_CloudEventsDeliveries_Observe(t *testing.T) {
+	// no parallel: using global metric
+
+	// SETUP
+	examinee := CloudEventsDeliveries.(*cloudEventsDeliveries)
+	examinee.metric.Reset()
+
+	// EXERCISE
+	examinee.Observe(DeliveryOutcomeSent)
+	examinee.Observe(DeliveryOutcomeSent)
+	examinee.Observe(DeliveryOutcomeFailed)
+
+	// VERIFY
+	assert.Equal(t, 2, testutil.CollectAndCount(examinee.metric))
+	assert.Equal(t, float64(2), testutil.ToFloat64(examinee.metric.WithLabelValues("sent")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(examinee.metric.WithLabelValues("failed")))
+}
@@ -0,0 +1,52 @@
+package metrics
+
+import (
+	"strconv"
+	"sync"
+
+	"github.com/SAP/stewardci-core/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	// ConfigInfo exposes the hash of the configuration currently loaded
+	// by the run controller and whether it is valid.
+	ConfigInfo ConfigInfoMetric = &configInfo{}
+)
+
+func init() {
+	ConfigInfo.(*configInfo).init()
+}
+
+type configInfo struct {
+	initOnlyOnce sync.Once
+	mutex        sync.Mutex
+	metric       *prometheus.GaugeVec
+}
+
+func (m *configInfo) init() {
+	m.initOnlyOnce.Do(func() {
+		m.metric = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Subsystem: subsystem,
+				Name:      "config_info",
+				Help: "Information about the configuration loaded by the run controller." +
+					" The value is always 1. Label 'hash' identifies the content of the configuration ConfigMaps," +
+					" label 'valid' tells whether the configuration could be parsed.",
+			},
+			[]string{
+				"hash",
+				"valid",
+			},
+		)
+		metrics.Registerer().MustRegister(m.metric)
+	})
+}
+
+func (m *configInfo) Set(hash string, valid bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	// only the current configuration is exposed
+	m.metric.Reset()
+	m.metric.WithLabelValues(hash, strconv.FormatBool(valid)).Set(1)
+}
@@ -0,0 +1,30 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"gotest.tools/v3/assert"
+)
+
+func Test_ConfigInfo_isInitialized(t *testing.T) {
+	t.Parallel()
+
+	// VERIFY
+	assert.Assert(t, ConfigInfo.(*configInfo).metric != nil)
+}
+
+func Test_ConfigInfo_Set_ExposesOnlyCurrentConfig(t *testing.T) {
+	// no parallel: using global metric
+
+	// SETUP
+	examinee := ConfigInfo.(*configInfo)
+
+	// EXERCISE
+	examinee.Set("hash1", true)
+	examinee.Set("hash2", false)
+
+	// VERIFY
+	assert.Equal(t, 1, testutil.CollectAndCount(examinee.metric))
+	assert.Equal(t, float64(1), testutil.ToFloat64(examinee.metric.WithLabelValues("hash2", "false")))
+}
@@ -0,0 +1,69 @@
+package metrics
+
+import (
+	"sync"
+
+	"github.com/SAP/stewardci-core/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	// ControllerLeader indicates whether the run controller instance is
+	// the leader (1) or a standby instance (0).
+	// Instances running without leader election, including instances with
+	// sharding, are always the leader.
+	ControllerLeader SettableGaugeMetric = &controllerLeader{}
+
+	// ControllerLeadershipChanges counts the number of times the run
+	// controller instance acquired or lost leadership.
+	ControllerLeadershipChanges CounterMetric = &controllerLeadershipChanges{}
+)
+
+func init() {
+	ControllerLeader.(*controllerLeader).init()
+	ControllerLeadershipChanges.(*controllerLeadershipChanges).init()
+}
+
+type controllerLeader struct {
+	initOnlyOnce sync.Once
+	metric       prometheus.Gauge
+}
+
+func (m *controllerLeader) init() {
+	m.initOnlyOnce.Do(func() {
+		m.metric = prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Subsystem: subsystem,
+				Name:      "controller_leader",
+				Help:      "Whether the run controller instance is the leader (1) or a standby instance (0).",
+			},
+		)
+		metrics.Registerer().MustRegister(m.metric)
+	})
+}
+
+func (m *controllerLeader) Set(value float64) {
+	m.metric.Set(value)
+}
+
+type controllerLeadershipChanges struct {
+	initOnlyOnce sync.Once
+	metric       prometheus.Counter
+}
+
+func (m *controllerLeadershipChanges) init() {
+	m.initOnlyOnce.Do(func() {
+		m.metric = prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Subsystem: subsystem,
+				Name:      "controller_leadership_changes_total",
+				Help:      "The number of times the run controller instance acquired or lost leadership.",
+			},
+		)
+		metrics.Registerer().MustRegister(m.metric)
+	})
+}
+
+func (m *controllerLeadershipChanges) Inc() {
+	m.metric.Inc()
+}
@@ -0,0 +1,21 @@
+package metrics
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func Test_ControllerLeader_isInitialized(t *testing.T) {
+	t.Parallel()
+
+	// VERIFY
+	assert.Assert(t, ControllerLeader.(*controllerLeader).metric != nil)
+}
+
+func Test_ControllerLeadershipChanges_isInitialized(t *testing.T) {
+	t.Parallel()
+
+	// VERIFY
+	assert.Assert(t, ControllerLeadershipChanges.(*controllerLeadershipChanges).metric != nil)
+}
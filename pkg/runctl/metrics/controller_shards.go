@@ -0,0 +1,67 @@
+package metrics
+
+import (
+	"sync"
+
+	"github.com/SAP/stewardci-core/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	// ControllerOwnedShards is the number of shards owned by the run
+	// controller instance if sharding is enabled.
+	ControllerOwnedShards SettableGaugeMetric = &controllerOwnedShards{}
+
+	// ControllerShardChanges counts the number of times the run controller
+	// instance acquired or released a shard.
+	ControllerShardChanges CounterMetric = &controllerShardChanges{}
+)
+
+func init() {
+	ControllerOwnedShards.(*controllerOwnedShards).init()
+	ControllerShardChanges.(*controllerShardChanges).init()
+}
+
+type controllerOwnedShards struct {
+	initOnlyOnce sync.Once
+	metric       prometheus.Gauge
+}
+
+func (m *controllerOwnedShards) init() {
+	m.initOnlyOnce.Do(func() {
+		m.metric = prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Subsystem: subsystem,
+				Name:      "controller_owned_shards",
+				Help:      "The number of shards owned by the run controller instance.",
+			},
+		)
+		metrics.Registerer().MustRegister(m.metric)
+	})
+}
+
+func (m *controllerOwnedShards) Set(value float64) {
+	m.metric.Set(value)
+}
+
+type controllerShardChanges struct {
+	initOnlyOnce sync.Once
+	metric       prometheus.Counter
+}
+
+func (m *controllerShardChanges) init() {
+	m.initOnlyOnce.Do(func() {
+		m.metric = prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Subsystem: subsystem,
+				Name:      "controller_shard_changes_total",
+				Help:      "The number of times the run controller instance acquired or released a shard.",
+			},
+		)
+		metrics.Registerer().MustRegister(m.metric)
+	})
+}
+
+func (m *controllerShardChanges) Inc() {
+	m.metric.Inc()
+}
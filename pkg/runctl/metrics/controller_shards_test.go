@@ -0,0 +1,21 @@
+package metrics
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func Test_ControllerOwnedShards_isInitialized(t *testing.T) {
+	t.Parallel()
+
+	// VERIFY
+	assert.Assert(t, ControllerOwnedShards.(*controllerOwnedShards).metric != nil)
+}
+
+func Test_ControllerShardChanges_isInitialized(t *testing.T) {
+	t.Parallel()
+
+	// VERIFY
+	assert.Assert(t, ControllerShardChanges.(*controllerShardChanges).metric != nil)
+}
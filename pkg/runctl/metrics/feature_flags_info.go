@@ -0,0 +1,54 @@
+package metrics
+
+import (
+	"strconv"
+	"sync"
+
+	"github.com/SAP/stewardci-core/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	// FeatureFlagsInfo exposes the current values of the feature flags of
+	// the run controller.
+	FeatureFlagsInfo FeatureFlagsInfoMetric = &featureFlagsInfo{}
+)
+
+func init() {
+	FeatureFlagsInfo.(*featureFlagsInfo).init()
+}
+
+type featureFlagsInfo struct {
+	initOnlyOnce sync.Once
+	mutex        sync.Mutex
+	metric       *prometheus.GaugeVec
+}
+
+func (m *featureFlagsInfo) init() {
+	m.initOnlyOnce.Do(func() {
+		m.metric = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Subsystem: subsystem,
+				Name:      "feature_flag_info",
+				Help: "The current values of the feature flags of the run controller." +
+					" The value is always 1. There is one series per feature flag with label 'key'" +
+					" being the feature flag key and label 'enabled' being 'true' or 'false'.",
+			},
+			[]string{
+				"key",
+				"enabled",
+			},
+		)
+		metrics.Registerer().MustRegister(m.metric)
+	})
+}
+
+func (m *featureFlagsInfo) Set(values map[string]bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	// only the current values are exposed
+	m.metric.Reset()
+	for key, enabled := range values {
+		m.metric.WithLabelValues(key, strconv.FormatBool(enabled)).Set(1)
+	}
+}
@@ -0,0 +1,31 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"gotest.tools/v3/assert"
+)
+
+func Test_FeatureFlagsInfo_isInitialized(t *testing.T) {
+	t.Parallel()
+
+	// VERIFY
+	assert.Assert(t, FeatureFlagsInfo.(*featureFlagsInfo).metric != nil)
+}
+
+func Test_FeatureFlagsInfo_Set_ExposesOnlyCurrentValues(t *testing.T) {
+	// no parallel: using global metric
+
+	// SETUP
+	examinee := FeatureFlagsInfo.(*featureFlagsInfo)
+
+	// EXERCISE
+	examinee.Set(map[string]bool{"flag1": false, "flag2": true})
+	examinee.Set(map[string]bool{"flag1": true, "flag2": true})
+
+	// VERIFY
+	assert.Equal(t, 2, testutil.CollectAndCount(examinee.metric))
+	assert.Equal(t, float64(1), testutil.ToFloat64(examinee.metric.WithLabelValues("flag1", "true")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(examinee.metric.WithLabelValues("flag2", "true")))
+}
@@ -1,6 +1,10 @@
 package metrics
 
-import stewardapi "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+import (
+	"time"
+
+	stewardapi "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+)
 
 // CounterMetric is a monotonic counter metric.
 type CounterMetric interface {
@@ -26,3 +30,56 @@ type StateItemsMetric interface {
 type ResultsMetric interface {
 	Observe(result stewardapi.Result)
 }
+
+// ConfigInfoMetric exposes information about the loaded configuration.
+type ConfigInfoMetric interface {
+	Set(hash string, valid bool)
+}
+
+// MaintenanceStateMetric exposes the current maintenance state.
+type MaintenanceStateMetric interface {
+	Set(state string)
+}
+
+// FeatureFlagsInfoMetric exposes the current values of the feature flags.
+type FeatureFlagsInfoMetric interface {
+	Set(values map[string]bool)
+}
+
+// OperationDurationMetric observes the duration of operations partitioned
+// by operation name and outcome.
+type OperationDurationMetric interface {
+	Observe(operation, outcome string, duration time.Duration)
+}
+
+// PipelineRunsCountMetric exposes the number of existing pipeline runs.
+type PipelineRunsCountMetric interface {
+	Set(pipelineRuns []*stewardapi.PipelineRun)
+}
+
+// NamespacedResultsMetric observes the result of a finished pipeline run
+// in a namespace.
+type NamespacedResultsMetric interface {
+	Observe(namespace string, result stewardapi.Result)
+}
+
+// PipelineRunsStartMetric observes the latency between the creation and
+// the start of pipeline runs.
+type PipelineRunsStartMetric interface {
+	// SetSLOThresholds sets the start latency thresholds pipeline runs
+	// are counted for as started within or not.
+	SetSLOThresholds(thresholds []time.Duration)
+
+	// ObserveStarted observes a pipeline run that started at the given
+	// time.
+	ObserveStarted(pipelineRun *stewardapi.PipelineRun, startedAt time.Time)
+
+	// ObserveWaitTimeout observes a pipeline run that failed because it
+	// did not start within the wait timeout.
+	ObserveWaitTimeout()
+}
+
+// OutcomesMetric counts operations partitioned by outcome.
+type OutcomesMetric interface {
+	Observe(outcome string)
+}
@@ -0,0 +1,49 @@
+package metrics
+
+import (
+	"sync"
+
+	"github.com/SAP/stewardci-core/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	// MaintenanceState exposes the current maintenance state of the
+	// run controller.
+	MaintenanceState MaintenanceStateMetric = &maintenanceState{}
+)
+
+func init() {
+	MaintenanceState.(*maintenanceState).init()
+}
+
+type maintenanceState struct {
+	initOnlyOnce sync.Once
+	mutex        sync.Mutex
+	metric       *prometheus.GaugeVec
+}
+
+func (m *maintenanceState) init() {
+	m.initOnlyOnce.Do(func() {
+		m.metric = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Subsystem: subsystem,
+				Name:      "maintenance_state",
+				Help: "The current maintenance state of the run controller." +
+					" The value is always 1. Label 'state' is one of 'inactive', 'announced', 'active' and 'aborting'.",
+			},
+			[]string{
+				"state",
+			},
+		)
+		metrics.Registerer().MustRegister(m.metric)
+	})
+}
+
+func (m *maintenanceState) Set(state string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	// only the current state is exposed
+	m.metric.Reset()
+	m.metric.WithLabelValues(state).Set(1)
+}
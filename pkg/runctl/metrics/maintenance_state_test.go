@@ -0,0 +1,30 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"gotest.tools/v3/assert"
+)
+
+func Test_MaintenanceState_isInitialized(t *testing.T) {
+	t.Parallel()
+
+	// VERIFY
+	assert.Assert(t, MaintenanceState.(*maintenanceState).metric != nil)
+}
+
+func Test_MaintenanceState_Set_ExposesOnlyCurrentState(t *testing.T) {
+	// no parallel: using global metric
+
+	// SETUP
+	examinee := MaintenanceState.(*maintenanceState)
+
+	// EXERCISE
+	examinee.Set("announced")
+	examinee.Set("active")
+
+	// VERIFY
+	assert.Equal(t, 1, testutil.CollectAndCount(examinee.metric))
+	assert.Equal(t, float64(1), testutil.ToFloat64(examinee.metric.WithLabelValues("active")))
+}
@@ -0,0 +1,92 @@
+package metrics
+
+import (
+	"sort"
+	"sync"
+)
+
+const (
+	// NamespaceOther is the value of label `namespace` of pipeline run
+	// metrics for namespaces that are not broken down.
+	NamespaceOther = "_other"
+)
+
+var (
+	// PipelineRunNamespaces selects the namespaces of pipeline runs that
+	// are broken down in label `namespace` of pipeline run metrics.
+	PipelineRunNamespaces = &namespaceBreakdown{}
+)
+
+// namespaceBreakdown limits the cardinality of label `namespace` of
+// pipeline run metrics.
+//
+// Namespaces in the allowlist are always broken down. In addition, the
+// top N namespaces by number of pipeline runs are broken down. All other
+// namespaces are aggregated as NamespaceOther. If neither an allowlist nor
+// a top N cutoff is configured, the breakdown by namespace is disabled and
+// the label value is empty.
+type namespaceBreakdown struct {
+	mutex     sync.RWMutex
+	allowlist map[string]bool
+	topN      int
+	top       map[string]bool
+}
+
+// Configure sets the namespaces that are always broken down and the number
+// of namespaces with most pipeline runs that are broken down in addition.
+func (b *namespaceBreakdown) Configure(allowlist []string, topN int) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.allowlist = map[string]bool{}
+	for _, namespace := range allowlist {
+		if namespace != "" {
+			b.allowlist[namespace] = true
+		}
+	}
+	if topN < 0 {
+		topN = 0
+	}
+	b.topN = topN
+	b.top = nil
+}
+
+// UpdateTop determines the top N namespaces from the given numbers of
+// pipeline runs per namespace. Ties are broken by namespace name.
+func (b *namespaceBreakdown) UpdateTop(counts map[string]int) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if b.topN == 0 {
+		return
+	}
+	namespaces := make([]string, 0, len(counts))
+	for namespace := range counts {
+		namespaces = append(namespaces, namespace)
+	}
+	sort.Slice(namespaces, func(i, j int) bool {
+		ci, cj := counts[namespaces[i]], counts[namespaces[j]]
+		if ci != cj {
+			return ci > cj
+		}
+		return namespaces[i] < namespaces[j]
+	})
+	if len(namespaces) > b.topN {
+		namespaces = namespaces[:b.topN]
+	}
+	b.top = map[string]bool{}
+	for _, namespace := range namespaces {
+		b.top[namespace] = true
+	}
+}
+
+// Label returns the value of label `namespace` for the given namespace.
+func (b *namespaceBreakdown) Label(namespace string) string {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+	if len(b.allowlist) == 0 && b.topN == 0 {
+		return ""
+	}
+	if b.allowlist[namespace] || b.top[namespace] {
+		return namespace
+	}
+	return NamespaceOther
+}
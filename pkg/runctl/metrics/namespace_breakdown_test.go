@@ -0,0 +1,63 @@
+package metrics
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func Test_namespaceBreakdown_Label(t *testing.T) {
+	t.Parallel()
+
+	counts := map[string]int{"ns1": 5, "ns2": 1, "ns3": 3, "ns4": 3}
+
+	for _, tc := range []struct {
+		name      string
+		allowlist []string
+		topN      int
+		expected  map[string]string
+	}{
+		{
+			name:     "disabled",
+			expected: map[string]string{"ns1": "", "ns2": "", "ns5": ""},
+		},
+		{
+			name:      "allowlist",
+			allowlist: []string{"ns2", "ns5"},
+			expected:  map[string]string{"ns1": "_other", "ns2": "ns2", "ns5": "ns5"},
+		},
+		{
+			name:     "top_n",
+			topN:     2,
+			expected: map[string]string{"ns1": "ns1", "ns2": "_other", "ns3": "ns3", "ns4": "_other", "ns5": "_other"},
+		},
+		{
+			name:     "top_n_greater_than_number_of_namespaces",
+			topN:     10,
+			expected: map[string]string{"ns1": "ns1", "ns2": "ns2", "ns5": "_other"},
+		},
+		{
+			name:      "allowlist_and_top_n",
+			allowlist: []string{"ns2"},
+			topN:      1,
+			expected:  map[string]string{"ns1": "ns1", "ns2": "ns2", "ns3": "_other"},
+		},
+	} {
+		tc := tc // capture current value before going parallel
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			examinee := &namespaceBreakdown{}
+			examinee.Configure(tc.allowlist, tc.topN)
+
+			// EXERCISE
+			examinee.UpdateTop(counts)
+
+			// VERIFY
+			for namespace, expected := range tc.expected {
+				assert.Equal(t, expected, examinee.Label(namespace), namespace)
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package metrics
+
+import (
+	stewardapi "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	serrors "github.com/SAP/stewardci-core/pkg/errors"
+)
+
+const (
+	// OutcomeSuccess is the outcome of an operation that succeeded.
+	OutcomeSuccess = "success"
+
+	// OutcomeRecoverableError is the outcome of an operation that failed
+	// with an error marked as recoverable, i.e. the operation is retried.
+	OutcomeRecoverableError = "recoverable_error"
+
+	// OutcomeError is the outcome of an operation that failed with an
+	// error which is neither recoverable nor classified.
+	OutcomeError = "error"
+)
+
+// OutcomeOf returns the outcome of an operation that returned the given
+// error.
+// For errors classified with a pipeline run result, the result
+// (e.g. `error_infra`) is returned.
+func OutcomeOf(err error) string {
+	if err == nil {
+		return OutcomeSuccess
+	}
+	if serrors.IsRecoverable(err) {
+		return OutcomeRecoverableError
+	}
+	if class := serrors.GetClass(err); class != stewardapi.ResultUndefined {
+		return string(class)
+	}
+	return OutcomeError
+}
@@ -0,0 +1,39 @@
+package metrics
+
+import (
+	"errors"
+	"testing"
+
+	stewardapi "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	serrors "github.com/SAP/stewardci-core/pkg/errors"
+	"gotest.tools/v3/assert"
+)
+
+func Test_OutcomeOf(t *testing.T) {
+	t.Parallel()
+
+	error1 := errors.New("error1")
+
+	for _, tc := range []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{"nil", nil, OutcomeSuccess},
+		{"plain", error1, OutcomeError},
+		{"recoverable", serrors.Recoverable(error1), OutcomeRecoverableError},
+		{"classified", serrors.Classify(error1, stewardapi.ResultErrorInfra), "error_infra"},
+		{"recoverable_and_classified", serrors.Recoverable(serrors.Classify(error1, stewardapi.ResultErrorInfra)), OutcomeRecoverableError},
+	} {
+		tc := tc // capture current value before going parallel
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// EXERCISE
+			result := OutcomeOf(tc.err)
+
+			// VERIFY
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package metrics
+
+import (
+	"sync"
+
+	stewardapi "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	// PipelineRunsCount exposes the number of existing pipeline runs
+	// partitioned by state and namespace.
+	PipelineRunsCount PipelineRunsCountMetric = &pipelineRunsCount{}
+)
+
+func init() {
+	PipelineRunsCount.(*pipelineRunsCount).init()
+}
+
+type pipelineRunsCount struct {
+	initOnlyOnce sync.Once
+	mutex        sync.Mutex
+	metric       *prometheus.GaugeVec
+}
+
+func (m *pipelineRunsCount) init() {
+	m.initOnlyOnce.Do(func() {
+		m.metric = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Subsystem: subsystem,
+				Name:      "count",
+				Help: "The number of existing pipeline runs partitioned by state and namespace." +
+					"\n\nLabel 'namespace' is empty if the breakdown by namespace is disabled" +
+					" and '" + NamespaceOther + "' for namespaces not broken down.",
+			},
+			[]string{
+				"state",
+				"namespace",
+			},
+		)
+		metrics.Registerer().MustRegister(m.metric)
+	})
+}
+
+// Set sets the metric to the number of the given pipeline runs.
+// The top N namespaces of PipelineRunNamespaces are updated before.
+func (m *pipelineRunsCount) Set(pipelineRuns []*stewardapi.PipelineRun) {
+	namespaceCounts := map[string]int{}
+	for _, run := range pipelineRuns {
+		namespaceCounts[run.GetNamespace()]++
+	}
+	PipelineRunNamespaces.UpdateTop(namespaceCounts)
+
+	type key struct {
+		state     stewardapi.State
+		namespace string
+	}
+	counts := map[key]int{}
+	for _, run := range pipelineRuns {
+		state := run.Status.State
+		if state == stewardapi.StateUndefined {
+			state = stewardapi.StateNew
+		}
+		counts[key{state, PipelineRunNamespaces.Label(run.GetNamespace())}]++
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	// series of combinations without pipeline runs are removed
+	m.metric.Reset()
+	for k, count := range counts {
+		m.metric.WithLabelValues(string(k.state), k.namespace).Set(float64(count))
+	}
+}
@@ -0,0 +1,49 @@
+package metrics
+
+import (
+	"testing"
+
+	stewardapi "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"gotest.tools/v3/assert"
+)
+
+func Test_PipelineRunsCount_isInitialized(t *testing.T) {
+	t.Parallel()
+
+	// VERIFY
+	assert.Assert(t, PipelineRunsCount.(*pipelineRunsCount).metric != nil)
+}
+
+func Test_PipelineRunsCount_Set(t *testing.T) {
+	// no parallel: using global metric and namespace breakdown
+
+	// SETUP
+	examinee := PipelineRunsCount.(*pipelineRunsCount)
+	PipelineRunNamespaces.Configure(nil, 1)
+	defer PipelineRunNamespaces.Configure(nil, 0)
+
+	newRun := func(namespace string, state stewardapi.State) *stewardapi.PipelineRun {
+		run := &stewardapi.PipelineRun{}
+		run.SetNamespace(namespace)
+		run.Status.State = state
+		return run
+	}
+
+	// EXERCISE
+	examinee.Set([]*stewardapi.PipelineRun{newRun("ns1", stewardapi.StateRunning)})
+	examinee.Set([]*stewardapi.PipelineRun{
+		newRun("ns1", stewardapi.StateUndefined),
+		newRun("ns1", stewardapi.StateWaiting),
+		newRun("ns1", stewardapi.StateWaiting),
+		newRun("ns2", stewardapi.StateWaiting),
+		newRun("ns3", stewardapi.StateNew),
+	})
+
+	// VERIFY
+	assert.Equal(t, 4, testutil.CollectAndCount(examinee.metric))
+	assert.Equal(t, float64(2), testutil.ToFloat64(examinee.metric.WithLabelValues("waiting", "ns1")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(examinee.metric.WithLabelValues("new", "ns1")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(examinee.metric.WithLabelValues("waiting", NamespaceOther)))
+	assert.Equal(t, float64(1), testutil.ToFloat64(examinee.metric.WithLabelValues("new", NamespaceOther)))
+}
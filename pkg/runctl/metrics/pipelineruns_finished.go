@@ -0,0 +1,47 @@
+package metrics
+
+import (
+	"sync"
+
+	stewardapi "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	// PipelineRunsFinished counts the number of finished pipeline runs by
+	// result type and namespace.
+	PipelineRunsFinished NamespacedResultsMetric = &pipelineRunsFinished{}
+)
+
+func init() {
+	PipelineRunsFinished.(*pipelineRunsFinished).init()
+}
+
+type pipelineRunsFinished struct {
+	initOnlyOnce sync.Once
+	metric       *prometheus.CounterVec
+}
+
+func (m *pipelineRunsFinished) init() {
+	m.initOnlyOnce.Do(func() {
+		m.metric = prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Subsystem: subsystem,
+				Name:      "finished_total",
+				Help: "The number of finished pipeline runs partitioned by result type and namespace." +
+					"\n\nLabel 'namespace' is empty if the breakdown by namespace is disabled" +
+					" and '" + NamespaceOther + "' for namespaces not broken down.",
+			},
+			[]string{
+				"result",
+				"namespace",
+			},
+		)
+		metrics.Registerer().MustRegister(m.metric)
+	})
+}
+
+func (m *pipelineRunsFinished) Observe(namespace string, result stewardapi.Result) {
+	m.metric.WithLabelValues(string(result), PipelineRunNamespaces.Label(namespace)).Inc()
+}
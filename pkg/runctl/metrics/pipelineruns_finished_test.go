@@ -0,0 +1,38 @@
+package metrics
+
+import (
+	"testing"
+
+	stewardapi "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"gotest.tools/v3/assert"
+)
+
+func Test_PipelineRunsFinished_isInitialized(t *testing.T) {
+	t.Parallel()
+
+	// VERIFY
+	assert.Assert(t, PipelineRunsFinished.(*pipelineRunsFinished).metric != nil)
+}
+
+func Test_PipelineRunsFinished_Observe(t *testing.T) {
+	// no parallel: using global metric and namespace breakdown
+
+	// SETUP
+	examinee := PipelineRunsFinished.(*pipelineRunsFinished)
+	examinee.metric.Reset()
+	PipelineRunNamespaces.Configure([]string{"ns1"}, 0)
+	defer PipelineRunNamespaces.Configure(nil, 0)
+
+	// EXERCISE
+	examinee.Observe("ns1", stewardapi.ResultSuccess)
+	examinee.Observe("ns1", stewardapi.ResultSuccess)
+	examinee.Observe("ns2", stewardapi.ResultSuccess)
+	examinee.Observe("ns3", stewardapi.ResultErrorContent)
+
+	// VERIFY
+	assert.Equal(t, 3, testutil.CollectAndCount(examinee.metric))
+	assert.Equal(t, float64(2), testutil.ToFloat64(examinee.metric.WithLabelValues("success", "ns1")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(examinee.metric.WithLabelValues("success", NamespaceOther)))
+	assert.Equal(t, float64(1), testutil.ToFloat64(examinee.metric.WithLabelValues("error_content", NamespaceOther)))
+}
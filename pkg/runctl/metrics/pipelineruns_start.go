@@ -0,0 +1,147 @@
+package metrics
+
+import (
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	stewardapi "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	// PipelineRunsStart observes the latency between the creation of
+	// pipeline runs and the start of the pipeline execution.
+	PipelineRunsStart PipelineRunsStartMetric = &pipelineRunsStart{}
+)
+
+// startLatencyComponents are the states making up the start latency of
+// a pipeline run.
+var startLatencyComponents = []stewardapi.State{
+	stewardapi.StateNew,
+	stewardapi.StatePreparing,
+	stewardapi.StateWaiting,
+}
+
+func init() {
+	PipelineRunsStart.(*pipelineRunsStart).init()
+}
+
+type pipelineRunsStart struct {
+	initOnlyOnce       sync.Once
+	mutex              sync.RWMutex
+	thresholds         []time.Duration
+	latencyMetric      prometheus.Histogram
+	componentsMetric   *prometheus.HistogramVec
+	sloMetric          *prometheus.CounterVec
+	waitTimeoutsMetric prometheus.Counter
+}
+
+func (m *pipelineRunsStart) init() {
+	m.initOnlyOnce.Do(func() {
+		buckets := prometheus.ExponentialBuckets(1, 2, 12)
+
+		m.latencyMetric = prometheus.NewHistogram(
+			prometheus.HistogramOpts{
+				Subsystem: subsystem,
+				Name:      "start_latency_seconds",
+				Help: "A histogram of the latency between the creation of pipeline runs and the start of the pipeline execution." +
+					"\n\nPipeline runs are observed when they enter state 'running'.",
+				Buckets: buckets,
+			},
+		)
+		metrics.Registerer().MustRegister(m.latencyMetric)
+
+		m.componentsMetric = prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Subsystem: subsystem,
+				Name:      "start_latency_components_seconds",
+				Help: "A histogram vector of the time pipeline runs spent in the states before the start of the pipeline execution" +
+					" partitioned by state ('new', 'preparing' and 'waiting')." +
+					"\n\nPipeline runs are observed when they enter state 'running'.",
+				Buckets: buckets,
+			},
+			[]string{
+				"state",
+			},
+		)
+		metrics.Registerer().MustRegister(m.componentsMetric)
+
+		m.sloMetric = prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Subsystem: subsystem,
+				Name:      "start_slo_total",
+				Help: "The number of pipeline runs that started within a start latency threshold or not, partitioned by threshold." +
+					"\n\nLabel 'within' is 'true' if the pipeline run started within the threshold and 'false' if it started later" +
+					" or failed because it did not start within the wait timeout.",
+			},
+			[]string{
+				"threshold_seconds",
+				"within",
+			},
+		)
+		metrics.Registerer().MustRegister(m.sloMetric)
+
+		m.waitTimeoutsMetric = prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Subsystem: subsystem,
+				Name:      "wait_timeouts_total",
+				Help:      "The number of pipeline runs that failed because they did not start within the wait timeout.",
+			},
+		)
+		metrics.Registerer().MustRegister(m.waitTimeoutsMetric)
+	})
+}
+
+func (m *pipelineRunsStart) SetSLOThresholds(thresholds []time.Duration) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.thresholds = append([]time.Duration(nil), thresholds...)
+	sort.Slice(m.thresholds, func(i, j int) bool { return m.thresholds[i] < m.thresholds[j] })
+	// series of former thresholds are removed
+	m.sloMetric.Reset()
+}
+
+func (m *pipelineRunsStart) ObserveStarted(run *stewardapi.PipelineRun, startedAt time.Time) {
+	latency := startedAt.Sub(run.CreationTimestamp.Time)
+	if run.CreationTimestamp.IsZero() || latency < 0 {
+		// cannot observe pipeline run without valid creation timestamp
+		return
+	}
+	m.latencyMetric.Observe(latency.Seconds())
+
+	components := map[stewardapi.State]time.Duration{}
+	for _, item := range run.Status.StateHistory {
+		if item.StartedAt.IsZero() || item.FinishedAt.IsZero() {
+			continue
+		}
+		components[item.State] += item.FinishedAt.Sub(item.StartedAt.Time)
+	}
+	for _, state := range startLatencyComponents {
+		if duration, found := components[state]; found && duration >= 0 {
+			m.componentsMetric.WithLabelValues(string(state)).Observe(duration.Seconds())
+		}
+	}
+
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	for _, threshold := range m.thresholds {
+		m.sloMetric.WithLabelValues(formatThreshold(threshold), strconv.FormatBool(latency <= threshold)).Inc()
+	}
+}
+
+func (m *pipelineRunsStart) ObserveWaitTimeout() {
+	m.waitTimeoutsMetric.Inc()
+
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	for _, threshold := range m.thresholds {
+		m.sloMetric.WithLabelValues(formatThreshold(threshold), "false").Inc()
+	}
+}
+
+func formatThreshold(threshold time.Duration) string {
+	return strconv.FormatFloat(threshold.Seconds(), 'g', -1, 64)
+}
@@ -0,0 +1,102 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	stewardapi "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"gotest.tools/v3/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_PipelineRunsStart_isInitialized(t *testing.T) {
+	t.Parallel()
+
+	// VERIFY
+	examinee := PipelineRunsStart.(*pipelineRunsStart)
+	assert.Assert(t, examinee.latencyMetric != nil)
+	assert.Assert(t, examinee.componentsMetric != nil)
+	assert.Assert(t, examinee.sloMetric != nil)
+	assert.Assert(t, examinee.waitTimeoutsMetric != nil)
+}
+
+func Test_PipelineRunsStart_ObserveStarted(t *testing.T) {
+	// no parallel: patching global state
+
+	// SETUP
+	examinee := newPipelineRunsStartWithPatchedRegistry(t)
+	examinee.SetSLOThresholds([]time.Duration{5 * time.Minute, time.Minute})
+
+	at := func(seconds int) metav1.Time {
+		return metav1.NewTime(fakeNow.Add(time.Duration(seconds) * time.Second))
+	}
+	run := &stewardapi.PipelineRun{}
+	run.SetCreationTimestamp(at(0))
+	run.Status.StateHistory = []stewardapi.StateItem{
+		{State: stewardapi.StateNew, StartedAt: at(0), FinishedAt: at(10)},
+		{State: stewardapi.StatePreparing, StartedAt: at(10), FinishedAt: at(30)},
+		{State: stewardapi.StateWaiting, StartedAt: at(30), FinishedAt: at(90)},
+	}
+
+	// EXERCISE
+	examinee.ObserveStarted(run, at(90).Time)
+
+	// VERIFY
+	assert.Equal(t, uint64(1), histogramSampleCount(t, examinee.latencyMetric))
+	assert.Equal(t, 3, testutil.CollectAndCount(examinee.componentsMetric))
+	assert.Equal(t, 2, testutil.CollectAndCount(examinee.sloMetric))
+	assert.Equal(t, float64(0), testutil.ToFloat64(examinee.sloMetric.WithLabelValues("60", "true")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(examinee.sloMetric.WithLabelValues("60", "false")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(examinee.sloMetric.WithLabelValues("300", "true")))
+}
+
+func Test_PipelineRunsStart_ObserveStarted_NoCreationTimestamp(t *testing.T) {
+	// no parallel: patching global state
+
+	// SETUP
+	examinee := newPipelineRunsStartWithPatchedRegistry(t)
+	examinee.SetSLOThresholds([]time.Duration{time.Minute})
+	run := &stewardapi.PipelineRun{}
+
+	// EXERCISE
+	examinee.ObserveStarted(run, fakeNow)
+
+	// VERIFY
+	assert.Equal(t, uint64(0), histogramSampleCount(t, examinee.latencyMetric))
+	assert.Equal(t, 0, testutil.CollectAndCount(examinee.sloMetric))
+}
+
+func Test_PipelineRunsStart_ObserveWaitTimeout(t *testing.T) {
+	// no parallel: patching global state
+
+	// SETUP
+	examinee := newPipelineRunsStartWithPatchedRegistry(t)
+	examinee.SetSLOThresholds([]time.Duration{time.Minute})
+
+	// EXERCISE
+	examinee.ObserveWaitTimeout()
+
+	// VERIFY
+	assert.Equal(t, float64(1), testutil.ToFloat64(examinee.waitTimeoutsMetric))
+	assert.Equal(t, float64(1), testutil.ToFloat64(examinee.sloMetric.WithLabelValues("60", "false")))
+}
+
+func newPipelineRunsStartWithPatchedRegistry(t *testing.T) *pipelineRunsStart {
+	t.Helper()
+	reg := prometheus.NewPedanticRegistry()
+	t.Cleanup(metrics.Testing{}.PatchRegistry(reg))
+	examinee := &pipelineRunsStart{}
+	examinee.init()
+	return examinee
+}
+
+func histogramSampleCount(t *testing.T, histogram prometheus.Histogram) uint64 {
+	t.Helper()
+	metric := &dto.Metric{}
+	assert.NilError(t, histogram.Write(metric))
+	return metric.GetHistogram().GetSampleCount()
+}
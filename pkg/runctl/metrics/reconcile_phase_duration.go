@@ -0,0 +1,48 @@
+package metrics
+
+import (
+	"sync"
+	"time"
+
+	"github.com/SAP/stewardci-core/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	// ReconcilePhaseDuration observes the duration of reconciliation
+	// phases, i.e. calls of the handler functions of the run controller
+	// that processed a pipeline run, partitioned by phase and outcome.
+	ReconcilePhaseDuration OperationDurationMetric = &reconcilePhaseDuration{}
+)
+
+func init() {
+	ReconcilePhaseDuration.(*reconcilePhaseDuration).init()
+}
+
+type reconcilePhaseDuration struct {
+	initOnlyOnce sync.Once
+	metric       *prometheus.HistogramVec
+}
+
+func (m *reconcilePhaseDuration) init() {
+	m.initOnlyOnce.Do(func() {
+		m.metric = prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Subsystem: subsystem,
+				Name:      "reconcile_phase_duration_seconds",
+				Help: "A histogram vector of the duration of reconciliation phases partitioned by phase and outcome." +
+					"\n\nLabel 'outcome' is 'success', 'recoverable_error', 'error' or the pipeline run result set in the phase.",
+				Buckets: prometheus.ExponentialBuckets(0.005, 2, 15),
+			},
+			[]string{
+				"phase",
+				"outcome",
+			},
+		)
+		metrics.Registerer().MustRegister(m.metric)
+	})
+}
+
+func (m *reconcilePhaseDuration) Observe(phase, outcome string, duration time.Duration) {
+	m.metric.WithLabelValues(phase, outcome).Observe(duration.Seconds())
+}
@@ -0,0 +1,32 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"gotest.tools/v3/assert"
+)
+
+func Test_ReconcilePhaseDuration_isInitialized(t *testing.T) {
+	t.Parallel()
+
+	// VERIFY
+	assert.Assert(t, ReconcilePhaseDuration.(*reconcilePhaseDuration).metric != nil)
+}
+
+func Test_ReconcilePhaseDuration_Observe(t *testing.T) {
+	// no parallel: using global metric
+
+	// SETUP
+	examinee := ReconcilePhaseDuration.(*reconcilePhaseDuration)
+	examinee.metric.Reset()
+
+	// EXERCISE
+	examinee.Observe("handlePipelineRunPrepare", OutcomeSuccess, 2*time.Second)
+	examinee.Observe("handlePipelineRunPrepare", OutcomeSuccess, 3*time.Second)
+	examinee.Observe("handlePipelineRunPrepare", "error_infra", time.Second)
+
+	// VERIFY
+	assert.Equal(t, 2, testutil.CollectAndCount(examinee.metric))
+}
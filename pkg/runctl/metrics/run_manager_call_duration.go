@@ -0,0 +1,47 @@
+package metrics
+
+import (
+	"sync"
+	"time"
+
+	"github.com/SAP/stewardci-core/pkg/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	// RunManagerCallDuration observes the duration of calls of run manager
+	// methods partitioned by method and outcome.
+	RunManagerCallDuration OperationDurationMetric = &runManagerCallDuration{}
+)
+
+func init() {
+	RunManagerCallDuration.(*runManagerCallDuration).init()
+}
+
+type runManagerCallDuration struct {
+	initOnlyOnce sync.Once
+	metric       *prometheus.HistogramVec
+}
+
+func (m *runManagerCallDuration) init() {
+	m.initOnlyOnce.Do(func() {
+		m.metric = prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Subsystem: subsystem,
+				Name:      "run_manager_call_duration_seconds",
+				Help: "A histogram vector of the duration of run manager calls partitioned by method and outcome." +
+					"\n\nLabel 'outcome' is 'success', 'recoverable_error', 'error' or the pipeline run result an error is classified with.",
+				Buckets: prometheus.ExponentialBuckets(0.005, 2, 15),
+			},
+			[]string{
+				"method",
+				"outcome",
+			},
+		)
+		metrics.Registerer().MustRegister(m.metric)
+	})
+}
+
+func (m *runManagerCallDuration) Observe(method, outcome string, duration time.Duration) {
+	m.metric.WithLabelValues(method, outcome).Observe(duration.Seconds())
+}
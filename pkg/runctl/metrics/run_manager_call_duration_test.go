@@ -0,0 +1,32 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"gotest.tools/v3/assert"
+)
+
+func Test_RunManagerCallDuration_isInitialized(t *testing.T) {
+	t.Parallel()
+
+	// VERIFY
+	assert.Assert(t, RunManagerCallDuration.(*runManagerCallDuration).metric != nil)
+}
+
+func Test_RunManagerCallDuration_Observe(t *testing.T) {
+	// no parallel: using global metric
+
+	// SETUP
+	examinee := RunManagerCallDuration.(*runManagerCallDuration)
+	examinee.metric.Reset()
+
+	// EXERCISE
+	examinee.Observe("CreateEnv", OutcomeSuccess, 2*time.Second)
+	examinee.Observe("CreateEnv", OutcomeSuccess, 3*time.Second)
+	examinee.Observe("CreateEnv", "error_infra", time.Second)
+
+	// VERIFY
+	assert.Equal(t, 2, testutil.CollectAndCount(examinee.metric))
+}
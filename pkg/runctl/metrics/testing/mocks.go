@@ -22,13 +22,14 @@
 //
 
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/SAP/stewardci-core/pkg/runctl/metrics (interfaces: CounterMetric,PipelineRunsMetric,StateItemsMetric,ResultsMetric)
+// Source: github.com/SAP/stewardci-core/pkg/runctl/metrics (interfaces: CounterMetric,PipelineRunsMetric,StateItemsMetric,ResultsMetric,OperationDurationMetric,PipelineRunsCountMetric,PipelineRunsStartMetric,OutcomesMetric)
 
 // Package testing is a generated GoMock package.
 package testing
 
 import (
 	reflect "reflect"
+	time "time"
 
 	v1alpha1 "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
 	gomock "github.com/golang/mock/gomock"
@@ -173,3 +174,167 @@ func (mr *MockResultsMetricMockRecorder) Observe(arg0 interface{}) *gomock.Call
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Observe", reflect.TypeOf((*MockResultsMetric)(nil).Observe), arg0)
 }
+
+// MockOperationDurationMetric is a mock of OperationDurationMetric interface.
+type MockOperationDurationMetric struct {
+	ctrl     *gomock.Controller
+	recorder *MockOperationDurationMetricMockRecorder
+}
+
+// MockOperationDurationMetricMockRecorder is the mock recorder for MockOperationDurationMetric.
+type MockOperationDurationMetricMockRecorder struct {
+	mock *MockOperationDurationMetric
+}
+
+// NewMockOperationDurationMetric creates a new mock instance.
+func NewMockOperationDurationMetric(ctrl *gomock.Controller) *MockOperationDurationMetric {
+	mock := &MockOperationDurationMetric{ctrl: ctrl}
+	mock.recorder = &MockOperationDurationMetricMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOperationDurationMetric) EXPECT() *MockOperationDurationMetricMockRecorder {
+	return m.recorder
+}
+
+// Observe mocks base method.
+func (m *MockOperationDurationMetric) Observe(arg0, arg1 string, arg2 time.Duration) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Observe", arg0, arg1, arg2)
+}
+
+// Observe indicates an expected call of Observe.
+func (mr *MockOperationDurationMetricMockRecorder) Observe(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Observe", reflect.TypeOf((*MockOperationDurationMetric)(nil).Observe), arg0, arg1, arg2)
+}
+
+// MockPipelineRunsCountMetric is a mock of PipelineRunsCountMetric interface.
+type MockPipelineRunsCountMetric struct {
+	ctrl     *gomock.Controller
+	recorder *MockPipelineRunsCountMetricMockRecorder
+}
+
+// MockPipelineRunsCountMetricMockRecorder is the mock recorder for MockPipelineRunsCountMetric.
+type MockPipelineRunsCountMetricMockRecorder struct {
+	mock *MockPipelineRunsCountMetric
+}
+
+// NewMockPipelineRunsCountMetric creates a new mock instance.
+func NewMockPipelineRunsCountMetric(ctrl *gomock.Controller) *MockPipelineRunsCountMetric {
+	mock := &MockPipelineRunsCountMetric{ctrl: ctrl}
+	mock.recorder = &MockPipelineRunsCountMetricMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPipelineRunsCountMetric) EXPECT() *MockPipelineRunsCountMetricMockRecorder {
+	return m.recorder
+}
+
+// Set mocks base method.
+func (m *MockPipelineRunsCountMetric) Set(arg0 []*v1alpha1.PipelineRun) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Set", arg0)
+}
+
+// Set indicates an expected call of Set.
+func (mr *MockPipelineRunsCountMetricMockRecorder) Set(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockPipelineRunsCountMetric)(nil).Set), arg0)
+}
+
+// MockPipelineRunsStartMetric is a mock of PipelineRunsStartMetric interface.
+type MockPipelineRunsStartMetric struct {
+	ctrl     *gomock.Controller
+	recorder *MockPipelineRunsStartMetricMockRecorder
+}
+
+// MockPipelineRunsStartMetricMockRecorder is the mock recorder for MockPipelineRunsStartMetric.
+type MockPipelineRunsStartMetricMockRecorder struct {
+	mock *MockPipelineRunsStartMetric
+}
+
+// NewMockPipelineRunsStartMetric creates a new mock instance.
+func NewMockPipelineRunsStartMetric(ctrl *gomock.Controller) *MockPipelineRunsStartMetric {
+	mock := &MockPipelineRunsStartMetric{ctrl: ctrl}
+	mock.recorder = &MockPipelineRunsStartMetricMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPipelineRunsStartMetric) EXPECT() *MockPipelineRunsStartMetricMockRecorder {
+	return m.recorder
+}
+
+// ObserveStarted mocks base method.
+func (m *MockPipelineRunsStartMetric) ObserveStarted(arg0 *v1alpha1.PipelineRun, arg1 time.Time) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ObserveStarted", arg0, arg1)
+}
+
+// ObserveStarted indicates an expected call of ObserveStarted.
+func (mr *MockPipelineRunsStartMetricMockRecorder) ObserveStarted(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ObserveStarted", reflect.TypeOf((*MockPipelineRunsStartMetric)(nil).ObserveStarted), arg0, arg1)
+}
+
+// ObserveWaitTimeout mocks base method.
+func (m *MockPipelineRunsStartMetric) ObserveWaitTimeout() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ObserveWaitTimeout")
+}
+
+// ObserveWaitTimeout indicates an expected call of ObserveWaitTimeout.
+func (mr *MockPipelineRunsStartMetricMockRecorder) ObserveWaitTimeout() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ObserveWaitTimeout", reflect.TypeOf((*MockPipelineRunsStartMetric)(nil).ObserveWaitTimeout))
+}
+
+// SetSLOThresholds mocks base method.
+func (m *MockPipelineRunsStartMetric) SetSLOThresholds(arg0 []time.Duration) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetSLOThresholds", arg0)
+}
+
+// SetSLOThresholds indicates an expected call of SetSLOThresholds.
+func (mr *MockPipelineRunsStartMetricMockRecorder) SetSLOThresholds(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSLOThresholds", reflect.TypeOf((*MockPipelineRunsStartMetric)(nil).SetSLOThresholds), arg0)
+}
+
+// MockOutcomesMetric is a mock of OutcomesMetric interface.
+type MockOutcomesMetric struct {
+	ctrl     *gomock.Controller
+	recorder *MockOutcomesMetricMockRecorder
+}
+
+// MockOutcomesMetricMockRecorder is the mock recorder for MockOutcomesMetric.
+type MockOutcomesMetricMockRecorder struct {
+	mock *MockOutcomesMetric
+}
+
+// NewMockOutcomesMetric creates a new mock instance.
+func NewMockOutcomesMetric(ctrl *gomock.Controller) *MockOutcomesMetric {
+	mock := &MockOutcomesMetric{ctrl: ctrl}
+	mock.recorder = &MockOutcomesMetricMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOutcomesMetric) EXPECT() *MockOutcomesMetricMockRecorder {
+	return m.recorder
+}
+
+// Observe mocks base method.
+func (m *MockOutcomesMetric) Observe(arg0 string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Observe", arg0)
+}
+
+// Observe indicates an expected call of Observe.
+func (mr *MockOutcomesMetricMockRecorder) Observe(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Observe", reflect.TypeOf((*MockOutcomesMetric)(nil).Observe), arg0)
+}
@@ -17,3 +17,83 @@ func PatchPipelineRunsPeriodic(replacement metrics.PipelineRunsMetric) func() {
 		metrics.PipelineRunsPeriodic = origValue
 	}
 }
+
+// PatchReconcilePhaseDuration patches
+// "github.com/SAP/stewardci-core/pkg/runctl/metrics".ReconcilePhaseDuration with
+// the given replacement and returns a function that reverts the patch.
+// Multiple nested replacements must be reverted in exactly the opposite order
+// (revert last replacement first).
+func PatchReconcilePhaseDuration(replacement metrics.OperationDurationMetric) func() {
+	origValue := metrics.ReconcilePhaseDuration
+	metrics.ReconcilePhaseDuration = replacement
+	return func() {
+		if metrics.ReconcilePhaseDuration != replacement {
+			panic("reverting not possible because current value is not the former replacement")
+		}
+		metrics.ReconcilePhaseDuration = origValue
+	}
+}
+
+// PatchRunManagerCallDuration patches
+// "github.com/SAP/stewardci-core/pkg/runctl/metrics".RunManagerCallDuration with
+// the given replacement and returns a function that reverts the patch.
+// Multiple nested replacements must be reverted in exactly the opposite order
+// (revert last replacement first).
+func PatchRunManagerCallDuration(replacement metrics.OperationDurationMetric) func() {
+	origValue := metrics.RunManagerCallDuration
+	metrics.RunManagerCallDuration = replacement
+	return func() {
+		if metrics.RunManagerCallDuration != replacement {
+			panic("reverting not possible because current value is not the former replacement")
+		}
+		metrics.RunManagerCallDuration = origValue
+	}
+}
+
+// PatchPipelineRunsCount patches
+// "github.com/SAP/stewardci-core/pkg/runctl/metrics".PipelineRunsCount with
+// the given replacement and returns a function that reverts the patch.
+// Multiple nested replacements must be reverted in exactly the opposite order
+// (revert last replacement first).
+func PatchPipelineRunsCount(replacement metrics.PipelineRunsCountMetric) func() {
+	origValue := metrics.PipelineRunsCount
+	metrics.PipelineRunsCount = replacement
+	return func() {
+		if metrics.PipelineRunsCount != replacement {
+			panic("reverting not possible because current value is not the former replacement")
+		}
+		metrics.PipelineRunsCount = origValue
+	}
+}
+
+// PatchPipelineRunsStart patches
+// "github.com/SAP/stewardci-core/pkg/runctl/metrics".PipelineRunsStart with
+// the given replacement and returns a function that reverts the patch.
+// Multiple nested replacements must be reverted in exactly the opposite order
+// (revert last replacement first).
+func PatchPipelineRunsStart(replacement metrics.PipelineRunsStartMetric) func() {
+	origValue := metrics.PipelineRunsStart
+	metrics.PipelineRunsStart = replacement
+	return func() {
+		if metrics.PipelineRunsStart != replacement {
+			panic("reverting not possible because current value is not the former replacement")
+		}
+		metrics.PipelineRunsStart = origValue
+	}
+}
+
+// PatchCloudEventsDeliveries patches
+// "github.com/SAP/stewardci-core/pkg/runctl/metrics".CloudEventsDeliveries with
+// the given replacement and returns a function that reverts the patch.
+// Multiple nested replacements must be reverted in exactly the opposite order
+// (revert last replacement first).
+func PatchCloudEventsDeliveries(replacement metrics.OutcomesMetric) func() {
+	origValue := metrics.CloudEventsDeliveries
+	metrics.CloudEventsDeliveries = replacement
+	return func() {
+		if metrics.CloudEventsDeliveries != replacement {
+			panic("reverting not possible because current value is not the former replacement")
+		}
+		metrics.CloudEventsDeliveries = origValue
+	}
+}
@@ -0,0 +1,140 @@
+package notifications
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// HeaderEvent is the HTTP header carrying the notification event.
+	HeaderEvent = "X-Steward-Event"
+
+	// HeaderSignature is the HTTP header carrying the HMAC-SHA256
+	// signature of the request body in the format `sha256=<hex digest>`.
+	HeaderSignature = "X-Steward-Signature-256"
+
+	// SigningSecretKey is the key of the signing secret's data entry
+	// holding the signing key.
+	SigningSecretKey = "key"
+)
+
+// Payload is the JSON body of notifications.
+type Payload struct {
+	Event api.NotificationEvent `json:"event"`
+
+	// Key is the key of the pipeline run in the format
+	// `<namespace>/<name>`.
+	Key string `json:"key"`
+
+	Namespace string    `json:"namespace"`
+	Name      string    `json:"name"`
+	UID       types.UID `json:"uid"`
+
+	State   api.State  `json:"state"`
+	Result  api.Result `json:"result,omitempty"`
+	Message string     `json:"message,omitempty"`
+
+	RunDetails *api.PipelineRunDetails `json:"runDetails,omitempty"`
+}
+
+// NewPayload returns the notification payload for the given event
+// carrying the current status of the given pipeline run.
+func NewPayload(event api.NotificationEvent, pipelineRun *api.PipelineRun) *Payload {
+	namespace, name := pipelineRun.GetNamespace(), pipelineRun.GetName()
+	return &Payload{
+		Event:      event,
+		Key:        namespace + "/" + name,
+		Namespace:  namespace,
+		Name:       name,
+		UID:        pipelineRun.GetUID(),
+		State:      pipelineRun.Status.State,
+		Result:     pipelineRun.Status.Result,
+		Message:    pipelineRun.Status.Message,
+		RunDetails: pipelineRun.Spec.RunDetails,
+	}
+}
+
+// Sign returns the value of header HeaderSignature for the given body
+// signed with the given key.
+func Sign(key, body []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// ForStart returns pending notifications for all targets interested in
+// the start of the pipeline execution.
+func ForStart(targets []api.NotificationTarget) []api.NotificationStatus {
+	var result []api.NotificationStatus
+	for _, target := range targets {
+		if subscribes(target, api.NotificationEventStarted) {
+			result = append(result, pending(target, api.NotificationEventStarted))
+		}
+	}
+	return result
+}
+
+// ForCompletion returns pending notifications for all targets interested
+// in the completion of a pipeline run with the given result.
+// Targets interested in both failures and completion get a single
+// `failed` notification if the result is not `success`.
+// No notifications are returned for deleted pipeline runs.
+func ForCompletion(targets []api.NotificationTarget, result api.Result) []api.NotificationStatus {
+	if result == api.ResultUndefined || result == api.ResultDeleted {
+		return nil
+	}
+	var notifications []api.NotificationStatus
+	for _, target := range targets {
+		switch {
+		case result != api.ResultSuccess && subscribes(target, api.NotificationEventFailed):
+			notifications = append(notifications, pending(target, api.NotificationEventFailed))
+		case subscribes(target, api.NotificationEventFinished):
+			notifications = append(notifications, pending(target, api.NotificationEventFinished))
+		}
+	}
+	return notifications
+}
+
+// HasPending returns whether the given pipeline run has notifications
+// not delivered yet.
+func HasPending(pipelineRun *api.PipelineRun) bool {
+	for _, notification := range pipelineRun.Status.Notifications {
+		if notification.State == api.NotificationStatePending {
+			return true
+		}
+	}
+	return false
+}
+
+func subscribes(target api.NotificationTarget, event api.NotificationEvent) bool {
+	if len(target.Events) == 0 {
+		return event == api.NotificationEventFinished
+	}
+	for _, e := range target.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+func pending(target api.NotificationTarget, event api.NotificationEvent) api.NotificationStatus {
+	return api.NotificationStatus{
+		Name:  target.Name,
+		Event: event,
+		State: api.NotificationStatePending,
+	}
+}
+
+func findTarget(pipelineRun *api.PipelineRun, name string) *api.NotificationTarget {
+	for i := range pipelineRun.Spec.Notifications {
+		if pipelineRun.Spec.Notifications[i].Name == name {
+			return &pipelineRun.Spec.Notifications[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package notifications
+
+import (
+	"testing"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	fake "github.com/SAP/stewardci-core/pkg/k8s/fake"
+	"gotest.tools/v3/assert"
+)
+
+func Test_ForStart(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	targets := []api.NotificationTarget{
+		{Name: "default"},
+		{Name: "started", Events: []api.NotificationEvent{api.NotificationEventStarted}},
+		{Name: "failed", Events: []api.NotificationEvent{api.NotificationEventFailed}},
+	}
+
+	// EXERCISE
+	result := ForStart(targets)
+
+	// VERIFY
+	assert.DeepEqual(t, []api.NotificationStatus{
+		{Name: "started", Event: api.NotificationEventStarted, State: api.NotificationStatePending},
+	}, result)
+}
+
+func Test_ForCompletion(t *testing.T) {
+	t.Parallel()
+
+	targets := []api.NotificationTarget{
+		{Name: "default"},
+		{Name: "started", Events: []api.NotificationEvent{api.NotificationEventStarted}},
+		{Name: "failed", Events: []api.NotificationEvent{api.NotificationEventFailed}},
+		{Name: "both", Events: []api.NotificationEvent{api.NotificationEventFinished, api.NotificationEventFailed}},
+	}
+	pending := func(name string, event api.NotificationEvent) api.NotificationStatus {
+		return api.NotificationStatus{Name: name, Event: event, State: api.NotificationStatePending}
+	}
+
+	for _, tc := range []struct {
+		name     string
+		result   api.Result
+		expected []api.NotificationStatus
+	}{
+		{"success", api.ResultSuccess, []api.NotificationStatus{
+			pending("default", api.NotificationEventFinished),
+			pending("both", api.NotificationEventFinished),
+		}},
+		{"error_content", api.ResultErrorContent, []api.NotificationStatus{
+			pending("default", api.NotificationEventFinished),
+			pending("failed", api.NotificationEventFailed),
+			pending("both", api.NotificationEventFailed),
+		}},
+		{"aborted", api.ResultAborted, []api.NotificationStatus{
+			pending("default", api.NotificationEventFinished),
+			pending("failed", api.NotificationEventFailed),
+			pending("both", api.NotificationEventFailed),
+		}},
+		{"deleted", api.ResultDeleted, nil},
+		{"undefined", api.ResultUndefined, nil},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// EXERCISE
+			result := ForCompletion(targets, tc.result)
+
+			// VERIFY
+			assert.DeepEqual(t, tc.expected, result)
+		})
+	}
+}
+
+func Test_HasPending(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name     string
+		states   []api.NotificationState
+		expected bool
+	}{
+		{"none", nil, false},
+		{"pending", []api.NotificationState{api.NotificationStateDelivered, api.NotificationStatePending}, true},
+		{"done", []api.NotificationState{api.NotificationStateDelivered, api.NotificationStateFailed}, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			pipelineRun := fake.PipelineRun("run1", "ns1", api.PipelineSpec{})
+			for _, state := range tc.states {
+				pipelineRun.Status.Notifications = append(pipelineRun.Status.Notifications,
+					api.NotificationStatus{Name: "target1", State: state})
+			}
+
+			// EXERCISE
+			result := HasPending(pipelineRun)
+
+			// VERIFY
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func Test_Sign(t *testing.T) {
+	t.Parallel()
+
+	// EXERCISE
+	result := Sign([]byte("key1"), []byte(`{"key":"value"}`))
+
+	// VERIFY
+	assert.Equal(t, "sha256=ed674ece3952eeeb94a0035629cb992fc82953b80cfbaf84d65bcc4a908ee8a7", result)
+}
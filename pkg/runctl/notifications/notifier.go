@@ -0,0 +1,352 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	stewardv1alpha1 "github.com/SAP/stewardci-core/pkg/client/clientset/versioned/typed/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/egress"
+	k8s "github.com/SAP/stewardci-core/pkg/k8s"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/client-go/util/workqueue"
+)
+
+const (
+	defaultMaxAttempts    = 8
+	defaultRetryDelay     = 10 * time.Second
+	defaultRequestTimeout = 10 * time.Second
+
+	// maxRetryDelay caps the exponential backoff between attempts.
+	maxRetryDelay = 30 * time.Minute
+
+	// maxResponseBodySize is the maximum number of bytes of a webhook
+	// response body read before the connection is closed.
+	maxResponseBodySize = 64 * 1024
+)
+
+// Options are the options for creating a Notifier.
+type Options struct {
+	// MaxAttempts is the maximum number of delivery attempts of a
+	// notification.
+	// If zero or negative, a default is used.
+	MaxAttempts int32
+
+	// RetryDelay is the delay before the second delivery attempt of a
+	// notification. It doubles with each further attempt.
+	// If zero or negative, a default is used.
+	RetryDelay time.Duration
+
+	// RequestTimeout is the timeout of a single delivery attempt.
+	// If zero or negative, a default is used.
+	RequestTimeout time.Duration
+
+	// AllowedNetworks are the networks notification targets may be
+	// located in although their addresses are loopback, link-local or
+	// private addresses. Notifications to such addresses are rejected
+	// otherwise.
+	AllowedNetworks []*net.IPNet
+
+	// IsOwned returns whether the pipeline run with the given key is
+	// processed by this instance, e.g. because it belongs to a shard
+	// owned by this instance. Notifications of other pipeline runs are
+	// not delivered.
+	// If nil, all pipeline runs are owned.
+	IsOwned func(key string) bool
+}
+
+// Notifier delivers the pending notifications recorded in the status of
+// pipeline runs to the webhooks specified in the pipeline run spec.
+//
+// Delivery happens independently of the reconciliation of the pipeline
+// runs. Failed deliveries are retried with exponential backoff until the
+// maximum number of attempts is reached. The delivery state of each
+// notification is recorded in the pipeline run status, so that delivery
+// resumes after a restart of the controller. Notifications are delivered
+// at least once.
+//
+// Notifications are delivered via HTTPS only and never to loopback,
+// link-local or private addresses outside the allowed networks, so that
+// pipeline runs cannot make the controller send requests to internal
+// services (see package egress).
+type Notifier struct {
+	factory    k8s.ClientFactory
+	httpClient *http.Client
+	queue      workqueue.RateLimitingInterface
+	opts       Options
+	logger     logr.Logger
+
+	// now returns the current time. Can be replaced in tests.
+	now func() time.Time
+}
+
+// NewNotifier creates a new Notifier. Notifications are delivered only
+// after the Notifier has been started.
+func NewNotifier(logger logr.Logger, factory k8s.ClientFactory, opts Options) *Notifier {
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = defaultMaxAttempts
+	}
+	if opts.RetryDelay <= 0 {
+		opts.RetryDelay = defaultRetryDelay
+	}
+	if opts.RequestTimeout <= 0 {
+		opts.RequestTimeout = defaultRequestTimeout
+	}
+	n := &Notifier{
+		factory: factory,
+		queue:   workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
+		opts:    opts,
+		logger:  logger.WithName("notifications"),
+		now:     time.Now,
+	}
+	n.httpClient = egress.NewHTTPClient(egress.Options{
+		Timeout:         opts.RequestTimeout,
+		AllowedNetworks: opts.AllowedNetworks,
+		FollowRedirects: true,
+	})
+	return n
+}
+
+// Enqueue schedules the delivery of the pending notifications of the
+// pipeline run with the given key.
+func (n *Notifier) Enqueue(key string) {
+	n.queue.Add(key)
+}
+
+// Len returns the number of pipeline runs with notifications ready for
+// processing.
+func (n *Notifier) Len() int {
+	return n.queue.Len()
+}
+
+// Run delivers notifications with the given number of workers until the
+// given channel is closed. It blocks until then.
+func (n *Notifier) Run(workers int, stopCh <-chan struct{}) {
+	defer utilruntime.HandleCrash()
+	defer n.queue.ShutDown()
+
+	for i := 0; i < workers; i++ {
+		go wait.Until(n.runWorker, time.Second, stopCh)
+	}
+	<-stopCh
+}
+
+func (n *Notifier) runWorker() {
+	for n.processNextWorkItem() {
+	}
+}
+
+func (n *Notifier) processNextWorkItem() bool {
+	obj, shutdown := n.queue.Get()
+	if shutdown {
+		return false
+	}
+	defer n.queue.Done(obj)
+
+	key, ok := obj.(string)
+	if !ok {
+		n.queue.Forget(obj)
+		utilruntime.HandleError(fmt.Errorf("expected string in notification queue but got %#v", obj))
+		return true
+	}
+	requeueAfter, err := n.process(context.Background(), key)
+	if err != nil {
+		n.logger.Error(err, "Failed to process notifications", "pipelineRun", key)
+		n.queue.AddRateLimited(key)
+		return true
+	}
+	n.queue.Forget(key)
+	if requeueAfter > 0 {
+		n.queue.AddAfter(key, requeueAfter)
+	}
+	return true
+}
+
+// process attempts the delivery of all pending notifications of the
+// pipeline run with the given key which are due, and records the outcome
+// in the pipeline run status.
+// It returns the duration after which the next pending notification is
+// due, or zero if there is none.
+func (n *Notifier) process(ctx context.Context, key string) (time.Duration, error) {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("invalid resource key: %s", key))
+		return 0, nil
+	}
+	if n.opts.IsOwned != nil && !n.opts.IsOwned(key) {
+		// delivered by the owning instance
+		n.logger.V(5).Info("Skipped notifications of pipeline run not owned", "pipelineRun", key)
+		return 0, nil
+	}
+	client := n.factory.StewardV1alpha1().PipelineRuns(namespace)
+	pipelineRun, err := client.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	now := n.now()
+	var updates []api.NotificationStatus
+	var requeueAfter time.Duration
+	for _, notification := range pipelineRun.Status.Notifications {
+		if notification.State != api.NotificationStatePending {
+			continue
+		}
+		if delay := n.nextAttemptAt(notification).Sub(now); delay > 0 {
+			requeueAfter = minPositive(requeueAfter, delay)
+			continue
+		}
+
+		update := *notification.DeepCopy()
+		update.Attempts++
+		update.LastAttemptAt = &metav1.Time{Time: now}
+		deliveryErr := n.deliver(ctx, pipelineRun, notification)
+		switch {
+		case deliveryErr == nil:
+			update.State = api.NotificationStateDelivered
+			update.Message = ""
+			n.logger.V(3).Info("Delivered notification",
+				"pipelineRun", key, "target", notification.Name, "event", notification.Event)
+		case update.Attempts >= n.opts.MaxAttempts:
+			update.State = api.NotificationStateFailed
+			update.Message = deliveryErr.Error()
+			n.logger.Error(deliveryErr, "Finally failed to deliver notification",
+				"pipelineRun", key, "target", notification.Name, "event", notification.Event,
+				"attempts", update.Attempts)
+		default:
+			update.Message = deliveryErr.Error()
+			requeueAfter = minPositive(requeueAfter, n.retryDelay(update.Attempts))
+			n.logger.V(3).Info("Failed to deliver notification, will retry",
+				"pipelineRun", key, "target", notification.Name, "event", notification.Event,
+				"attempts", update.Attempts, "error", deliveryErr.Error())
+		}
+		updates = append(updates, update)
+	}
+
+	if len(updates) > 0 {
+		if err := updateStatus(ctx, client, name, updates); err != nil {
+			return 0, errors.Wrap(err, "failed to record notification delivery status")
+		}
+	}
+	return requeueAfter, nil
+}
+
+// deliver posts the given notification to its target.
+func (n *Notifier) deliver(ctx context.Context, pipelineRun *api.PipelineRun, notification api.NotificationStatus) error {
+	target := findTarget(pipelineRun, notification.Name)
+	if target == nil {
+		return fmt.Errorf("notification target %q not found in spec", notification.Name)
+	}
+	body, err := json.Marshal(NewPayload(notification.Event, pipelineRun))
+	if err != nil {
+		return errors.Wrap(err, "failed to encode notification")
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, target.URL, bytes.NewReader(body))
+	if err != nil {
+		return errors.New("invalid notification target URL")
+	}
+	if err := egress.CheckScheme(request.URL); err != nil {
+		return errors.Wrap(err, "invalid notification target")
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(HeaderEvent, string(notification.Event))
+	if target.SigningSecret != "" {
+		key, err := n.getSigningKey(ctx, pipelineRun.GetNamespace(), target.SigningSecret)
+		if err != nil {
+			return err
+		}
+		request.Header.Set(HeaderSignature, Sign(key, body))
+	}
+
+	response, err := n.httpClient.Do(request)
+	if err != nil {
+		// the URL may contain credentials and must not be part of the
+		// message recorded in the status
+		if urlErr, ok := err.(*url.Error); ok {
+			err = urlErr.Err
+		}
+		return errors.Wrap(err, "request failed")
+	}
+	defer response.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(response.Body, maxResponseBodySize))
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return fmt.Errorf("webhook responded with status code %d", response.StatusCode)
+	}
+	return nil
+}
+
+func (n *Notifier) getSigningKey(ctx context.Context, namespace, secretName string) ([]byte, error) {
+	secret, err := n.factory.CoreV1().Secrets(namespace).Get(ctx, secretName, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get signing secret %q", secretName)
+	}
+	key, ok := secret.Data[SigningSecretKey]
+	if !ok || len(key) == 0 {
+		return nil, fmt.Errorf("signing secret %q has no key %q", secretName, SigningSecretKey)
+	}
+	return key, nil
+}
+
+// nextAttemptAt returns the earliest time of the next delivery attempt
+// of the given notification.
+func (n *Notifier) nextAttemptAt(notification api.NotificationStatus) time.Time {
+	if notification.Attempts == 0 || notification.LastAttemptAt == nil {
+		return time.Time{}
+	}
+	return notification.LastAttemptAt.Add(n.retryDelay(notification.Attempts))
+}
+
+// retryDelay returns the delay after the given number of failed attempts.
+func (n *Notifier) retryDelay(attempts int32) time.Duration {
+	delay := n.opts.RetryDelay
+	for i := int32(1); i < attempts && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxRetryDelay {
+		delay = maxRetryDelay
+	}
+	return delay
+}
+
+// updateStatus replaces the notification records of the pipeline run
+// with the given name by the given updated records.
+func updateStatus(ctx context.Context, client stewardv1alpha1.PipelineRunInterface, name string, updates []api.NotificationStatus) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		pipelineRun, err := client.Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		for _, update := range updates {
+			for i, existing := range pipelineRun.Status.Notifications {
+				if existing.Name == update.Name && existing.Event == update.Event {
+					pipelineRun.Status.Notifications[i] = update
+				}
+			}
+		}
+		_, err = client.UpdateStatus(ctx, pipelineRun, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+func minPositive(a, b time.Duration) time.Duration {
+	if a <= 0 || b < a {
+		return b
+	}
+	return a
+}
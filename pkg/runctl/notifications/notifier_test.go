@@ -0,0 +1,366 @@
+package notifications
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	fake "github.com/SAP/stewardci-core/pkg/k8s/fake"
+	"github.com/go-logr/logr"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type receivedRequest struct {
+	header http.Header
+	body   string
+}
+
+// newReceiver starts a local HTTPS server responding with the given
+// status code and returns it together with a channel of the received
+// requests.
+func newReceiver(t *testing.T, statusCode int) (*httptest.Server, <-chan receivedRequest) {
+	t.Helper()
+	requests := make(chan receivedRequest, 10)
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests <- receivedRequest{header: r.Header.Clone(), body: string(body)}
+		w.WriteHeader(statusCode)
+	}))
+	t.Cleanup(server.Close)
+	return server, requests
+}
+
+// newExaminee creates a Notifier delivering to the local receivers
+// started by newReceiver.
+func newExaminee(t *testing.T, now time.Time, pipelineRun *api.PipelineRun, secrets ...*corev1.Secret) (*Notifier, *fake.ClientFactory) {
+	t.Helper()
+	return newExamineeWithOptions(t, now, pipelineRun, Options{
+		MaxAttempts:     3,
+		RetryDelay:      time.Minute,
+		AllowedNetworks: []*net.IPNet{mustParseCIDR(t, "127.0.0.0/8")},
+	}, secrets...)
+}
+
+func newExamineeWithOptions(t *testing.T, now time.Time, pipelineRun *api.PipelineRun, opts Options, secrets ...*corev1.Secret) (*Notifier, *fake.ClientFactory) {
+	t.Helper()
+	factory := fake.NewClientFactory(pipelineRun)
+	for _, secret := range secrets {
+		_, err := factory.CoreV1().Secrets(secret.Namespace).Create(context.Background(), secret, metav1.CreateOptions{})
+		assert.NilError(t, err)
+	}
+	examinee := NewNotifier(logr.Discard(), factory, opts)
+	examinee.now = func() time.Time { return now }
+	// trust the self-signed certificates of the local receivers
+	examinee.httpClient.Transport.(*http.Transport).TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	return examinee, factory
+}
+
+func mustParseCIDR(t *testing.T, cidr string) *net.IPNet {
+	t.Helper()
+	_, network, err := net.ParseCIDR(cidr)
+	assert.NilError(t, err)
+	return network
+}
+
+func getNotifications(t *testing.T, factory *fake.ClientFactory, pipelineRun *api.PipelineRun) []api.NotificationStatus {
+	t.Helper()
+	result, err := factory.StewardV1alpha1().PipelineRuns(pipelineRun.Namespace).Get(context.Background(), pipelineRun.Name, metav1.GetOptions{})
+	assert.NilError(t, err)
+	return result.Status.Notifications
+}
+
+func Test_Notifier_process_DeliversSignedNotification(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	server, requests := newReceiver(t, http.StatusNoContent)
+	pipelineRun := fake.PipelineRun("run1", "ns1", api.PipelineSpec{
+		Notifications: []api.NotificationTarget{
+			{Name: "target1", URL: server.URL, SigningSecret: "secret1"},
+		},
+	})
+	pipelineRun.Status.State = api.StateFinished
+	pipelineRun.Status.Result = api.ResultSuccess
+	pipelineRun.Status.Notifications = []api.NotificationStatus{
+		{Name: "target1", Event: api.NotificationEventFinished, State: api.NotificationStatePending},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: fake.ObjectMeta("secret1", "ns1"),
+		Data:       map[string][]byte{"key": []byte("key1")},
+	}
+	examinee, factory := newExaminee(t, now, pipelineRun, secret)
+
+	// EXERCISE
+	requeueAfter, resultErr := examinee.process(context.Background(), "ns1/run1")
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	assert.Equal(t, time.Duration(0), requeueAfter)
+	request := <-requests
+	assert.Equal(t, "application/json", request.header.Get("Content-Type"))
+	assert.Equal(t, "finished", request.header.Get(HeaderEvent))
+	assert.Equal(t, Sign([]byte("key1"), []byte(request.body)), request.header.Get(HeaderSignature))
+	assert.Equal(t, `{"event":"finished","key":"ns1/run1","namespace":"ns1","name":"run1","uid":"","state":"finished","result":"success"}`, request.body)
+	assert.DeepEqual(t, []api.NotificationStatus{
+		{
+			Name:          "target1",
+			Event:         api.NotificationEventFinished,
+			State:         api.NotificationStateDelivered,
+			Attempts:      1,
+			LastAttemptAt: &metav1.Time{Time: now},
+		},
+	}, getNotifications(t, factory, pipelineRun))
+}
+
+func Test_Notifier_process_RecordsFailedAttempt(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	server, requests := newReceiver(t, http.StatusServiceUnavailable)
+	pipelineRun := fake.PipelineRun("run1", "ns1", api.PipelineSpec{
+		Notifications: []api.NotificationTarget{{Name: "target1", URL: server.URL}},
+	})
+	pipelineRun.Status.Notifications = []api.NotificationStatus{
+		{
+			Name:          "target1",
+			Event:         api.NotificationEventFailed,
+			State:         api.NotificationStatePending,
+			Attempts:      1,
+			LastAttemptAt: &metav1.Time{Time: now.Add(-time.Minute)},
+		},
+	}
+	examinee, factory := newExaminee(t, now, pipelineRun)
+
+	// EXERCISE
+	requeueAfter, resultErr := examinee.process(context.Background(), "ns1/run1")
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	assert.Equal(t, 2*time.Minute, requeueAfter)
+	assert.Equal(t, 1, len(requests))
+	assert.DeepEqual(t, []api.NotificationStatus{
+		{
+			Name:          "target1",
+			Event:         api.NotificationEventFailed,
+			State:         api.NotificationStatePending,
+			Attempts:      2,
+			LastAttemptAt: &metav1.Time{Time: now},
+			Message:       "webhook responded with status code 503",
+		},
+	}, getNotifications(t, factory, pipelineRun))
+}
+
+func Test_Notifier_process_FailsFinallyAfterMaxAttempts(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	pipelineRun := fake.PipelineRun("run1", "ns1", api.PipelineSpec{
+		Notifications: []api.NotificationTarget{{Name: "target1", URL: "https://localhost:1", SigningSecret: "missing"}},
+	})
+	pipelineRun.Status.Notifications = []api.NotificationStatus{
+		{
+			Name:          "target1",
+			Event:         api.NotificationEventFinished,
+			State:         api.NotificationStatePending,
+			Attempts:      2,
+			LastAttemptAt: &metav1.Time{Time: now.Add(-2 * time.Minute)},
+		},
+	}
+	examinee, factory := newExaminee(t, now, pipelineRun)
+
+	// EXERCISE
+	requeueAfter, resultErr := examinee.process(context.Background(), "ns1/run1")
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	assert.Equal(t, time.Duration(0), requeueAfter)
+	notifications := getNotifications(t, factory, pipelineRun)
+	assert.Equal(t, 1, len(notifications))
+	assert.Equal(t, api.NotificationStateFailed, notifications[0].State)
+	assert.Equal(t, int32(3), notifications[0].Attempts)
+	assert.Equal(t, `failed to get signing secret "missing": secrets "missing" not found`, notifications[0].Message)
+}
+
+func Test_Notifier_process_SkipsNotificationsNotDue(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	server, requests := newReceiver(t, http.StatusOK)
+	pipelineRun := fake.PipelineRun("run1", "ns1", api.PipelineSpec{
+		Notifications: []api.NotificationTarget{{Name: "target1", URL: server.URL}},
+	})
+	pipelineRun.Status.Notifications = []api.NotificationStatus{
+		{Name: "target1", Event: api.NotificationEventStarted, State: api.NotificationStateDelivered, Attempts: 1},
+		{
+			Name:          "target1",
+			Event:         api.NotificationEventFinished,
+			State:         api.NotificationStatePending,
+			Attempts:      2,
+			LastAttemptAt: &metav1.Time{Time: now.Add(-30 * time.Second)},
+		},
+	}
+	examinee, factory := newExaminee(t, now, pipelineRun)
+
+	// EXERCISE
+	requeueAfter, resultErr := examinee.process(context.Background(), "ns1/run1")
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	assert.Equal(t, 90*time.Second, requeueAfter)
+	assert.Equal(t, 0, len(requests))
+	assert.DeepEqual(t, pipelineRun.Status.Notifications, getNotifications(t, factory, pipelineRun))
+}
+
+func Test_Notifier_process_PipelineRunNotFound(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	pipelineRun := fake.PipelineRun("run1", "ns1", api.PipelineSpec{})
+	examinee, _ := newExaminee(t, time.Now(), pipelineRun)
+
+	// EXERCISE
+	requeueAfter, resultErr := examinee.process(context.Background(), "ns1/other")
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	assert.Equal(t, time.Duration(0), requeueAfter)
+}
+
+func Test_Notifier_process_SkipsPipelineRunNotOwned(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	server, requests := newReceiver(t, http.StatusOK)
+	pipelineRun := fake.PipelineRun("run1", "ns1", api.PipelineSpec{
+		Notifications: []api.NotificationTarget{{Name: "target1", URL: server.URL}},
+	})
+	pipelineRun.Status.Notifications = []api.NotificationStatus{
+		{Name: "target1", Event: api.NotificationEventStarted, State: api.NotificationStatePending},
+	}
+	var ownedKeys []string
+	examinee, factory := newExamineeWithOptions(t, now, pipelineRun, Options{
+		AllowedNetworks: []*net.IPNet{mustParseCIDR(t, "127.0.0.0/8")},
+		IsOwned: func(key string) bool {
+			ownedKeys = append(ownedKeys, key)
+			return false
+		},
+	})
+
+	// EXERCISE
+	requeueAfter, resultErr := examinee.process(context.Background(), "ns1/run1")
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	assert.Equal(t, time.Duration(0), requeueAfter)
+	assert.DeepEqual(t, []string{"ns1/run1"}, ownedKeys)
+	assert.Equal(t, 0, len(requests))
+	assert.DeepEqual(t, pipelineRun.Status.Notifications, getNotifications(t, factory, pipelineRun))
+}
+
+func Test_Notifier_process_RejectsTargets(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name             string
+		url              func(server *httptest.Server) string
+		allowedNetworks  []string
+		expectedMessage  string
+		expectedRequests int
+	}{
+		{
+			name:            "http",
+			url:             func(server *httptest.Server) string { return "http://" + server.Listener.Addr().String() },
+			allowedNetworks: []string{"127.0.0.0/8"},
+			expectedMessage: "invalid notification target: URL must use scheme https",
+		},
+		{
+			name:            "loopback",
+			url:             func(server *httptest.Server) string { return server.URL },
+			expectedMessage: "request failed: dial tcp 127.0.0.1:",
+		},
+		{
+			name:            "loopback_other_network_allowed",
+			url:             func(server *httptest.Server) string { return server.URL },
+			allowedNetworks: []string{"10.0.0.0/8"},
+			expectedMessage: "request failed: dial tcp 127.0.0.1:",
+		},
+		{
+			name:             "redirect_to_http",
+			url:              func(server *httptest.Server) string { return server.URL + "/redirect" },
+			allowedNetworks:  []string{"127.0.0.0/8"},
+			expectedMessage:  "request failed: URL must use scheme https",
+			expectedRequests: 1,
+		},
+	} {
+		tc := tc // capture current value before going parallel
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// SETUP
+			now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+			requests := make(chan string, 10)
+			server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests <- r.URL.Path
+				if r.URL.Path == "/redirect" {
+					http.Redirect(w, r, "http://"+r.Host+"/", http.StatusTemporaryRedirect)
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			t.Cleanup(server.Close)
+
+			pipelineRun := fake.PipelineRun("run1", "ns1", api.PipelineSpec{
+				Notifications: []api.NotificationTarget{{Name: "target1", URL: tc.url(server)}},
+			})
+			pipelineRun.Status.Notifications = []api.NotificationStatus{
+				{Name: "target1", Event: api.NotificationEventStarted, State: api.NotificationStatePending},
+			}
+			var allowedNetworks []*net.IPNet
+			for _, cidr := range tc.allowedNetworks {
+				allowedNetworks = append(allowedNetworks, mustParseCIDR(t, cidr))
+			}
+			examinee, factory := newExamineeWithOptions(t, now, pipelineRun, Options{
+				MaxAttempts:     3,
+				AllowedNetworks: allowedNetworks,
+			})
+
+			// EXERCISE
+			_, resultErr := examinee.process(context.Background(), "ns1/run1")
+
+			// VERIFY
+			assert.NilError(t, resultErr)
+			notifications := getNotifications(t, factory, pipelineRun)
+			assert.Equal(t, 1, len(notifications))
+			assert.Equal(t, api.NotificationStatePending, notifications[0].State)
+			assert.Assert(t, strings.HasPrefix(notifications[0].Message, tc.expectedMessage), notifications[0].Message)
+			assert.Equal(t, tc.expectedRequests, len(requests))
+		})
+	}
+}
+
+func Test_Notifier_retryDelay(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	examinee := NewNotifier(logr.Discard(), fake.NewClientFactory(), Options{RetryDelay: time.Minute})
+
+	// EXERCISE and VERIFY
+	assert.Equal(t, time.Minute, examinee.retryDelay(1))
+	assert.Equal(t, 2*time.Minute, examinee.retryDelay(2))
+	assert.Equal(t, 16*time.Minute, examinee.retryDelay(5))
+	assert.Equal(t, maxRetryDelay, examinee.retryDelay(20))
+}
@@ -0,0 +1,72 @@
+package runctl
+
+import (
+	"context"
+	"testing"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	fake "github.com/SAP/stewardci-core/pkg/k8s/fake"
+	runmocks "github.com/SAP/stewardci-core/pkg/runctl/run/mocks"
+	gomock "github.com/golang/mock/gomock"
+	assert "gotest.tools/v3/assert"
+)
+
+func Test__Controller_syncHandler__Notifications_Completion(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	pipelineRun := fake.PipelineRun("foo", "ns1", api.PipelineSpec{
+		Intent: api.IntentAbort,
+		Notifications: []api.NotificationTarget{
+			{Name: "default", URL: "https://default.example.com"},
+			{Name: "started", URL: "https://started.example.com", Events: []api.NotificationEvent{api.NotificationEventStarted}},
+			{Name: "failed", URL: "https://failed.example.com", Events: []api.NotificationEvent{api.NotificationEventFailed}},
+		},
+	})
+	pipelineRun.Status.State = api.StatePreparing
+
+	controller, cf := newController(t, pipelineRun)
+
+	runManager := runmocks.NewMockManager(mockCtrl)
+	runManager.EXPECT().DeleteEnv(gomock.Any(), gomock.Any()).Return(nil)
+
+	controller.testing = &controllerTesting{
+		createRunManagerStub:       newSimpleCreateRunManagerStub(runManager),
+		loadPipelineRunsConfigStub: newEmptyRunsConfig,
+		getMaintenanceStatusStub:   newMaintenanceStatusStub(false, nil),
+	}
+
+	// EXERCISE
+	resultErr := controller.syncHandler(context.Background(), "ns1/foo")
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	result, err := getAPIPipelineRun(cf, "foo", "ns1")
+	assert.NilError(t, err)
+	assert.Equal(t, api.ResultAborted, result.Status.Result)
+	assert.DeepEqual(t, []api.NotificationStatus{
+		{Name: "default", Event: api.NotificationEventFinished, State: api.NotificationStatePending},
+		{Name: "failed", Event: api.NotificationEventFailed, State: api.NotificationStatePending},
+	}, result.Status.Notifications)
+}
+
+func Test__Controller_addToWorkqueue__EnqueuesPendingNotifications(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	pipelineRun := fake.PipelineRun("foo", "ns1", api.PipelineSpec{})
+	pipelineRun.Status.State = api.StateFinished
+	pipelineRun.Status.Notifications = []api.NotificationStatus{
+		{Name: "default", Event: api.NotificationEventFinished, State: api.NotificationStatePending},
+	}
+	controller, _ := newController(t)
+
+	// EXERCISE
+	controller.addToWorkqueue(pipelineRun)
+
+	// VERIFY
+	assert.Equal(t, 1, controller.notifier.Len())
+}
@@ -24,6 +24,17 @@ type Manager interface {
 	// GetRun returns the run or nil if a run has not been created yet.
 	GetRun(ctx context.Context, pipelineRun k8s.PipelineRun) (Run, error)
 
+	// GetPendingReason returns the most significant known reason why
+	// the run has not started yet, as derived from the run pod and the
+	// events in the run namespace. Returns nil if no reason is known.
+	GetPendingReason(ctx context.Context, pipelineRun k8s.PipelineRun) (*PendingReason, error)
+
+	// GetLogTail returns at most the given number of last lines of the
+	// Jenkinsfile Runner log, limited to the given number of bytes, with
+	// the values of all secrets copied into the run namespace redacted.
+	// Returns an empty string if there is no log.
+	GetLogTail(ctx context.Context, pipelineRun k8s.PipelineRun, maxLines, maxBytes int64) (string, error)
+
 	// DeleteRun deletes a task run for a given pipeline run.
 	DeleteRun(ctx context.Context, pipelineRun k8s.PipelineRun) error
 
@@ -60,6 +71,15 @@ type Run interface {
 	// GetMessage returns the status message.
 	GetMessage() string
 
+	// GetExitCode returns the exit code of the Jenkinsfile Runner container
+	// or nil if it has not terminated.
+	GetExitCode() *int32
+
+	// GetFailureReason returns the reason why the run failed or cannot
+	// start, if it is a known resource-related reason like `OOMKilled`.
+	// Returns an empty string otherwise.
+	GetFailureReason() string
+
 	// IsDeleted returns true if the receiver is nil or is marked as deleted.
 	IsDeleted() bool
 }
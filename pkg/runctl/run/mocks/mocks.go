@@ -91,6 +91,34 @@ func (mr *MockRunMockRecorder) GetContainerInfo() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetContainerInfo", reflect.TypeOf((*MockRun)(nil).GetContainerInfo))
 }
 
+// GetExitCode mocks base method.
+func (m *MockRun) GetExitCode() *int32 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExitCode")
+	ret0, _ := ret[0].(*int32)
+	return ret0
+}
+
+// GetExitCode indicates an expected call of GetExitCode.
+func (mr *MockRunMockRecorder) GetExitCode() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExitCode", reflect.TypeOf((*MockRun)(nil).GetExitCode))
+}
+
+// GetFailureReason mocks base method.
+func (m *MockRun) GetFailureReason() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFailureReason")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// GetFailureReason indicates an expected call of GetFailureReason.
+func (mr *MockRunMockRecorder) GetFailureReason() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFailureReason", reflect.TypeOf((*MockRun)(nil).GetFailureReason))
+}
+
 // GetMessage mocks base method.
 func (m *MockRun) GetMessage() string {
 	m.ctrl.T.Helper()
@@ -243,6 +271,36 @@ func (mr *MockManagerMockRecorder) DeleteRun(arg0, arg1 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRun", reflect.TypeOf((*MockManager)(nil).DeleteRun), arg0, arg1)
 }
 
+// GetLogTail mocks base method.
+func (m *MockManager) GetLogTail(arg0 context.Context, arg1 k8s.PipelineRun, arg2, arg3 int64) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLogTail", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLogTail indicates an expected call of GetLogTail.
+func (mr *MockManagerMockRecorder) GetLogTail(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLogTail", reflect.TypeOf((*MockManager)(nil).GetLogTail), arg0, arg1, arg2, arg3)
+}
+
+// GetPendingReason mocks base method.
+func (m *MockManager) GetPendingReason(arg0 context.Context, arg1 k8s.PipelineRun) (*run.PendingReason, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPendingReason", arg0, arg1)
+	ret0, _ := ret[0].(*run.PendingReason)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPendingReason indicates an expected call of GetPendingReason.
+func (mr *MockManagerMockRecorder) GetPendingReason(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingReason", reflect.TypeOf((*MockManager)(nil).GetPendingReason), arg0, arg1)
+}
+
 // GetRun mocks base method.
 func (m *MockManager) GetRun(arg0 context.Context, arg1 k8s.PipelineRun) (run.Run, error) {
 	m.ctrl.T.Helper()
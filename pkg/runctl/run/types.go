@@ -0,0 +1,42 @@
+package run
+
+// Known reasons of runs failing due to missing resources, as returned
+// by Run.GetFailureReason().
+const (
+	// FailureReasonOOMKilled means the Jenkinsfile Runner container has
+	// been terminated because it exceeded its memory limit.
+	FailureReasonOOMKilled = "OOMKilled"
+
+	// FailureReasonEvicted means the run pod has been evicted from its
+	// node, e.g. because the node ran low on resources.
+	FailureReasonEvicted = "Evicted"
+
+	// FailureReasonExceededResourceQuota means the run pod cannot be
+	// created because it would exceed the resource quota of the run
+	// namespace.
+	FailureReasonExceededResourceQuota = "ExceededResourceQuota"
+
+	// FailureReasonExceededNodeResources means the run pod cannot be
+	// scheduled because no node has sufficient resources.
+	FailureReasonExceededNodeResources = "ExceededNodeResources"
+)
+
+// PendingReason describes why a run has not started yet, e.g. because
+// the run pod cannot be scheduled or its container images cannot be
+// pulled.
+type PendingReason struct {
+	// Reason is a short, machine-readable reason in CamelCase, like
+	// `FailedScheduling` or `ErrImagePull`.
+	Reason string
+
+	// Message is a human-readable description.
+	Message string
+}
+
+// String returns the reason and the message in a single line.
+func (r *PendingReason) String() string {
+	if r.Message == "" {
+		return r.Reason
+	}
+	return r.Reason + ": " + r.Message
+}
@@ -200,6 +200,7 @@ func (h *testHelper1) prepareMocksWithSpec(ctrl *gomock.Controller, spec *stewar
 	mockPipelineRun.EXPECT().UpdateAuxNamespace(gomock.Any()).Do(func(arg string) {
 		auxNamespace = arg
 	}).MaxTimes(1)
+	mockPipelineRun.EXPECT().UpdateCopiedSecrets(gomock.Any()).MaxTimes(1)
 	mockPipelineRun.EXPECT().CommitStatus(gomock.Any()).MaxTimes(1)
 
 	mockSecretProvider := secretmocks.NewMockSecretProvider(ctrl)
@@ -0,0 +1,175 @@
+package runmgr
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	stewardv1alpha1 "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/k8s"
+	tektonpipeline "github.com/tektoncd/pipeline/pkg/apis/pipeline"
+	corev1api "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// logTailRedaction replaces the values of pipeline secrets in log tails.
+const logTailRedaction = "****"
+
+// GetLogTail implements runifc.Manager.
+//
+// The values to be redacted are taken from all secrets copied into the run
+// namespace as recorded in the pipeline run status, regardless of whether
+// they are excluded from secret masking in the pipeline log.
+func (c *TektonRunManager) GetLogTail(ctx context.Context, pipelineRun k8s.PipelineRun, maxLines, maxBytes int64) (string, error) {
+	namespace := pipelineRun.GetRunNamespace()
+	if namespace == "" || maxLines <= 0 || maxBytes <= 0 {
+		return "", nil
+	}
+
+	pods, err := c.factory.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: tektonpipeline.TaskRunLabelKey + "=" + JFRTaskRunName,
+	})
+	if err != nil {
+		return "", c.recoverableIfTransient(err)
+	}
+	if len(pods.Items) == 0 {
+		return "", nil
+	}
+
+	log, err := c.factory.CoreV1().Pods(namespace).GetLogs(pods.Items[0].GetName(), &corev1api.PodLogOptions{
+		Container: "step-" + JFRTaskRunStepName,
+		TailLines: &maxLines,
+	}).DoRaw(ctx)
+	if err != nil {
+		return "", c.recoverableIfTransient(err)
+	}
+
+	values, err := c.getRedactionValues(ctx, namespace, pipelineRun.GetStatus().CopiedSecrets)
+	if err != nil {
+		return "", err
+	}
+	return truncateLogTail(redact(string(log), values), maxBytes), nil
+}
+
+// getRedactionValues returns the values of all given secrets copied into
+// the given namespace. Secrets not found are skipped.
+func (c *TektonRunManager) getRedactionValues(ctx context.Context, namespace string, copiedSecrets []stewardv1alpha1.CopiedSecret) ([]string, error) {
+	var values []string
+	for _, copiedSecret := range copiedSecrets {
+		secret, err := c.factory.CoreV1().Secrets(namespace).Get(ctx, copiedSecret.TargetName, metav1.GetOptions{})
+		if err != nil {
+			if k8serrors.IsNotFound(err) {
+				continue
+			}
+			return nil, c.recoverableIfTransient(err)
+		}
+		for _, value := range secret.Data {
+			values = append(values, string(value))
+		}
+		for _, value := range secret.StringData {
+			values = append(values, value)
+		}
+	}
+	return values, nil
+}
+
+// redact replaces all occurrences of the given values in the given log.
+// Besides the values as is, their base64 encoded and JSON escaped forms
+// are redacted, as well as the string values contained in values which
+// are JSON documents (like Docker config JSON).
+// The lines of multi-line values are redacted individually, too, as the
+// tail may start in the middle of such a value.
+// Longer values are replaced first, so that values containing other
+// values are redacted completely.
+func redact(log string, values []string) string {
+	var expanded []string
+	for _, value := range values {
+		expanded = append(expanded, value)
+		expanded = append(expanded, jsonStringValues(value)...)
+	}
+	values = nil
+	for _, value := range expanded {
+		values = append(values, value, base64.StdEncoding.EncodeToString([]byte(value)))
+		if escaped := jsonEscape(value); escaped != value {
+			values = append(values, escaped)
+		}
+	}
+	for _, value := range values {
+		if strings.Contains(value, "\n") {
+			values = append(values, strings.Split(value, "\n")...)
+		}
+	}
+	sort.Slice(values, func(i, j int) bool {
+		return len(values[i]) > len(values[j])
+	})
+	for _, value := range values {
+		if strings.TrimSpace(value) == "" {
+			continue
+		}
+		log = strings.ReplaceAll(log, value, logTailRedaction)
+	}
+	return log
+}
+
+// jsonEscape returns the given value escaped as JSON string without the
+// enclosing quotes.
+func jsonEscape(value string) string {
+	escaped, err := json.Marshal(value)
+	if err != nil {
+		return value
+	}
+	return string(escaped[1 : len(escaped)-1])
+}
+
+// jsonStringValues returns all string values contained in the given value
+// if it is a JSON object or array, and nil otherwise.
+func jsonStringValues(value string) []string {
+	trimmed := strings.TrimSpace(value)
+	if !strings.HasPrefix(trimmed, "{") && !strings.HasPrefix(trimmed, "[") {
+		return nil
+	}
+	var document interface{}
+	if err := json.Unmarshal([]byte(trimmed), &document); err != nil {
+		return nil
+	}
+	var result []string
+	var collect func(node interface{})
+	collect = func(node interface{}) {
+		switch node := node.(type) {
+		case string:
+			result = append(result, node)
+		case []interface{}:
+			for _, item := range node {
+				collect(item)
+			}
+		case map[string]interface{}:
+			for _, item := range node {
+				collect(item)
+			}
+		}
+	}
+	collect(document)
+	return result
+}
+
+// truncateLogTail returns the end of the given log with a size of at most
+// maxBytes bytes. If the log needs to be truncated, it is truncated at the
+// start of a line if possible, and at the start of a UTF-8 character
+// otherwise.
+func truncateLogTail(log string, maxBytes int64) string {
+	if int64(len(log)) <= maxBytes {
+		return log
+	}
+	tail := log[int64(len(log))-maxBytes:]
+	if i := strings.IndexByte(tail, '\n'); i >= 0 && i < len(tail)-1 {
+		return tail[i+1:]
+	}
+	for len(tail) > 0 && !utf8.RuneStart(tail[0]) {
+		tail = tail[1:]
+	}
+	return tail
+}
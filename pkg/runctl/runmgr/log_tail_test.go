@@ -0,0 +1,270 @@
+package runmgr
+
+import (
+	"testing"
+
+	stewardv1alpha1 "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	k8smocks "github.com/SAP/stewardci-core/pkg/k8s/mocks"
+	"github.com/SAP/stewardci-core/pkg/runctl/secretmgr"
+	gomock "github.com/golang/mock/gomock"
+	"gotest.tools/v3/assert"
+	corev1api "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test__TektonRunManager_GetLogTail(t *testing.T) {
+	t.Parallel()
+
+	runPod := &corev1api.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "pod1",
+			Labels: map[string]string{"tekton.dev/taskRun": JFRTaskRunName},
+		},
+	}
+	// the masking secret never lists values excluded from masking
+	maskingSecret := &corev1api.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: secretmgr.MaskingSecretName},
+		Data: map[string][]byte{
+			secretmgr.MaskingSecretKeyValues: []byte(`[]`),
+		},
+	}
+
+	for _, tc := range []struct {
+		name     string
+		pod      *corev1api.Pod
+		secrets  []*corev1api.Secret
+		copied   []string
+		maxLines int64
+		expected string
+	}{
+		{
+			name:     "no pod",
+			maxLines: 10,
+			expected: "",
+		},
+		{
+			name:     "disabled",
+			pod:      runPod,
+			maxLines: 0,
+			expected: "",
+		},
+		{
+			name:     "no copied secrets",
+			pod:      runPod,
+			secrets:  []*corev1api.Secret{maskingSecret},
+			maxLines: 10,
+			// the fake clientset always returns this log
+			expected: "fake logs",
+		},
+		{
+			name:     "copied secret not found",
+			pod:      runPod,
+			copied:   []string{"secret1"},
+			maxLines: 10,
+			expected: "fake logs",
+		},
+		{
+			name: "pipeline secret",
+			pod:  runPod,
+			secrets: []*corev1api.Secret{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "secret1"},
+					Type:       corev1api.SecretTypeOpaque,
+					Data:       map[string][]byte{"key1": []byte("fake")},
+				},
+			},
+			copied:   []string{"secret1"},
+			maxLines: 10,
+			expected: "**** logs",
+		},
+		{
+			name: "secret type excluded from masking",
+			pod:  runPod,
+			secrets: []*corev1api.Secret{
+				maskingSecret,
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "secret1"},
+					Type:       corev1api.SecretTypeBasicAuth,
+					Data:       map[string][]byte{"password": []byte("fake")},
+				},
+			},
+			copied:   []string{"secret1"},
+			maxLines: 10,
+			expected: "**** logs",
+		},
+		{
+			name: "key excluded from masking",
+			pod:  runPod,
+			secrets: []*corev1api.Secret{
+				maskingSecret,
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "secret1",
+						Annotations: map[string]string{
+							stewardv1alpha1.AnnotationSecretMaskingExcludeKeys: "key1",
+						},
+					},
+					Type: corev1api.SecretTypeOpaque,
+					Data: map[string][]byte{"key1": []byte("fake")},
+				},
+			},
+			copied:   []string{"secret1"},
+			maxLines: 10,
+			expected: "**** logs",
+		},
+		{
+			name: "docker config json",
+			pod:  runPod,
+			secrets: []*corev1api.Secret{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "secret1"},
+					Type:       corev1api.SecretTypeDockerConfigJson,
+					Data: map[string][]byte{
+						corev1api.DockerConfigJsonKey: []byte(`{"auths":{"registry1":{"password":"fake"}}}`),
+					},
+				},
+			},
+			copied:   []string{"secret1"},
+			maxLines: 10,
+			expected: "**** logs",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			h := newTestHelper1(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			mockFactory, _, mockSecretProvider := h.prepareMocks(mockCtrl)
+			if tc.pod != nil {
+				_, err := mockFactory.CoreV1().Pods(h.runNamespace1).Create(h.ctx, tc.pod, metav1.CreateOptions{})
+				assert.NilError(t, err)
+			}
+			for _, secret := range tc.secrets {
+				_, err := mockFactory.CoreV1().Secrets(h.runNamespace1).Create(h.ctx, secret, metav1.CreateOptions{})
+				assert.NilError(t, err)
+			}
+			status := &stewardv1alpha1.PipelineStatus{}
+			for _, name := range tc.copied {
+				status.CopiedSecrets = append(status.CopiedSecrets, stewardv1alpha1.CopiedSecret{
+					Purpose:    stewardv1alpha1.SecretPurposePipeline,
+					SourceName: name,
+					TargetName: name,
+				})
+			}
+			mockPipelineRun := k8smocks.NewMockPipelineRun(mockCtrl)
+			mockPipelineRun.EXPECT().GetRunNamespace().Return(h.runNamespace1).AnyTimes()
+			mockPipelineRun.EXPECT().GetStatus().Return(status).AnyTimes()
+
+			examinee := NewTektonRunManager(mockFactory, mockSecretProvider, nil)
+
+			// EXERCISE
+			result, resultErr := examinee.GetLogTail(h.ctx, mockPipelineRun, tc.maxLines, 1000)
+
+			// VERIFY
+			assert.NilError(t, resultErr)
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func Test__redact(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name     string
+		log      string
+		values   []string
+		expected string
+	}{
+		{
+			name:     "no values",
+			log:      "line1 secret1\n",
+			values:   nil,
+			expected: "line1 secret1\n",
+		},
+		{
+			name:     "multiple occurrences",
+			log:      "secret1 line1 secret1\nsecret2\n",
+			values:   []string{"secret1", "secret2"},
+			expected: "**** line1 ****\n****\n",
+		},
+		{
+			name:     "longer values first",
+			log:      "secret1suffix secret1\n",
+			values:   []string{"secret1", "secret1suffix"},
+			expected: "**** ****\n",
+		},
+		{
+			name:     "lines of multi-line values",
+			log:      "keyline2\nkeyline3\nline1\n",
+			values:   []string{"keyline1\nkeyline2\nkeyline3"},
+			expected: "****\n****\nline1\n",
+		},
+		{
+			name:     "base64 encoded values",
+			log:      "line1 c2VjcmV0MQ==\n",
+			values:   []string{"secret1"},
+			expected: "line1 ****\n",
+		},
+		{
+			name:     "JSON escaped values",
+			log:      `{"value":"sec\"ret\\1"}` + "\n",
+			values:   []string{`sec"ret\1`},
+			expected: `{"value":"****"}` + "\n",
+		},
+		{
+			name:     "string values of JSON documents",
+			log:      "user1 secret1 c2VjcmV0MQ==\n",
+			values:   []string{`{"auths":{"registry1":{"username":"user1","password":"secret1"}}}`},
+			expected: "**** **** ****\n",
+		},
+		{
+			name:     "blank values ignored",
+			log:      "line1 line2\n",
+			values:   []string{"", " ", "a\n\nb"},
+			expected: "line1 line2\n",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// EXERCISE
+			result := redact(tc.log, tc.values)
+
+			// VERIFY
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func Test__truncateLogTail(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name     string
+		log      string
+		maxBytes int64
+		expected string
+	}{
+		{"short", "line1\nline2\n", 100, "line1\nline2\n"},
+		{"exact", "line1\nline2\n", 12, "line1\nline2\n"},
+		{"at line start", "line1\nline2\n", 10, "line2\n"},
+		{"within single line", "line1\nline2", 3, "ne2"},
+		{"at rune start", "äöü", 3, "ü"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// EXERCISE
+			result := truncateLogTail(tc.log, tc.maxBytes)
+
+			// VERIFY
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package runmgr
+
+import (
+	"context"
+	"time"
+
+	"github.com/SAP/stewardci-core/pkg/k8s"
+	runifc "github.com/SAP/stewardci-core/pkg/runctl/run"
+	tektonpipeline "github.com/tektoncd/pipeline/pkg/apis/pipeline"
+	corev1api "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// pendingContainerReasons are the reasons of waiting containers which
+// prevent the run pod from starting.
+var pendingContainerReasons = map[string]bool{
+	"ErrImagePull":               true,
+	"ImagePullBackOff":           true,
+	"InvalidImageName":           true,
+	"CreateContainerConfigError": true,
+	"CreateContainerError":       true,
+}
+
+// GetPendingReason implements runifc.Manager.
+//
+// Problems of the run pod itself are considered most significant, with
+// waiting containers (e.g. failing image pulls) taking precedence over
+// scheduling problems. Otherwise the latest warning event in the run
+// namespace is used, which also covers problems of other objects the run
+// pod depends on, like pending persistent volume claims or the
+// exceedance of resource quotas preventing the creation of the pod.
+func (c *TektonRunManager) GetPendingReason(ctx context.Context, pipelineRun k8s.PipelineRun) (*runifc.PendingReason, error) {
+	namespace := pipelineRun.GetRunNamespace()
+	if namespace == "" {
+		return nil, nil
+	}
+
+	pods, err := c.factory.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: tektonpipeline.TaskRunLabelKey + "=" + JFRTaskRunName,
+	})
+	if err != nil {
+		return nil, c.recoverableIfTransient(err)
+	}
+	for i := range pods.Items {
+		if reason := getPodPendingReason(&pods.Items[i]); reason != nil {
+			return reason, nil
+		}
+	}
+
+	events, err := c.factory.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, c.recoverableIfTransient(err)
+	}
+	return getLatestWarningEventReason(events.Items), nil
+}
+
+func getPodPendingReason(pod *corev1api.Pod) *runifc.PendingReason {
+	var containerStatuses []corev1api.ContainerStatus
+	containerStatuses = append(containerStatuses, pod.Status.InitContainerStatuses...)
+	containerStatuses = append(containerStatuses, pod.Status.ContainerStatuses...)
+	for _, containerStatus := range containerStatuses {
+		waiting := containerStatus.State.Waiting
+		if waiting != nil && pendingContainerReasons[waiting.Reason] {
+			return &runifc.PendingReason{Reason: waiting.Reason, Message: waiting.Message}
+		}
+	}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1api.PodScheduled && condition.Status == corev1api.ConditionFalse {
+			return &runifc.PendingReason{Reason: condition.Reason, Message: condition.Message}
+		}
+	}
+	return nil
+}
+
+func getLatestWarningEventReason(events []corev1api.Event) *runifc.PendingReason {
+	var latest *corev1api.Event
+	for i := range events {
+		event := &events[i]
+		if event.Type != corev1api.EventTypeWarning {
+			continue
+		}
+		if latest == nil || getEventTime(event).After(getEventTime(latest)) {
+			latest = event
+		}
+	}
+	if latest == nil {
+		return nil
+	}
+	return &runifc.PendingReason{Reason: latest.Reason, Message: latest.Message}
+}
+
+// getEventTime returns the time the given event occurred last.
+func getEventTime(event *corev1api.Event) time.Time {
+	if !event.LastTimestamp.IsZero() {
+		return event.LastTimestamp.Time
+	}
+	if !event.EventTime.IsZero() {
+		return event.EventTime.Time
+	}
+	return event.CreationTimestamp.Time
+}
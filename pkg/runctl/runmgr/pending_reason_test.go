@@ -0,0 +1,138 @@
+package runmgr
+
+import (
+	"testing"
+	"time"
+
+	runifc "github.com/SAP/stewardci-core/pkg/runctl/run"
+	gomock "github.com/golang/mock/gomock"
+	"gotest.tools/v3/assert"
+	corev1api "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test__TektonRunManager_GetPendingReason(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	runPod := func(status corev1api.PodStatus) *corev1api.Pod {
+		return &corev1api.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "pod1",
+				Labels: map[string]string{"tekton.dev/taskRun": JFRTaskRunName},
+			},
+			Status: status,
+		}
+	}
+	warningEvent := func(name, reason, message string, lastTimestamp time.Time) *corev1api.Event {
+		return &corev1api.Event{
+			ObjectMeta:    metav1.ObjectMeta{Name: name},
+			Type:          corev1api.EventTypeWarning,
+			Reason:        reason,
+			Message:       message,
+			LastTimestamp: metav1.NewTime(lastTimestamp),
+		}
+	}
+
+	for _, tc := range []struct {
+		name     string
+		pods     []*corev1api.Pod
+		events   []*corev1api.Event
+		expected *runifc.PendingReason
+	}{
+		{
+			name:     "nothing",
+			expected: nil,
+		},
+		{
+			name: "image pull of init container fails",
+			pods: []*corev1api.Pod{runPod(corev1api.PodStatus{
+				InitContainerStatuses: []corev1api.ContainerStatus{{
+					State: corev1api.ContainerState{Waiting: &corev1api.ContainerStateWaiting{
+						Reason:  "ImagePullBackOff",
+						Message: `Back-off pulling image "image1"`,
+					}},
+				}},
+				Conditions: []corev1api.PodCondition{{Type: corev1api.PodScheduled, Status: corev1api.ConditionTrue}},
+			})},
+			events: []*corev1api.Event{warningEvent("event1", "reason1", "message1", now)},
+			expected: &runifc.PendingReason{
+				Reason:  "ImagePullBackOff",
+				Message: `Back-off pulling image "image1"`,
+			},
+		},
+		{
+			name: "container is waiting for other reason",
+			pods: []*corev1api.Pod{runPod(corev1api.PodStatus{
+				ContainerStatuses: []corev1api.ContainerStatus{{
+					State: corev1api.ContainerState{Waiting: &corev1api.ContainerStateWaiting{
+						Reason: "ContainerCreating",
+					}},
+				}},
+			})},
+			expected: nil,
+		},
+		{
+			name: "pod unschedulable",
+			pods: []*corev1api.Pod{runPod(corev1api.PodStatus{
+				Conditions: []corev1api.PodCondition{{
+					Type:    corev1api.PodScheduled,
+					Status:  corev1api.ConditionFalse,
+					Reason:  "Unschedulable",
+					Message: "0/3 nodes are available",
+				}},
+			})},
+			events: []*corev1api.Event{warningEvent("event1", "reason1", "message1", now)},
+			expected: &runifc.PendingReason{
+				Reason:  "Unschedulable",
+				Message: "0/3 nodes are available",
+			},
+		},
+		{
+			name: "latest warning event",
+			events: []*corev1api.Event{
+				warningEvent("event1", "FailedCreate", "exceeded quota", now.Add(-time.Minute)),
+				warningEvent("event2", "ProvisioningFailed", "no storage class", now),
+				warningEvent("event3", "FailedCreate", "exceeded quota", now.Add(-2*time.Minute)),
+				{
+					ObjectMeta:    metav1.ObjectMeta{Name: "event4"},
+					Type:          corev1api.EventTypeNormal,
+					Reason:        "Scheduled",
+					LastTimestamp: metav1.NewTime(now.Add(time.Minute)),
+				},
+			},
+			expected: &runifc.PendingReason{
+				Reason:  "ProvisioningFailed",
+				Message: "no storage class",
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			h := newTestHelper1(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			mockFactory, mockPipelineRun, mockSecretProvider := h.prepareMocks(mockCtrl)
+			for _, pod := range tc.pods {
+				_, err := mockFactory.CoreV1().Pods(h.runNamespace1).Create(h.ctx, pod, metav1.CreateOptions{})
+				assert.NilError(t, err)
+			}
+			for _, event := range tc.events {
+				_, err := mockFactory.CoreV1().Events(h.runNamespace1).Create(h.ctx, event, metav1.CreateOptions{})
+				assert.NilError(t, err)
+			}
+
+			examinee := NewTektonRunManager(mockFactory, mockSecretProvider, nil)
+
+			// EXERCISE
+			result, resultErr := examinee.GetPendingReason(h.ctx, mockPipelineRun)
+
+			// VERIFY
+			assert.NilError(t, resultErr)
+			assert.DeepEqual(t, tc.expected, result)
+		})
+	}
+}
@@ -16,9 +16,17 @@ const (
 	jfrExitCodeErrorConfig  = 3
 )
 
+// podReasonEvicted is the reason set by the kubelet in the status of pods
+// it evicts.
+const podReasonEvicted = "Evicted"
+
 // tektonRun is a runifc.Run based on Tekton.
 type tektonRun struct {
 	tektonTaskRun *tekton.TaskRun
+
+	// pod is the pod of the TaskRun. It is only set if the TaskRun has
+	// failed and the pod still exists.
+	pod *corev1.Pod
 }
 
 // Compiler check for interface compliance
@@ -105,10 +113,15 @@ func (r *tektonRun) IsFinished() (bool, steward.Result) {
 		return true, steward.ResultSuccess
 	}
 	// TaskRun finished unsuccessfully, check reason...
-	switch condition.Reason {
-	case string(tekton.TaskRunReasonTimedOut):
+	if condition.Reason == string(tekton.TaskRunReasonTimedOut) {
 		return true, steward.ResultTimeout
-	case string(tekton.TaskRunReasonFailed):
+	}
+	// the failure reason is only set if the Jenkinsfile Runner has not
+	// terminated with an exit code determining the result
+	if r.GetFailureReason() != "" {
+		return true, steward.ResultErrorResources
+	}
+	if condition.Reason == string(tekton.TaskRunReasonFailed) {
 		jfrStepState := r.getJFRStepState()
 		if jfrStepState != nil && jfrStepState.Terminated != nil {
 			switch jfrStepState.Terminated.ExitCode {
@@ -118,16 +131,68 @@ func (r *tektonRun) IsFinished() (bool, steward.Result) {
 				return true, steward.ResultErrorConfig
 			}
 		}
-	default:
-		// TODO handle other failure reasons like quota exceedance
 	}
 	return true, steward.ResultErrorInfra
 }
 
+// GetExitCode implements runifc.Run.
+func (r *tektonRun) GetExitCode() *int32 {
+	jfrStepState := r.getJFRStepState()
+	if jfrStepState == nil || jfrStepState.Terminated == nil {
+		return nil
+	}
+	exitCode := jfrStepState.Terminated.ExitCode
+	return &exitCode
+}
+
+// GetFailureReason implements runifc.Run.
+//
+// If the Jenkinsfile Runner has terminated, its exit code determines the
+// result, unless it has been OOMKilled. Other reasons are not returned
+// then, e.g. if the pod has been evicted after the Jenkinsfile Runner
+// terminated.
+func (r *tektonRun) GetFailureReason() string {
+	jfrStepState := r.getJFRStepState()
+	if jfrStepState != nil && jfrStepState.Terminated != nil {
+		if jfrStepState.Terminated.Reason == runifc.FailureReasonOOMKilled {
+			return runifc.FailureReasonOOMKilled
+		}
+		return ""
+	}
+	condition := r.getSucceededCondition()
+	if condition == nil || condition.IsTrue() {
+		return ""
+	}
+	switch condition.Reason {
+	case
+		runifc.FailureReasonExceededResourceQuota,
+		runifc.FailureReasonExceededNodeResources:
+		return condition.Reason
+	}
+	if condition.IsFalse() {
+		if condition.Reason == podReasonEvicted ||
+			(r.pod != nil && r.pod.Status.Reason == podReasonEvicted) {
+			return runifc.FailureReasonEvicted
+		}
+	}
+	return ""
+}
+
 // GetMessage implements runifc.Run.
 func (r *tektonRun) GetMessage() string {
 	var msg string
 
+	switch r.GetFailureReason() {
+	case runifc.FailureReasonOOMKilled:
+		return "the pipeline run exceeded its memory limit and has been terminated (OOMKilled)"
+	case runifc.FailureReasonEvicted:
+		return "the pipeline run pod has been evicted: " + r.getSucceededCondition().Message
+	case
+		runifc.FailureReasonExceededResourceQuota,
+		runifc.FailureReasonExceededNodeResources:
+		return "the pipeline run pod cannot be started due to missing resources: " + r.getSucceededCondition().Message
+	}
+
 	containerInfo := r.GetContainerInfo()
 	if containerInfo != nil && containerInfo.Terminated != nil {
 		msg = containerInfo.Terminated.Message
@@ -10,13 +10,16 @@ import (
 	stewardv1alpha1 "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
 	serrors "github.com/SAP/stewardci-core/pkg/errors"
 	"github.com/SAP/stewardci-core/pkg/featureflag"
+	"github.com/SAP/stewardci-core/pkg/githubapp"
 	"github.com/SAP/stewardci-core/pkg/k8s"
 	secrets "github.com/SAP/stewardci-core/pkg/k8s/secrets"
+	k8ssecretprovider "github.com/SAP/stewardci-core/pkg/k8s/secrets/providers/k8s"
 	"github.com/SAP/stewardci-core/pkg/runctl/cfg"
 	"github.com/SAP/stewardci-core/pkg/runctl/constants"
 	runifc "github.com/SAP/stewardci-core/pkg/runctl/run"
 	"github.com/SAP/stewardci-core/pkg/runctl/secretmgr"
 	slabels "github.com/SAP/stewardci-core/pkg/stewardlabels"
+	"github.com/SAP/stewardci-core/pkg/tracing"
 	"github.com/SAP/stewardci-core/pkg/utils"
 	"github.com/pkg/errors"
 	tektonPod "github.com/tektoncd/pipeline/pkg/apis/pipeline/pod"
@@ -25,18 +28,17 @@ import (
 	networkingv1api "k8s.io/api/networking/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
-	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
-	yamlserial "k8s.io/apimachinery/pkg/runtime/serializer/yaml"
 	"k8s.io/client-go/util/retry"
 	klog "k8s.io/klog/v2"
+	"knative.dev/pkg/system"
 )
 
 // TektonRunManager is an implementation of runifc.Manager based on Tekton.
 type TektonRunManager struct {
 	factory        k8s.ClientFactory
 	secretProvider secrets.SecretProvider
+	gitHubApp      *githubapp.Client
 
 	testing *tektonRunManagerTesting
 }
@@ -69,10 +71,13 @@ type runContext struct {
 }
 
 // NewTektonRunManager creates a new TektonRunManager.
-func NewTektonRunManager(factory k8s.ClientFactory, secretProvider secrets.SecretProvider) *TektonRunManager {
+// gitHubApp is used to exchange GitHub App secrets for installation tokens.
+// If nil, a client with default settings is used.
+func NewTektonRunManager(factory k8s.ClientFactory, secretProvider secrets.SecretProvider, gitHubApp *githubapp.Client) *TektonRunManager {
 	return &TektonRunManager{
 		factory:        factory,
 		secretProvider: secretProvider,
+		gitHubApp:      gitHubApp,
 	}
 }
 
@@ -133,37 +138,56 @@ func (c *TektonRunManager) prepareRunNamespace(ctx context.Context, runCtx *runC
 		return err
 	}
 
-	runCtx.runNamespace, err = c.createNamespace(ctx, runCtx, "main", randName)
-	if err != nil {
-		return err
-	}
-
-	if featureflag.CreateAuxNamespaceIfUnused.Enabled() {
-		runCtx.auxNamespace, err = c.createNamespace(ctx, runCtx, "aux", randName)
+	err = tracing.Trace(ctx, "createNamespaces", func(ctx context.Context) (err error) {
+		runCtx.runNamespace, err = c.createNamespace(ctx, runCtx, "main", randName)
 		if err != nil {
 			return err
 		}
+
+		if featureflag.CreateAuxNamespaceIfUnused.Enabled() {
+			runCtx.auxNamespace, err = c.createNamespace(ctx, runCtx, "aux", randName)
+		}
+		return err
+	})
+	if err != nil {
+		return err
 	}
 
-	pipelineCloneSecretName, imagePullSecretNames, err := c.copySecretsToRunNamespace(ctx, runCtx)
+	var pipelineCloneSecretName string
+	var imagePullSecretNames []string
+	err = tracing.Trace(ctx, "copySecretsToRunNamespace", func(ctx context.Context) (err error) {
+		pipelineCloneSecretName, imagePullSecretNames, err = c.copySecretsToRunNamespace(ctx, runCtx)
+		return err
+	})
 	if err != nil {
 		return err
 	}
 
-	err = c.setupServiceAccount(ctx, runCtx, pipelineCloneSecretName, imagePullSecretNames)
+	err = tracing.Trace(ctx, "setupServiceAccount", func(ctx context.Context) error {
+		return c.setupServiceAccount(ctx, runCtx, pipelineCloneSecretName, imagePullSecretNames)
+	})
 	if err != nil {
 		return err
 	}
 
-	if err = c.setupStaticNetworkPolicies(ctx, runCtx); err != nil {
+	err = tracing.Trace(ctx, "setupStaticNetworkPolicies", func(ctx context.Context) error {
+		return c.setupStaticNetworkPolicies(ctx, runCtx)
+	})
+	if err != nil {
 		return err
 	}
 
-	if err = c.setupStaticLimitRange(ctx, runCtx); err != nil {
+	err = tracing.Trace(ctx, "setupStaticLimitRange", func(ctx context.Context) error {
+		return c.setupStaticLimitRange(ctx, runCtx)
+	})
+	if err != nil {
 		return err
 	}
 
-	if err = c.setupStaticResourceQuota(ctx, runCtx); err != nil {
+	err = tracing.Trace(ctx, "setupStaticResourceQuota", func(ctx context.Context) error {
+		return c.setupStaticResourceQuota(ctx, runCtx)
+	})
+	if err != nil {
 		return err
 	}
 
@@ -248,7 +272,13 @@ func (c *TektonRunManager) getSecretManager(runCtx *runContext) runifc.SecretMan
 	}
 	targetClient := c.factory.CoreV1().Secrets(runCtx.runNamespace)
 	secretHelper := secrets.NewSecretHelper(c.secretProvider, runCtx.runNamespace, targetClient)
-	return secretmgr.NewSecretManager(secretHelper)
+	var systemSecretHelper secrets.SecretHelper
+	if len(runCtx.pipelineRunsConfig.DefaultImagePullSecrets) > 0 {
+		systemNamespace := system.Namespace()
+		systemSecretProvider := k8ssecretprovider.NewProvider(c.factory.CoreV1().Secrets(systemNamespace), systemNamespace)
+		systemSecretHelper = secrets.NewSecretHelper(systemSecretProvider, runCtx.runNamespace, targetClient)
+	}
+	return secretmgr.NewSecretManager(secretHelper, systemSecretHelper, runCtx.pipelineRunsConfig, c.gitHubApp)
 }
 
 func (c *TektonRunManager) setupStaticNetworkPolicies(ctx context.Context, runCtx *runContext) error {
@@ -396,25 +426,9 @@ func (c *TektonRunManager) setupResourceQuotaFromConfig(ctx context.Context, run
 }
 
 func (c *TektonRunManager) createResource(ctx context.Context, configStr string, resource string, resourceDisplayName string, expectedGroupKind schema.GroupKind, runCtx *runContext) error {
-	var obj *unstructured.Unstructured
-
-	// decode
-	{
-		// We don't assume a specific resource version so that users can configure
-		// whatever the K8s apiserver understands.
-		yamlSerializer := yamlserial.NewDecodingSerializer(unstructured.UnstructuredJSONScheme)
-		o, err := runtime.Decode(yamlSerializer, []byte(configStr))
-		if err != nil {
-			return errors.Wrapf(err, "failed to decode configured %s", resourceDisplayName)
-		}
-		gvk := o.GetObjectKind().GroupVersionKind()
-		if gvk.GroupKind() != expectedGroupKind {
-			return errors.Errorf(
-				"configured %s does not denote a %q but a %q",
-				resourceDisplayName, expectedGroupKind.String(), gvk.GroupKind().String(),
-			)
-		}
-		obj = o.(*unstructured.Unstructured)
+	obj, err := cfg.DecodeManifest(configStr, expectedGroupKind, resourceDisplayName)
+	if err != nil {
+		return err
 	}
 
 	// set metadata
@@ -516,6 +530,7 @@ func (c *TektonRunManager) createTektonTaskRunObject(ctx context.Context, runCtx
 	}
 
 	c.addTektonTaskRunParamsForRunDetails(runCtx, &tektonTaskRun)
+	c.addTektonTaskRunParamsForSecretMasking(runCtx, &tektonTaskRun)
 
 	return &tektonTaskRun, nil
 }
@@ -590,6 +605,18 @@ func (c *TektonRunManager) addTektonTaskRunParamsForRunDetails(
 	}
 }
 
+func (c *TektonRunManager) addTektonTaskRunParamsForSecretMasking(
+	runCtx *runContext,
+	tektonTaskRun *tekton.TaskRun,
+) {
+	// the masking secret is created only if there are pipeline secrets
+	if len(runCtx.pipelineRun.GetSpec().Secrets) > 0 {
+		tektonTaskRun.Spec.Params = append(tektonTaskRun.Spec.Params,
+			tektonStringParam("PIPELINE_SECRET_MASKING_SECRET", secretmgr.MaskingSecretName),
+		)
+	}
+}
+
 func (c *TektonRunManager) addTektonTaskRunParamsForPipeline(
 	runCtx *runContext,
 	tektonTaskRun *tekton.TaskRun,
@@ -679,7 +706,18 @@ func (c *TektonRunManager) GetRun(ctx context.Context, pipelineRun k8s.PipelineR
 	if err != nil {
 		return nil, c.recoverableIfTransient(err)
 	}
-	return newRun(run), nil
+	result := newRun(run)
+	if condition := result.getSucceededCondition(); condition.IsFalse() && run.Status.PodName != "" {
+		// the pod status tells whether the pod has been evicted
+		pod, err := c.factory.CoreV1().Pods(namespace).Get(ctx, run.Status.PodName, metav1.GetOptions{})
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return nil, c.recoverableIfTransient(err)
+		}
+		if err == nil {
+			result.pod = pod
+		}
+	}
+	return result, nil
 }
 
 // DeleteRun creates a new TektonRunManager.
@@ -18,6 +18,7 @@ import (
 	cfg "github.com/SAP/stewardci-core/pkg/runctl/cfg"
 	runifc "github.com/SAP/stewardci-core/pkg/runctl/run"
 	runmocks "github.com/SAP/stewardci-core/pkg/runctl/run/mocks"
+	"github.com/SAP/stewardci-core/pkg/runctl/secretmgr"
 	runctltesting "github.com/SAP/stewardci-core/pkg/runctl/testing"
 	"github.com/SAP/stewardci-core/pkg/utils"
 	spew "github.com/davecgh/go-spew/spew"
@@ -34,6 +35,7 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	schema "k8s.io/apimachinery/pkg/runtime/schema"
 	dynamicfake "k8s.io/client-go/dynamic/fake"
+	knativeapis "knative.dev/pkg/apis"
 )
 
 func newTektonRunManagerTestingWithAllNoopStubs() *tektonRunManagerTesting {
@@ -82,7 +84,7 @@ func Test__TektonRunManager_prepareRunNamespace__CreatesNamespaces(t *testing.T)
 			config := &cfg.PipelineRunsConfigStruct{}
 			secretProvider := secretproviderfakes.NewProvider(h.namespace1)
 
-			examinee := NewTektonRunManager(cf, secretProvider)
+			examinee := NewTektonRunManager(cf, secretProvider, nil)
 			examinee.testing = newTektonRunManagerTestingWithAllNoopStubs()
 
 			pipelineRunHelper, err := k8s.NewPipelineRun(h.ctx, h.getPipelineRunFromStorage(cf, h.namespace1, h.pipelineRun1), cf)
@@ -135,7 +137,7 @@ func Test__TektonRunManager_prepareRunNamespace__Calls__copySecretsToRunNamespac
 	pipelineRunHelper, err := k8s.NewPipelineRun(h.ctx, h.getPipelineRunFromStorage(cf, h.namespace1, h.pipelineRun1), cf)
 	assert.NilError(t, err)
 
-	examinee := NewTektonRunManager(cf, secretProvider)
+	examinee := NewTektonRunManager(cf, secretProvider, nil)
 	examinee.testing = newTektonRunManagerTestingWithAllNoopStubs()
 
 	expectedError := errors.New("some error")
@@ -176,7 +178,7 @@ func Test__TektonRunManager_prepareRunNamespace__Calls_setupServiceAccount_AndPr
 	pipelineRunHelper, err := k8s.NewPipelineRun(h.ctx, h.getPipelineRunFromStorage(cf, h.namespace1, h.pipelineRun1), cf)
 	assert.NilError(t, err)
 
-	examinee := NewTektonRunManager(cf, secretProvider)
+	examinee := NewTektonRunManager(cf, secretProvider, nil)
 	examinee.testing = newTektonRunManagerTestingWithAllNoopStubs()
 
 	expectedPipelineCloneSecretName := "pipelineCloneSecret1"
@@ -223,7 +225,7 @@ func Test__TektonRunManager_prepareRunNamespace__Calls_setupStaticNetworkPolicie
 	pipelineRunHelper, err := k8s.NewPipelineRun(h.ctx, h.getPipelineRunFromStorage(cf, h.namespace1, h.pipelineRun1), cf)
 	assert.NilError(t, err)
 
-	examinee := NewTektonRunManager(cf, secretProvider)
+	examinee := NewTektonRunManager(cf, secretProvider, nil)
 	examinee.testing = newTektonRunManagerTestingWithAllNoopStubs()
 
 	expectedError := errors.New("some error")
@@ -1328,7 +1330,7 @@ func Test__TektonRunManager_addTektonTaskRunParamsForLoggingElasticsearch(t *tes
 			defer mockCtrl.Finish()
 			mockFactory, mockPipelineRun, mockSecretProvider := h.prepareMocksWithSpec(mockCtrl, test.spec)
 
-			examinee := NewTektonRunManager(mockFactory, mockSecretProvider)
+			examinee := NewTektonRunManager(mockFactory, mockSecretProvider, nil)
 			examinee.testing = newTektonRunManagerTestingWithRequiredStubs()
 
 			runCtx := &runContext{
@@ -1362,7 +1364,7 @@ func Test__TektonRunManager_CreateRun__CreatesTektonTaskRun(t *testing.T) {
 	h.preparePredefinedClusterRole(mockFactory)
 	config := &cfg.PipelineRunsConfigStruct{}
 
-	examinee := NewTektonRunManager(mockFactory, mockSecretProvider)
+	examinee := NewTektonRunManager(mockFactory, mockSecretProvider, nil)
 	examinee.testing = newTektonRunManagerTestingWithRequiredStubs()
 
 	// EXERCISE
@@ -1425,7 +1427,7 @@ func Test__TektonRunManager_CreateEnv__CleanupOnError(t *testing.T) {
 			mockFactory, mockPipelineRun, mockSecretProvider := h.prepareMocks(mockCtrl)
 			config := &cfg.PipelineRunsConfigStruct{}
 
-			examinee := NewTektonRunManager(mockFactory, mockSecretProvider)
+			examinee := NewTektonRunManager(mockFactory, mockSecretProvider, nil)
 			examinee.testing = newTektonRunManagerTestingWithRequiredStubs()
 
 			var cleanupCalled int
@@ -1556,6 +1558,60 @@ func Test__TektonRunManager_addTektonTaskRunParamsForJenkinsfileRunnerImage(t *t
 	}
 }
 
+func Test__TektonRunManager_addTektonTaskRunParamsForSecretMasking(t *testing.T) {
+	t.Parallel()
+
+	examinee := TektonRunManager{}
+	for _, tc := range []struct {
+		name                string
+		spec                *stewardv1alpha1.PipelineSpec
+		expectedAddedParams tektonv1beta1.Params
+	}{
+		{
+			name:                "no_pipeline_secrets",
+			spec:                &stewardv1alpha1.PipelineSpec{},
+			expectedAddedParams: nil,
+		},
+		{
+			name: "pipeline_secrets",
+			spec: &stewardv1alpha1.PipelineSpec{
+				Secrets: []string{"secret1"},
+			},
+			expectedAddedParams: tektonv1beta1.Params{
+				tektonStringParam("PIPELINE_SECRET_MASKING_SECRET", secretmgr.MaskingSecretName),
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc
+			t.Parallel()
+
+			// SETUP
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			mockPipelineRun := k8smocks.NewMockPipelineRun(mockCtrl)
+			mockPipelineRun.EXPECT().GetSpec().Return(tc.spec).AnyTimes()
+			existingParam := tektonStringParam("AlreadyExistingParam1", "foo")
+			tektonTaskRun := tektonv1beta1.TaskRun{
+				Spec: tektonv1beta1.TaskRunSpec{
+					Params: tektonv1beta1.Params{*existingParam.DeepCopy()},
+				},
+			}
+			runCtx := &runContext{
+				pipelineRun: mockPipelineRun,
+			}
+
+			// EXERCISE
+			examinee.addTektonTaskRunParamsForSecretMasking(runCtx, &tektonTaskRun)
+
+			// VERIFY
+			expectedParams := tektonv1beta1.Params{existingParam}
+			expectedParams = append(expectedParams, tc.expectedAddedParams...)
+			assert.DeepEqual(t, expectedParams, tektonTaskRun.Spec.Params)
+		})
+	}
+}
+
 func Test__TektonRunManager_CreateEnv__DoesNotSetPipelineRunStatus(t *testing.T) {
 	t.Parallel()
 
@@ -1567,7 +1623,7 @@ func Test__TektonRunManager_CreateEnv__DoesNotSetPipelineRunStatus(t *testing.T)
 	h.preparePredefinedClusterRole(mockFactory)
 	config := &cfg.PipelineRunsConfigStruct{}
 
-	examinee := NewTektonRunManager(mockFactory, mockSecretProvider)
+	examinee := NewTektonRunManager(mockFactory, mockSecretProvider, nil)
 	examinee.testing = newTektonRunManagerTestingWithRequiredStubs()
 
 	// EXERCISE
@@ -1590,7 +1646,7 @@ func Test__TektonRunManager_CreateRun__DoesNotSetPipelineRunStatus(t *testing.T)
 	h.preparePredefinedClusterRole(mockFactory)
 	config := &cfg.PipelineRunsConfigStruct{}
 
-	examinee := NewTektonRunManager(mockFactory, mockSecretProvider)
+	examinee := NewTektonRunManager(mockFactory, mockSecretProvider, nil)
 	examinee.testing = newTektonRunManagerTestingWithRequiredStubs()
 
 	// EXERCISE
@@ -1655,7 +1711,7 @@ func Test__TektonRunManager_DeleteEnv__RemovesNamespaces(t *testing.T) {
 			config := &cfg.PipelineRunsConfigStruct{}
 			secretProvider := secretproviderfakes.NewProvider(h.namespace1)
 
-			examinee := NewTektonRunManager(cf, secretProvider)
+			examinee := NewTektonRunManager(cf, secretProvider, nil)
 			examinee.testing = newTektonRunManagerTestingWithAllNoopStubs()
 			examinee.testing.cleanupStub = nil
 
@@ -1748,6 +1804,7 @@ func Test__TektonRunManager__Log_Elasticsearch(t *testing.T) {
 		examinee = NewTektonRunManager(
 			cf,
 			dummySecretProvider(cf, pipelineRun.GetNamespace()),
+			nil,
 		)
 		examinee.testing = newTektonRunManagerTestingWithRequiredStubs()
 		runCtx = &runContext{
@@ -2066,7 +2123,7 @@ func Test__TektonRunManager_GetRun_Missing(t *testing.T) {
 	mockFactory, mockPipelineRun, mockSecretProvider := h.prepareMocks(mockCtrl)
 	h.addTektonTaskRun(mockFactory)
 
-	examinee := NewTektonRunManager(mockFactory, mockSecretProvider)
+	examinee := NewTektonRunManager(mockFactory, mockSecretProvider, nil)
 
 	// EXERCISE
 	run, resultError := examinee.GetRun(h.ctx, mockPipelineRun)
@@ -2087,7 +2144,7 @@ func Test__TektonRunManager_GetRun_Existing(t *testing.T) {
 	defer mockCtrl.Finish()
 	mockFactory, mockPipelineRun, mockSecretProvider := h.prepareMocks(mockCtrl)
 
-	examinee := NewTektonRunManager(mockFactory, mockSecretProvider)
+	examinee := NewTektonRunManager(mockFactory, mockSecretProvider, nil)
 
 	// EXERCISE
 	run, resultError := examinee.GetRun(h.ctx, mockPipelineRun)
@@ -2099,6 +2156,60 @@ func Test__TektonRunManager_GetRun_Existing(t *testing.T) {
 	mockPipelineRun.EXPECT().UpdateState(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
 }
 
+func Test__TektonRunManager_GetRun_EvictedPod(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name           string
+		podReason      string
+		createPod      bool
+		expectedReason string
+	}{
+		{"evicted", "Evicted", true, runifc.FailureReasonEvicted},
+		{"not_evicted", "", true, ""},
+		{"pod_missing", "", false, ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			h := newTestHelper1(t)
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			mockFactory, mockPipelineRun, mockSecretProvider := h.prepareMocks(mockCtrl)
+
+			taskRun := h.dummyTektonTaskRun()
+			taskRun.Status.PodName = "pod1"
+			taskRun.Status.SetCondition(&knativeapis.Condition{
+				Type:    knativeapis.ConditionSucceeded,
+				Status:  corev1.ConditionFalse,
+				Reason:  "Failed",
+				Message: "The node was low on resource: memory.",
+			})
+			_, err := mockFactory.TektonV1beta1().TaskRuns(h.runNamespace1).Create(h.ctx, taskRun, metav1.CreateOptions{})
+			assert.NilError(t, err)
+			if tc.createPod {
+				pod := &corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{Name: "pod1"},
+					Status:     corev1.PodStatus{Reason: tc.podReason},
+				}
+				_, err = mockFactory.CoreV1().Pods(h.runNamespace1).Create(h.ctx, pod, metav1.CreateOptions{})
+				assert.NilError(t, err)
+			}
+
+			examinee := NewTektonRunManager(mockFactory, mockSecretProvider, nil)
+
+			// EXERCISE
+			run, resultError := examinee.GetRun(h.ctx, mockPipelineRun)
+
+			// VERIFY
+			assert.NilError(t, resultError)
+			assert.Equal(t, tc.expectedReason, run.GetFailureReason())
+		})
+	}
+}
+
 func Test__TektonRunManager_DeleteRun_Success(t *testing.T) {
 	t.Parallel()
 
@@ -2109,7 +2220,7 @@ func Test__TektonRunManager_DeleteRun_Success(t *testing.T) {
 	mockFactory, mockPipelineRun, mockSecretProvider := h.prepareMocks(mockCtrl)
 	h.addTektonTaskRun(mockFactory)
 
-	examinee := NewTektonRunManager(mockFactory, mockSecretProvider)
+	examinee := NewTektonRunManager(mockFactory, mockSecretProvider, nil)
 
 	// EXERCISE
 	resultError := examinee.DeleteRun(h.ctx, mockPipelineRun)
@@ -2129,7 +2240,7 @@ func Test__TektonRunManager_DeleteRun_Missing(t *testing.T) {
 	defer mockCtrl.Finish()
 	mockFactory, mockPipelineRun, mockSecretProvider := h.prepareMocks(mockCtrl)
 
-	examinee := NewTektonRunManager(mockFactory, mockSecretProvider)
+	examinee := NewTektonRunManager(mockFactory, mockSecretProvider, nil)
 
 	// EXERCISE
 	resultError := examinee.DeleteRun(h.ctx, mockPipelineRun)
@@ -2149,7 +2260,7 @@ func Test__TektonRunManager_DeleteRun_MissingRunNamespace(t *testing.T) {
 	defer mockCtrl.Finish()
 	mockFactory, mockPipelineRun, mockSecretProvider := h.prepareMocks(mockCtrl)
 
-	examinee := NewTektonRunManager(mockFactory, mockSecretProvider)
+	examinee := NewTektonRunManager(mockFactory, mockSecretProvider, nil)
 
 	mockPipelineRun.EXPECT().GetName().Return("foo").Times(1)
 
@@ -2193,7 +2304,7 @@ func Test__TektonRunManager_DeleteRun_Recoverable(t *testing.T) {
 			mockFactory, mockPipelineRun, mockSecretProvider := h.prepareMocks(mockCtrl)
 
 			h.tektonClientset.PrependReactor("delete", "*", k8sfake.NewErrorReactor(tc.transientError))
-			examinee := NewTektonRunManager(mockFactory, mockSecretProvider)
+			examinee := NewTektonRunManager(mockFactory, mockSecretProvider, nil)
 			// EXERCISE
 			resultError := examinee.DeleteRun(h.ctx, mockPipelineRun)
 
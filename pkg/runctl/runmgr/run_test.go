@@ -7,9 +7,11 @@ import (
 	"time"
 
 	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	runifc "github.com/SAP/stewardci-core/pkg/runctl/run"
 	tekton "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
 
 	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -303,6 +305,101 @@ const (
 			]
 		}
 	}`
+
+	completedOOMKilled = `{
+		"status": {
+			"conditions": [
+				{
+					"message": "\"step-jenkinsfile-runner\" exited with code 137",
+					"reason": "Failed",
+					"status": "False",
+					"type": "Succeeded"
+				}
+			],
+			"steps": [
+				{
+					"name": "jenkinsfile-runner",
+					"terminated": {
+						"reason": "OOMKilled",
+						"exitCode": 137
+					}
+				}
+			]
+		}
+	}`
+
+	completedEvicted = `{
+		"status": {
+			"conditions": [
+				{
+					"message": "The node was low on resource: memory.",
+					"reason": "Failed",
+					"status": "False",
+					"type": "Succeeded"
+				}
+			]
+		}
+	}`
+
+	completedEvictedReason = `{
+		"status": {
+			"conditions": [
+				{
+					"message": "The node was low on resource: memory.",
+					"reason": "Evicted",
+					"status": "False",
+					"type": "Succeeded"
+				}
+			]
+		}
+	}`
+
+	completedEvictedAfterJFRTerminated = `{
+		"status": {
+			"conditions": [
+				{
+					"message": "The node was low on resource: memory.",
+					"reason": "Failed",
+					"status": "False",
+					"type": "Succeeded"
+				}
+			],
+			"steps": [
+				{
+					"name": "jenkinsfile-runner",
+					"terminated": {
+						"exitCode": 2
+					}
+				}
+			]
+		}
+	}`
+
+	pendingExceededResourceQuota = `{
+		"status": {
+			"conditions": [
+				{
+					"message": "TaskRun Pod exceeded available resources: exceeded quota",
+					"reason": "ExceededResourceQuota",
+					"status": "Unknown",
+					"type": "Succeeded"
+				}
+			]
+		}
+	}`
+
+	pendingExceededNodeResources = `{
+		"status": {
+			"conditions": [
+				{
+					"message": "TaskRun Pod exceeded available resources",
+					"reason": "ExceededNodeResources",
+					"status": "Unknown",
+					"type": "Succeeded"
+				}
+			]
+		}
+	}`
 )
 
 func generateTime(timeRFC3339String string) *metav1.Time {
@@ -430,6 +527,147 @@ func Test__IsFinished__Timeout(t *testing.T) {
 	assert.Equal(t, result, api.ResultTimeout)
 }
 
+func Test__GetExitCode(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name             string
+		taskRun          string
+		expectedExitCode *int32
+	}{
+		{"empty", emptyBuild, nil},
+		{"running", runningBuild, nil},
+		{"success", completedSuccess, int32Ptr(0)},
+		{"error_content", completedErrorContent, int32Ptr(jfrExitCodeErrorContent)},
+		{"oom_killed", completedOOMKilled, int32Ptr(137)},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			run := newRun(fakeTektonTaskRunFromJSON(tc.taskRun))
+
+			// EXERCISE
+			exitCode := run.GetExitCode()
+
+			// VERIFY
+			assert.DeepEqual(t, tc.expectedExitCode, exitCode)
+		})
+	}
+}
+
+func Test__FailureReason(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name             string
+		taskRun          string
+		podReason        string
+		expectedReason   string
+		expectedFinished bool
+		expectedResult   api.Result
+		expectedMessage  string
+	}{
+		{
+			name:             "success",
+			taskRun:          completedSuccess,
+			expectedReason:   "",
+			expectedFinished: true,
+			expectedResult:   api.ResultSuccess,
+			expectedMessage:  "ok",
+		},
+		{
+			name:             "other failure",
+			taskRun:          completedErrorInfra,
+			expectedReason:   "",
+			expectedFinished: true,
+			expectedResult:   api.ResultErrorInfra,
+			expectedMessage:  "ko",
+		},
+		{
+			name:             "OOMKilled",
+			taskRun:          completedOOMKilled,
+			expectedReason:   runifc.FailureReasonOOMKilled,
+			expectedFinished: true,
+			expectedResult:   api.ResultErrorResources,
+			expectedMessage:  "the pipeline run exceeded its memory limit and has been terminated (OOMKilled)",
+		},
+		{
+			name:             "evicted",
+			taskRun:          completedEvicted,
+			podReason:        "Evicted",
+			expectedReason:   runifc.FailureReasonEvicted,
+			expectedFinished: true,
+			expectedResult:   api.ResultErrorResources,
+			expectedMessage:  "the pipeline run pod has been evicted: The node was low on resource: memory.",
+		},
+		{
+			name:             "evicted condition reason",
+			taskRun:          completedEvictedReason,
+			expectedReason:   runifc.FailureReasonEvicted,
+			expectedFinished: true,
+			expectedResult:   api.ResultErrorResources,
+			expectedMessage:  "the pipeline run pod has been evicted: The node was low on resource: memory.",
+		},
+		{
+			name:             "eviction message without pod reason",
+			taskRun:          completedEvicted,
+			expectedReason:   "",
+			expectedFinished: true,
+			expectedResult:   api.ResultErrorInfra,
+			expectedMessage:  "The node was low on resource: memory.",
+		},
+		{
+			name:             "evicted after Jenkinsfile Runner terminated",
+			taskRun:          completedEvictedAfterJFRTerminated,
+			podReason:        "Evicted",
+			expectedReason:   "",
+			expectedFinished: true,
+			expectedResult:   api.ResultErrorContent,
+			expectedMessage:  "The node was low on resource: memory.",
+		},
+		{
+			name:             "exceeded resource quota",
+			taskRun:          pendingExceededResourceQuota,
+			expectedReason:   runifc.FailureReasonExceededResourceQuota,
+			expectedFinished: false,
+			expectedResult:   api.ResultUndefined,
+			expectedMessage:  "the pipeline run pod cannot be started due to missing resources: TaskRun Pod exceeded available resources: exceeded quota",
+		},
+		{
+			name:             "exceeded node resources",
+			taskRun:          pendingExceededNodeResources,
+			expectedReason:   runifc.FailureReasonExceededNodeResources,
+			expectedFinished: false,
+			expectedResult:   api.ResultUndefined,
+			expectedMessage:  "the pipeline run pod cannot be started due to missing resources: TaskRun Pod exceeded available resources",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			run := newRun(fakeTektonTaskRunFromJSON(tc.taskRun))
+			if tc.podReason != "" {
+				run.pod = &corev1.Pod{Status: corev1.PodStatus{Reason: tc.podReason}}
+			}
+
+			// EXERCISE
+			reason := run.GetFailureReason()
+			finished, result := run.IsFinished()
+			message := run.GetMessage()
+
+			// VERIFY
+			assert.Equal(t, tc.expectedReason, reason)
+			assert.Equal(t, tc.expectedFinished, finished)
+			assert.Equal(t, tc.expectedResult, result)
+			assert.Equal(t, tc.expectedMessage, message)
+		})
+	}
+}
+
 func Test__IsRestartable__False(t *testing.T) {
 	for id, taskrun := range []string{
 		completedSuccess,
@@ -559,3 +797,5 @@ func Test__IsDeleted__WithTerminationTimestamp(t *testing.T) {
 	// VERIFY
 	assert.Assert(t, result == true)
 }
+
+func int32Ptr(val int32) *int32 { return &val }
@@ -0,0 +1,88 @@
+package secretmgr
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	serrors "github.com/SAP/stewardci-core/pkg/errors"
+	"github.com/SAP/stewardci-core/pkg/githubapp"
+	"github.com/SAP/stewardci-core/pkg/k8s"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// keys of the data of GitHub App secrets
+	gitHubAppSecretKeyAppID      = "appID"
+	gitHubAppSecretKeyPrivateKey = "privateKey"
+	gitHubAppSecretKeyAPIURL     = "apiURL"
+
+	// gitHubAppTokenUsername is the username to be used for Git
+	// authentication with a GitHub App installation token.
+	gitHubAppTokenUsername = "x-access-token"
+)
+
+func isGitHubAppSecret(secret *corev1.Secret) bool {
+	return secret.GetAnnotations()[v1alpha1.AnnotationCredentialKind] == v1alpha1.CredentialKindGitHubApp
+}
+
+// gitHubAppTokenSecret exchanges the given GitHub App secret for an
+// installation token for the owner of the pipeline repository and returns
+// a secret of type `kubernetes.io/basic-auth` containing the token.
+// The metadata of the returned secret is the one of the given secret.
+func (s SecretManager) gitHubAppTokenSecret(ctx context.Context, pipelineRun k8s.PipelineRun, secret *corev1.Secret) (*corev1.Secret, error) {
+	repoURL, owner, repo, err := parseGitHubRepoURL(pipelineRun.GetSpec().JenkinsFile.URL)
+	if err != nil {
+		return nil, serrors.Classify(err, v1alpha1.ResultErrorConfig)
+	}
+
+	credentials := githubapp.Credentials{
+		AppID:      strings.TrimSpace(string(secret.Data[gitHubAppSecretKeyAppID])),
+		PrivateKey: secret.Data[gitHubAppSecretKeyPrivateKey],
+		APIURL:     strings.TrimSpace(string(secret.Data[gitHubAppSecretKeyAPIURL])),
+	}
+	if credentials.APIURL == "" {
+		credentials.APIURL = githubapp.DefaultAPIURL(repoURL.Scheme, repoURL.Host)
+	}
+
+	token, err := s.gitHubApp.CreateInstallationToken(ctx, credentials, owner, repo)
+	if err != nil {
+		err = errors.Wrapf(err, "GitHub App secret %q", secret.GetName())
+		if githubapp.IsClientError(err) || githubapp.IsInvalidCredentials(err) {
+			// rejected by GitHub or invalid secret content
+			return nil, serrors.Classify(err, v1alpha1.ResultErrorConfig)
+		}
+		return nil, serrors.Classify(err, v1alpha1.ResultErrorInfra)
+	}
+	logger := klog.FromContext(ctx)
+	logger.V(3).Info("Created GitHub App installation token",
+		"secret", secret.GetName(), "owner", owner, "expiresAt", token.ExpiresAt)
+
+	tokenSecret := secret.DeepCopy()
+	delete(tokenSecret.Annotations, v1alpha1.AnnotationCredentialKind)
+	tokenSecret.Type = corev1.SecretTypeBasicAuth
+	tokenSecret.StringData = nil
+	tokenSecret.Data = map[string][]byte{
+		corev1.BasicAuthUsernameKey: []byte(gitHubAppTokenUsername),
+		corev1.BasicAuthPasswordKey: []byte(token.Token),
+	}
+	return tokenSecret, nil
+}
+
+// parseGitHubRepoURL parses the URL of a GitHub repository of the form
+// `<scheme>://<host>/<owner>/<repo>[.git]`.
+func parseGitHubRepoURL(urlString string) (*url.URL, string, string, error) {
+	repoURL, err := url.Parse(urlString)
+	if err != nil {
+		return nil, "", "", errors.Wrapf(err, "value %q of field spec.jenkinsFile.url is invalid", urlString)
+	}
+	segments := strings.Split(strings.Trim(repoURL.Path, "/"), "/")
+	if len(segments) != 2 || segments[0] == "" || strings.TrimSuffix(segments[1], ".git") == "" {
+		return nil, "", "", fmt.Errorf("value %q of field spec.jenkinsFile.url is invalid: not a GitHub repository URL", urlString)
+	}
+	return repoURL, segments[0], strings.TrimSuffix(segments[1], ".git"), nil
+}
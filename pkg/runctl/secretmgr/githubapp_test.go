@@ -0,0 +1,267 @@
+package secretmgr
+
+import (
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	stewardv1alpha1 "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	serrors "github.com/SAP/stewardci-core/pkg/errors"
+	"github.com/SAP/stewardci-core/pkg/githubapp"
+	"github.com/SAP/stewardci-core/pkg/githubapp/fake"
+	"github.com/SAP/stewardci-core/pkg/runctl/cfg"
+	gomock "github.com/golang/mock/gomock"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func newGitHubAppTestServer(t *testing.T) *fake.Server {
+	t.Helper()
+	server, err := fake.NewServer("1234")
+	assert.NilError(t, err)
+	t.Cleanup(server.Close)
+	server.AddInstallation("owner1", 42)
+	return server
+}
+
+func newGitHubAppSecret(server *fake.Server) *corev1.Secret {
+	secret := newSecret("app_secret1", corev1.SecretTypeOpaque)
+	secret.SetUID("uid1")
+	secret.SetAnnotations(map[string]string{
+		stewardv1alpha1.AnnotationCredentialKind: stewardv1alpha1.CredentialKindGitHubApp,
+		"annotation1":                            "value1",
+	})
+	secret.Data = map[string][]byte{
+		gitHubAppSecretKeyAppID:      []byte(server.AppID),
+		gitHubAppSecretKeyPrivateKey: server.PrivateKey,
+		gitHubAppSecretKeyAPIURL:     []byte(server.URL),
+	}
+	return secret
+}
+
+func Test_copyPipelineCloneSecretToRunNamespace_GitHubApp_Success(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	server := newGitHubAppTestServer(t)
+	th := newTestHelper(t)
+	th.spec.JenkinsFile.URL = "https://github.example.com/owner1/repo1.git"
+	th.spec.JenkinsFile.RepoAuthSecret = "app_secret1"
+	mockCtrl, examinee, mockPipelineRun, mockSecretHelper := mockPipelineRunWithSpec(th)
+	defer mockCtrl.Finish()
+	examinee.gitHubApp = githubapp.NewClient(server.Client())
+
+	appSecret := newGitHubAppSecret(server)
+
+	// EXPECT
+	mockPipelineRun.EXPECT().GetValidatedJenkinsfileRepoServerURL().Return("https://github.example.com", nil).AnyTimes()
+	mockSecretHelper.EXPECT().GetSecret(th.ctx, "app_secret1").Return(appSecret, nil)
+	mockSecretHelper.EXPECT().
+		CopySecret(th.ctx, gomock.Any(), th.cloneSecretTransormerMatcher).
+		DoAndReturn(func(_ interface{}, secret *corev1.Secret, _ ...interface{}) (*stewardv1alpha1.CopiedSecret, error) {
+			assert.Equal(t, "app_secret1", secret.GetName())
+			assert.Equal(t, corev1.SecretTypeBasicAuth, secret.Type)
+			assert.DeepEqual(t, map[string]string{"annotation1": "value1"}, secret.GetAnnotations())
+			assert.DeepEqual(t, map[string][]byte{
+				corev1.BasicAuthUsernameKey: []byte("x-access-token"),
+				corev1.BasicAuthPasswordKey: []byte(server.Token),
+			}, secret.Data)
+			return &stewardv1alpha1.CopiedSecret{SourceName: secret.GetName(), SourceUID: secret.GetUID(), TargetName: "app_secret1-a", Type: secret.Type}, nil
+		})
+
+	// EXERCISE
+	copiedSecret, err := examinee.copyPipelineCloneSecretToRunNamespace(th.ctx, mockPipelineRun)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.DeepEqual(t, &stewardv1alpha1.CopiedSecret{
+		Purpose:    stewardv1alpha1.SecretPurposePipelineClone,
+		SourceName: "app_secret1",
+		SourceUID:  "uid1",
+		TargetName: "app_secret1-a",
+		// type of the source secret, not the one of the token secret
+		Type: corev1.SecretTypeOpaque,
+	}, copiedSecret)
+	assert.DeepEqual(t, []string{"repo1"}, server.TokenRequests()[0].Repositories)
+	// source secret must not be modified
+	assert.Equal(t, corev1.SecretTypeOpaque, appSecret.Type)
+}
+
+func Test_copyPipelineCloneSecretToRunNamespace_GitHubApp_UsesGivenClient(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	server := newGitHubAppTestServer(t)
+	th := newTestHelper(t)
+	th.spec.JenkinsFile.URL = "https://github.example.com/owner1/repo1.git"
+	th.spec.JenkinsFile.RepoAuthSecret = "app_secret1"
+	mockCtrl, _, mockPipelineRun, mockSecretHelper := mockPipelineRunWithSpec(th)
+	defer mockCtrl.Finish()
+
+	transport := &countingRoundTripper{delegate: server.Client().Transport}
+	examinee := NewSecretManager(mockSecretHelper, nil, &cfg.PipelineRunsConfigStruct{},
+		githubapp.NewClient(&http.Client{Transport: transport}))
+
+	// EXPECT
+	mockPipelineRun.EXPECT().GetValidatedJenkinsfileRepoServerURL().Return("https://github.example.com", nil).AnyTimes()
+	mockSecretHelper.EXPECT().GetSecret(th.ctx, "app_secret1").Return(newGitHubAppSecret(server), nil)
+	mockSecretHelper.EXPECT().
+		CopySecret(th.ctx, gomock.Any(), th.cloneSecretTransormerMatcher).
+		Return(&stewardv1alpha1.CopiedSecret{SourceName: "app_secret1", TargetName: "app_secret1-a"}, nil)
+
+	// EXERCISE
+	_, err := examinee.copyPipelineCloneSecretToRunNamespace(th.ctx, mockPipelineRun)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Assert(t, transport.count.Load() > 0)
+}
+
+type countingRoundTripper struct {
+	delegate http.RoundTripper
+	count    atomic.Int32
+}
+
+func (rt *countingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	rt.count.Add(1)
+	return rt.delegate.RoundTrip(req)
+}
+
+func Test_copyPipelineCloneSecretToRunNamespace_GitHubApp_Errors(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name          string
+		repoURL       string
+		modifySecret  func(*corev1.Secret, *fake.Server)
+		expectedError string
+		expectedClass stewardv1alpha1.Result
+	}{
+		{
+			name:          "not_installed",
+			repoURL:       "https://github.example.com/owner2/repo1",
+			expectedError: "HTTP status 404",
+			expectedClass: stewardv1alpha1.ResultErrorConfig,
+		},
+		{
+			name:          "no_repo_url",
+			repoURL:       "https://github.example.com/owner1",
+			expectedError: "not a GitHub repository URL",
+			expectedClass: stewardv1alpha1.ResultErrorConfig,
+		},
+		{
+			name:    "missing_app_id",
+			repoURL: "https://github.example.com/owner1/repo1",
+			modifySecret: func(secret *corev1.Secret, _ *fake.Server) {
+				delete(secret.Data, gitHubAppSecretKeyAppID)
+			},
+			expectedError: "GitHub App ID is empty",
+			expectedClass: stewardv1alpha1.ResultErrorConfig,
+		},
+		{
+			name:    "invalid_private_key",
+			repoURL: "https://github.example.com/owner1/repo1",
+			modifySecret: func(secret *corev1.Secret, _ *fake.Server) {
+				secret.Data[gitHubAppSecretKeyPrivateKey] = []byte("foo")
+			},
+			expectedError: "invalid private key",
+			expectedClass: stewardv1alpha1.ResultErrorConfig,
+		},
+		{
+			name:    "http_api_url",
+			repoURL: "https://github.example.com/owner1/repo1",
+			modifySecret: func(secret *corev1.Secret, _ *fake.Server) {
+				secret.Data[gitHubAppSecretKeyAPIURL] = []byte("http://github.example.com/api/v3")
+			},
+			expectedError: "URL must use scheme https",
+			expectedClass: stewardv1alpha1.ResultErrorConfig,
+		},
+		{
+			name:    "derived_http_api_url",
+			repoURL: "http://github.example.com/owner1/repo1",
+			modifySecret: func(secret *corev1.Secret, _ *fake.Server) {
+				delete(secret.Data, gitHubAppSecretKeyAPIURL)
+			},
+			expectedError: "URL must use scheme https",
+			expectedClass: stewardv1alpha1.ResultErrorConfig,
+		},
+		{
+			name:    "server_unavailable",
+			repoURL: "https://github.example.com/owner1/repo1",
+			modifySecret: func(_ *corev1.Secret, server *fake.Server) {
+				server.Close()
+			},
+			expectedError: `GitHub App secret "app_secret1"`,
+			expectedClass: stewardv1alpha1.ResultErrorInfra,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			server := newGitHubAppTestServer(t)
+			th := newTestHelper(t)
+			th.spec.JenkinsFile.URL = tc.repoURL
+			th.spec.JenkinsFile.RepoAuthSecret = "app_secret1"
+			mockCtrl, examinee, mockPipelineRun, mockSecretHelper := mockPipelineRunWithSpec(th)
+			defer mockCtrl.Finish()
+			examinee.gitHubApp = githubapp.NewClient(server.Client())
+
+			appSecret := newGitHubAppSecret(server)
+			if tc.modifySecret != nil {
+				tc.modifySecret(appSecret, server)
+			}
+
+			// EXPECT
+			mockPipelineRun.EXPECT().GetValidatedJenkinsfileRepoServerURL().Return("https://github.example.com", nil).AnyTimes()
+			mockSecretHelper.EXPECT().GetSecret(th.ctx, "app_secret1").Return(appSecret, nil)
+			mockSecretHelper.EXPECT().CopySecret(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+			// EXERCISE
+			copiedSecret, err := examinee.copyPipelineCloneSecretToRunNamespace(th.ctx, mockPipelineRun)
+
+			// VERIFY
+			assert.Assert(t, copiedSecret == nil)
+			assert.ErrorContains(t, err, tc.expectedError)
+			assert.Equal(t, tc.expectedClass, serrors.GetClass(err))
+		})
+	}
+}
+
+func Test_parseGitHubRepoURL(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		url           string
+		expectedOwner string
+		expectedRepo  string
+		expectedError bool
+	}{
+		{"https://github.com/owner1/repo1", "owner1", "repo1", false},
+		{"https://github.com/owner1/repo1.git", "owner1", "repo1", false},
+		{"https://github.com/owner1/repo1/", "owner1", "repo1", false},
+		{"https://github.com/owner1", "", "", true},
+		{"https://github.com/owner1/.git", "", "", true},
+		{"https://github.com/owner1/repo1/tree/main", "", "", true},
+		{"https://github.com", "", "", true},
+		{"://github.com/owner1/repo1", "", "", true},
+	} {
+		t.Run(tc.url, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// EXERCISE
+			_, owner, repo, err := parseGitHubRepoURL(tc.url)
+
+			// VERIFY
+			if tc.expectedError {
+				assert.Assert(t, err != nil)
+			} else {
+				assert.NilError(t, err)
+				assert.Equal(t, tc.expectedOwner, owner)
+				assert.Equal(t, tc.expectedRepo, repo)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package secretmgr
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	secrets "github.com/SAP/stewardci-core/pkg/k8s/secrets"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// MaskingSecretName is the name of the secret in the run namespace
+	// listing the values of all pipeline secrets which should be masked
+	// in the pipeline log.
+	MaskingSecretName = "steward-secret-masking"
+
+	// MaskingSecretKeyValues is the key of the masking secret's data entry
+	// containing the values to be masked as JSON array of strings.
+	MaskingSecretKeyValues = "values.json"
+)
+
+// alwaysMaskingExcludedSecretTypes are the types of secrets whose values
+// are never added to the masking secret, as they are not used as plain
+// values in pipelines.
+var alwaysMaskingExcludedSecretTypes = []corev1.SecretType{
+	corev1.SecretTypeDockerConfigJson,
+}
+
+// maskingValuesCollector collects the values of secrets to be masked in
+// the pipeline log.
+type maskingValuesCollector struct {
+	excludedSecretTypes []corev1.SecretType
+	values              map[string]struct{}
+}
+
+func newMaskingValuesCollector(excludedSecretTypes []corev1.SecretType) *maskingValuesCollector {
+	return &maskingValuesCollector{
+		excludedSecretTypes: append(append([]corev1.SecretType{}, alwaysMaskingExcludedSecretTypes...), excludedSecretTypes...),
+		values:              map[string]struct{}{},
+	}
+}
+
+// transformer returns a secret transformer which does not modify the secret
+// but collects its values to be masked.
+func (c *maskingValuesCollector) transformer() secrets.SecretTransformer {
+	return c.collect
+}
+
+func (c *maskingValuesCollector) collect(secret *corev1.Secret) {
+	for _, excludedType := range c.excludedSecretTypes {
+		if secret.Type == excludedType {
+			return
+		}
+	}
+	excludedKeys := map[string]bool{}
+	for _, key := range strings.Split(secret.GetAnnotations()[v1alpha1.AnnotationSecretMaskingExcludeKeys], ",") {
+		excludedKeys[strings.TrimSpace(key)] = true
+	}
+	for key, value := range secret.Data {
+		if excludedKeys[key] || strings.TrimSpace(string(value)) == "" {
+			continue
+		}
+		c.values[string(value)] = struct{}{}
+	}
+}
+
+// maskingSecret returns the masking secret containing all values collected
+// so far.
+func (c *maskingValuesCollector) maskingSecret() (*corev1.Secret, error) {
+	values := make([]string, 0, len(c.values))
+	for value := range c.values {
+		values = append(values, value)
+	}
+	sort.Strings(values)
+	valuesJSON, err := json.Marshal(values)
+	if err != nil {
+		return nil, err
+	}
+	secret := &corev1.Secret{
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			MaskingSecretKeyValues: valuesJSON,
+		},
+	}
+	secret.SetName(MaskingSecretName)
+	secret.SetLabels(map[string]string{
+		v1alpha1.LabelSystemManaged: "",
+	})
+	return secret, nil
+}
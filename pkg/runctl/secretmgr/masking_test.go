@@ -0,0 +1,94 @@
+package secretmgr
+
+import (
+	"testing"
+
+	stewardv1alpha1 "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_maskingValuesCollector(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name                string
+		excludedSecretTypes []corev1.SecretType
+		secrets             []*corev1.Secret
+		expectedValuesJSON  string
+	}{
+		{
+			name:               "no_secrets",
+			expectedValuesJSON: `[]`,
+		},
+		{
+			name: "all_values_sorted_and_unique",
+			secrets: []*corev1.Secret{
+				{Type: corev1.SecretTypeOpaque, Data: map[string][]byte{"a": []byte("v2"), "b": []byte("v1")}},
+				{Type: corev1.SecretTypeBasicAuth, Data: map[string][]byte{"username": []byte("v1"), "password": []byte("v3")}},
+			},
+			expectedValuesJSON: `["v1","v2","v3"]`,
+		},
+		{
+			name: "empty_values_skipped",
+			secrets: []*corev1.Secret{
+				{Type: corev1.SecretTypeOpaque, Data: map[string][]byte{"a": []byte(""), "b": []byte(" \n"), "c": []byte("v1")}},
+			},
+			expectedValuesJSON: `["v1"]`,
+		},
+		{
+			name: "docker_config_json_always_excluded",
+			secrets: []*corev1.Secret{
+				{Type: corev1.SecretTypeDockerConfigJson, Data: map[string][]byte{corev1.DockerConfigJsonKey: []byte("{}")}},
+			},
+			expectedValuesJSON: `[]`,
+		},
+		{
+			name:                "configured_types_excluded",
+			excludedSecretTypes: []corev1.SecretType{corev1.SecretTypeSSHAuth},
+			secrets: []*corev1.Secret{
+				{Type: corev1.SecretTypeSSHAuth, Data: map[string][]byte{corev1.SSHAuthPrivateKey: []byte("key1")}},
+				{Type: corev1.SecretTypeOpaque, Data: map[string][]byte{"a": []byte("v1")}},
+			},
+			expectedValuesJSON: `["v1"]`,
+		},
+		{
+			name: "keys_excluded_by_annotation",
+			secrets: []*corev1.Secret{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Annotations: map[string]string{
+							stewardv1alpha1.AnnotationSecretMaskingExcludeKeys: "username, url",
+						},
+					},
+					Type: corev1.SecretTypeOpaque,
+					Data: map[string][]byte{"username": []byte("user1"), "url": []byte("url1"), "password": []byte("pwd1")},
+				},
+			},
+			expectedValuesJSON: `["pwd1"]`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			examinee := newMaskingValuesCollector(tc.excludedSecretTypes)
+
+			// EXERCISE
+			for _, secret := range tc.secrets {
+				examinee.transformer()(secret)
+			}
+			result, err := examinee.maskingSecret()
+
+			// VERIFY
+			assert.NilError(t, err)
+			assert.Equal(t, MaskingSecretName, result.GetName())
+			assert.Equal(t, corev1.SecretTypeOpaque, result.Type)
+			assert.Equal(t, tc.expectedValuesJSON, string(result.Data[MaskingSecretKeyValues]))
+			_, isSystemManaged := result.GetLabels()[stewardv1alpha1.LabelSystemManaged]
+			assert.Assert(t, isSystemManaged)
+		})
+	}
+}
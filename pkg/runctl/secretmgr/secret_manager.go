@@ -5,9 +5,12 @@ import (
 
 	"github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
 	serrors "github.com/SAP/stewardci-core/pkg/errors"
+	"github.com/SAP/stewardci-core/pkg/githubapp"
 	"github.com/SAP/stewardci-core/pkg/k8s"
 	secrets "github.com/SAP/stewardci-core/pkg/k8s/secrets"
+	"github.com/SAP/stewardci-core/pkg/runctl/cfg"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/klog/v2"
 )
@@ -19,37 +22,87 @@ const (
 
 // SecretManager manages the serets in a run-namespace for the controller.
 type SecretManager struct {
-	secretHelper secrets.SecretHelper
+	secretHelper               secrets.SecretHelper
+	systemSecretHelper         secrets.SecretHelper
+	maskingExcludedSecretTypes []corev1.SecretType
+	defaultImagePullSecrets    []string
+	gitHubApp                  *githubapp.Client
 }
 
-// NewSecretManager creates secrets in the run namesapce
-func NewSecretManager(secretHelper secrets.SecretHelper) SecretManager {
+// NewSecretManager creates secrets in the run namesapce.
+// secretHelper copies secrets from the namespace of the pipeline run,
+// systemSecretHelper copies the default image pull secrets from the Steward
+// system namespace.
+// The values of pipeline secrets of the configured excluded secret types
+// are not added to the masking secret.
+// gitHubApp is used to exchange GitHub App secrets for installation tokens.
+// If nil, a client with default settings is used.
+func NewSecretManager(secretHelper, systemSecretHelper secrets.SecretHelper, pipelineRunsConfig *cfg.PipelineRunsConfigStruct, gitHubApp *githubapp.Client) SecretManager {
+	if gitHubApp == nil {
+		gitHubApp = githubapp.NewClient(nil)
+	}
 	return SecretManager{
-		secretHelper: secretHelper,
+		secretHelper:               secretHelper,
+		systemSecretHelper:         systemSecretHelper,
+		maskingExcludedSecretTypes: pipelineRunsConfig.SecretMaskingExcludedSecretTypes,
+		defaultImagePullSecrets:    pipelineRunsConfig.DefaultImagePullSecrets,
+		gitHubApp:                  gitHubApp,
 	}
 }
 
 // CopyAll copies the required secrets of a pipeline run to the respective run namespace.
+// All secrets copied are recorded in the status of the pipeline run, even if
+// an error occurs.
+// If the pipeline run has pipeline secrets, the masking secret listing
+// their values is created in the run namespace, too.
 func (s SecretManager) CopyAll(ctx context.Context, pipelineRun k8s.PipelineRun) (string, []string, error) {
-	imagePullSecretNames, err := s.copyImagePullSecretsToRunNamespace(ctx, pipelineRun)
+	var copiedSecrets []v1alpha1.CopiedSecret
+	defer func() {
+		pipelineRun.UpdateCopiedSecrets(copiedSecrets)
+	}()
+
+	imagePullSecrets, err := s.copyImagePullSecretsToRunNamespace(ctx, pipelineRun)
+	copiedSecrets = append(copiedSecrets, imagePullSecrets...)
 	if err != nil {
 		return "", nil, errors.Wrap(err, "failed to copy image pull secrets")
 	}
 
-	pipelineCloneSecretName, err := s.copyPipelineCloneSecretToRunNamespace(ctx, pipelineRun)
+	defaultImagePullSecrets, err := s.copyDefaultImagePullSecretsToRunNamespace(ctx)
+	copiedSecrets = append(copiedSecrets, defaultImagePullSecrets...)
+	if err != nil {
+		return "", nil, errors.Wrap(err, "failed to copy default image pull secrets")
+	}
+	imagePullSecrets = append(imagePullSecrets, defaultImagePullSecrets...)
+
+	pipelineCloneSecret, err := s.copyPipelineCloneSecretToRunNamespace(ctx, pipelineRun)
+	if pipelineCloneSecret != nil {
+		copiedSecrets = append(copiedSecrets, *pipelineCloneSecret)
+	}
 	if err != nil {
 		return "", nil, errors.Wrap(err, "failed to copy pipeline clone secret")
 	}
 
-	_, err = s.copyPipelineSecretsToRunNamespace(ctx, pipelineRun)
+	maskingValues := newMaskingValuesCollector(s.maskingExcludedSecretTypes)
+	pipelineSecrets, err := s.copyPipelineSecretsToRunNamespace(ctx, pipelineRun, maskingValues)
+	copiedSecrets = append(copiedSecrets, pipelineSecrets...)
 	if err != nil {
 		return "", nil, errors.Wrap(err, "failed to copy pipeline secrets")
 	}
 
-	return pipelineCloneSecretName, imagePullSecretNames, nil
+	if len(pipelineRun.GetSpec().Secrets) > 0 {
+		if err := s.createMaskingSecret(ctx, maskingValues); err != nil {
+			return "", nil, errors.Wrap(err, "failed to create secret masking secret")
+		}
+	}
+
+	pipelineCloneSecretName := ""
+	if pipelineCloneSecret != nil {
+		pipelineCloneSecretName = pipelineCloneSecret.TargetName
+	}
+	return pipelineCloneSecretName, targetNames(imagePullSecrets), nil
 }
 
-func (s SecretManager) copyImagePullSecretsToRunNamespace(ctx context.Context, pipelineRun k8s.PipelineRun) ([]string, error) {
+func (s SecretManager) copyImagePullSecretsToRunNamespace(ctx context.Context, pipelineRun k8s.PipelineRun) ([]v1alpha1.CopiedSecret, error) {
 	secretNames := pipelineRun.GetSpec().ImagePullSecrets
 	transformers := []secrets.SecretTransformer{
 		secrets.StripAnnotationsTransformer(annotationPrefixTekton),
@@ -57,51 +110,137 @@ func (s SecretManager) copyImagePullSecretsToRunNamespace(ctx context.Context, p
 		secrets.StripLabelsTransformer(annotationPrefixJenkins),
 		secrets.UniqueNameTransformer(),
 	}
-	return s.copySecrets(ctx, pipelineRun, secretNames, secrets.DockerOnly, transformers...)
+	return s.copySecrets(ctx, pipelineRun, v1alpha1.SecretPurposeImagePull, secretNames, secrets.DockerOnly, transformers...)
+}
+
+// copyDefaultImagePullSecretsToRunNamespace copies the configured default
+// image pull secrets from the Steward system namespace to the run namespace.
+// As the default image pull secrets are maintained by the Steward
+// administrator, all errors are classified as infrastructure errors.
+func (s SecretManager) copyDefaultImagePullSecretsToRunNamespace(ctx context.Context) ([]v1alpha1.CopiedSecret, error) {
+	if len(s.defaultImagePullSecrets) == 0 {
+		return nil, nil
+	}
+	transformers := []secrets.SecretTransformer{
+		secrets.StripAnnotationsTransformer(annotationPrefixTekton),
+		secrets.StripAnnotationsTransformer(annotationPrefixJenkins),
+		secrets.StripLabelsTransformer(annotationPrefixJenkins),
+		secrets.UniqueNameTransformer(),
+	}
+	copiedSecrets, err := s.systemSecretHelper.CopySecrets(ctx, s.defaultImagePullSecrets, secrets.DockerOnly, transformers...)
+	copiedSecrets = withPurpose(v1alpha1.SecretPurposeDefaultImagePull, copiedSecrets)
+	if err != nil {
+		logger := klog.FromContext(ctx)
+		logger.Error(err, "Could not copy default image pull secrets", "secrets", s.defaultImagePullSecrets)
+		return copiedSecrets, serrors.Classify(err, v1alpha1.ResultErrorInfra)
+	}
+	return copiedSecrets, nil
 }
 
-func (s SecretManager) copyPipelineCloneSecretToRunNamespace(ctx context.Context, pipelineRun k8s.PipelineRun) (string, error) {
+// copyPipelineCloneSecretToRunNamespace copies the pipeline clone secret
+// to the run namespace.
+// A GitHub App secret is not copied as is, but exchanged for an
+// installation token stored as basic-auth secret.
+// For SSH URLs, the secret must be an SSH auth secret with known hosts.
+func (s SecretManager) copyPipelineCloneSecretToRunNamespace(ctx context.Context, pipelineRun k8s.PipelineRun) (*v1alpha1.CopiedSecret, error) {
 	secretName := pipelineRun.GetSpec().JenkinsFile.RepoAuthSecret
 	if secretName == "" {
-		return "", nil
+		return nil, nil
 	}
 	repoServerURL, err := pipelineRun.GetValidatedJenkinsfileRepoServerURL()
 	if err != nil {
-		return "", serrors.Classify(err, v1alpha1.ResultErrorConfig)
+		return nil, serrors.Classify(err, v1alpha1.ResultErrorConfig)
+	}
+	secret, err := s.secretHelper.GetSecret(ctx, secretName)
+	if err != nil {
+		return nil, s.classifySecretsError(ctx, err, []string{secretName})
+	}
+	gitServer, isSSH := tektonGitServer(repoServerURL)
+	if err := validateCloneSecretForTransport(secret, isSSH); err != nil {
+		return nil, serrors.Classify(err, v1alpha1.ResultErrorConfig)
+	}
+	sourceType := secret.Type
+	isGitHubApp := isGitHubAppSecret(secret)
+	if isGitHubApp {
+		secret, err = s.gitHubAppTokenSecret(ctx, pipelineRun, secret)
+		if err != nil {
+			return nil, err
+		}
 	}
 	transformers := []secrets.SecretTransformer{
 		secrets.StripAnnotationsTransformer(annotationPrefixJenkins),
 		secrets.StripLabelsTransformer(annotationPrefixJenkins),
 		secrets.UniqueNameTransformer(),
-		secrets.SetAnnotationTransformer("tekton.dev/git-0", repoServerURL),
+		secrets.SetAnnotationTransformer("tekton.dev/git-0", gitServer),
 	}
-	names, err := s.copySecrets(ctx, pipelineRun, []string{secretName}, nil, transformers...)
+	copiedSecret, err := s.secretHelper.CopySecret(ctx, secret, transformers...)
 	if err != nil {
-		return "", err
+		return nil, s.classifySecretsError(ctx, err, []string{secretName})
 	}
-	return names[0], nil
+	copiedSecret.Purpose = v1alpha1.SecretPurposePipelineClone
+	if isGitHubApp {
+		// record the type of the source secret, not the one of the token secret
+		copiedSecret.Type = sourceType
+	}
+	return copiedSecret, nil
 }
 
-func (s SecretManager) copyPipelineSecretsToRunNamespace(ctx context.Context, pipelineRun k8s.PipelineRun) ([]string, error) {
+func (s SecretManager) copyPipelineSecretsToRunNamespace(ctx context.Context, pipelineRun k8s.PipelineRun, maskingValues *maskingValuesCollector) ([]v1alpha1.CopiedSecret, error) {
 	secretNames := pipelineRun.GetSpec().Secrets
 	transformers := []secrets.SecretTransformer{
+		maskingValues.transformer(),
 		secrets.StripAnnotationsTransformer(annotationPrefixTekton),
 		secrets.RenameByAnnotationTransformer(v1alpha1.AnnotationSecretRename),
 	}
-	return s.copySecrets(ctx, pipelineRun, secretNames, nil, transformers...)
+	return s.copySecrets(ctx, pipelineRun, v1alpha1.SecretPurposePipeline, secretNames, nil, transformers...)
 }
 
-func (s SecretManager) copySecrets(ctx context.Context, pipelineRun k8s.PipelineRun, secretNames []string, filter secrets.SecretFilter, transformers ...secrets.SecretTransformer) ([]string, error) {
-	storedSecretNames, err := s.secretHelper.CopySecrets(ctx, secretNames, filter, transformers...)
+func (s SecretManager) copySecrets(ctx context.Context, pipelineRun k8s.PipelineRun, purpose v1alpha1.SecretPurpose, secretNames []string, filter secrets.SecretFilter, transformers ...secrets.SecretTransformer) ([]v1alpha1.CopiedSecret, error) {
+	copiedSecrets, err := s.secretHelper.CopySecrets(ctx, secretNames, filter, transformers...)
+	copiedSecrets = withPurpose(purpose, copiedSecrets)
 	if err != nil {
+		return copiedSecrets, s.classifySecretsError(ctx, err, secretNames)
+	}
+	return copiedSecrets, nil
+}
+
+func (s SecretManager) classifySecretsError(ctx context.Context, err error, secretNames []string) error {
+	logger := klog.FromContext(ctx)
+	logger.Error(err, "Could not copy secrets", "secrets", secretNames)
+	if s.secretHelper.IsNotFound(err) || k8serrors.IsInvalid(err) || k8serrors.IsAlreadyExists(err) {
+		return serrors.Classify(err, v1alpha1.ResultErrorConfig)
+	}
+	return serrors.Classify(err, v1alpha1.ResultErrorInfra)
+}
+
+func (s SecretManager) createMaskingSecret(ctx context.Context, maskingValues *maskingValuesCollector) error {
+	secret, err := maskingValues.maskingSecret()
+	if err != nil {
+		return serrors.Classify(err, v1alpha1.ResultErrorInfra)
+	}
+	if _, err = s.secretHelper.CreateSecret(ctx, secret); err != nil {
 		logger := klog.FromContext(ctx)
-		logger.Error(err, "Could not copy secrets", "secrets", secretNames)
-		if s.secretHelper.IsNotFound(err) || k8serrors.IsInvalid(err) || k8serrors.IsAlreadyExists(err) {
-			err = serrors.Classify(err, v1alpha1.ResultErrorConfig)
-		} else {
-			err = serrors.Classify(err, v1alpha1.ResultErrorInfra)
+		logger.Error(err, "Could not create secret masking secret", "secret", MaskingSecretName)
+		if k8serrors.IsAlreadyExists(err) {
+			// a pipeline secret has been renamed to the name of the masking secret
+			return serrors.Classify(err, v1alpha1.ResultErrorConfig)
 		}
-		return storedSecretNames, err
+		return serrors.Classify(err, v1alpha1.ResultErrorInfra)
+	}
+	return nil
+}
+
+func withPurpose(purpose v1alpha1.SecretPurpose, copiedSecrets []v1alpha1.CopiedSecret) []v1alpha1.CopiedSecret {
+	for i := range copiedSecrets {
+		copiedSecrets[i].Purpose = purpose
+	}
+	return copiedSecrets
+}
+
+func targetNames(copiedSecrets []v1alpha1.CopiedSecret) []string {
+	var names []string
+	for _, copiedSecret := range copiedSecrets {
+		names = append(names, copiedSecret.TargetName)
 	}
-	return storedSecretNames, nil
+	return names
 }
@@ -2,6 +2,7 @@ package secretmgr
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"testing"
 
@@ -9,8 +10,11 @@ import (
 	serrors "github.com/SAP/stewardci-core/pkg/errors"
 	mocks "github.com/SAP/stewardci-core/pkg/k8s/mocks"
 	secretMocks "github.com/SAP/stewardci-core/pkg/k8s/secrets/mocks"
+	"github.com/SAP/stewardci-core/pkg/runctl/cfg"
 	gomock "github.com/golang/mock/gomock"
 	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 )
 
 type testHelper struct {
@@ -27,7 +31,7 @@ func newTestHelper(t *testing.T) *testHelper {
 	return &testHelper{
 		t:                                t,
 		ctx:                              context.Background(),
-		pipelineSecretTransormerMatcher:  gomock.Len(2),
+		pipelineSecretTransormerMatcher:  gomock.Len(3),
 		imagePullSecretFilterMatcher:     gomock.Any(),
 		imagePullSecretTransormerMatcher: gomock.Len(4),
 		cloneSecretTransormerMatcher:     gomock.Len(4),
@@ -48,12 +52,18 @@ func newTestHelper(t *testing.T) *testHelper {
 	}
 }
 
+func newSecret(name string, secretType corev1.SecretType) *corev1.Secret {
+	secret := &corev1.Secret{Type: secretType}
+	secret.SetName(name)
+	return secret
+}
+
 func mockPipelineRunWithSpec(th *testHelper) (*gomock.Controller, SecretManager, *mocks.MockPipelineRun, *secretMocks.MockSecretHelper) {
 	mockCtrl := gomock.NewController(th.t)
 
 	mockPipelineRun := mocks.NewMockPipelineRun(mockCtrl)
 	mockSecretHelper := secretMocks.NewMockSecretHelper(mockCtrl)
-	examinee := NewSecretManager(mockSecretHelper)
+	examinee := NewSecretManager(mockSecretHelper, nil, &cfg.PipelineRunsConfigStruct{}, nil)
 
 	// EXPECT
 	mockPipelineRun.EXPECT().GetSpec().Return(th.spec).AnyTimes()
@@ -76,14 +86,91 @@ func Test_copyImagePullSecretsToRunNamespace(t *testing.T) {
 			[]string{"imagePullSecret1", "imagePullSecret2"},
 			th.imagePullSecretFilterMatcher,
 			th.imagePullSecretTransormerMatcher).
-		Return([]string{"imagePullSecret1", "imagePullSecret2"}, nil)
+		Return([]stewardv1alpha1.CopiedSecret{
+			{SourceName: "imagePullSecret1", TargetName: "imagePullSecret1-foo"},
+			{SourceName: "imagePullSecret2", TargetName: "imagePullSecret2-foo"},
+		}, nil)
+
+	// EXERCISE
+	copiedSecrets, err := examinee.copyImagePullSecretsToRunNamespace(th.ctx, mockPipelineRun)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []stewardv1alpha1.CopiedSecret{
+		{Purpose: stewardv1alpha1.SecretPurposeImagePull, SourceName: "imagePullSecret1", TargetName: "imagePullSecret1-foo"},
+		{Purpose: stewardv1alpha1.SecretPurposeImagePull, SourceName: "imagePullSecret2", TargetName: "imagePullSecret2-foo"},
+	}, copiedSecrets)
+}
+
+func Test_copyDefaultImagePullSecretsToRunNamespace_NoneConfigured(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	th := newTestHelper(t)
+	mockCtrl, examinee, _, _ := mockPipelineRunWithSpec(th)
+	defer mockCtrl.Finish()
+
+	// EXERCISE
+	copiedSecrets, err := examinee.copyDefaultImagePullSecretsToRunNamespace(th.ctx)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Assert(t, copiedSecrets == nil)
+}
+
+func Test_copyDefaultImagePullSecretsToRunNamespace_Success(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	th := newTestHelper(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockSystemSecretHelper := secretMocks.NewMockSecretHelper(mockCtrl)
+	examinee := NewSecretManager(nil, mockSystemSecretHelper, &cfg.PipelineRunsConfigStruct{
+		DefaultImagePullSecrets: []string{"defaultPullSecret1"},
+	}, nil)
+
+	// EXPECT
+	mockSystemSecretHelper.EXPECT().
+		CopySecrets(th.ctx, []string{"defaultPullSecret1"}, th.imagePullSecretFilterMatcher, th.imagePullSecretTransormerMatcher).
+		Return([]stewardv1alpha1.CopiedSecret{
+			{SourceName: "defaultPullSecret1", TargetName: "defaultPullSecret1-foo"},
+		}, nil)
 
 	// EXERCISE
-	names, err := examinee.copyImagePullSecretsToRunNamespace(th.ctx, mockPipelineRun)
+	copiedSecrets, err := examinee.copyDefaultImagePullSecretsToRunNamespace(th.ctx)
 
 	// VERIFY
 	assert.NilError(t, err)
-	assert.DeepEqual(t, []string{"imagePullSecret1", "imagePullSecret2"}, names)
+	assert.DeepEqual(t, []stewardv1alpha1.CopiedSecret{
+		{Purpose: stewardv1alpha1.SecretPurposeDefaultImagePull, SourceName: "defaultPullSecret1", TargetName: "defaultPullSecret1-foo"},
+	}, copiedSecrets)
+}
+
+func Test_copyDefaultImagePullSecretsToRunNamespace_FailsWithInfraErrorOnNotFound(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	th := newTestHelper(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockSystemSecretHelper := secretMocks.NewMockSecretHelper(mockCtrl)
+	examinee := NewSecretManager(nil, mockSystemSecretHelper, &cfg.PipelineRunsConfigStruct{
+		DefaultImagePullSecrets: []string{"defaultPullSecret1"},
+	}, nil)
+
+	// EXPECT
+	mockSystemSecretHelper.EXPECT().
+		CopySecrets(th.ctx, []string{"defaultPullSecret1"}, gomock.Any(), gomock.Any()).
+		Return(nil, fmt.Errorf("secret not found: 'defaultPullSecret1'"))
+
+	// EXERCISE
+	copiedSecrets, err := examinee.copyDefaultImagePullSecretsToRunNamespace(th.ctx)
+
+	// VERIFY
+	assert.ErrorContains(t, err, "secret not found: 'defaultPullSecret1'")
+	assert.Equal(t, stewardv1alpha1.ResultErrorInfra, serrors.GetClass(err))
+	assert.Equal(t, 0, len(copiedSecrets))
 }
 
 func Test_copyPipelineCloneSecretToRunNamespace_Success(t *testing.T) {
@@ -95,13 +182,46 @@ func Test_copyPipelineCloneSecretToRunNamespace_Success(t *testing.T) {
 	defer mockCtrl.Finish()
 
 	// VERIFY
+	secret := newSecret("scm_secret1", corev1.SecretTypeBasicAuth)
 	mockPipelineRun.EXPECT().GetValidatedJenkinsfileRepoServerURL().Return("server", nil).AnyTimes()
+	mockSecretHelper.EXPECT().GetSecret(th.ctx, "scm_secret1").Return(secret, nil)
 	mockSecretHelper.EXPECT().
-		CopySecrets(th.ctx, []string{"scm_secret1"}, nil, th.cloneSecretTransormerMatcher).
-		Return([]string{"scm_secret1"}, nil)
+		CopySecret(th.ctx, secret, th.cloneSecretTransormerMatcher).
+		Return(&stewardv1alpha1.CopiedSecret{SourceName: "scm_secret1", TargetName: "scm_secret1"}, nil)
 
 	// EXERCISE
-	examinee.copyPipelineCloneSecretToRunNamespace(th.ctx, mockPipelineRun)
+	copiedSecret, err := examinee.copyPipelineCloneSecretToRunNamespace(th.ctx, mockPipelineRun)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.DeepEqual(t, &stewardv1alpha1.CopiedSecret{
+		Purpose:    stewardv1alpha1.SecretPurposePipelineClone,
+		SourceName: "scm_secret1",
+		TargetName: "scm_secret1",
+	}, copiedSecret)
+}
+
+func Test_copyPipelineCloneSecretToRunNamespace_FailsWithConfigErrorOnNotFound(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	th := newTestHelper(t)
+	mockCtrl, examinee, mockPipelineRun, mockSecretHelper := mockPipelineRunWithSpec(th)
+	defer mockCtrl.Finish()
+
+	expectedError := fmt.Errorf("err1")
+	// EXPECT
+	mockPipelineRun.EXPECT().GetValidatedJenkinsfileRepoServerURL().Return("server", nil).AnyTimes()
+	mockSecretHelper.EXPECT().GetSecret(th.ctx, "scm_secret1").Return(nil, expectedError)
+	mockSecretHelper.EXPECT().IsNotFound(expectedError).Return(true)
+
+	// EXERCISE
+	copiedSecret, err := examinee.copyPipelineCloneSecretToRunNamespace(th.ctx, mockPipelineRun)
+
+	// VERIFY
+	assert.Assert(t, copiedSecret == nil)
+	assert.Equal(t, "err1", err.Error())
+	assert.Equal(t, stewardv1alpha1.ResultErrorConfig, serrors.GetClass(err))
 }
 
 func Test_copyPipelineCloneSecretToRunNamespace_FailsWithConfigErrorOnGetPipelineRepoServerURLError(t *testing.T) {
@@ -133,10 +253,10 @@ func Test_copyPipelineSecretsToRunNamespace(t *testing.T) {
 	// VERIFY
 	mockSecretHelper.EXPECT().
 		CopySecrets(th.ctx, []string{"secret1", "secret2"}, nil, th.pipelineSecretTransormerMatcher).
-		Return([]string{"secret1", "secret2"}, nil)
+		Return([]stewardv1alpha1.CopiedSecret{{SourceName: "secret1"}, {SourceName: "secret2"}}, nil)
 
 	// EXERCISE
-	examinee.copyPipelineSecretsToRunNamespace(th.ctx, mockPipelineRun)
+	examinee.copyPipelineSecretsToRunNamespace(th.ctx, mockPipelineRun, newMaskingValuesCollector(nil))
 
 }
 
@@ -156,7 +276,7 @@ func Test_copySecrets_FailsWithConfigErrorOnNotFound(t *testing.T) {
 		IsNotFound(expectedError).Return(true)
 
 	// EXERCISE
-	_, err := examinee.copySecrets(th.ctx, mockPipelineRun, []string{"foo"}, nil, nil)
+	_, err := examinee.copySecrets(th.ctx, mockPipelineRun, stewardv1alpha1.SecretPurposePipeline, []string{"foo"}, nil, nil)
 
 	// VERIFY
 	assert.Assert(t, err != nil)
@@ -180,10 +300,199 @@ func Test_copySecrets_FailsWithInfraErrorOnOtherError(t *testing.T) {
 		IsNotFound(expectedError).Return(false)
 
 	// EXERCISE
-	_, err := examinee.copySecrets(th.ctx, mockPipelineRun, []string{"foo"}, nil, nil)
+	_, err := examinee.copySecrets(th.ctx, mockPipelineRun, stewardv1alpha1.SecretPurposePipeline, []string{"foo"}, nil, nil)
 
 	// VERIFY
 	assert.Assert(t, err != nil)
 	assert.Equal(t, "err1", err.Error())
 	assert.Equal(t, stewardv1alpha1.ResultErrorInfra, serrors.GetClass(err))
 }
+
+func Test_CopyAll_RecordsCopiedSecretsInStatus(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	th := newTestHelper(t)
+	mockCtrl, examinee, mockPipelineRun, mockSecretHelper := mockPipelineRunWithSpec(th)
+	defer mockCtrl.Finish()
+
+	mockPipelineRun.EXPECT().GetValidatedJenkinsfileRepoServerURL().Return("server", nil).AnyTimes()
+	mockSecretHelper.EXPECT().
+		CopySecrets(th.ctx, []string{"imagePullSecret1", "imagePullSecret2"}, th.imagePullSecretFilterMatcher, th.imagePullSecretTransormerMatcher).
+		Return([]stewardv1alpha1.CopiedSecret{
+			{SourceName: "imagePullSecret1", SourceUID: "uid1", SourceResourceVersion: "1", TargetName: "imagePullSecret1-a", Type: corev1.SecretTypeDockerConfigJson},
+		}, nil)
+	cloneSecret := newSecret("scm_secret1", corev1.SecretTypeBasicAuth)
+	mockSecretHelper.EXPECT().GetSecret(th.ctx, "scm_secret1").Return(cloneSecret, nil)
+	mockSecretHelper.EXPECT().
+		CopySecret(th.ctx, cloneSecret, th.cloneSecretTransormerMatcher).
+		Return(&stewardv1alpha1.CopiedSecret{
+			SourceName: "scm_secret1", SourceUID: "uid2", SourceResourceVersion: "2", TargetName: "scm_secret1-b", Type: corev1.SecretTypeBasicAuth,
+		}, nil)
+	expectedError := fmt.Errorf("err1")
+	mockSecretHelper.EXPECT().
+		CopySecrets(th.ctx, []string{"secret1", "secret2"}, nil, th.pipelineSecretTransormerMatcher).
+		Return([]stewardv1alpha1.CopiedSecret{
+			{SourceName: "secret1", SourceUID: "uid3", SourceResourceVersion: "3", TargetName: "secret1", Type: corev1.SecretTypeOpaque},
+		}, expectedError)
+	mockSecretHelper.EXPECT().IsNotFound(expectedError).Return(true)
+
+	// EXPECT
+	mockPipelineRun.EXPECT().UpdateCopiedSecrets([]stewardv1alpha1.CopiedSecret{
+		{Purpose: stewardv1alpha1.SecretPurposeImagePull, SourceName: "imagePullSecret1", SourceUID: "uid1", SourceResourceVersion: "1", TargetName: "imagePullSecret1-a", Type: corev1.SecretTypeDockerConfigJson},
+		{Purpose: stewardv1alpha1.SecretPurposePipelineClone, SourceName: "scm_secret1", SourceUID: "uid2", SourceResourceVersion: "2", TargetName: "scm_secret1-b", Type: corev1.SecretTypeBasicAuth},
+		{Purpose: stewardv1alpha1.SecretPurposePipeline, SourceName: "secret1", SourceUID: "uid3", SourceResourceVersion: "3", TargetName: "secret1", Type: corev1.SecretTypeOpaque},
+	})
+
+	// EXERCISE
+	_, _, err := examinee.CopyAll(th.ctx, mockPipelineRun)
+
+	// VERIFY
+	assert.ErrorContains(t, err, "failed to copy pipeline secrets: err1")
+	assert.Equal(t, stewardv1alpha1.ResultErrorConfig, serrors.GetClass(err))
+}
+
+func Test_CopyAll_AddsDefaultImagePullSecrets(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	th := newTestHelper(t)
+	th.spec.JenkinsFile.RepoAuthSecret = ""
+	th.spec.Secrets = nil
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockPipelineRun := mocks.NewMockPipelineRun(mockCtrl)
+	mockPipelineRun.EXPECT().GetSpec().Return(th.spec).AnyTimes()
+	mockSecretHelper := secretMocks.NewMockSecretHelper(mockCtrl)
+	mockSystemSecretHelper := secretMocks.NewMockSecretHelper(mockCtrl)
+	examinee := NewSecretManager(mockSecretHelper, mockSystemSecretHelper, &cfg.PipelineRunsConfigStruct{
+		DefaultImagePullSecrets: []string{"defaultPullSecret1"},
+	}, nil)
+
+	// EXPECT
+	mockSecretHelper.EXPECT().
+		CopySecrets(th.ctx, []string{"imagePullSecret1", "imagePullSecret2"}, th.imagePullSecretFilterMatcher, th.imagePullSecretTransormerMatcher).
+		Return([]stewardv1alpha1.CopiedSecret{
+			{SourceName: "imagePullSecret1", TargetName: "imagePullSecret1-a"},
+		}, nil)
+	mockSecretHelper.EXPECT().
+		CopySecrets(th.ctx, nil, nil, th.pipelineSecretTransormerMatcher)
+	mockSystemSecretHelper.EXPECT().
+		CopySecrets(th.ctx, []string{"defaultPullSecret1"}, th.imagePullSecretFilterMatcher, th.imagePullSecretTransormerMatcher).
+		Return([]stewardv1alpha1.CopiedSecret{
+			{SourceName: "defaultPullSecret1", TargetName: "defaultPullSecret1-b"},
+		}, nil)
+	mockPipelineRun.EXPECT().UpdateCopiedSecrets([]stewardv1alpha1.CopiedSecret{
+		{Purpose: stewardv1alpha1.SecretPurposeImagePull, SourceName: "imagePullSecret1", TargetName: "imagePullSecret1-a"},
+		{Purpose: stewardv1alpha1.SecretPurposeDefaultImagePull, SourceName: "defaultPullSecret1", TargetName: "defaultPullSecret1-b"},
+	})
+
+	// EXERCISE
+	cloneSecretName, imagePullSecretNames, err := examinee.CopyAll(th.ctx, mockPipelineRun)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.Equal(t, "", cloneSecretName)
+	assert.DeepEqual(t, []string{"imagePullSecret1-a", "defaultPullSecret1-b"}, imagePullSecretNames)
+}
+
+func Test_CopyAll_CreatesMaskingSecret(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	th := newTestHelper(t)
+	th.spec.JenkinsFile.RepoAuthSecret = ""
+	th.spec.ImagePullSecrets = nil
+	mockCtrl, examinee, mockPipelineRun, mockSecretHelper := mockPipelineRunWithSpec(th)
+	defer mockCtrl.Finish()
+
+	secret1 := &corev1.Secret{Type: corev1.SecretTypeBasicAuth, Data: map[string][]byte{"password": []byte("pwd1")}}
+
+	// EXPECT
+	mockPipelineRun.EXPECT().UpdateCopiedSecrets(gomock.Any())
+	mockSecretHelper.EXPECT().CopySecrets(th.ctx, nil, th.imagePullSecretFilterMatcher, th.imagePullSecretTransormerMatcher)
+	mockSecretHelper.EXPECT().
+		CopySecrets(th.ctx, []string{"secret1", "secret2"}, nil, th.pipelineSecretTransormerMatcher).
+		DoAndReturn(func(_ context.Context, _ []string, _ interface{}, transformers ...func(*corev1.Secret)) ([]stewardv1alpha1.CopiedSecret, error) {
+			for _, transformer := range transformers {
+				transformer(secret1)
+			}
+			return []stewardv1alpha1.CopiedSecret{{SourceName: "secret1"}}, nil
+		})
+	mockSecretHelper.EXPECT().
+		CreateSecret(th.ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, secret *corev1.Secret) (*corev1.Secret, error) {
+			assert.Equal(t, MaskingSecretName, secret.GetName())
+			assert.Equal(t, `["pwd1"]`, string(secret.Data[MaskingSecretKeyValues]))
+			return secret, nil
+		})
+
+	// EXERCISE
+	_, _, err := examinee.CopyAll(th.ctx, mockPipelineRun)
+
+	// VERIFY
+	assert.NilError(t, err)
+}
+
+func Test_CopyAll_NoMaskingSecretWithoutPipelineSecrets(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	th := newTestHelper(t)
+	th.spec.JenkinsFile.RepoAuthSecret = ""
+	th.spec.ImagePullSecrets = nil
+	th.spec.Secrets = nil
+	mockCtrl, examinee, mockPipelineRun, mockSecretHelper := mockPipelineRunWithSpec(th)
+	defer mockCtrl.Finish()
+
+	// EXPECT
+	mockPipelineRun.EXPECT().UpdateCopiedSecrets(gomock.Any())
+	mockSecretHelper.EXPECT().CopySecrets(th.ctx, nil, gomock.Any(), gomock.Any()).Times(2)
+	mockSecretHelper.EXPECT().CreateSecret(gomock.Any(), gomock.Any()).Times(0)
+
+	// EXERCISE
+	_, _, err := examinee.CopyAll(th.ctx, mockPipelineRun)
+
+	// VERIFY
+	assert.NilError(t, err)
+}
+
+func Test_createMaskingSecret_FailsWithConfigErrorOnAlreadyExists(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	th := newTestHelper(t)
+	mockCtrl, examinee, _, mockSecretHelper := mockPipelineRunWithSpec(th)
+	defer mockCtrl.Finish()
+
+	expectedError := k8serrors.NewAlreadyExists(corev1.Resource("secrets"), MaskingSecretName)
+	// EXPECT
+	mockSecretHelper.EXPECT().CreateSecret(th.ctx, gomock.Any()).Return(nil, expectedError)
+
+	// EXERCISE
+	err := examinee.createMaskingSecret(th.ctx, newMaskingValuesCollector(nil))
+
+	// VERIFY
+	assert.Assert(t, errors.Is(err, expectedError))
+	assert.Equal(t, stewardv1alpha1.ResultErrorConfig, serrors.GetClass(err))
+}
+
+func Test_createMaskingSecret_FailsWithInfraErrorOnOtherError(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	th := newTestHelper(t)
+	mockCtrl, examinee, _, mockSecretHelper := mockPipelineRunWithSpec(th)
+	defer mockCtrl.Finish()
+
+	expectedError := fmt.Errorf("err1")
+	// EXPECT
+	mockSecretHelper.EXPECT().CreateSecret(th.ctx, gomock.Any()).Return(nil, expectedError)
+
+	// EXERCISE
+	err := examinee.createMaskingSecret(th.ctx, newMaskingValuesCollector(nil))
+
+	// VERIFY
+	assert.Equal(t, "err1", err.Error())
+	assert.Equal(t, stewardv1alpha1.ResultErrorInfra, serrors.GetClass(err))
+}
@@ -0,0 +1,58 @@
+package secretmgr
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	sshURLPrefix = "ssh://"
+
+	// sshKnownHostsKey is the key of the known hosts data in secrets of
+	// type `kubernetes.io/ssh-auth` as expected by Tekton.
+	sshKnownHostsKey = "known_hosts"
+)
+
+// tektonGitServer returns the value for the Tekton `tekton.dev/git-*`
+// annotation for the given repository server URL as returned by
+// `PipelineRun.GetValidatedJenkinsfileRepoServerURL()`, and whether the
+// server is accessed via SSH.
+// For SSH, Tekton expects the host with optional port but without scheme.
+func tektonGitServer(repoServerURL string) (string, bool) {
+	if strings.HasPrefix(repoServerURL, sshURLPrefix) {
+		return strings.TrimPrefix(repoServerURL, sshURLPrefix), true
+	}
+	return repoServerURL, false
+}
+
+// validateCloneSecretForTransport checks whether the given pipeline clone
+// secret can be used to clone via SSH or HTTP(S), respectively.
+func validateCloneSecretForTransport(secret *corev1.Secret, isSSH bool) error {
+	if !isSSH {
+		if secret.Type == corev1.SecretTypeSSHAuth {
+			return fmt.Errorf(
+				"pipeline clone secret %q is of type %q, which requires an SSH URL in field spec.jenkinsFile.url",
+				secret.GetName(), secret.Type,
+			)
+		}
+		return nil
+	}
+	if isGitHubAppSecret(secret) || secret.Type != corev1.SecretTypeSSHAuth {
+		return fmt.Errorf(
+			"pipeline clone secret %q must be of type %q without credential kind annotation, as field spec.jenkinsFile.url is an SSH URL",
+			secret.GetName(), corev1.SecretTypeSSHAuth,
+		)
+	}
+	if len(secret.Data[corev1.SSHAuthPrivateKey]) == 0 {
+		return fmt.Errorf("pipeline clone secret %q does not contain key %q", secret.GetName(), corev1.SSHAuthPrivateKey)
+	}
+	if strings.TrimSpace(string(secret.Data[sshKnownHostsKey])) == "" {
+		return fmt.Errorf(
+			"pipeline clone secret %q does not contain key %q: host key verification requires the known hosts of the Git server",
+			secret.GetName(), sshKnownHostsKey,
+		)
+	}
+	return nil
+}
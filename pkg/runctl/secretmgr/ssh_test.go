@@ -0,0 +1,196 @@
+package secretmgr
+
+import (
+	"testing"
+
+	stewardv1alpha1 "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	serrors "github.com/SAP/stewardci-core/pkg/errors"
+	"github.com/SAP/stewardci-core/pkg/k8s/secrets"
+	gomock "github.com/golang/mock/gomock"
+	"gotest.tools/v3/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func newSSHAuthSecret(name string) *corev1.Secret {
+	secret := newSecret(name, corev1.SecretTypeSSHAuth)
+	secret.Data = map[string][]byte{
+		corev1.SSHAuthPrivateKey: []byte("key1"),
+		sshKnownHostsKey:         []byte("github.example.com ssh-ed25519 AAAA"),
+	}
+	return secret
+}
+
+func Test_copyPipelineCloneSecretToRunNamespace_SSH_Success(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	th := newTestHelper(t)
+	th.spec.JenkinsFile.URL = "git@github.example.com:owner1/repo1.git"
+	mockCtrl, examinee, mockPipelineRun, mockSecretHelper := mockPipelineRunWithSpec(th)
+	defer mockCtrl.Finish()
+
+	secret := newSSHAuthSecret("scm_secret1")
+
+	// EXPECT
+	mockPipelineRun.EXPECT().GetValidatedJenkinsfileRepoServerURL().Return("ssh://github.example.com:2222", nil).AnyTimes()
+	mockSecretHelper.EXPECT().GetSecret(th.ctx, "scm_secret1").Return(secret, nil)
+	mockSecretHelper.EXPECT().
+		CopySecret(th.ctx, secret, th.cloneSecretTransormerMatcher).
+		DoAndReturn(func(_ interface{}, secret *corev1.Secret, transformers ...secrets.SecretTransformer) (*stewardv1alpha1.CopiedSecret, error) {
+			transformed := secret.DeepCopy()
+			for _, transformer := range transformers {
+				transformer(transformed)
+			}
+			assert.Equal(t, "github.example.com:2222", transformed.GetAnnotations()["tekton.dev/git-0"])
+			return &stewardv1alpha1.CopiedSecret{SourceName: "scm_secret1", TargetName: "scm_secret1-a", Type: secret.Type}, nil
+		})
+
+	// EXERCISE
+	copiedSecret, err := examinee.copyPipelineCloneSecretToRunNamespace(th.ctx, mockPipelineRun)
+
+	// VERIFY
+	assert.NilError(t, err)
+	assert.DeepEqual(t, &stewardv1alpha1.CopiedSecret{
+		Purpose:    stewardv1alpha1.SecretPurposePipelineClone,
+		SourceName: "scm_secret1",
+		TargetName: "scm_secret1-a",
+		Type:       corev1.SecretTypeSSHAuth,
+	}, copiedSecret)
+}
+
+func Test_copyPipelineCloneSecretToRunNamespace_SSH_FailsWithConfigErrorOnInvalidSecret(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	th := newTestHelper(t)
+	th.spec.JenkinsFile.URL = "ssh://git@github.example.com/owner1/repo1.git"
+	mockCtrl, examinee, mockPipelineRun, mockSecretHelper := mockPipelineRunWithSpec(th)
+	defer mockCtrl.Finish()
+
+	secret := newSSHAuthSecret("scm_secret1")
+	delete(secret.Data, sshKnownHostsKey)
+
+	// EXPECT
+	mockPipelineRun.EXPECT().GetValidatedJenkinsfileRepoServerURL().Return("ssh://github.example.com", nil).AnyTimes()
+	mockSecretHelper.EXPECT().GetSecret(th.ctx, "scm_secret1").Return(secret, nil)
+	mockSecretHelper.EXPECT().CopySecret(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	// EXERCISE
+	copiedSecret, err := examinee.copyPipelineCloneSecretToRunNamespace(th.ctx, mockPipelineRun)
+
+	// VERIFY
+	assert.Assert(t, copiedSecret == nil)
+	assert.ErrorContains(t, err, `pipeline clone secret "scm_secret1" does not contain key "known_hosts"`)
+	assert.Equal(t, stewardv1alpha1.ResultErrorConfig, serrors.GetClass(err))
+}
+
+func Test_tektonGitServer(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		repoServerURL  string
+		expectedServer string
+		expectedIsSSH  bool
+	}{
+		{"https://github.example.com", "https://github.example.com", false},
+		{"http://github.example.com:8080", "http://github.example.com:8080", false},
+		{"ssh://github.example.com", "github.example.com", true},
+		{"ssh://github.example.com:2222", "github.example.com:2222", true},
+		{"ssh://[::1]:2222", "[::1]:2222", true},
+	} {
+		t.Run(tc.repoServerURL, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// EXERCISE
+			server, isSSH := tektonGitServer(tc.repoServerURL)
+
+			// VERIFY
+			assert.Equal(t, tc.expectedServer, server)
+			assert.Equal(t, tc.expectedIsSSH, isSSH)
+		})
+	}
+}
+
+func Test_validateCloneSecretForTransport(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name          string
+		isSSH         bool
+		modifySecret  func(*corev1.Secret)
+		expectedError string
+	}{
+		{
+			name:  "ssh_valid",
+			isSSH: true,
+		},
+		{
+			name:  "ssh_basic_auth",
+			isSSH: true,
+			modifySecret: func(secret *corev1.Secret) {
+				secret.Type = corev1.SecretTypeBasicAuth
+			},
+			expectedError: `must be of type "kubernetes.io/ssh-auth"`,
+		},
+		{
+			name:  "ssh_github_app",
+			isSSH: true,
+			modifySecret: func(secret *corev1.Secret) {
+				secret.SetAnnotations(map[string]string{
+					stewardv1alpha1.AnnotationCredentialKind: stewardv1alpha1.CredentialKindGitHubApp,
+				})
+			},
+			expectedError: `must be of type "kubernetes.io/ssh-auth" without credential kind annotation`,
+		},
+		{
+			name:  "ssh_no_private_key",
+			isSSH: true,
+			modifySecret: func(secret *corev1.Secret) {
+				delete(secret.Data, corev1.SSHAuthPrivateKey)
+			},
+			expectedError: `does not contain key "ssh-privatekey"`,
+		},
+		{
+			name:  "ssh_blank_known_hosts",
+			isSSH: true,
+			modifySecret: func(secret *corev1.Secret) {
+				secret.Data[sshKnownHostsKey] = []byte(" \n")
+			},
+			expectedError: `does not contain key "known_hosts"`,
+		},
+		{
+			name:          "http_ssh_auth",
+			isSSH:         false,
+			expectedError: `is of type "kubernetes.io/ssh-auth", which requires an SSH URL`,
+		},
+		{
+			name:  "http_basic_auth",
+			isSSH: false,
+			modifySecret: func(secret *corev1.Secret) {
+				secret.Type = corev1.SecretTypeBasicAuth
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			secret := newSSHAuthSecret("scm_secret1")
+			if tc.modifySecret != nil {
+				tc.modifySecret(secret)
+			}
+
+			// EXERCISE
+			err := validateCloneSecretForTransport(secret, tc.isSSH)
+
+			// VERIFY
+			if tc.expectedError == "" {
+				assert.NilError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tc.expectedError)
+			}
+		})
+	}
+}
@@ -0,0 +1,631 @@
+package sharding
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/SAP/stewardci-core/pkg/runctl/metrics"
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	coordinationv1client "k8s.io/client-go/kubernetes/typed/coordination/v1"
+	klog "k8s.io/klog/v2"
+)
+
+const (
+	// labelMember is the label set on member leases. Its value is the
+	// lease name prefix.
+	labelMember = "steward.sap.com/run-controller-member"
+
+	// drainPollInterval is the interval for checking whether all in-flight
+	// keys have been processed when shutting down.
+	drainPollInterval = 100 * time.Millisecond
+)
+
+// Ownership tells whether keys belong to shards owned by this instance.
+type Ownership interface {
+	// Owns returns true if the given key belongs to a shard currently owned
+	// by this instance that is not being handed over.
+	Owns(key string) bool
+
+	// StartProcessing must be called before a key is processed.
+	// It returns false if the key must not be processed by this instance.
+	// Otherwise it returns a context derived from ctx which must be used
+	// for processing the key, and a function which must be called after
+	// processing. The context is cancelled when the ownership of the
+	// shard of the key ends.
+	StartProcessing(ctx context.Context, key string) (context.Context, func(), bool)
+
+	// SetOnAcquired sets a function that is called after this instance
+	// acquired one or more shards. It is intended to enqueue all keys
+	// of the new shards.
+	SetOnAcquired(onAcquired func())
+}
+
+// Config is the configuration of sharding.
+type Config struct {
+	// Shards is the total number of shards.
+	Shards int
+
+	// LeaseNamePrefix is the prefix of the names of the
+	// `coordination.k8s.io/v1` Lease objects. The shard leases are named
+	// `<prefix>-shard-<index>`, the member leases
+	// `<prefix>-member-<identity>`.
+	LeaseNamePrefix string
+
+	// LeaseNamespace is the namespace of the Lease objects.
+	LeaseNamespace string
+
+	// Identity is the unique identity of this instance, e.g. the pod name.
+	Identity string
+
+	// LeaseDuration is the time other instances wait after the last
+	// observed renewal of a shard lease before taking it over.
+	LeaseDuration time.Duration
+
+	// RenewDeadline is the time after the last successful renewal of a
+	// shard lease after which this instance stops processing keys of
+	// the shard and cancels the contexts of its in-flight keys. It must be
+	// less than LeaseDuration.
+	RenewDeadline time.Duration
+
+	// RetryPeriod is the interval of renewing leases and rebalancing
+	// shards.
+	RetryPeriod time.Duration
+}
+
+// Validate checks the configuration.
+func (c Config) Validate() error {
+	if c.Shards < 1 {
+		return fmt.Errorf("number of shards must be positive but is %d", c.Shards)
+	}
+	if c.LeaseNamePrefix == "" || c.LeaseNamespace == "" || c.Identity == "" {
+		return errors.New("lease name prefix, lease namespace and identity must not be empty")
+	}
+	if c.RetryPeriod <= 0 || c.RenewDeadline <= c.RetryPeriod || c.LeaseDuration <= c.RenewDeadline {
+		return errors.New("retry period, renew deadline and lease duration must be positive and strictly increasing")
+	}
+	return nil
+}
+
+// ShardOf returns the shard the given key belongs to.
+func ShardOf(key string, shards int) int {
+	hash := fnv.New32a()
+	hash.Write([]byte(key))
+	return int(hash.Sum32() % uint32(shards))
+}
+
+// Manager acquires, renews and releases shard leases.
+// It implements Ownership.
+//
+// No key is processed by two instances at the same time:
+// A shard that is handed over to another instance is released only after
+// all of its in-flight keys have been processed.
+// If a shard lease is lost or cannot be renewed within RenewDeadline, the
+// contexts of the in-flight keys of the shard get cancelled, while other
+// instances take over the shard only after LeaseDuration. A lost shard is
+// not acquired again before all of its in-flight keys have been processed.
+type Manager struct {
+	config Config
+	client coordinationv1client.LeasesGetter
+	logger logr.Logger
+	now    func() time.Time
+
+	mutex      sync.Mutex
+	owned      map[int]*ownedShard
+	inFlight   map[int]int
+	observed   map[int]observedLease
+	onAcquired func()
+}
+
+// Compiler check for interface compliance
+var _ Ownership = (*Manager)(nil)
+
+type ownedShard struct {
+	// renewedAt is the local time of the last successful renewal.
+	renewedAt time.Time
+
+	// releasing is true while the shard is handed over.
+	releasing bool
+
+	// lost is true if the lease has been lost or could not be renewed
+	// within RenewDeadline. The shard is forgotten after all of its
+	// in-flight keys have been processed.
+	lost bool
+
+	// ctx is the parent of the contexts of all keys of the shard
+	// processed. It is cancelled when the ownership of the shard ends.
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// expiry cancels ctx if the lease has not been renewed within
+	// RenewDeadline.
+	expiry *time.Timer
+}
+
+// end cancels the context of the shard and stops the expiry timer.
+func (s *ownedShard) end() {
+	s.expiry.Stop()
+	s.cancel()
+}
+
+// observedLease is a lease of another instance as observed locally.
+type observedLease struct {
+	holder     string
+	renewTime  time.Time
+	observedAt time.Time
+}
+
+// NewManager creates a new Manager.
+func NewManager(logger logr.Logger, client coordinationv1client.LeasesGetter, config Config) (*Manager, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Wrap(err, "invalid sharding configuration")
+	}
+	return &Manager{
+		config:   config,
+		client:   client,
+		logger:   logger.WithValues("identity", config.Identity),
+		now:      time.Now,
+		owned:    map[int]*ownedShard{},
+		inFlight: map[int]int{},
+		observed: map[int]observedLease{},
+	}, nil
+}
+
+// SetOnAcquired implements Ownership.
+func (m *Manager) SetOnAcquired(onAcquired func()) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.onAcquired = onAcquired
+}
+
+// Owns implements Ownership.
+func (m *Manager) Owns(key string) bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.ownsShardLocked(ShardOf(key, m.config.Shards))
+}
+
+// StartProcessing implements Ownership.
+func (m *Manager) StartProcessing(ctx context.Context, key string) (context.Context, func(), bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	shard := ShardOf(key, m.config.Shards)
+	if !m.ownsShardLocked(shard) {
+		return nil, nil, false
+	}
+	m.inFlight[shard]++
+
+	ctx, cancel := context.WithCancel(ctx)
+	stop := context.AfterFunc(m.owned[shard].ctx, cancel)
+	done := func() {
+		stop()
+		cancel()
+		m.doneProcessing(shard)
+	}
+	return ctx, done, true
+}
+
+func (m *Manager) doneProcessing(shard int) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if m.inFlight[shard] > 0 {
+		m.inFlight[shard]--
+	}
+}
+
+func (m *Manager) ownsShardLocked(shard int) bool {
+	state, ok := m.owned[shard]
+	return ok && !state.releasing && !state.lost && state.ctx.Err() == nil &&
+		m.now().Sub(state.renewedAt) < m.config.RenewDeadline
+}
+
+// OwnedShards returns the indices of the shards currently owned by this
+// instance, including shards being handed over but excluding lost shards.
+func (m *Manager) OwnedShards() []int {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.ownedShardsLocked(true)
+}
+
+func (m *Manager) ownedShardsLocked(includeReleasing bool) []int {
+	var shards []int
+	for shard, state := range m.owned {
+		if !state.lost && (includeReleasing || !state.releasing) {
+			shards = append(shards, shard)
+		}
+	}
+	sort.Ints(shards)
+	return shards
+}
+
+// Run renews the leases and rebalances the shards periodically until
+// stopCh is closed. Then it waits for in-flight keys to be processed
+// (at most for RenewDeadline) and releases all leases.
+func (m *Manager) Run(stopCh <-chan struct{}) {
+	ctx := klog.NewContext(context.Background(), m.logger)
+	m.logger.Info("Starting sharding", "shards", m.config.Shards)
+	wait.Until(func() { m.sync(ctx) }, m.config.RetryPeriod, stopCh)
+	m.shutdown(ctx)
+}
+
+// sync performs a single renewal and rebalancing step.
+func (m *Manager) sync(ctx context.Context) {
+	if err := m.renewMemberLease(ctx); err != nil {
+		m.logger.Error(err, "Failed to renew member lease")
+	}
+	members, err := m.countMembers(ctx)
+	if err != nil {
+		m.logger.Error(err, "Failed to list member leases")
+		members = 1
+	}
+	desired := (m.config.Shards + members - 1) / members
+
+	m.renewShards(ctx)
+	m.releaseDrainedShards(ctx)
+
+	m.mutex.Lock()
+	active := m.ownedShardsLocked(false)
+	if len(active) > desired {
+		// hand over the shards with the highest indices
+		for _, shard := range active[desired:] {
+			m.logger.Info("Handing over shard", "shard", shard, "members", members, "desiredShards", desired)
+			m.owned[shard].releasing = true
+		}
+	}
+	m.mutex.Unlock()
+	// released immediately if there are no in-flight keys
+	m.releaseDrainedShards(ctx)
+
+	acquired := false
+	for shard := 0; shard < m.config.Shards && len(m.OwnedShards()) < desired; shard++ {
+		m.mutex.Lock()
+		_, isOwned := m.owned[shard]
+		m.mutex.Unlock()
+		if isOwned {
+			continue
+		}
+		ok, err := m.tryAcquire(ctx, shard)
+		if err != nil {
+			m.logger.Error(err, "Failed to acquire shard", "shard", shard)
+			continue
+		}
+		acquired = acquired || ok
+	}
+
+	m.mutex.Lock()
+	metrics.ControllerOwnedShards.Set(float64(len(m.ownedShardsLocked(false))))
+	onAcquired := m.onAcquired
+	m.mutex.Unlock()
+	if acquired && onAcquired != nil {
+		onAcquired()
+	}
+}
+
+func (m *Manager) shardLeaseName(shard int) string {
+	return fmt.Sprintf("%s-shard-%d", m.config.LeaseNamePrefix, shard)
+}
+
+func (m *Manager) memberLeaseName() string {
+	return fmt.Sprintf("%s-member-%s", m.config.LeaseNamePrefix, m.config.Identity)
+}
+
+func (m *Manager) leaseDurationSeconds() *int32 {
+	seconds := int32(m.config.LeaseDuration / time.Second)
+	if seconds < 1 {
+		seconds = 1
+	}
+	return &seconds
+}
+
+func (m *Manager) renewMemberLease(ctx context.Context) error {
+	leases := m.client.Leases(m.config.LeaseNamespace)
+	now := metav1.NewMicroTime(m.now())
+	lease, err := leases.Get(ctx, m.memberLeaseName(), metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		lease = &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      m.memberLeaseName(),
+				Namespace: m.config.LeaseNamespace,
+				Labels:    map[string]string{labelMember: m.config.LeaseNamePrefix},
+			},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity:       &m.config.Identity,
+				LeaseDurationSeconds: m.leaseDurationSeconds(),
+				AcquireTime:          &now,
+				RenewTime:            &now,
+			},
+		}
+		_, err = leases.Create(ctx, lease, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	lease.Spec.RenewTime = &now
+	lease.Spec.LeaseDurationSeconds = m.leaseDurationSeconds()
+	_, err = leases.Update(ctx, lease, metav1.UpdateOptions{})
+	return err
+}
+
+// countMembers returns the number of instances with a member lease that
+// has not expired, including this instance.
+func (m *Manager) countMembers(ctx context.Context) (int, error) {
+	list, err := m.client.Leases(m.config.LeaseNamespace).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", labelMember, m.config.LeaseNamePrefix),
+	})
+	if err != nil {
+		return 0, err
+	}
+	members := map[string]bool{m.config.Identity: true}
+	for _, lease := range list.Items {
+		if lease.Spec.HolderIdentity == nil || lease.Spec.RenewTime == nil || lease.Spec.LeaseDurationSeconds == nil {
+			continue
+		}
+		expiry := lease.Spec.RenewTime.Add(time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second)
+		if m.now().Before(expiry) {
+			members[*lease.Spec.HolderIdentity] = true
+		}
+	}
+	return len(members), nil
+}
+
+// renewShards renews the leases of all owned shards.
+// A shard is lost if its lease is held by another instance or could not be
+// renewed within RenewDeadline. Then the contexts of its in-flight keys
+// get cancelled. The shard is forgotten by releaseDrainedShards after
+// all of its in-flight keys have been processed.
+func (m *Manager) renewShards(ctx context.Context) {
+	for _, shard := range m.OwnedShards() {
+		m.mutex.Lock()
+		state := m.owned[shard]
+		expired := state.ctx.Err() != nil || m.now().Sub(state.renewedAt) >= m.config.RenewDeadline
+		if expired {
+			m.loseShardLocked(shard, state, errors.New("lease has not been renewed within the renew deadline"))
+		}
+		m.mutex.Unlock()
+		if expired {
+			continue
+		}
+
+		err := m.renewShard(ctx, shard)
+		if err == nil {
+			continue
+		}
+		m.mutex.Lock()
+		if errors.Is(err, errLeaseLost) || m.now().Sub(state.renewedAt) >= m.config.RenewDeadline {
+			m.loseShardLocked(shard, state, err)
+		} else {
+			m.logger.V(3).Info("Failed to renew shard lease, will retry", "shard", shard, "error", err.Error())
+		}
+		m.mutex.Unlock()
+	}
+}
+
+var errLeaseLost = errors.New("lease is held by another instance")
+
+func (m *Manager) loseShardLocked(shard int, state *ownedShard, err error) {
+	if state.lost {
+		return
+	}
+	m.logger.Error(err, "Lost shard", "shard", shard, "inFlightKeys", m.inFlight[shard])
+	state.lost = true
+	state.end()
+}
+
+func (m *Manager) renewShard(ctx context.Context, shard int) error {
+	leases := m.client.Leases(m.config.LeaseNamespace)
+	lease, err := leases.Get(ctx, m.shardLeaseName(shard), metav1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return errLeaseLost
+		}
+		return err
+	}
+	if lease.Spec.HolderIdentity == nil || *lease.Spec.HolderIdentity != m.config.Identity {
+		return errLeaseLost
+	}
+	now := m.now()
+	renewTime := metav1.NewMicroTime(now)
+	lease.Spec.RenewTime = &renewTime
+	if _, err = leases.Update(ctx, lease, metav1.UpdateOptions{}); err != nil {
+		return err
+	}
+	m.mutex.Lock()
+	if state, ok := m.owned[shard]; ok && !state.lost {
+		state.renewedAt = now
+		state.expiry.Reset(m.config.RenewDeadline)
+	}
+	m.mutex.Unlock()
+	return nil
+}
+
+// tryAcquire tries to acquire the given shard. It returns true if the
+// shard has been acquired.
+// A shard can be acquired if its lease does not exist, has been released,
+// or has not been renewed for LeaseDuration as observed locally.
+func (m *Manager) tryAcquire(ctx context.Context, shard int) (bool, error) {
+	leases := m.client.Leases(m.config.LeaseNamespace)
+	now := m.now()
+	microNow := metav1.NewMicroTime(now)
+
+	lease, err := leases.Get(ctx, m.shardLeaseName(shard), metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		lease = &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      m.shardLeaseName(shard),
+				Namespace: m.config.LeaseNamespace,
+			},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity:       &m.config.Identity,
+				LeaseDurationSeconds: m.leaseDurationSeconds(),
+				AcquireTime:          &microNow,
+				RenewTime:            &microNow,
+			},
+		}
+		if _, err = leases.Create(ctx, lease, metav1.CreateOptions{}); err != nil {
+			if k8serrors.IsAlreadyExists(err) {
+				return false, nil
+			}
+			return false, err
+		}
+		m.acquired(shard, now)
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	holder := ""
+	if lease.Spec.HolderIdentity != nil {
+		holder = *lease.Spec.HolderIdentity
+	}
+	if holder != "" && holder != m.config.Identity && !m.isExpired(shard, holder, lease.Spec.RenewTime, now) {
+		return false, nil
+	}
+
+	transitions := int32(0)
+	if lease.Spec.LeaseTransitions != nil {
+		transitions = *lease.Spec.LeaseTransitions
+	}
+	if holder != m.config.Identity {
+		transitions++
+	}
+	lease.Spec.HolderIdentity = &m.config.Identity
+	lease.Spec.LeaseDurationSeconds = m.leaseDurationSeconds()
+	lease.Spec.AcquireTime = &microNow
+	lease.Spec.RenewTime = &microNow
+	lease.Spec.LeaseTransitions = &transitions
+	// the update fails with a conflict if another instance has modified
+	// the lease in the meantime
+	if _, err = leases.Update(ctx, lease, metav1.UpdateOptions{}); err != nil {
+		if k8serrors.IsConflict(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	m.acquired(shard, now)
+	return true, nil
+}
+
+// isExpired returns true if the lease of the given shard held by another
+// instance has not been renewed for LeaseDuration as observed locally.
+// Local observation is used to be independent of clock skew between
+// instances.
+func (m *Manager) isExpired(shard int, holder string, renewTime *metav1.MicroTime, now time.Time) bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	observation := observedLease{holder: holder, observedAt: now}
+	if renewTime != nil {
+		observation.renewTime = renewTime.Time
+	}
+	previous, ok := m.observed[shard]
+	if !ok || previous.holder != observation.holder || !previous.renewTime.Equal(observation.renewTime) {
+		m.observed[shard] = observation
+		return false
+	}
+	return now.Sub(previous.observedAt) >= m.config.LeaseDuration
+}
+
+func (m *Manager) acquired(shard int, now time.Time) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	state := &ownedShard{renewedAt: now}
+	state.ctx, state.cancel = context.WithCancel(context.Background())
+	state.expiry = time.AfterFunc(m.config.RenewDeadline, state.cancel)
+	m.owned[shard] = state
+	delete(m.observed, shard)
+	m.logger.Info("Acquired shard", "shard", shard)
+	metrics.ControllerShardChanges.Inc()
+}
+
+// releaseDrainedShards releases the shards being handed over that have no
+// in-flight keys anymore. Lost shards without in-flight keys are
+// forgotten.
+func (m *Manager) releaseDrainedShards(ctx context.Context) {
+	m.mutex.Lock()
+	var drained []int
+	for shard, state := range m.owned {
+		if m.inFlight[shard] > 0 {
+			continue
+		}
+		if state.lost {
+			delete(m.owned, shard)
+			continue
+		}
+		if state.releasing {
+			drained = append(drained, shard)
+			// no new processing can start from now on
+			state.end()
+			delete(m.owned, shard)
+		}
+	}
+	m.mutex.Unlock()
+
+	for _, shard := range drained {
+		if err := m.release(ctx, shard); err != nil {
+			// the lease expires and can be taken over by other instances
+			m.logger.Error(err, "Failed to release shard", "shard", shard)
+			continue
+		}
+		m.logger.Info("Released shard", "shard", shard)
+		metrics.ControllerShardChanges.Inc()
+	}
+}
+
+func (m *Manager) release(ctx context.Context, shard int) error {
+	leases := m.client.Leases(m.config.LeaseNamespace)
+	lease, err := leases.Get(ctx, m.shardLeaseName(shard), metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if lease.Spec.HolderIdentity == nil || *lease.Spec.HolderIdentity != m.config.Identity {
+		return nil
+	}
+	empty := ""
+	lease.Spec.HolderIdentity = &empty
+	_, err = leases.Update(ctx, lease, metav1.UpdateOptions{})
+	return err
+}
+
+// shutdown hands over all shards and deletes the member lease.
+func (m *Manager) shutdown(ctx context.Context) {
+	m.logger.Info("Stopping sharding, handing over all shards")
+	m.mutex.Lock()
+	for _, state := range m.owned {
+		state.releasing = true
+	}
+	m.mutex.Unlock()
+
+	deadline := m.now().Add(m.config.RenewDeadline)
+	for {
+		m.releaseDrainedShards(ctx)
+		remaining := m.OwnedShards()
+		if len(remaining) == 0 {
+			break
+		}
+		if m.now().After(deadline) {
+			m.logger.Info("Shards still have in-flight keys, cancelling them and leaving their leases to expire", "shards", remaining)
+			m.mutex.Lock()
+			for _, state := range m.owned {
+				state.end()
+			}
+			m.mutex.Unlock()
+			break
+		}
+		time.Sleep(drainPollInterval)
+	}
+	metrics.ControllerOwnedShards.Set(0)
+
+	err := m.client.Leases(m.config.LeaseNamespace).Delete(ctx, m.memberLeaseName(), metav1.DeleteOptions{})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		m.logger.Error(err, "Failed to delete member lease")
+	}
+}
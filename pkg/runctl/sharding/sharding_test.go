@@ -0,0 +1,425 @@
+package sharding
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sclientfake "k8s.io/client-go/kubernetes/fake"
+	coordinationv1client "k8s.io/client-go/kubernetes/typed/coordination/v1"
+	klog "k8s.io/klog/v2"
+)
+
+const (
+	testLeaseNamePrefix = "prefix1"
+	testLeaseNamespace  = "namespace1"
+	testShards          = 4
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func (c *fakeClock) Step(d time.Duration) { c.now = c.now.Add(d) }
+
+func newTestConfig(identity string) Config {
+	return Config{
+		Shards:          testShards,
+		LeaseNamePrefix: testLeaseNamePrefix,
+		LeaseNamespace:  testLeaseNamespace,
+		Identity:        identity,
+		LeaseDuration:   15 * time.Second,
+		RenewDeadline:   10 * time.Second,
+		RetryPeriod:     2 * time.Second,
+	}
+}
+
+func newTestManager(t *testing.T, client coordinationv1client.LeasesGetter, clock *fakeClock, identity string) *Manager {
+	t.Helper()
+	m, err := NewManager(klog.Background(), client, newTestConfig(identity))
+	assert.NilError(t, err)
+	m.now = clock.Now
+	return m
+}
+
+// keyOfShard returns a key belonging to the given shard.
+func keyOfShard(t *testing.T, shard int) string {
+	t.Helper()
+	for i := 0; i < 1000; i++ {
+		key := fmt.Sprintf("ns1/run%d", i)
+		if ShardOf(key, testShards) == shard {
+			return key
+		}
+	}
+	t.Fatalf("no key found for shard %d", shard)
+	return ""
+}
+
+func getShardHolder(t *testing.T, client coordinationv1client.LeasesGetter, shard int) string {
+	t.Helper()
+	lease, err := client.Leases(testLeaseNamespace).Get(context.Background(), fmt.Sprintf("%s-shard-%d", testLeaseNamePrefix, shard), metav1.GetOptions{})
+	assert.NilError(t, err)
+	if lease.Spec.HolderIdentity == nil {
+		return ""
+	}
+	return *lease.Spec.HolderIdentity
+}
+
+// assertDisjoint verifies that no key is owned by more than one manager.
+func assertDisjoint(t *testing.T, managers ...*Manager) {
+	t.Helper()
+	for shard := 0; shard < testShards; shard++ {
+		key := keyOfShard(t, shard)
+		owners := 0
+		for _, m := range managers {
+			if m.Owns(key) {
+				owners++
+			}
+		}
+		assert.Assert(t, owners <= 1, "shard %d owned by %d instances", shard, owners)
+	}
+}
+
+// assertCancelled verifies that the given context gets cancelled.
+func assertCancelled(t *testing.T, ctx context.Context) {
+	t.Helper()
+	select {
+	case <-ctx.Done():
+	case <-time.After(10 * time.Second):
+		t.Fatal("timeout waiting for the context to be cancelled")
+	}
+}
+
+func Test_ShardOf(t *testing.T) {
+	t.Parallel()
+
+	for _, key := range []string{"", "ns1/run1", "ns2/run2", "a/b"} {
+		// EXERCISE
+		shard := ShardOf(key, testShards)
+
+		// VERIFY
+		assert.Assert(t, shard >= 0 && shard < testShards)
+		assert.Equal(t, shard, ShardOf(key, testShards))
+	}
+}
+
+func Test_NewManager_InvalidConfig(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name   string
+		modify func(*Config)
+	}{
+		{"no_shards", func(c *Config) { c.Shards = 0 }},
+		{"no_identity", func(c *Config) { c.Identity = "" }},
+		{"renew_deadline_not_less_than_lease_duration", func(c *Config) { c.RenewDeadline = c.LeaseDuration }},
+		{"retry_period_not_less_than_renew_deadline", func(c *Config) { c.RetryPeriod = c.RenewDeadline }},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc := tc // capture current value before going parallel
+			t.Parallel()
+
+			// SETUP
+			config := newTestConfig("instance1")
+			tc.modify(&config)
+
+			// EXERCISE
+			_, err := NewManager(klog.Background(), k8sclientfake.NewSimpleClientset().CoordinationV1(), config)
+
+			// VERIFY
+			assert.ErrorContains(t, err, "invalid sharding configuration")
+		})
+	}
+}
+
+func Test_Manager_sync_SingleInstanceAcquiresAllShards(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	client := k8sclientfake.NewSimpleClientset().CoordinationV1()
+	clock := &fakeClock{now: time.Now()}
+	examinee := newTestManager(t, client, clock, "instance1")
+	acquiredCalls := 0
+	examinee.SetOnAcquired(func() { acquiredCalls++ })
+
+	// EXERCISE
+	examinee.sync(context.Background())
+
+	// VERIFY
+	assert.DeepEqual(t, []int{0, 1, 2, 3}, examinee.OwnedShards())
+	assert.Equal(t, 1, acquiredCalls)
+	for shard := 0; shard < testShards; shard++ {
+		assert.Assert(t, examinee.Owns(keyOfShard(t, shard)))
+		assert.Equal(t, "instance1", getShardHolder(t, client, shard))
+	}
+}
+
+func Test_Manager_sync_RebalancesWithHandover(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	client := k8sclientfake.NewSimpleClientset().CoordinationV1()
+	clock := &fakeClock{now: time.Now()}
+	m1 := newTestManager(t, client, clock, "instance1")
+	m2 := newTestManager(t, client, clock, "instance2")
+	ctx := context.Background()
+
+	m1.sync(ctx)
+	inFlightKey := keyOfShard(t, 3)
+	inFlightCtx, done, ok := m1.StartProcessing(ctx, inFlightKey)
+	assert.Assert(t, ok)
+
+	// EXERCISE and VERIFY
+
+	// instance2 joins but all shards are held by instance1
+	clock.Step(time.Second)
+	m2.sync(ctx)
+	assert.Equal(t, 0, len(m2.OwnedShards()))
+	assertDisjoint(t, m1, m2)
+
+	// instance1 hands over shards 2 and 3, shard 3 has an in-flight key
+	clock.Step(time.Second)
+	m1.sync(ctx)
+	assert.DeepEqual(t, []int{0, 1, 3}, m1.OwnedShards())
+	assert.Assert(t, !m1.Owns(inFlightKey))
+	_, _, ok = m1.StartProcessing(ctx, inFlightKey)
+	assert.Assert(t, !ok)
+	// handover waits for the in-flight key without cancelling it
+	assert.NilError(t, inFlightCtx.Err())
+	assert.Equal(t, "", getShardHolder(t, client, 2))
+	assert.Equal(t, "instance1", getShardHolder(t, client, 3))
+
+	clock.Step(time.Second)
+	m2.sync(ctx)
+	assert.DeepEqual(t, []int{2}, m2.OwnedShards())
+	assertDisjoint(t, m1, m2)
+
+	// in-flight key finished, shard 3 can be released
+	done()
+	clock.Step(time.Second)
+	m1.sync(ctx)
+	assert.DeepEqual(t, []int{0, 1}, m1.OwnedShards())
+
+	clock.Step(time.Second)
+	m2.sync(ctx)
+	assert.DeepEqual(t, []int{2, 3}, m2.OwnedShards())
+	assert.Assert(t, m2.Owns(inFlightKey))
+	assertDisjoint(t, m1, m2)
+}
+
+func Test_Manager_sync_TakesOverExpiredShard(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	client := k8sclientfake.NewSimpleClientset().CoordinationV1()
+	clock := &fakeClock{now: time.Now()}
+	dead := newTestManager(t, client, clock, "dead")
+	dead.sync(context.Background())
+	clock.Step(time.Minute)
+	examinee := newTestManager(t, client, clock, "instance1")
+	ctx := context.Background()
+
+	// EXERCISE and VERIFY
+	examinee.sync(ctx)
+	assert.Equal(t, 0, len(examinee.OwnedShards()), "leases must be observed for the lease duration")
+
+	clock.Step(10 * time.Second)
+	examinee.sync(ctx)
+	assert.Equal(t, 0, len(examinee.OwnedShards()))
+
+	clock.Step(5 * time.Second)
+	examinee.sync(ctx)
+	assert.DeepEqual(t, []int{0, 1, 2, 3}, examinee.OwnedShards())
+	assert.Equal(t, "instance1", getShardHolder(t, client, 0))
+}
+
+func Test_Manager_sync_LostShard(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	client := k8sclientfake.NewSimpleClientset().CoordinationV1()
+	clock := &fakeClock{now: time.Now()}
+	examinee := newTestManager(t, client, clock, "instance1")
+	ctx := context.Background()
+	examinee.sync(ctx)
+
+	lease, err := client.Leases(testLeaseNamespace).Get(ctx, testLeaseNamePrefix+"-shard-1", metav1.GetOptions{})
+	assert.NilError(t, err)
+	other := "other"
+	lease.Spec.HolderIdentity = &other
+	_, err = client.Leases(testLeaseNamespace).Update(ctx, lease, metav1.UpdateOptions{})
+	assert.NilError(t, err)
+
+	// EXERCISE
+	clock.Step(time.Second)
+	examinee.sync(ctx)
+
+	// VERIFY
+	assert.DeepEqual(t, []int{0, 2, 3}, examinee.OwnedShards())
+	assert.Assert(t, !examinee.Owns(keyOfShard(t, 1)))
+}
+
+func Test_Manager_sync_LostShard_CancelsInFlightKey(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	client := k8sclientfake.NewSimpleClientset().CoordinationV1()
+	clock := &fakeClock{now: time.Now()}
+	examinee := newTestManager(t, client, clock, "instance1")
+	ctx := context.Background()
+	examinee.sync(ctx)
+
+	key := keyOfShard(t, 1)
+	keyCtx, done, ok := examinee.StartProcessing(ctx, key)
+	assert.Assert(t, ok)
+
+	setShardHolder := func(holder string) {
+		lease, err := client.Leases(testLeaseNamespace).Get(ctx, testLeaseNamePrefix+"-shard-1", metav1.GetOptions{})
+		assert.NilError(t, err)
+		lease.Spec.HolderIdentity = &holder
+		_, err = client.Leases(testLeaseNamespace).Update(ctx, lease, metav1.UpdateOptions{})
+		assert.NilError(t, err)
+	}
+
+	// EXERCISE and VERIFY
+
+	// another instance took over the shard
+	setShardHolder("other")
+	clock.Step(time.Second)
+	examinee.sync(ctx)
+	assertCancelled(t, keyCtx)
+	assert.Assert(t, !examinee.Owns(key))
+
+	// the shard is not acquired again while the key is still in flight
+	setShardHolder("")
+	clock.Step(time.Second)
+	examinee.sync(ctx)
+	assert.DeepEqual(t, []int{0, 2, 3}, examinee.OwnedShards())
+	assert.Equal(t, "", getShardHolder(t, client, 1))
+
+	// the shard can be acquired again after the key has been processed
+	done()
+	clock.Step(time.Second)
+	examinee.sync(ctx)
+	assert.DeepEqual(t, []int{0, 1, 2, 3}, examinee.OwnedShards())
+	keyCtx, done, ok = examinee.StartProcessing(ctx, key)
+	assert.Assert(t, ok)
+	assert.NilError(t, keyCtx.Err())
+	done()
+}
+
+func Test_Manager_sync_RenewDeadlineExceeded_CancelsInFlightKey(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	client := k8sclientfake.NewSimpleClientset().CoordinationV1()
+	clock := &fakeClock{now: time.Now()}
+	examinee := newTestManager(t, client, clock, "instance1")
+	ctx := context.Background()
+	examinee.sync(ctx)
+	keyCtx, done, ok := examinee.StartProcessing(ctx, keyOfShard(t, 0))
+	assert.Assert(t, ok)
+	defer done()
+
+	// EXERCISE
+	// e.g. the instance was not scheduled for a while
+	clock.Step(10 * time.Second)
+	examinee.sync(ctx)
+
+	// VERIFY
+	assertCancelled(t, keyCtx)
+	// shards without in-flight keys are acquired again immediately
+	assert.DeepEqual(t, []int{1, 2, 3}, examinee.OwnedShards())
+	assert.Assert(t, !examinee.Owns(keyOfShard(t, 0)))
+}
+
+func Test_Manager_StartProcessing_ContextCancelledAtRenewDeadline(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	client := k8sclientfake.NewSimpleClientset().CoordinationV1()
+	config := newTestConfig("instance1")
+	config.RetryPeriod = 10 * time.Millisecond
+	config.RenewDeadline = 50 * time.Millisecond
+	config.LeaseDuration = time.Second
+	examinee, err := NewManager(klog.Background(), client, config)
+	assert.NilError(t, err)
+	ctx := context.Background()
+	examinee.sync(ctx)
+
+	// EXERCISE
+	// no renewal after starting processing
+	keyCtx, done, ok := examinee.StartProcessing(ctx, keyOfShard(t, 0))
+	assert.Assert(t, ok)
+	defer done()
+
+	// VERIFY
+	assertCancelled(t, keyCtx)
+	assert.Assert(t, !examinee.Owns(keyOfShard(t, 0)))
+}
+
+func Test_Manager_Owns_FalseAfterRenewDeadline(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	client := k8sclientfake.NewSimpleClientset().CoordinationV1()
+	clock := &fakeClock{now: time.Now()}
+	examinee := newTestManager(t, client, clock, "instance1")
+	examinee.sync(context.Background())
+	key := keyOfShard(t, 0)
+	assert.Assert(t, examinee.Owns(key))
+
+	// EXERCISE
+	clock.Step(10 * time.Second)
+
+	// VERIFY
+	assert.Assert(t, !examinee.Owns(key))
+	_, _, ok := examinee.StartProcessing(context.Background(), key)
+	assert.Assert(t, !ok)
+}
+
+func Test_Manager_Run_ReleasesLeasesOnStop(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	client := k8sclientfake.NewSimpleClientset().CoordinationV1()
+	config := newTestConfig("instance1")
+	config.RetryPeriod = 10 * time.Millisecond
+	examinee, err := NewManager(klog.Background(), client, config)
+	assert.NilError(t, err)
+	acquired := make(chan struct{})
+	examinee.SetOnAcquired(func() { close(acquired) })
+	stopCh := make(chan struct{})
+	done := make(chan struct{})
+
+	// EXERCISE
+	go func() {
+		defer close(done)
+		examinee.Run(stopCh)
+	}()
+	select {
+	case <-acquired:
+	case <-time.After(10 * time.Second):
+		t.Fatal("timeout waiting for shards to be acquired")
+	}
+	close(stopCh)
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("timeout waiting for Run to return")
+	}
+
+	// VERIFY
+	assert.Equal(t, 0, len(examinee.OwnedShards()))
+	for shard := 0; shard < testShards; shard++ {
+		assert.Equal(t, "", getShardHolder(t, client, shard))
+	}
+	_, err = client.Leases(testLeaseNamespace).Get(context.Background(), testLeaseNamePrefix+"-member-instance1", metav1.GetOptions{})
+	assert.Assert(t, k8serrors.IsNotFound(err))
+}
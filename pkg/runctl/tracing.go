@@ -0,0 +1,150 @@
+package runctl
+
+import (
+	"context"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	"github.com/SAP/stewardci-core/pkg/k8s"
+	"github.com/SAP/stewardci-core/pkg/runctl/cfg"
+	run "github.com/SAP/stewardci-core/pkg/runctl/run"
+	"github.com/SAP/stewardci-core/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	klog "k8s.io/klog/v2"
+)
+
+const (
+	// spanNamePipelineRun is the name of the root span of the trace of a
+	// pipeline run.
+	spanNamePipelineRun = "PipelineRun"
+
+	// spanNameReconcile is the name of the span covering one
+	// reconciliation of a pipeline run.
+	spanNameReconcile = "Reconcile"
+
+	// spanNameCommitStatus is the name of the span covering the commit of
+	// the pipeline run status.
+	spanNameCommitStatus = "CommitStatus"
+
+	// spanNamePrefixRunManager is the prefix of the names of spans
+	// covering calls of run.Manager methods.
+	spanNamePrefixRunManager = "run.Manager/"
+)
+
+// startReconcileSpan starts the span covering one reconciliation of the
+// given pipeline run.
+//
+// All reconciliations of a pipeline run are recorded in the same trace.
+// The trace is identified by the W3C trace context stored in annotation
+// `AnnotationTraceParent` of the pipeline run. If the annotation does not
+// exist yet and tracing is enabled, a root span is recorded and its trace
+// context gets stored in the annotation. The root span is recorded only
+// if the annotation has been stored, so that each pipeline run has a
+// single trace. Otherwise the failure is logged and the reconciliation is
+// not traced, but does not fail.
+//
+// There must not be any pending changes of the pipeline run.
+func startReconcileSpan(ctx context.Context, pipelineRun k8s.PipelineRun) (context.Context, trace.Span) {
+	attributes := []attribute.KeyValue{
+		attribute.String("steward.pipelinerun.namespace", pipelineRun.GetNamespace()),
+		attribute.String("steward.pipelinerun.name", pipelineRun.GetName()),
+	}
+
+	traceParent, found := pipelineRun.GetAPIObject().GetAnnotations()[api.AnnotationTraceParent]
+	if !found && pipelineRun.GetStatus().State != api.StateFinished {
+		rootCtx, rootSpan := tracing.Start(context.Background(), spanNamePipelineRun, attributes...)
+		if !rootSpan.SpanContext().IsSampled() {
+			rootSpan.End()
+			return tracing.ContextWithoutSampling(ctx, rootSpan.SpanContext())
+		}
+		traceParent = tracing.TraceParent(rootCtx)
+		err := pipelineRun.AddAnnotationAndCommitIfNotPresent(ctx, api.AnnotationTraceParent, traceParent)
+		if err != nil {
+			// The root span is not ended and therefore never recorded.
+			// The next reconciliation starts a new trace.
+			logger := klog.FromContext(ctx)
+			logger.V(3).Info("Failed to store trace context at pipeline run", "err", err.Error())
+			return tracing.ContextWithoutSampling(ctx, rootSpan.SpanContext())
+		}
+		rootSpan.End()
+	}
+
+	ctx = tracing.ContextWithTraceParent(ctx, traceParent)
+	return tracing.Start(ctx, spanNameReconcile, append(attributes,
+		attribute.String("steward.pipelinerun.state", string(pipelineRun.GetStatus().State)),
+	)...)
+}
+
+// traceHandler calls the given reconciliation handler within a span with
+// the given name.
+func traceHandler(ctx context.Context, name string, handler func(context.Context) (bool, error)) (bool, error) {
+	ctx, span := tracing.Start(ctx, name)
+	doReturn, err := handler(ctx)
+	span.SetAttributes(attribute.Bool("steward.handler.return", doReturn))
+	tracing.End(span, err)
+	return doReturn, err
+}
+
+// tracingRunManager is a run.Manager recording a span for each call
+// delegated to another run.Manager.
+type tracingRunManager struct {
+	delegate run.Manager
+}
+
+var _ run.Manager = (*tracingRunManager)(nil)
+
+// CreateEnv implements run.Manager.
+func (m *tracingRunManager) CreateEnv(ctx context.Context, pipelineRun k8s.PipelineRun, pipelineRunsConfig *cfg.PipelineRunsConfigStruct) (string, string, error) {
+	ctx, span := tracing.Start(ctx, spanNamePrefixRunManager+"CreateEnv")
+	namespace, auxNamespace, err := m.delegate.CreateEnv(ctx, pipelineRun, pipelineRunsConfig)
+	tracing.End(span, err)
+	return namespace, auxNamespace, err
+}
+
+// CreateRun implements run.Manager.
+func (m *tracingRunManager) CreateRun(ctx context.Context, pipelineRun k8s.PipelineRun, pipelineRunsConfig *cfg.PipelineRunsConfigStruct) error {
+	ctx, span := tracing.Start(ctx, spanNamePrefixRunManager+"CreateRun")
+	err := m.delegate.CreateRun(ctx, pipelineRun, pipelineRunsConfig)
+	tracing.End(span, err)
+	return err
+}
+
+// GetRun implements run.Manager.
+func (m *tracingRunManager) GetRun(ctx context.Context, pipelineRun k8s.PipelineRun) (run.Run, error) {
+	ctx, span := tracing.Start(ctx, spanNamePrefixRunManager+"GetRun")
+	result, err := m.delegate.GetRun(ctx, pipelineRun)
+	tracing.End(span, err)
+	return result, err
+}
+
+// GetPendingReason implements run.Manager.
+func (m *tracingRunManager) GetPendingReason(ctx context.Context, pipelineRun k8s.PipelineRun) (*run.PendingReason, error) {
+	ctx, span := tracing.Start(ctx, spanNamePrefixRunManager+"GetPendingReason")
+	result, err := m.delegate.GetPendingReason(ctx, pipelineRun)
+	tracing.End(span, err)
+	return result, err
+}
+
+// GetLogTail implements run.Manager.
+func (m *tracingRunManager) GetLogTail(ctx context.Context, pipelineRun k8s.PipelineRun, maxLines, maxBytes int64) (string, error) {
+	ctx, span := tracing.Start(ctx, spanNamePrefixRunManager+"GetLogTail")
+	result, err := m.delegate.GetLogTail(ctx, pipelineRun, maxLines, maxBytes)
+	tracing.End(span, err)
+	return result, err
+}
+
+// DeleteRun implements run.Manager.
+func (m *tracingRunManager) DeleteRun(ctx context.Context, pipelineRun k8s.PipelineRun) error {
+	ctx, span := tracing.Start(ctx, spanNamePrefixRunManager+"DeleteRun")
+	err := m.delegate.DeleteRun(ctx, pipelineRun)
+	tracing.End(span, err)
+	return err
+}
+
+// DeleteEnv implements run.Manager.
+func (m *tracingRunManager) DeleteEnv(ctx context.Context, pipelineRun k8s.PipelineRun) error {
+	ctx, span := tracing.Start(ctx, spanNamePrefixRunManager+"DeleteEnv")
+	err := m.delegate.DeleteEnv(ctx, pipelineRun)
+	tracing.End(span, err)
+	return err
+}
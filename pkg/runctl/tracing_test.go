@@ -0,0 +1,250 @@
+package runctl
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	api "github.com/SAP/stewardci-core/pkg/apis/steward/v1alpha1"
+	fake "github.com/SAP/stewardci-core/pkg/k8s/fake"
+	runmocks "github.com/SAP/stewardci-core/pkg/runctl/run/mocks"
+	"github.com/SAP/stewardci-core/pkg/tracing"
+	gomock "github.com/golang/mock/gomock"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	assert "gotest.tools/v3/assert"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func patchWithInMemoryExporter(t *testing.T) *tracetest.InMemoryExporter {
+	t.Helper()
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	t.Cleanup(tracing.Testing{}.PatchTracerProvider(provider))
+	return exporter
+}
+
+func spansByName(spans tracetest.SpanStubs) map[string]tracetest.SpanStub {
+	result := map[string]tracetest.SpanStub{}
+	for _, span := range spans {
+		result[span.Name] = span
+	}
+	return result
+}
+
+func Test__Controller_syncHandler__Tracing_NewTrace(t *testing.T) {
+	// no parallel: patching global tracer provider
+
+	// SETUP
+	exporter := patchWithInMemoryExporter(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	pipelineRun := fake.PipelineRun("foo", "ns1", api.PipelineSpec{})
+	pipelineRun.Status.State = api.StatePreparing
+
+	controller, cf := newController(t, pipelineRun)
+
+	runManager := runmocks.NewMockManager(mockCtrl)
+	runManager.EXPECT().
+		CreateEnv(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return("runNamespace1", "", nil)
+
+	controller.testing = &controllerTesting{
+		createRunManagerStub:       newSimpleCreateRunManagerStub(runManager),
+		loadPipelineRunsConfigStub: newEmptyRunsConfig,
+		getMaintenanceStatusStub:   newMaintenanceStatusStub(false, nil),
+	}
+
+	// EXERCISE
+	resultErr := controller.syncHandler(context.Background(), "ns1/foo")
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+
+	spans := spansByName(exporter.GetSpans())
+	for _, name := range []string{
+		"PipelineRun",
+		"Reconcile",
+		"handlePipelineRunFinalizerAndDeletion",
+		"handlePipelineRunNew",
+		"handlePipelineRunPrepare",
+		"run.Manager/CreateEnv",
+		"CommitStatus",
+	} {
+		span, found := spans[name]
+		assert.Assert(t, found, "span %q not recorded", name)
+		assert.Equal(t, spans["PipelineRun"].SpanContext.TraceID(), span.SpanContext.TraceID(), name)
+	}
+	assert.Equal(t, spans["PipelineRun"].SpanContext.SpanID(), spans["Reconcile"].Parent.SpanID())
+	assert.Equal(t, spans["Reconcile"].SpanContext.SpanID(), spans["handlePipelineRunPrepare"].Parent.SpanID())
+	assert.Equal(t, spans["handlePipelineRunPrepare"].SpanContext.SpanID(), spans["run.Manager/CreateEnv"].Parent.SpanID())
+	_, found := spans["handlePipelineRunWaiting"]
+	assert.Assert(t, !found)
+
+	result, err := getAPIPipelineRun(cf, "foo", "ns1")
+	assert.NilError(t, err)
+	traceParent := result.GetAnnotations()[api.AnnotationTraceParent]
+	assert.Equal(t,
+		"00-"+spans["PipelineRun"].SpanContext.TraceID().String()+"-"+spans["PipelineRun"].SpanContext.SpanID().String()+"-01",
+		traceParent,
+	)
+}
+
+func Test__Controller_syncHandler__Tracing_ExistingTrace(t *testing.T) {
+	// no parallel: patching global tracer provider
+
+	// SETUP
+	exporter := patchWithInMemoryExporter(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	const traceParent = "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"
+	pipelineRun := fake.PipelineRun("foo", "ns1", api.PipelineSpec{})
+	pipelineRun.Status.State = api.StatePreparing
+	pipelineRun.SetAnnotations(map[string]string{api.AnnotationTraceParent: traceParent})
+
+	controller, cf := newController(t, pipelineRun)
+
+	error1 := errors.New("error1")
+	runManager := runmocks.NewMockManager(mockCtrl)
+	runManager.EXPECT().
+		CreateEnv(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return("", "", error1)
+
+	controller.testing = &controllerTesting{
+		createRunManagerStub:       newSimpleCreateRunManagerStub(runManager),
+		loadPipelineRunsConfigStub: newEmptyRunsConfig,
+		getMaintenanceStatusStub:   newMaintenanceStatusStub(false, nil),
+	}
+
+	// EXERCISE
+	controller.syncHandler(context.Background(), "ns1/foo")
+
+	// VERIFY
+	spans := spansByName(exporter.GetSpans())
+	_, found := spans["PipelineRun"]
+	assert.Assert(t, !found)
+	reconcileSpan := spans["Reconcile"]
+	assert.Equal(t, "0af7651916cd43dd8448eb211c80319c", reconcileSpan.SpanContext.TraceID().String())
+	assert.Equal(t, "b7ad6b7169203331", reconcileSpan.Parent.SpanID().String())
+	assert.Assert(t, reconcileSpan.Parent.IsRemote())
+
+	createEnvSpan := spans["run.Manager/CreateEnv"]
+	assert.Equal(t, codes.Error, createEnvSpan.Status.Code)
+	assert.Equal(t, "error1", createEnvSpan.Status.Description)
+
+	result, err := getAPIPipelineRun(cf, "foo", "ns1")
+	assert.NilError(t, err)
+	assert.Equal(t, traceParent, result.GetAnnotations()[api.AnnotationTraceParent])
+}
+
+func Test__Controller_syncHandler__Tracing_TraceContextNotStored(t *testing.T) {
+	// no parallel: patching global tracer provider
+
+	// SETUP
+	exporter := patchWithInMemoryExporter(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	pipelineRun := fake.PipelineRun("foo", "ns1", api.PipelineSpec{})
+	pipelineRun.Status.State = api.StatePreparing
+
+	controller, cf := newController(t, pipelineRun)
+	// fail the first update only, which stores the trace context
+	failed := false
+	cf.StewardClientset().PrependReactor("update", "pipelineruns", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if failed || action.GetSubresource() != "" {
+			return false, nil, nil
+		}
+		failed = true
+		return true, nil, errors.New("update error1")
+	})
+
+	runManager := runmocks.NewMockManager(mockCtrl)
+	runManager.EXPECT().
+		CreateEnv(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return("runNamespace1", "", nil)
+
+	controller.testing = &controllerTesting{
+		createRunManagerStub:       newSimpleCreateRunManagerStub(runManager),
+		loadPipelineRunsConfigStub: newEmptyRunsConfig,
+		getMaintenanceStatusStub:   newMaintenanceStatusStub(false, nil),
+	}
+
+	// EXERCISE
+	resultErr := controller.syncHandler(context.Background(), "ns1/foo")
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	assert.Assert(t, failed)
+	assert.Equal(t, 0, len(exporter.GetSpans()))
+
+	result, err := getAPIPipelineRun(cf, "foo", "ns1")
+	assert.NilError(t, err)
+	_, found := result.GetAnnotations()[api.AnnotationTraceParent]
+	assert.Assert(t, !found)
+	assert.Equal(t, api.StateWaiting, result.Status.State)
+}
+
+func Test__Controller_syncHandler__Tracing_Disabled(t *testing.T) {
+	t.Parallel()
+
+	// SETUP
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	pipelineRun := fake.PipelineRun("foo", "ns1", api.PipelineSpec{})
+	pipelineRun.Status.State = api.StatePreparing
+
+	controller, cf := newController(t, pipelineRun)
+
+	runManager := runmocks.NewMockManager(mockCtrl)
+	runManager.EXPECT().
+		CreateEnv(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return("runNamespace1", "", nil)
+
+	controller.testing = &controllerTesting{
+		createRunManagerStub:       newSimpleCreateRunManagerStub(runManager),
+		loadPipelineRunsConfigStub: newEmptyRunsConfig,
+		getMaintenanceStatusStub:   newMaintenanceStatusStub(false, nil),
+	}
+
+	// EXERCISE
+	resultErr := controller.syncHandler(context.Background(), "ns1/foo")
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	result, err := getAPIPipelineRun(cf, "foo", "ns1")
+	assert.NilError(t, err)
+	_, found := result.GetAnnotations()[api.AnnotationTraceParent]
+	assert.Assert(t, !found)
+}
+
+func Test_tracingRunManager_GetRun(t *testing.T) {
+	// no parallel: patching global tracer provider
+
+	// SETUP
+	exporter := patchWithInMemoryExporter(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	ctx := context.Background()
+	run1 := runmocks.NewMockRun(mockCtrl)
+	delegate := runmocks.NewMockManager(mockCtrl)
+	delegate.EXPECT().GetRun(gomock.Any(), nil).Return(run1, nil)
+	examinee := &tracingRunManager{delegate: delegate}
+
+	// EXERCISE
+	result, resultErr := examinee.GetRun(ctx, nil)
+
+	// VERIFY
+	assert.NilError(t, resultErr)
+	assert.Equal(t, run1, result)
+	spans := exporter.GetSpans()
+	assert.Equal(t, 1, len(spans))
+	assert.Equal(t, "run.Manager/GetRun", spans[0].Name)
+	assert.Equal(t, codes.Unset, spans[0].Status.Code)
+}
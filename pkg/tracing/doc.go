@@ -0,0 +1,21 @@
+/*
+Package tracing provides OpenTelemetry tracing support shared among all
+packages in this Go module:
+
+  - the tracer provider and exporting spans via OTLP
+  - propagating trace contexts via annotation values
+  - helpers to record spans
+
+# Global State
+
+The API of this package makes use of global state to get access to the
+tracer provider so that keeping and passing references is not necessary.
+Without initialization (see Init) a no-op tracer provider is used, i.e.
+spans are not recorded.
+
+For testing, the tracer provider can be patched, e.g. with one using an
+in-memory exporter. Be aware that tests patching global state must not run
+concurrently to other tests to avoid interference. See the Testing type for
+test support.
+*/
+package tracing
@@ -0,0 +1,22 @@
+package tracing
+
+import "go.opentelemetry.io/otel/trace"
+
+// Testing provides utility functions for testing with this package.
+// Do not use it for non-testing purposes!
+type Testing struct{}
+
+// PatchTracerProvider replaces the tracer provider with a replacement and
+// returns a function that reverts the patch.
+// Multiple nested replacements must be reverted in exactly the opposite
+// order (revert last replacement first).
+func (Testing) PatchTracerProvider(replacement trace.TracerProvider) func() {
+	origValue := tracerProvider
+	tracerProvider = replacement
+	return func() {
+		if tracerProvider != replacement {
+			panic("reverting not possible because current value is not the former replacement")
+		}
+		tracerProvider = origValue
+	}
+}
@@ -0,0 +1,123 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+const (
+	// InstrumentationName is the name of the instrumentation scope of all
+	// spans recorded by this module.
+	InstrumentationName = "github.com/SAP/stewardci-core"
+
+	traceParentKey = "traceparent"
+)
+
+var (
+	tracerProvider trace.TracerProvider = noop.NewTracerProvider()
+	propagator                          = propagation.TraceContext{}
+)
+
+// Options are the options for Init.
+type Options struct {
+	// ServiceName is the name of the service recording spans.
+	ServiceName string
+
+	// OTLPEndpoint is the host and optional port of the OTLP/HTTP endpoint
+	// spans are exported to, e.g. "otel-collector:4318".
+	OTLPEndpoint string
+
+	// Insecure disables TLS for the connection to the OTLP endpoint.
+	Insecure bool
+}
+
+// Init sets up a tracer provider exporting spans via OTLP/HTTP as
+// configured by the given options. It returns a function that flushes
+// pending spans and shuts down the tracer provider.
+func Init(ctx context.Context, opts Options) (func(context.Context) error, error) {
+	exporterOpts := []otlptracehttp.Option{
+		otlptracehttp.WithEndpoint(opts.OTLPEndpoint),
+	}
+	if opts.Insecure {
+		exporterOpts = append(exporterOpts, otlptracehttp.WithInsecure())
+	}
+	exporter, err := otlptracehttp.New(ctx, exporterOpts...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create OTLP trace exporter")
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(
+			semconv.ServiceName(opts.ServiceName),
+		)),
+	)
+	tracerProvider = provider
+	return provider.Shutdown, nil
+}
+
+// Tracer returns the tracer to record spans with.
+func Tracer() trace.Tracer {
+	return tracerProvider.Tracer(InstrumentationName)
+}
+
+// Start starts a span with the given name and attributes as child of the
+// span in ctx, if any.
+func Start(ctx context.Context, name string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, name, trace.WithAttributes(attributes...))
+}
+
+// End ends the given span. If err is not nil, it is recorded and the
+// span status is set to error.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Trace calls fn within a span with the given name.
+func Trace(ctx context.Context, name string, fn func(ctx context.Context) error) error {
+	ctx, span := Start(ctx, name)
+	err := fn(ctx)
+	End(span, err)
+	return err
+}
+
+// TraceParent returns the W3C trace context "traceparent" value for the
+// span in ctx, or an empty string if there is no valid span context.
+func TraceParent(ctx context.Context) string {
+	carrier := propagation.MapCarrier{}
+	propagator.Inject(ctx, carrier)
+	return carrier[traceParentKey]
+}
+
+// ContextWithTraceParent returns a copy of ctx containing the remote span
+// context denoted by the given W3C trace context "traceparent" value.
+// Spans started from the returned context are children of that span.
+// If the value is empty or invalid, ctx is returned.
+func ContextWithTraceParent(ctx context.Context, traceParent string) context.Context {
+	if traceParent == "" {
+		return ctx
+	}
+	return propagator.Extract(ctx, propagation.MapCarrier{traceParentKey: traceParent})
+}
+
+// ContextWithoutSampling returns a copy of ctx containing the given span
+// context marked as not sampled, and the non-recording span representing
+// it. Spans started from the returned context are not recorded.
+func ContextWithoutSampling(ctx context.Context, spanContext trace.SpanContext) (context.Context, trace.Span) {
+	spanContext = spanContext.WithTraceFlags(spanContext.TraceFlags().WithSampled(false))
+	ctx = trace.ContextWithSpanContext(ctx, spanContext)
+	return ctx, trace.SpanFromContext(ctx)
+}
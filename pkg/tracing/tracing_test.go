@@ -0,0 +1,116 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"gotest.tools/v3/assert"
+)
+
+func patchWithInMemoryExporter(t *testing.T) *tracetest.InMemoryExporter {
+	t.Helper()
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	t.Cleanup(Testing{}.PatchTracerProvider(provider))
+	return exporter
+}
+
+func Test_Tracer_NoopByDefault(t *testing.T) {
+	t.Parallel()
+
+	// EXERCISE
+	ctx, span := Start(context.Background(), "span1")
+	defer span.End()
+
+	// VERIFY
+	assert.Assert(t, !span.SpanContext().IsValid())
+	assert.Equal(t, "", TraceParent(ctx))
+}
+
+func Test_Trace(t *testing.T) {
+	// no parallel: patching global state
+
+	// SETUP
+	exporter := patchWithInMemoryExporter(t)
+	error1 := errors.New("error1")
+
+	// EXERCISE
+	resultErr := Trace(context.Background(), "parent", func(ctx context.Context) error {
+		return Trace(ctx, "child", func(context.Context) error {
+			return error1
+		})
+	})
+
+	// VERIFY
+	assert.Equal(t, error1, resultErr)
+	spans := exporter.GetSpans()
+	assert.Equal(t, 2, len(spans))
+	child, parent := spans[0], spans[1]
+	assert.Equal(t, "child", child.Name)
+	assert.Equal(t, "parent", parent.Name)
+	assert.Equal(t, parent.SpanContext.SpanID(), child.Parent.SpanID())
+	assert.Equal(t, codes.Error, child.Status.Code)
+	assert.Equal(t, "error1", child.Status.Description)
+	assert.Equal(t, 1, len(child.Events))
+}
+
+func Test_TraceParent_RoundTrip(t *testing.T) {
+	// no parallel: patching global state
+
+	// SETUP
+	exporter := patchWithInMemoryExporter(t)
+	ctx, root := Start(context.Background(), "root")
+	root.End()
+
+	// EXERCISE
+	traceParent := TraceParent(ctx)
+	_, span := Start(ContextWithTraceParent(context.Background(), traceParent), "child")
+	span.End()
+
+	// VERIFY
+	assert.Assert(t, traceParent != "")
+	spans := exporter.GetSpans()
+	assert.Equal(t, 2, len(spans))
+	assert.Equal(t, spans[0].SpanContext.TraceID(), spans[1].SpanContext.TraceID())
+	assert.Equal(t, spans[0].SpanContext.SpanID(), spans[1].Parent.SpanID())
+	assert.Assert(t, spans[1].Parent.IsRemote())
+}
+
+func Test_ContextWithTraceParent_Invalid(t *testing.T) {
+	t.Parallel()
+
+	for _, traceParent := range []string{"", "invalid1"} {
+		// SETUP
+		ctx := context.Background()
+
+		// EXERCISE
+		result := ContextWithTraceParent(ctx, traceParent)
+
+		// VERIFY
+		assert.Equal(t, "", TraceParent(result))
+	}
+}
+
+func Test_ContextWithoutSampling(t *testing.T) {
+	// no parallel: patching global state
+
+	// SETUP
+	exporter := patchWithInMemoryExporter(t)
+	_, root := Start(context.Background(), "root")
+
+	// EXERCISE
+	ctx, span := ContextWithoutSampling(context.Background(), root.SpanContext())
+	_, child := Start(ctx, "child")
+	child.End()
+	span.End()
+
+	// VERIFY
+	assert.Assert(t, !span.SpanContext().IsSampled())
+	assert.Equal(t, root.SpanContext().TraceID(), span.SpanContext().TraceID())
+	assert.Assert(t, !child.SpanContext().IsSampled())
+	assert.Equal(t, 0, len(exporter.GetSpans()))
+}